@@ -0,0 +1,62 @@
+package config
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/oidc"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// InitRequireVerifiedEmail reads REQUIRE_VERIFIED_EMAIL, off when not set. Accounts created
+// before email verification existed are unverified and have to request a verification mail.
+func InitRequireVerifiedEmail() bool {
+	value := os.Getenv("REQUIRE_VERIFIED_EMAIL")
+	if value == "" {
+		return false
+	}
+	required, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Fatal("REQUIRE_VERIFIED_EMAIL: " + err.Error())
+	}
+	return required
+}
+
+// InitTwoFactorRoles reads TWO_FACTOR_REQUIRED_ROLES, a comma separated list of role names whose
+// users must enable two-factor authentication. Empty keeps it optional for everyone.
+func InitTwoFactorRoles() []string {
+	var roles []string
+	for _, name := range strings.Split(os.Getenv("TWO_FACTOR_REQUIRED_ROLES"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			roles = append(roles, name)
+		}
+	}
+	return roles
+}
+
+// InitOIDCProviders reads the OpenID Connect providers users can login with. OIDC_PROVIDERS is a
+// comma separated list of names used in the login URL, every provider NAME needs:
+//
+//	OIDC_NAME_ISSUER         issuer URL, like https://accounts.google.com or https://sso.example.com/realms/umkm
+//	OIDC_NAME_CLIENT_ID      client registered at the provider
+//	OIDC_NAME_CLIENT_SECRET
+//	OIDC_NAME_REDIRECT_URL   page of the app that receives code and state and posts them to the callback
+func InitOIDCProviders() []domain.OIDCProvider {
+	var providers []domain.OIDCProvider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		issuer := os.Getenv(prefix + "ISSUER")
+		clientID := os.Getenv(prefix + "CLIENT_ID")
+		redirectURL := os.Getenv(prefix + "REDIRECT_URL")
+		if issuer == "" || clientID == "" || redirectURL == "" {
+			log.Fatal(prefix + "ISSUER, " + prefix + "CLIENT_ID and " + prefix + "REDIRECT_URL are required")
+		}
+		providers = append(providers, oidc.NewProvider(name, issuer, clientID, os.Getenv(prefix+"CLIENT_SECRET"), redirectURL, nil))
+	}
+	return providers
+}
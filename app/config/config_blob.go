@@ -0,0 +1,40 @@
+package config
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/blob"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"time"
+)
+
+// InitBlobStore picks where uploaded images are kept with BLOB_STORE. For "s3" it is the bucket
+// described by the S3_ variables. Otherwise the store is nil and the router keeps the images in
+// the directory of the second result, UPLOAD_DIR or "uploads", and serves them itself.
+func InitBlobStore() (domain.BlobStore, string) {
+	switch store := os.Getenv("BLOB_STORE"); store {
+	case "", "local":
+		dir := os.Getenv("UPLOAD_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		return nil, dir
+	case "s3":
+		s3Config := blob.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		}
+		if err := s3Config.Validate(); err != nil {
+			log.Fatal("BLOB_STORE: " + err.Error())
+		}
+		return blob.NewS3Store(s3Config, &http.Client{Timeout: 30 * time.Second}), ""
+	default:
+		log.Fatal("BLOB_STORE must be local or s3, not " + store)
+		return nil, ""
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"github.com/labstack/echo/v4"
+	log "github.com/sirupsen/logrus"
+	"net"
+	"os"
+	"strings"
+)
+
+// InitIPExtractor decides where the client IP of a request comes from, it is recorded in the
+// audit log and keys the login throttle. Without TRUSTED_PROXIES it is the address of the
+// connection, so clients cannot pick their IP with X-Forwarded-For or X-Real-IP. Behind a load
+// balancer TRUSTED_PROXIES is a comma separated list of its CIDRs, then the IP is read from
+// X-Forwarded-For, skipping only hops in those ranges.
+func InitIPExtractor() echo.IPExtractor {
+	var options []echo.TrustOption
+	for _, cidr := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if cidr = strings.TrimSpace(cidr); cidr == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Fatal("TRUSTED_PROXIES: " + err.Error())
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	if len(options) == 0 {
+		return echo.ExtractIPDirect()
+	}
+	options = append(options, echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false))
+	return echo.ExtractIPFromXFFHeader(options...)
+}
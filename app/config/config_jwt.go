@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"strings"
+)
+
+// defaultJWTIssuer is used for iss and aud when JWT_ISSUER or JWT_AUDIENCE is not set.
+const defaultJWTIssuer = "mini-project"
+
+// InitJWT creates the token issuer and verifier. JWT_ISSUER and JWT_AUDIENCE set the iss and aud
+// claims, other services verifying our tokens must expect the same values.
+func InitJWT() *helper.GoJWT {
+	issuer := os.Getenv("JWT_ISSUER")
+	if issuer == "" {
+		issuer = defaultJWTIssuer
+	}
+	audience := os.Getenv("JWT_AUDIENCE")
+	if audience == "" {
+		audience = defaultJWTIssuer
+	}
+	return helper.NewGoJWT(InitJWTKeys(), issuer, audience)
+}
+
+// InitJWTKeys builds the key set from the environment:
+//
+//	JWT_KEY_ID             kid of the key used to sign new tokens
+//	JWT_PRIVATE_KEY_FILE   PEM RSA or P-256 private key, signs with RS256 or ES256
+//	JWT_SECRET             HS256 secret, used when no private key file is set
+//	JWT_VERIFICATION_KEYS  kid=path pairs separated by comma, old keys still accepted during rotation.
+//	                       The file holds a PEM public key, anything else is read as an HS256 secret.
+func InitJWTKeys() *helper.KeySet {
+	keyID := os.Getenv("JWT_KEY_ID")
+
+	var signing *helper.SigningKey
+	var err error
+	if path := os.Getenv("JWT_PRIVATE_KEY_FILE"); path != "" {
+		data, errRead := os.ReadFile(path)
+		if errRead != nil {
+			log.Fatal(errRead.Error())
+		}
+		signing, err = helper.ParsePrivateKeyPEM(keyID, data)
+	} else {
+		signing, err = helper.NewHMACKey(keyID, []byte(os.Getenv("JWT_SECRET")))
+	}
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	var verification []*helper.SigningKey
+	for _, pair := range strings.Split(os.Getenv("JWT_VERIFICATION_KEYS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, path, ok := strings.Cut(pair, "=")
+		if !ok {
+			log.Fatal("JWT_VERIFICATION_KEYS entry must be kid=path, got " + pair)
+		}
+		key, err := loadVerificationKey(strings.TrimSpace(id), strings.TrimSpace(path))
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		verification = append(verification, key)
+	}
+
+	keys, err := helper.NewKeySet(signing, verification...)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	return keys
+}
+
+func loadVerificationKey(id string, path string) (*helper.SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		return helper.ParsePublicKeyPEM(id, data)
+	}
+	return helper.NewHMACKey(id, bytes.TrimSpace(data))
+}
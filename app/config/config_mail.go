@@ -0,0 +1,35 @@
+package config
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/mail"
+	log "github.com/sirupsen/logrus"
+	"os"
+)
+
+// defaultMailFile collects mails when no SMTP server is configured.
+const defaultMailFile = "mail.log"
+
+// InitMailer sends through MAIL_SMTP_HOST when it is set, otherwise mails are appended to
+// MAIL_FILE so password reset works in development.
+func InitMailer() domain.Mailer {
+	host := os.Getenv("MAIL_SMTP_HOST")
+	if host == "" {
+		path := os.Getenv("MAIL_FILE")
+		if path == "" {
+			path = defaultMailFile
+		}
+		log.Info("MAIL_SMTP_HOST is not set, mails are written to " + path)
+		return mail.NewFileMailer(path)
+	}
+
+	port := os.Getenv("MAIL_SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		log.Fatal("MAIL_FROM is required when MAIL_SMTP_HOST is set")
+	}
+	return mail.NewSMTPMailer(host, port, os.Getenv("MAIL_SMTP_USERNAME"), os.Getenv("MAIL_SMTP_PASSWORD"), from)
+}
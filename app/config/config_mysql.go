@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"github.com/nrmadi02/mini-project/app/utils"
+	"github.com/nrmadi02/mini-project/db/migrations"
 	"github.com/nrmadi02/mini-project/db/seeds"
 	"github.com/nrmadi02/mini-project/domain"
 	log "github.com/sirupsen/logrus"
@@ -57,11 +58,15 @@ func InitDB() *gorm.DB {
 }
 
 func InitialMigration() {
-	err := DB.AutoMigrate(&domain.User{}, &domain.Role{}, &domain.Tag{}, &domain.Enterprise{}, &domain.RatingEnterprise{}, &domain.Favorite{}, &domain.Review{})
+	err := migrations.Execute(DB)
+	if err != nil {
+		panic("could not migrate data " + err.Error())
+	}
+
+	err = DB.AutoMigrate(&domain.User{}, &domain.Role{}, &domain.Permission{}, &domain.Tag{}, &domain.Enterprise{}, &domain.RatingEnterprise{}, &domain.Favorite{}, &domain.Review{}, &domain.EnterpriseStatusHistory{}, &domain.EnterpriseImage{}, &domain.RefreshToken{}, &domain.RevokedToken{}, &domain.PasswordResetToken{}, &domain.EmailVerificationToken{}, &domain.LoginAttempt{}, &domain.TwoFactorChallenge{}, &domain.RecoveryCode{}, &domain.APIKey{}, &domain.OIDCState{}, &domain.UserIdentity{}, &domain.AuditLog{})
 
 	if err != nil {
 		panic("could not connect to db " + err.Error())
-		return
 	}
 	seeds.Execute(DB)
 }
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/search"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"strconv"
+)
+
+// InitSearcher opens the enterprise search index at SEARCH_INDEX_PATH. When it is not set the
+// searcher is nil and the router searches the database instead. The second result asks for a
+// reindex, set by SEARCH_REINDEX or an index without enterprises.
+func InitSearcher() (domain.Searcher, bool) {
+	path := os.Getenv("SEARCH_INDEX_PATH")
+	if path == "" {
+		log.Info("SEARCH_INDEX_PATH is not set, enterprises are searched in the database")
+		return nil, false
+	}
+
+	index, err := search.OpenIndex(path)
+	if err != nil {
+		log.Fatal("SEARCH_INDEX_PATH: " + err.Error())
+	}
+	reindex := index.Len() == 0
+	if value := os.Getenv("SEARCH_REINDEX"); value != "" {
+		forced, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatal("SEARCH_REINDEX: " + err.Error())
+		}
+		reindex = reindex || forced
+	}
+	return index, reindex
+}
@@ -0,0 +1,158 @@
+package router_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// fakeDB is a database/sql driver that answers the queries of the repositories from in-memory
+// rows, so the router can be driven end to end. A SELECT returns the rows of its table that
+// match every "column = ?" and "column IN (?,...)" condition of the WHERE clause, COUNT and AVG
+// are computed over them. Writes succeed without changing the rows.
+type fakeDB map[string][]fakeRow
+
+type fakeRow map[string]driver.Value
+
+var (
+	fromTable  = regexp.MustCompile("(?i)\\bFROM `?(\\w+)`?")
+	conditions = regexp.MustCompile("(?i)([\\w`.]+)\\s*(=|IN)\\s*(\\(\\?(?:,\\?)*\\)|\\?)")
+	clauseEnd  = regexp.MustCompile("(?i) (ORDER BY|GROUP BY|LIMIT|FOR UPDATE)\\b")
+)
+
+func (f fakeDB) open() *sql.DB {
+	return sql.OpenDB(fakeConnector{f})
+}
+
+type fakeConnector struct{ db fakeDB }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("fakedb: prepare is not supported: %s", query)
+}
+func (c fakeConn) Close() error              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c fakeConn) ExecContext(_ context.Context, _ string, _ []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	table := fromTable.FindStringSubmatch(query)
+	if table == nil {
+		return nil, fmt.Errorf("fakedb: no table in %s", query)
+	}
+	rows := c.db.match(table[1], query, args)
+
+	lower := strings.ToLower(query)
+	switch {
+	case strings.Contains(lower, "count("):
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{int64(len(rows))}}}, nil
+	case strings.Contains(lower, "avg("):
+		var sum float64
+		for _, row := range rows {
+			sum += float64(row["rating"].(int64))
+		}
+		var avg driver.Value
+		if len(rows) > 0 {
+			avg = sum / float64(len(rows))
+		}
+		return &fakeRows{columns: []string{"avg"}, values: [][]driver.Value{{avg}}}, nil
+	}
+	return newFakeRows(c.db[table[1]], rows), nil
+}
+
+// match returns the rows of table that satisfy the conditions of the WHERE clause of query.
+// Only the placeholders of the WHERE clause may take arguments.
+func (f fakeDB) match(table, query string, args []driver.NamedValue) []fakeRow {
+	where := ""
+	if i := strings.Index(strings.ToUpper(query), " WHERE "); i >= 0 {
+		where = query[i+len(" WHERE "):]
+		if end := clauseEnd.FindStringIndex(where); end != nil {
+			where = where[:end[0]]
+		}
+	}
+
+	var matched []fakeRow
+	for _, row := range f[table] {
+		next, ok := 0, true
+		for _, condition := range conditions.FindAllStringSubmatch(where, -1) {
+			column := condition[1][strings.LastIndex(condition[1], ".")+1:]
+			column = strings.Trim(column, "`")
+			placeholders := strings.Count(condition[3], "?")
+			if next+placeholders > len(args) {
+				break
+			}
+			found := false
+			for _, arg := range args[next : next+placeholders] {
+				if fmt.Sprint(row[column]) == fmt.Sprint(arg.Value) {
+					found = true
+				}
+			}
+			next += placeholders
+			ok = ok && found
+		}
+		if ok {
+			matched = append(matched, row)
+		}
+	}
+	return matched
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+// newFakeRows has a column for every key used by the rows of the table, a row that does not
+// set one reads NULL.
+func newFakeRows(all, rows []fakeRow) *fakeRows {
+	seen := map[string]bool{}
+	res := &fakeRows{}
+	for _, row := range all {
+		for column := range row {
+			if !seen[column] {
+				seen[column] = true
+				res.columns = append(res.columns, column)
+			}
+		}
+	}
+	sort.Strings(res.columns)
+	if len(res.columns) == 0 {
+		res.columns = []string{"id"}
+	}
+	for _, row := range rows {
+		values := make([]driver.Value, len(res.columns))
+		for i, column := range res.columns {
+			values[i] = row[column]
+		}
+		res.values = append(res.values, values)
+	}
+	return res
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
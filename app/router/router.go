@@ -2,81 +2,208 @@ package router
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/domain"
+	http7 "github.com/nrmadi02/mini-project/internal/apikey/delivery/http"
+	repository8 "github.com/nrmadi02/mini-project/internal/apikey/repository"
+	usecase8 "github.com/nrmadi02/mini-project/internal/apikey/usecase"
+	http8 "github.com/nrmadi02/mini-project/internal/audit/delivery/http"
+	repository9 "github.com/nrmadi02/mini-project/internal/audit/repository"
+	usecase9 "github.com/nrmadi02/mini-project/internal/audit/usecase"
+	"github.com/nrmadi02/mini-project/internal/blob"
 	http3 "github.com/nrmadi02/mini-project/internal/enterprise/delivery/http"
 	repository4 "github.com/nrmadi02/mini-project/internal/enterprise/repository"
 	usecase3 "github.com/nrmadi02/mini-project/internal/enterprise/usecase"
 	http4 "github.com/nrmadi02/mini-project/internal/favorite/delivery/http"
 	repository6 "github.com/nrmadi02/mini-project/internal/favorite/repository"
 	usecase5 "github.com/nrmadi02/mini-project/internal/favorite/usecase"
+	http10 "github.com/nrmadi02/mini-project/internal/image/delivery/http"
+	repository10 "github.com/nrmadi02/mini-project/internal/image/repository"
+	usecase11 "github.com/nrmadi02/mini-project/internal/image/usecase"
 	repository5 "github.com/nrmadi02/mini-project/internal/rating/repository"
 	usecase4 "github.com/nrmadi02/mini-project/internal/rating/usecase"
 	http5 "github.com/nrmadi02/mini-project/internal/review/delivery/http"
 	repository7 "github.com/nrmadi02/mini-project/internal/review/repository"
 	usecase6 "github.com/nrmadi02/mini-project/internal/review/usecase"
 	repository2 "github.com/nrmadi02/mini-project/internal/role/repository"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+	"github.com/nrmadi02/mini-project/internal/search"
+	http9 "github.com/nrmadi02/mini-project/internal/suggest/delivery/http"
+	usecase10 "github.com/nrmadi02/mini-project/internal/suggest/usecase"
 	http2 "github.com/nrmadi02/mini-project/internal/tag/delivery/http"
 	repository3 "github.com/nrmadi02/mini-project/internal/tag/repository"
 	usecase2 "github.com/nrmadi02/mini-project/internal/tag/usecase"
 	http6 "github.com/nrmadi02/mini-project/internal/user/delivery/http"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
 	mid "github.com/nrmadi02/mini-project/internal/user/delivery/http/middleware"
 	"github.com/nrmadi02/mini-project/internal/user/repository"
 	usecase7 "github.com/nrmadi02/mini-project/internal/user/usecase"
+	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-func SetupRouter(c *echo.Echo, db *gorm.DB) {
-	authMiddleware := mid.NewGoMiddleware().AuthMiddleware()
+// Options are the dependencies of the routes that are built from the environment.
+type Options struct {
+	JWT    *helper.GoJWT
+	Mailer domain.Mailer
+	// RequireVerifiedEmail blocks creating enterprises and posting reviews until the email is verified.
+	RequireVerifiedEmail bool
+	// TwoFactorRoles must enable two-factor authentication before using admin and delete routes.
+	TwoFactorRoles []string
+	// OIDCProviders are the OpenID Connect providers users can login with.
+	OIDCProviders []domain.OIDCProvider
+	// Searcher finds enterprises by text, nil searches the database.
+	Searcher domain.Searcher
+	// ReindexSearch puts every enterprise in Searcher before serving.
+	ReindexSearch bool
+	// BlobStore keeps uploaded images, nil keeps them in UploadDir and serves them at /uploads/.
+	BlobStore domain.BlobStore
+	// UploadDir defaults to "uploads".
+	UploadDir string
+}
 
+func SetupRouter(c *echo.Echo, db *gorm.DB, options Options) {
 	userRepository := repository.NewUserRepository(db)
+	tokenRepository := repository.NewTokenRepository(db)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(db)
 	roleRepository := repository2.NewRoleRepository(db)
 	tagRepository := repository3.NewTagRepository(db)
 	enterpriseRepository := repository4.NewEnterpriseRepository(db)
 	ratingRepository := repository5.NewRatingRepository(db)
 	favoriteRepository := repository6.NewFavoriteRepository(db)
 	reviewRepository := repository7.NewReviewRepository(db)
+	apiKeyRepository := repository8.NewAPIKeyRepository(db)
+	auditRepository := repository9.NewAuditRepository(db)
+	imageRepository := repository10.NewImageRepository(db)
+
+	blobStore := options.BlobStore
+	if blobStore == nil {
+		uploadDir := options.UploadDir
+		if uploadDir == "" {
+			uploadDir = "uploads"
+		}
+		blobStore = blob.NewLocalStore(uploadDir, "/uploads")
+		c.Static("/uploads/", uploadDir)
+	}
 
-	authUsecase := usecase7.NewAuthUsecase(userRepository, roleRepository, favoriteRepository, enterpriseRepository)
-	userUsecase := usecase7.NewUserUsecase(userRepository)
-	tagUsecase := usecase2.NewTagUsecase(tagRepository)
-	enterpriseUsecase := usecase3.NewEnterpriseUsecase(enterpriseRepository, tagRepository, userRepository)
-	ratingUsecase := usecase4.NewRatingUsecase(userRepository, enterpriseRepository, ratingRepository)
+	searcher := options.Searcher
+	if searcher == nil {
+		searcher = search.NewSQLSearcher(db)
+	}
+	auditUsecase := usecase9.NewAuditUsecase(auditRepository)
+	authUsecase := usecase7.NewAuthUsecase(userRepository, roleRepository, favoriteRepository, enterpriseRepository, tokenRepository, options.JWT, options.Mailer, loginAttemptRepository, options.TwoFactorRoles, options.OIDCProviders, auditUsecase)
+	userUsecase := usecase7.NewUserUsecase(userRepository, roleRepository, tokenRepository, ratingRepository, reviewRepository, imageRepository, enterpriseRepository, authUsecase, blobStore, searcher, auditUsecase)
+	tagUsecase := usecase2.NewTagUsecase(tagRepository, enterpriseRepository, searcher, auditUsecase)
+	enterpriseUsecase := usecase3.NewEnterpriseUsecase(enterpriseRepository, tagRepository, userRepository, searcher, blobStore, auditUsecase)
+	if options.ReindexSearch {
+		if err := enterpriseUsecase.ReindexSearch(); err != nil {
+			log.Error("reindex search: " + err.Error())
+		}
+	}
+	ratingUsecase := usecase4.NewRatingUsecase(userRepository, enterpriseRepository, ratingRepository, auditUsecase)
 	favoriteUsecase := usecase5.NewFavoriteUsecase(enterpriseRepository, favoriteRepository)
-	reviewUsecase := usecase6.NewReviewUsecase(enterpriseRepository, userRepository, reviewRepository, authUsecase)
+	reviewUsecase := usecase6.NewReviewUsecase(enterpriseRepository, userRepository, reviewRepository, authUsecase, auditUsecase)
+	apiKeyUsecase := usecase8.NewAPIKeyUsecase(apiKeyRepository, userRepository)
+	suggestUsecase := usecase10.NewSuggestUsecase(enterpriseRepository, tagRepository, ratingRepository)
+	imageUsecase := usecase11.NewImageUsecase(imageRepository, enterpriseRepository, userRepository, blobStore)
+
+	goMiddleware := mid.NewGoMiddleware(tokenRepository, options.JWT, authUsecase, apiKeyUsecase)
+	authMiddleware := goMiddleware.AuthMiddleware()
+	session := goMiddleware.RequireSession()
+	requirePermission := goMiddleware.RequirePermission
+	verifiedEmail := goMiddleware.RequireVerifiedEmail()
+	if !options.RequireVerifiedEmail {
+		verifiedEmail = func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
+	twoFactor := goMiddleware.RequireTwoFactor()
+	if len(options.TwoFactorRoles) == 0 {
+		twoFactor = func(next echo.HandlerFunc) echo.HandlerFunc { return next }
+	}
 
 	authController := http6.NewAuthController(authUsecase)
-	userController := http6.NewUserController(authUsecase, ratingUsecase)
+	keyController := http6.NewKeyController(options.JWT.Keys())
+	userController := http6.NewUserController(authUsecase, userUsecase, ratingUsecase)
 	adminController := http6.NewAdminController(authUsecase, userUsecase)
 	tagController := http2.NewTagController(authUsecase, tagUsecase)
 	enterpriseController := http3.NewEnterpriseController(authUsecase, enterpriseUsecase, ratingUsecase)
 	favoriteController := http4.NewFavoriteController(favoriteUsecase, authUsecase, ratingUsecase)
 	reviewController := http5.NewReviewController(reviewUsecase, enterpriseUsecase, authUsecase)
+	apiKeyController := http7.NewAPIKeyController(apiKeyUsecase)
+	auditController := http8.NewAuditController(auditUsecase)
+	suggestController := http9.NewSuggestController(suggestUsecase)
+	imageController := http10.NewImageController(imageUsecase)
+
+	c.GET("/.well-known/jwks.json", keyController.JWKS)
 
 	// Auth Endpoints (User)
 	c.POST("/api/v1/register", authController.Register)
 	c.POST("/api/v1/login", authController.Login)
+	c.POST("/api/v1/login/2fa", authController.LoginTwoFactor)
+	c.GET("/api/v1/login/oidc/:provider", authController.OIDCAuthorize)
+	c.POST("/api/v1/login/oidc/:provider/callback", authController.OIDCCallback)
+	c.POST("/api/v1/token/refresh", authController.RefreshToken)
+	c.POST("/api/v1/logout", authController.Logout, authMiddleware, session)
+	c.POST("/api/v1/password/forgot", authController.ForgotPassword)
+	c.POST("/api/v1/password/reset", authController.ResetPassword)
+	c.GET("/api/v1/verify-email", authController.VerifyEmail)
+	c.POST("/api/v1/user/verify-email", authController.SendEmailVerification, authMiddleware, session)
 
 	//user endpoints
-	c.GET("/api/v1/users", adminController.GetUserList, authMiddleware)
+	c.GET("/api/v1/users", adminController.GetUserList, authMiddleware, requirePermission(role.UserManage))
+	c.POST("/api/v1/users/:id/roles", adminController.AssignRole, authMiddleware, twoFactor, requirePermission(role.UserManage))
+	c.DELETE("/api/v1/users/:id/roles/:role", adminController.RevokeRole, authMiddleware, twoFactor, requirePermission(role.UserManage))
+	c.PUT("/api/v1/users/:id/suspend", adminController.SuspendUser, authMiddleware, twoFactor, requirePermission(role.UserManage))
+	c.PUT("/api/v1/users/:id/reactivate", adminController.ReactivateUser, authMiddleware, twoFactor, requirePermission(role.UserManage))
+	c.PUT("/api/v1/users/:id/password-reset", adminController.ForcePasswordReset, authMiddleware, twoFactor, requirePermission(role.UserManage))
+	c.DELETE("/api/v1/users/:id", adminController.DeleteUser, authMiddleware, twoFactor, requirePermission(role.UserManage))
+	c.GET("/api/v1/users/locked", adminController.GetLockedAccounts, authMiddleware, requirePermission(role.UserManage))
+	c.DELETE("/api/v1/users/:id/lockout", adminController.UnlockAccount, authMiddleware, twoFactor, requirePermission(role.UserManage))
 	c.GET("/api/v1/user", userController.User, authMiddleware)
+	c.PUT("/api/v1/user", userController.UpdateProfile, authMiddleware, session)
+	c.PUT("/api/v1/user/password", userController.ChangePassword, authMiddleware, session)
+	c.GET("/api/v1/user/export", userController.ExportData, authMiddleware, session)
+	c.DELETE("/api/v1/user", userController.DeleteAccount, authMiddleware, session)
+	c.POST("/api/v1/user/2fa/setup", userController.SetupTwoFactor, authMiddleware, session)
+	c.POST("/api/v1/user/2fa/enable", userController.EnableTwoFactor, authMiddleware, session)
+	c.POST("/api/v1/user/2fa/disable", userController.DisableTwoFactor, authMiddleware, session)
+	c.POST("/api/v1/user/2fa/recovery-codes", userController.RegenerateRecoveryCodes, authMiddleware, session)
+
+	//api key endpoints
+	c.POST("/api/v1/user/api-keys", apiKeyController.CreateAPIKey, authMiddleware, session)
+	c.GET("/api/v1/user/api-keys", apiKeyController.GetAPIKeys, authMiddleware, session)
+	c.DELETE("/api/v1/user/api-keys/:id", apiKeyController.RevokeAPIKey, authMiddleware, session)
+	c.POST("/api/v1/users/:id/api-keys", apiKeyController.CreateUserAPIKey, authMiddleware, session, twoFactor, requirePermission(role.UserManage))
+	c.GET("/api/v1/users/:id/api-keys", apiKeyController.GetUserAPIKeys, authMiddleware, session, requirePermission(role.UserManage))
+	c.DELETE("/api/v1/users/:id/api-keys/:keyId", apiKeyController.RevokeUserAPIKey, authMiddleware, session, twoFactor, requirePermission(role.UserManage))
+
+	//audit endpoints
+	c.GET("/api/v1/audit-logs", auditController.GetAuditLogs, authMiddleware, requirePermission(role.AuditRead))
 
 	//tag endpoints
 	c.GET("/api/v1/tags", tagController.GetTagsList, authMiddleware)
-	c.DELETE("/api/v1/tag/:id", tagController.DeleteTag, authMiddleware)
-	c.POST("/api/v1/tag", tagController.CreateTag, authMiddleware)
+	c.DELETE("/api/v1/tag/:id", tagController.DeleteTag, authMiddleware, requirePermission(role.TagManage))
+	c.POST("/api/v1/tag", tagController.CreateTag, authMiddleware, requirePermission(role.TagManage))
 
 	//enterprise endpoints
-	c.POST("/api/v1/enterprise", enterpriseController.CreateNewEnterprise, authMiddleware)
+	c.POST("/api/v1/enterprise", enterpriseController.CreateNewEnterprise, authMiddleware, verifiedEmail, requirePermission(role.EnterpriseCreate))
 	c.PUT("/api/v1/enterprise/:id/status", enterpriseController.UpdateStatusEnterprise, authMiddleware)
+	c.GET("/api/v1/enterprise/:id/status/history", enterpriseController.GetStatusHistories, authMiddleware)
+	c.GET("/api/v1/enterprises/nearby", enterpriseController.GetNearbyEnterprises, authMiddleware)
 	c.GET("/api/v1/enterprises/:status", enterpriseController.GetEnterpriseByStatus, authMiddleware)
 	c.PUT("/api/v1/enterprise/:id", enterpriseController.UpdateEnterpriseByID, authMiddleware)
 	c.GET("/api/v1/enterprises", enterpriseController.GetAllEnterprises, authMiddleware)
-	c.DELETE("/api/v1/enterprise/:id", enterpriseController.DeleteEnterpriseByID, authMiddleware)
+	c.DELETE("/api/v1/enterprise/:id", enterpriseController.DeleteEnterpriseByID, authMiddleware, twoFactor)
 	c.GET("/api/v1/enterprise/:id", enterpriseController.GetDetailEnterpriseByID, authMiddleware)
 	c.GET("/api/v1/enterprise/:id/distance", enterpriseController.GetDistance, authMiddleware)
 	c.POST("/api/v1/enterprise/:id/rating", enterpriseController.AddNewRanting, authMiddleware)
 	c.GET("/api/v1/enterprise/:id/rating/user/:userid", enterpriseController.CekRatingUser, authMiddleware)
-	c.DELETE("/api/v1/enterprise/:id/rating/user/:userid", enterpriseController.DeleteRatingUser, authMiddleware)
-	c.PUT("/api/v1/enterprise/:id/rating/user/:userid", enterpriseController.UpdateRating, authMiddleware)
+	c.DELETE("/api/v1/enterprise/:id/rating", enterpriseController.DeleteRatingUser, authMiddleware, twoFactor)
+	c.PUT("/api/v1/enterprise/:id/rating", enterpriseController.UpdateRating, authMiddleware)
+	// echo keeps the param names of the first route on a path, so upload and delete share :image.
+	c.POST("/api/v1/enterprise/:id/images/:image", imageController.UploadImage, authMiddleware)
+	c.DELETE("/api/v1/enterprise/:id/images/:image", imageController.DeleteImage, authMiddleware)
+
+	//search endpoints
+	c.GET("/api/v1/search/suggest", suggestController.Suggest, authMiddleware)
 
 	//favorite endpoints
 	c.POST("/api/v1/favorite", favoriteController.AddFavoriteEnterprise, authMiddleware)
@@ -84,7 +211,7 @@ func SetupRouter(c *echo.Echo, db *gorm.DB) {
 	c.GET("/api/v1/favorite", favoriteController.GetDetailFavoriteEnterprise, authMiddleware)
 
 	//review endpoints
-	c.POST("/api/v1/review/enterprise/:id", reviewController.AddReviewEnterprise, authMiddleware)
+	c.POST("/api/v1/review/enterprise/:id", reviewController.AddReviewEnterprise, authMiddleware, verifiedEmail)
 	c.GET("/api/v1/review/enterprise/:id", reviewController.GetListReviewByEnterpriseID, authMiddleware)
 	c.PUT("/api/v1/review/enterprise/:id", reviewController.UpdateReviewEnterprise, authMiddleware)
 	c.DELETE("/api/v1/review/enterprise/:id", reviewController.DeleteReviewEnterprise, authMiddleware)
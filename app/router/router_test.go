@@ -0,0 +1,275 @@
+package router_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	sqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/app/router"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+
+var routes = []struct {
+	method string
+	path   string
+	public bool
+}{
+	{http.MethodGet, "/.well-known/jwks.json", true},
+	{http.MethodGet, "/uploads/*", true},
+	{http.MethodPost, "/api/v1/register", true},
+	{http.MethodPost, "/api/v1/login", true},
+	{http.MethodPost, "/api/v1/login/2fa", true},
+	{http.MethodGet, "/api/v1/login/oidc/:provider", true},
+	{http.MethodPost, "/api/v1/login/oidc/:provider/callback", true},
+	{http.MethodPost, "/api/v1/token/refresh", true},
+	{http.MethodPost, "/api/v1/logout", false},
+	{http.MethodPost, "/api/v1/password/forgot", true},
+	{http.MethodPost, "/api/v1/password/reset", true},
+	{http.MethodGet, "/api/v1/verify-email", true},
+	{http.MethodPost, "/api/v1/user/verify-email", false},
+	{http.MethodGet, "/api/v1/users", false},
+	{http.MethodPost, "/api/v1/users/:id/roles", false},
+	{http.MethodDelete, "/api/v1/users/:id/roles/:role", false},
+	{http.MethodPut, "/api/v1/users/:id/suspend", false},
+	{http.MethodPut, "/api/v1/users/:id/reactivate", false},
+	{http.MethodPut, "/api/v1/users/:id/password-reset", false},
+	{http.MethodDelete, "/api/v1/users/:id", false},
+	{http.MethodGet, "/api/v1/users/locked", false},
+	{http.MethodDelete, "/api/v1/users/:id/lockout", false},
+	{http.MethodGet, "/api/v1/user", false},
+	{http.MethodPut, "/api/v1/user", false},
+	{http.MethodPut, "/api/v1/user/password", false},
+	{http.MethodGet, "/api/v1/user/export", false},
+	{http.MethodDelete, "/api/v1/user", false},
+	{http.MethodPost, "/api/v1/user/2fa/setup", false},
+	{http.MethodPost, "/api/v1/user/2fa/enable", false},
+	{http.MethodPost, "/api/v1/user/2fa/disable", false},
+	{http.MethodPost, "/api/v1/user/2fa/recovery-codes", false},
+	{http.MethodPost, "/api/v1/user/api-keys", false},
+	{http.MethodGet, "/api/v1/user/api-keys", false},
+	{http.MethodDelete, "/api/v1/user/api-keys/:id", false},
+	{http.MethodPost, "/api/v1/users/:id/api-keys", false},
+	{http.MethodGet, "/api/v1/users/:id/api-keys", false},
+	{http.MethodDelete, "/api/v1/users/:id/api-keys/:keyId", false},
+	{http.MethodGet, "/api/v1/audit-logs", false},
+	{http.MethodGet, "/api/v1/tags", false},
+	{http.MethodDelete, "/api/v1/tag/:id", false},
+	{http.MethodPost, "/api/v1/tag", false},
+	{http.MethodPost, "/api/v1/enterprise", false},
+	{http.MethodPut, "/api/v1/enterprise/:id/status", false},
+	{http.MethodGet, "/api/v1/enterprise/:id/status/history", false},
+	{http.MethodGet, "/api/v1/enterprises/nearby", false},
+	{http.MethodGet, "/api/v1/enterprises/:status", false},
+	{http.MethodPut, "/api/v1/enterprise/:id", false},
+	{http.MethodGet, "/api/v1/enterprises", false},
+	{http.MethodDelete, "/api/v1/enterprise/:id", false},
+	{http.MethodGet, "/api/v1/enterprise/:id", false},
+	{http.MethodGet, "/api/v1/enterprise/:id/distance", false},
+	{http.MethodPost, "/api/v1/enterprise/:id/rating", false},
+	{http.MethodGet, "/api/v1/enterprise/:id/rating/user/:userid", false},
+	{http.MethodDelete, "/api/v1/enterprise/:id/rating", false},
+	{http.MethodPut, "/api/v1/enterprise/:id/rating", false},
+	{http.MethodPost, "/api/v1/enterprise/:id/images/:image", false},
+	{http.MethodDelete, "/api/v1/enterprise/:id/images/:image", false},
+	{http.MethodGet, "/api/v1/search/suggest", false},
+	{http.MethodPost, "/api/v1/favorite", false},
+	{http.MethodDelete, "/api/v1/favorite", false},
+	{http.MethodGet, "/api/v1/favorite", false},
+	{http.MethodPost, "/api/v1/review/enterprise/:id", false},
+	{http.MethodGet, "/api/v1/review/enterprise/:id", false},
+	{http.MethodPut, "/api/v1/review/enterprise/:id", false},
+	{http.MethodDelete, "/api/v1/review/enterprise/:id", false},
+	{http.MethodGet, "/api/v1/review/:id", false},
+}
+
+func setupEcho(t *testing.T) *echo.Echo {
+	db, _, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		DSN:                       "sqlmock_db_0",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{PrepareStmt: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	router.SetupRouter(e, gormDB, router.Options{JWT: helper.NewGoJWT(keySet, "test", "test")})
+	return e
+}
+
+func TestSetupRouter_Routes(t *testing.T) {
+	e := setupEcho(t)
+
+	var want, got []string
+	for _, r := range routes {
+		want = append(want, r.method+" "+r.path)
+	}
+	for _, r := range e.Routes() {
+		got = append(got, r.Method+" "+r.Path)
+	}
+	sort.Strings(want)
+	sort.Strings(got)
+	assert.Equal(t, want, got)
+}
+
+func TestSetupRouter_Unauthorized(t *testing.T) {
+	e := setupEcho(t)
+
+	for _, r := range routes {
+		if r.public {
+			continue
+		}
+		path := strings.NewReplacer(":id", "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", ":userid", "35d6a9a1-aa5e-41f1-9991-08878dfdf891",
+			":keyId", "1", ":image", "logo", ":role", "ROLE_ADMIN", ":status", "1").Replace(r.path)
+		t.Run(r.method+" "+r.path, func(t *testing.T) {
+			req := httptest.NewRequest(r.method, path, nil)
+			req.Header.Set(echo.HeaderAuthorization, "Bearer invalid")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		})
+	}
+}
+
+var (
+	owner      = domain.User{ID: uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf891"), Roles: []domain.Role{{ID: 2, Name: "ROLE_CLIENT"}}}
+	stranger   = domain.User{ID: uuid.FromStringOrNil("0cf712fc-4a5b-4c6d-8e7f-9a0b1c298edf"), Roles: []domain.Role{{ID: 2, Name: "ROLE_CLIENT"}}}
+	moderator  = domain.User{ID: uuid.FromStringOrNil("5b1e6d2c-7f3a-4e8b-9c0d-1a2b3c4d5e6f"), Roles: []domain.Role{{ID: 4, Name: "ROLE_MODERATOR"}}}
+	admin      = domain.User{ID: uuid.FromStringOrNil("9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b"), Roles: []domain.Role{{ID: 1, Name: "ROLE_ADMIN"}}}
+	enterprise = "35d6a9a1-aa5e-41f1-9991-08878dfdf89a"
+	image      = "8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"
+)
+
+// policyRoutes are the routes on resources that belong to a user, with the status each kind of
+// user gets. Everything belongs to owner. Rating and review routes send the userid of owner,
+// the routes that honour it act on the rating or review of owner.
+var policyRoutes = []struct {
+	method                            string
+	path                              string
+	body                              string
+	owner, stranger, moderator, admin int
+}{
+	{http.MethodPut, "/api/v1/enterprise/" + enterprise, `{"name":"renamed","tags":[]}`, 200, 403, 403, 200},
+	{http.MethodDelete, "/api/v1/enterprise/" + enterprise, "", 200, 403, 403, 200},
+	{http.MethodGet, "/api/v1/enterprise/" + enterprise + "/status/history", "", 200, 403, 200, 200},
+	{http.MethodPut, "/api/v1/enterprise/" + enterprise + "/status?status=submitted", "", 200, 403, 403, 403},
+	{http.MethodPut, "/api/v1/enterprise/" + enterprise + "/rating?value=4&userid=" + owner.ID.String(), "", 200, 404, 404, 404},
+	{http.MethodDelete, "/api/v1/enterprise/" + enterprise + "/rating?userid=" + owner.ID.String(), "", 200, 403, 200, 200},
+	{http.MethodPut, "/api/v1/review/enterprise/" + enterprise + "?userid=" + owner.ID.String(), `{"review":"changed"}`, 200, 400, 400, 400},
+	{http.MethodDelete, "/api/v1/review/enterprise/" + enterprise + "?userid=" + owner.ID.String(), "", 200, 403, 200, 200},
+	{http.MethodPost, "/api/v1/enterprise/" + enterprise + "/images/logo", "", 201, 403, 403, 201},
+	{http.MethodDelete, "/api/v1/enterprise/" + enterprise + "/images/" + image, "", 200, 403, 403, 200},
+}
+
+// policyDB holds the users above with the permissions db/seeds gives their roles, and an
+// enterprise of owner with a rating, a review and a logo.
+func policyDB() fakeDB {
+	user := func(u domain.User, name string) fakeRow {
+		return fakeRow{"id": u.ID.String(), "fullname": name, "email": name + "@email.com", "username": name}
+	}
+	return fakeDB{
+		"users": {user(owner, "owner"), user(stranger, "stranger"), user(moderator, "moderator"), user(admin, "admin")},
+		"user_roles": {
+			{"user_id": owner.ID.String(), "role_id": int64(2)},
+			{"user_id": stranger.ID.String(), "role_id": int64(2)},
+			{"user_id": moderator.ID.String(), "role_id": int64(4)},
+			{"user_id": admin.ID.String(), "role_id": int64(1)},
+		},
+		"roles": {
+			{"id": int64(1), "name": "ROLE_ADMIN"},
+			{"id": int64(2), "name": "ROLE_CLIENT"},
+			{"id": int64(4), "name": "ROLE_MODERATOR"},
+		},
+		"role_permissions": {
+			{"role_id": int64(1), "permission_id": int64(3)},
+			{"role_id": int64(1), "permission_id": int64(4)},
+			{"role_id": int64(1), "permission_id": int64(5)},
+			{"role_id": int64(4), "permission_id": int64(4)},
+			{"role_id": int64(4), "permission_id": int64(5)},
+		},
+		"permissions": {
+			{"id": int64(3), "name": "enterprise:manage"},
+			{"id": int64(4), "name": "review:moderate"},
+			{"id": int64(5), "name": "enterprise:publish"},
+		},
+		"enterprises":        {{"id": enterprise, "user_id": owner.ID.String(), "name": "enterprise", "status": int64(domain.EnterpriseDraft)}},
+		"enterprise_images":  {{"id": image, "enterprise_id": enterprise, "kind": string(domain.ImageLogo), "key": "logo.png", "url": "/uploads/logo.png"}},
+		"rating_enterprises": {{"id": "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d", "enterprise_id": enterprise, "user_id": owner.ID.String(), "rating": int64(5)}},
+		"reviews":            {{"id": "d4c3b2a1-6f5e-4b7a-9d8c-5c4b3a2f1e0d", "enterprise_id": enterprise, "user_id": owner.ID.String(), "review": "good"}},
+	}
+}
+
+func policyRequest(method, path, body, token string) *http.Request {
+	var req *http.Request
+	if strings.Contains(path, "/images/logo") {
+		var form bytes.Buffer
+		writer := multipart.NewWriter(&form)
+		part, _ := writer.CreateFormFile("image", "logo.png")
+		_, _ = part.Write([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+		_ = writer.Close()
+		req = httptest.NewRequest(method, path, &form)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	} else {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req = httptest.NewRequest(method, path, reader)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	return req
+}
+
+func TestSetupRouter_Policy(t *testing.T) {
+	jwt := helper.NewGoJWT(keySet, "test", "test")
+
+	for _, r := range policyRoutes {
+		for _, actor := range []struct {
+			name string
+			user domain.User
+			want int
+		}{
+			{"owner", owner, r.owner},
+			{"stranger", stranger, r.stranger},
+			{"moderator", moderator, r.moderator},
+			{"admin", admin, r.admin},
+		} {
+			t.Run(r.method+" "+r.path+" as "+actor.name, func(t *testing.T) {
+				gormDB, err := gorm.Open(mysql.New(mysql.Config{
+					Conn:                      policyDB().open(),
+					SkipInitializeWithVersion: true,
+				}), &gorm.Config{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				e := echo.New()
+				router.SetupRouter(e, gormDB, router.Options{JWT: jwt, UploadDir: t.TempDir()})
+
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, policyRequest(r.method, r.path, r.body, jwt.CreateTokenJWT(&actor.user)))
+				assert.Equal(t, actor.want, rec.Code, rec.Body.String())
+			})
+		}
+	}
+}
@@ -22,15 +22,28 @@ import (
 // @securityDefinitions.apiKey JWT
 // @in header
 // @name Authorization
+// @securityDefinitions.apiKey APIKey
+// @in header
+// @name X-API-Key
 
 func Run() {
 	docs.SwaggerInfo.Host = os.Getenv("APP_HOST")
 
+	options := router.Options{
+		JWT:                  config.InitJWT(),
+		Mailer:               config.InitMailer(),
+		RequireVerifiedEmail: config.InitRequireVerifiedEmail(),
+		TwoFactorRoles:       config.InitTwoFactorRoles(),
+		OIDCProviders:        config.InitOIDCProviders(),
+	}
+	options.Searcher, options.ReindexSearch = config.InitSearcher()
+	options.BlobStore, options.UploadDir = config.InitBlobStore()
 	db := config.InitDB()
 
 	e := echo.New()
+	e.IPExtractor = config.InitIPExtractor()
 	e.Use(loggingMiddleware())
-	router.SetupRouter(e, db)
+	router.SetupRouter(e, db, options)
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	address := fmt.Sprintf(":%s", os.Getenv("APP_PORT"))
@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/enterprise/utils"
+	"gorm.io/gorm"
+	"strings"
+)
+
+type legacyCoordinate struct {
+	ID        string
+	Latitude  string
+	Longitude string
+}
+
+// enterpriseCoordinate moves the free-form latitude/longitude strings into numeric columns.
+// Values that cannot be parsed or are out of range become NULL instead of failing the migration.
+func enterpriseCoordinate(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&domain.Enterprise{}) {
+		return nil
+	}
+
+	textColumn, err := isTextColumn(db, "latitude")
+	if err != nil {
+		return err
+	}
+	if textColumn {
+		if err := migrator.RenameColumn(&domain.Enterprise{}, "latitude", "latitude_legacy"); err != nil {
+			return err
+		}
+		if err := migrator.RenameColumn(&domain.Enterprise{}, "longitude", "longitude_legacy"); err != nil {
+			return err
+		}
+	}
+
+	// a previous run may have stopped halfway, so the legacy columns decide whether there is work left
+	if !migrator.HasColumn(&domain.Enterprise{}, "latitude_legacy") {
+		return nil
+	}
+	for _, field := range []string{"Latitude", "Longitude"} {
+		if !migrator.HasColumn(&domain.Enterprise{}, field) {
+			if err := migrator.AddColumn(&domain.Enterprise{}, field); err != nil {
+				return err
+			}
+		}
+	}
+
+	var legacy []legacyCoordinate
+	err = db.Table("enterprises").Select("id, latitude_legacy AS latitude, longitude_legacy AS longitude").Scan(&legacy).Error
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, row := range legacy {
+			latitude, longitude := parseLegacyCoordinate(row)
+			err := tx.Table("enterprises").Where("id = ?", row.ID).
+				Updates(map[string]interface{}{"latitude": latitude, "longitude": longitude}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := migrator.DropColumn(&domain.Enterprise{}, "latitude_legacy"); err != nil {
+		return err
+	}
+	return migrator.DropColumn(&domain.Enterprise{}, "longitude_legacy")
+}
+
+func isTextColumn(db *gorm.DB, name string) (bool, error) {
+	columnTypes, err := db.Migrator().ColumnTypes(&domain.Enterprise{})
+	if err != nil {
+		return false, err
+	}
+	for _, columnType := range columnTypes {
+		if strings.EqualFold(columnType.Name(), name) {
+			typeName := strings.ToLower(columnType.DatabaseTypeName())
+			return strings.Contains(typeName, "char") || strings.Contains(typeName, "text"), nil
+		}
+	}
+	return false, nil
+}
+
+func parseLegacyCoordinate(row legacyCoordinate) (latitude, longitude *float64) {
+	lat, errLat := geo.ParseCoordinate(row.Latitude)
+	lon, errLon := geo.ParseCoordinate(row.Longitude)
+	if errLat != nil || errLon != nil || !geo.ValidCoordinate(lat, lon) {
+		return nil, nil
+	}
+	return &lat, &lon
+}
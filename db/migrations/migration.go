@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// migration converts existing data that AutoMigrate cannot change on its own.
+// Every migration must be safe to run again on an already migrated database.
+type migration struct {
+	name string
+	run  func(db *gorm.DB) error
+}
+
+var migrations = []migration{
+	{name: "EnterpriseCoordinate", run: enterpriseCoordinate},
+}
+
+// Execute runs before AutoMigrate so the old schema is still in place.
+func Execute(db *gorm.DB) error {
+	for _, m := range migrations {
+		log.Println("Migrating " + m.name + " ...")
+		if err := m.run(db); err != nil {
+			return err
+		}
+		log.Println("Migrating " + m.name + " successful")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package seeds
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+)
+
+func (s Seed) PermissionSeed() {
+	for _, permission := range role.Permissions {
+		s.db.FirstOrCreate(&domain.Permission{}, domain.Permission{Name: permission.String()})
+	}
+}
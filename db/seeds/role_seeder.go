@@ -3,10 +3,43 @@ package seeds
 import (
 	"github.com/nrmadi02/mini-project/domain"
 	"github.com/nrmadi02/mini-project/internal/role/utils"
+	log "github.com/sirupsen/logrus"
 )
 
+// rolePermissions is granted on every start, permissions added by hand are kept.
+// Clients keep enterprise:create because they could register an enterprise before owners existed.
+var rolePermissions = map[role.Role][]role.Permission{
+	role.Admin:     role.Permissions,
+	role.Client:    {role.EnterpriseCreate},
+	role.Owner:     {role.EnterpriseCreate},
+	role.Moderator: {role.EnterprisePublish, role.ReviewModerate},
+}
+
 func (s Seed) RoleSeed() {
 
 	s.db.FirstOrCreate(&domain.Role{}, domain.Role{Name: role.Admin.String(), ID: 1})
 	s.db.FirstOrCreate(&domain.Role{}, domain.Role{Name: role.Client.String(), ID: 2})
+	s.db.FirstOrCreate(&domain.Role{}, domain.Role{Name: role.Owner.String(), ID: 3})
+	s.db.FirstOrCreate(&domain.Role{}, domain.Role{Name: role.Moderator.String(), ID: 4})
+
+	for r, permissions := range rolePermissions {
+		var names []string
+		for _, permission := range permissions {
+			names = append(names, permission.String())
+		}
+
+		var roleModel domain.Role
+		var permissionModels []domain.Permission
+		if err := s.db.Where("name = ?", r.String()).First(&roleModel).Error; err != nil {
+			log.Error(err.Error())
+			continue
+		}
+		if err := s.db.Where("name IN ?", names).Find(&permissionModels).Error; err != nil {
+			log.Error(err.Error())
+			continue
+		}
+		if err := s.db.Model(&roleModel).Association("Permissions").Append(permissionModels); err != nil {
+			log.Error(err.Error())
+		}
+	}
 }
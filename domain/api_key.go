@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/web/request"
+	uuid "github.com/satori/go.uuid"
+	"strings"
+	"time"
+)
+
+// Scopes of an API key. Read allows GET requests and write every other method, a route behind
+// RequirePermission also needs the permission name as scope.
+const (
+	APIKeyScopeRead  = "read"
+	APIKeyScopeWrite = "write"
+)
+
+// APIKey lets a machine client act as its user with the X-API-Key header. Only the hash of
+// the key is stored, Prefix is kept to tell keys apart.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" gorm:"PrimaryKey"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"notnull;type:varchar;size:256;index"`
+	Name       string     `json:"name" gorm:"notnull;size:100"`
+	Prefix     string     `json:"prefix" gorm:"notnull;size:16"`
+	KeyHash    string     `json:"-" gorm:"notnull;size:64;unique"`
+	Scopes     string     `json:"scopes" gorm:"type:text"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ScopeList returns the scopes, they are stored comma separated.
+func (k APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckUsable returns an error when the key is revoked or expired at now.
+func (k APIKey) CheckUsable(now time.Time) error {
+	if k.RevokedAt != nil {
+		return errors.New("api key revoked")
+	}
+	if k.ExpiresAt != nil && now.After(*k.ExpiresAt) {
+		return errors.New("api key expired")
+	}
+	return nil
+}
+
+type APIKeyRepository interface {
+	Save(key APIKey) (APIKey, error)
+	FindByHash(hash string) (APIKey, error)
+	FindByID(id string) (APIKey, error)
+	FindByUserID(userID string) ([]APIKey, error)
+	Revoke(key APIKey) error
+	TouchLastUsed(key APIKey, at time.Time) error
+}
+
+type APIKeyUsecase interface {
+	CreateAPIKey(userID string, request request.APIKeyCreateRequest) (APIKey, string, error)
+	GetAPIKeys(userID string) ([]APIKey, error)
+	RevokeAPIKey(userID string, id string) error
+	Authenticate(key string) (APIKey, error)
+}
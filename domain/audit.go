@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"encoding/json"
+	uuid "github.com/satori/go.uuid"
+	"reflect"
+	"time"
+)
+
+// Audit actions, named resource.action like the permissions.
+const (
+	AuditLogin             = "auth.login"
+	AuditLoginFailed       = "auth.login_failed"
+	AuditAccountUnlock     = "user.unlock"
+	AuditRoleAssign        = "user.role_assign"
+	AuditRoleRevoke        = "user.role_revoke"
+	AuditUserSuspend       = "user.suspend"
+	AuditUserReactivate    = "user.reactivate"
+	AuditUserPasswordReset = "user.password_reset"
+	AuditUserDelete        = "user.delete"
+	AuditUserErase         = "user.erase"
+	AuditEnterpriseStatus  = "enterprise.status"
+	AuditEnterpriseDelete  = "enterprise.delete"
+	AuditTagDelete         = "tag.delete"
+	AuditRatingDelete      = "rating.delete"
+	AuditReviewDelete      = "review.delete"
+)
+
+// Audit target types.
+const (
+	AuditTargetUser       = "user"
+	AuditTargetEnterprise = "enterprise"
+	AuditTargetTag        = "tag"
+	AuditTargetRating     = "rating"
+	AuditTargetReview     = "review"
+)
+
+// Actor is who makes a request. UserID is empty before login.
+type Actor struct {
+	UserID    string
+	IP        string
+	UserAgent string
+}
+
+// AuditLog records a security relevant action. The table is append-only, rows are never
+// updated or deleted, also not when the actor or target is deleted.
+type AuditLog struct {
+	ID         uuid.UUID `json:"id" gorm:"PrimaryKey"`
+	ActorID    string    `json:"actor_id" gorm:"size:256;index"`
+	Action     string    `json:"action" gorm:"notnull;size:100;index"`
+	TargetType string    `json:"target_type" gorm:"notnull;size:50;index:idx_audit_logs_target"`
+	TargetID   string    `json:"target_id" gorm:"size:256;index:idx_audit_logs_target"`
+	Before     string    `json:"before" gorm:"type:text"`
+	After      string    `json:"after" gorm:"type:text"`
+	IP         string    `json:"ip" gorm:"size:64"`
+	UserAgent  string    `json:"user_agent" gorm:"size:512"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
+
+// NewAuditLog builds the entry for an action on a target. Before and after only keep the
+// fields that changed, a field missing on one side is kept on the other.
+func NewAuditLog(actor Actor, action string, targetType string, targetID string, before map[string]interface{}, after map[string]interface{}) AuditLog {
+	changedBefore := map[string]interface{}{}
+	changedAfter := map[string]interface{}{}
+	for key, value := range before {
+		if afterValue, ok := after[key]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changedBefore[key] = value
+		}
+	}
+	for key, value := range after {
+		if beforeValue, ok := before[key]; !ok || !reflect.DeepEqual(value, beforeValue) {
+			changedAfter[key] = value
+		}
+	}
+
+	userAgent := actor.UserAgent
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+	return AuditLog{
+		ID:         uuid.NewV4(),
+		ActorID:    actor.UserID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     auditJSON(changedBefore),
+		After:      auditJSON(changedAfter),
+		IP:         actor.IP,
+		UserAgent:  userAgent,
+	}
+}
+
+func auditJSON(fields map[string]interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// AuditLogFilter narrows the audit log, empty fields match everything.
+type AuditLogFilter struct {
+	ActorID    string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	Length     int
+}
+
+// PageLength is the page length used for the filter, 50 by default and at most 200 so the
+// whole trail is never read at once.
+func (f AuditLogFilter) PageLength() int {
+	if f.Length <= 0 {
+		return 50
+	}
+	if f.Length > 200 {
+		return 200
+	}
+	return f.Length
+}
+
+type AuditRepository interface {
+	Save(log AuditLog) error
+	Find(filter AuditLogFilter) ([]AuditLog, int, error)
+}
+
+type AuditUsecase interface {
+	// Record writes the entry, a failed write is logged and does not fail the action.
+	Record(actor Actor, action string, targetType string, targetID string, before map[string]interface{}, after map[string]interface{})
+	GetAuditLogs(filter AuditLogFilter) ([]AuditLog, int, error)
+}
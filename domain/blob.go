@@ -0,0 +1,12 @@
+package domain
+
+import "io"
+
+// BlobStore keeps uploaded files under keys such as enterprises/<id>/<image id>.jpg.
+type BlobStore interface {
+	Put(key string, contentType string, body io.Reader, size int64) error
+	// Delete succeeds when there is nothing stored under key.
+	Delete(key string) error
+	// URL is where clients download the file stored under key.
+	URL(key string) string
+}
@@ -7,33 +7,73 @@ import (
 )
 
 type Enterprise struct {
-	ID               uuid.UUID          `json:"id" gorm:"PrimaryKey"`
-	UserID           uuid.UUID          `json:"user_id" gorm:"notnull;type:varchar;size:256"`
-	Name             string             `json:"name" gorm:"notnull"`
-	NumberPhone      string             `json:"number_phone" gorm:"notnull"`
-	Address          string             `json:"address" gorm:"notnull"`
-	Postcode         int                `json:"postcode" gorm:"notnull"`
-	Latitude         string             `json:"latitude" gorm:"null"`
-	Longitude        string             `json:"longitude" gorm:"null"`
-	Description      string             `json:"description" gorm:"notnull;type:text"`
-	Status           int                `json:"status" gorm:"notnull"`
-	Tags             []Tag              `json:"tags,omitempty" gorm:"many2many:enterprise_tags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
-	RatingEnterprise []RatingEnterprise `json:"rating_enterprise,omitempty" gorm:"foreignKey:EnterpriseID;references:ID"`
-	Reviews          []Review           `json:"reviews,omitempty" gorm:"foreignKey:EnterpriseID;references:ID"`
-	CreatedAt        time.Time          `json:"created_at"`
-	UpdatedAt        time.Time          `json:"updated_at"`
+	ID               uuid.UUID                 `json:"id" gorm:"PrimaryKey"`
+	UserID           uuid.UUID                 `json:"user_id" gorm:"notnull;type:varchar;size:256"`
+	Name             string                    `json:"name" gorm:"notnull"`
+	NumberPhone      string                    `json:"number_phone" gorm:"notnull"`
+	Address          string                    `json:"address" gorm:"notnull"`
+	Postcode         int                       `json:"postcode" gorm:"notnull"`
+	Latitude         *float64                  `json:"latitude" gorm:"null;index:idx_enterprises_location,priority:1"`
+	Longitude        *float64                  `json:"longitude" gorm:"null;index:idx_enterprises_location,priority:2"`
+	Description      string                    `json:"description" gorm:"notnull;type:text"`
+	Status           EnterpriseStatus          `json:"status" gorm:"notnull"`
+	StatusReason     string                    `json:"status_reason,omitempty" gorm:"type:text"`
+	Tags             []Tag                     `json:"tags,omitempty" gorm:"many2many:enterprise_tags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	RatingEnterprise []RatingEnterprise        `json:"rating_enterprise,omitempty" gorm:"foreignKey:EnterpriseID;references:ID"`
+	Reviews          []Review                  `json:"reviews,omitempty" gorm:"foreignKey:EnterpriseID;references:ID"`
+	StatusHistories  []EnterpriseStatusHistory `json:"status_histories,omitempty" gorm:"foreignKey:EnterpriseID;references:ID"`
+	Images           []EnterpriseImage         `json:"images,omitempty" gorm:"foreignKey:EnterpriseID;references:ID"`
+	Distance         float64                   `json:"distance,omitempty" gorm:"->;-:migration"`
+	AverageRating    float64                   `json:"-" gorm:"->;-:migration"`
+	SearchRank       int                       `json:"-" gorm:"->;-:migration"`
+	CreatedAt        time.Time                 `json:"created_at"`
+	UpdatedAt        time.Time                 `json:"updated_at"`
 }
 
 type Enterprises []Enterprise
 
+// EnterpriseSort is the order of EnterpriseRepository.FindAll, the zero value keeps the storage order.
+type EnterpriseSort string
+
+const (
+	SortEnterpriseName   EnterpriseSort = "name"
+	SortEnterpriseRating EnterpriseSort = "rating"
+	SortEnterpriseNewest EnterpriseSort = "newest"
+	// SortEnterpriseDistance leaves out enterprises without a location.
+	SortEnterpriseDistance EnterpriseSort = "distance"
+	// SortEnterpriseRelevance keeps the order of EnterpriseFilter.IDs, the ranking of a search.
+	SortEnterpriseRelevance EnterpriseSort = "relevance"
+)
+
+// EnterpriseFilter narrows EnterpriseRepository.FindAll; zero values are not applied.
+type EnterpriseFilter struct {
+	// IDs keeps only these enterprises, the hits of a Searcher.
+	IDs    []string
+	TagIDs []string
+	// AllTags keeps only enterprises having every tag in TagIDs instead of any of them.
+	AllTags   bool
+	Status    *EnterpriseStatus
+	Postcode  int
+	OwnerID   string
+	MinRating float64
+	// CreatedTo is exclusive.
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Sort        EnterpriseSort
+	// Latitude and Longitude are the origin of SortEnterpriseDistance.
+	Latitude  float64
+	Longitude float64
+}
+
 type EnterpriseRepository interface {
 	FindByID(id string) (Enterprise, error)
 	FindByUserID(id string) (Enterprises, error)
-	FindAll(search string, page, length int) (enterprises Enterprises, totalData int, err error)
+	FindAll(filter EnterpriseFilter, page PageRequest) (Enterprises, PageInfo, error)
 	FindByIDs(ids []string) (Enterprises, error)
-	FindByStatusDraft() (Enterprises, error)
-	FindByStatusPublish() (Enterprises, error)
-	UpdateStatusByID(id string, status int) (Enterprise, error)
+	FindByStatus(status EnterpriseStatus) (Enterprises, error)
+	FindStatusHistories(id string) (EnterpriseStatusHistories, error)
+	FindNearby(latitude, longitude, radius float64, tags []string) (Enterprises, error)
+	UpdateStatus(enterprise Enterprise, history EnterpriseStatusHistory) (Enterprise, error)
 	Update(enterprise Enterprise) (Enterprise, error)
 	Save(enterprise Enterprise) (Enterprise, error)
 	Delete(enterprise Enterprise) error
@@ -41,10 +81,15 @@ type EnterpriseRepository interface {
 
 type EnterpriseUsecase interface {
 	CreateNewEnterprise(request request2.CreateEnterpriseRequest, userid string) (Enterprise, error)
-	UpdateStatusEnterprise(id string, status int) (Enterprise, error)
-	UpdateEnterpriseByID(id string, userid string, request request2.CreateEnterpriseRequest) (Enterprise, error)
+	UpdateStatusEnterprise(id string, actor Actor, request request2.UpdateStatusRequest) (Enterprise, error)
+	UpdateEnterpriseByID(id string, actor Actor, request request2.CreateEnterpriseRequest) (Enterprise, error)
 	GetDetailEnterpriseByID(id string) (Enterprise, error)
-	GetListEnterpriseByStatus(status int) (Enterprises, error)
-	GetListAllEnterprise(search string, page, length int) (enterprises Enterprises, totalData int, err error)
-	DeleteEnterpriseByID(id string) error
+	GetDistanceEnterprise(id string, request request2.DistanceRequest) (float64, Enterprise, error)
+	GetListEnterpriseByStatus(status EnterpriseStatus) (Enterprises, error)
+	GetStatusHistories(id string, actor Actor) (EnterpriseStatusHistories, error)
+	GetNearbyEnterprises(request request2.NearbyRequest) (Enterprises, error)
+	GetListAllEnterprise(request request2.ListEnterpriseRequest, page PageRequest) (Enterprises, PageInfo, error)
+	DeleteEnterpriseByID(id string, actor Actor) error
+	// ReindexSearch puts every enterprise in the Searcher again, for a new or stale index.
+	ReindexSearch() error
 }
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	uuid "github.com/satori/go.uuid"
+	"io"
+	"time"
+)
+
+type ImageKind string
+
+const (
+	ImageLogo  ImageKind = "logo"
+	ImageCover ImageKind = "cover"
+	// ImageGallery is the only kind an enterprise has several of.
+	ImageGallery ImageKind = "gallery"
+)
+
+func ParseImageKind(name string) (ImageKind, error) {
+	switch kind := ImageKind(name); kind {
+	case ImageLogo, ImageCover, ImageGallery:
+		return kind, nil
+	}
+	return "", errors.New("image kind must be logo, cover or gallery")
+}
+
+// MaxImageSize is the largest image that can be uploaded, in bytes.
+const MaxImageSize = 5 << 20
+
+type EnterpriseImage struct {
+	ID           uuid.UUID `json:"id" gorm:"PrimaryKey"`
+	EnterpriseID uuid.UUID `json:"enterprise_id" gorm:"notnull;type:varchar;size:256;index"`
+	Kind         ImageKind `json:"kind" gorm:"notnull;size:16"`
+	// Key names the file in the BlobStore, URL is where the store served it at upload.
+	Key         string    `json:"-" gorm:"notnull;size:256"`
+	URL         string    `json:"url" gorm:"notnull;type:text"`
+	ContentType string    `json:"content_type" gorm:"notnull;size:32"`
+	Size        int64     `json:"size" gorm:"notnull"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type EnterpriseImages []EnterpriseImage
+
+type ImageRepository interface {
+	FindByID(id string) (EnterpriseImage, error)
+	FindByEnterpriseID(id string) (EnterpriseImages, error)
+	// FindByUserID finds the images of every enterprise of the user.
+	FindByUserID(id string) (EnterpriseImages, error)
+	Save(image EnterpriseImage) (EnterpriseImage, error)
+	// SaveGallery saves a gallery image unless the enterprise already has max of them. The count
+	// and the insert are one transaction, concurrent uploads cannot go over max.
+	SaveGallery(image EnterpriseImage, max int) (EnterpriseImage, error)
+	// ReplaceImage saves a logo or cover and deletes the images of its kind the enterprise had
+	// in one transaction, it returns the deleted images.
+	ReplaceImage(image EnterpriseImage) (EnterpriseImage, EnterpriseImages, error)
+	Delete(image EnterpriseImage) error
+}
+
+type ImageUsecase interface {
+	// UploadImage stores the image read from file. A new logo or cover replaces the old one.
+	UploadImage(enterpriseID string, actor Actor, kind ImageKind, file io.Reader) (EnterpriseImage, error)
+	DeleteImage(enterpriseID string, imageID string, actor Actor) error
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	uuid "github.com/satori/go.uuid"
+	"strings"
+	"time"
+)
+
+// EnterpriseStatus keeps the stored values of the old 0 = draft, 1 = publish scheme.
+type EnterpriseStatus int
+
+const (
+	EnterpriseDraft EnterpriseStatus = iota
+	EnterprisePublished
+	EnterpriseSubmitted
+	EnterpriseRejected
+	EnterpriseSuspended
+	EnterpriseArchived
+)
+
+var enterpriseStatusNames = map[EnterpriseStatus]string{
+	EnterpriseDraft:     "draft",
+	EnterprisePublished: "published",
+	EnterpriseSubmitted: "submitted",
+	EnterpriseRejected:  "rejected",
+	EnterpriseSuspended: "suspended",
+	EnterpriseArchived:  "archived",
+}
+
+func (s EnterpriseStatus) String() string {
+	return enterpriseStatusNames[s]
+}
+
+// ParseEnterpriseStatus also accepts "publish" and "approved", both meaning published.
+func ParseEnterpriseStatus(name string) (EnterpriseStatus, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "publish", "approved":
+		return EnterprisePublished, nil
+	}
+	for status, statusName := range enterpriseStatusNames {
+		if statusName == name {
+			return status, nil
+		}
+	}
+	return 0, errors.New("status not found")
+}
+
+type EnterpriseStatusHistory struct {
+	ID           uuid.UUID        `json:"id" gorm:"PrimaryKey"`
+	EnterpriseID uuid.UUID        `json:"enterprise_id" gorm:"notnull;type:varchar;size:256;index"`
+	ActorID      uuid.UUID        `json:"actor_id" gorm:"notnull;type:varchar;size:256"`
+	From         EnterpriseStatus `json:"from" gorm:"notnull"`
+	To           EnterpriseStatus `json:"to" gorm:"notnull"`
+	Reason       string           `json:"reason" gorm:"type:text"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+type EnterpriseStatusHistories []EnterpriseStatusHistory
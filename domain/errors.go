@@ -0,0 +1,15 @@
+package domain
+
+// ForbiddenError is returned by usecases when the actor is known but may not act on the resource.
+// Controllers answer it with 403 instead of 400.
+type ForbiddenError struct {
+	Reason string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Reason
+}
+
+func NewForbiddenError(reason string) error {
+	return &ForbiddenError{Reason: reason}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoginAttempt counts failed logins for one key, an account ("user:<id>") or a client IP ("ip:<address>").
+type LoginAttempt struct {
+	Key           string     `json:"key" gorm:"PrimaryKey;size:256"`
+	Failures      int        `json:"failures" gorm:"notnull"`
+	LastFailureAt time.Time  `json:"last_failure_at" gorm:"notnull"`
+	LockedUntil   *time.Time `json:"locked_until" gorm:"index"`
+}
+
+// IsLocked reports whether the key is locked out at now.
+func (a LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// LockedAccount is a user that cannot login until LockedUntil.
+type LockedAccount struct {
+	User        User      `json:"user"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// LoginThrottledError is returned by Login while a key is locked or has to wait before the next try.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry in %d seconds", int(e.RetryAfter.Seconds()+0.5))
+}
+
+// LoginAttemptRepository stores failed login counters. Instances of the API must share the store,
+// otherwise every instance allows its own number of guesses.
+type LoginAttemptRepository interface {
+	Get(key string) (LoginAttempt, error)
+	// RecordFailure adds one failure, counting starts again when the last failure is older than window.
+	// Concurrent failures of a key are each counted.
+	RecordFailure(key string, now time.Time, window time.Duration) (LoginAttempt, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+	FindLocked(now time.Time) ([]LoginAttempt, error)
+}
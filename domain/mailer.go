@@ -0,0 +1,6 @@
+package domain
+
+// Mailer sends a plain text mail.
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
@@ -9,6 +9,8 @@ import (
 	request "github.com/nrmadi02/mini-project/web/request"
 
 	response "github.com/nrmadi02/mini-project/web/response"
+
+	time "time"
 )
 
 // AuthUsecase is an autogenerated mock type for the AuthUsecase type
@@ -16,6 +18,20 @@ type AuthUsecase struct {
 	mock.Mock
 }
 
+// CheckEmailVerified provides a mock function with given fields: id
+func (_m *AuthUsecase) CheckEmailVerified(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CheckIfUserIsAdmin provides a mock function with given fields: id
 func (_m *AuthUsecase) CheckIfUserIsAdmin(id string) (bool, error) {
 	ret := _m.Called(id)
@@ -37,6 +53,108 @@ func (_m *AuthUsecase) CheckIfUserIsAdmin(id string) (bool, error) {
 	return r0, r1
 }
 
+// CheckTwoFactor provides a mock function with given fields: id
+func (_m *AuthUsecase) CheckTwoFactor(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckUserActive provides a mock function with given fields: id
+func (_m *AuthUsecase) CheckUserActive(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableTwoFactor provides a mock function with given fields: id, _a1
+func (_m *AuthUsecase) DisableTwoFactor(id string, _a1 request.DisableTwoFactorRequest) error {
+	ret := _m.Called(id, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, request.DisableTwoFactorRequest) error); ok {
+		r0 = rf(id, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTwoFactor provides a mock function with given fields: id, _a1
+func (_m *AuthUsecase) EnableTwoFactor(id string, _a1 request.TwoFactorCodeRequest) ([]string, error) {
+	ret := _m.Called(id, _a1)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, request.TwoFactorCodeRequest) []string); ok {
+		r0 = rf(id, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, request.TwoFactorCodeRequest) error); ok {
+		r1 = rf(id, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForgotPassword provides a mock function with given fields: _a0
+func (_m *AuthUsecase) ForgotPassword(_a0 request.ForgotPasswordRequest) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(request.ForgotPasswordRequest) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLockedAccounts provides a mock function with given fields:
+func (_m *AuthUsecase) GetLockedAccounts() ([]domain.LockedAccount, error) {
+	ret := _m.Called()
+
+	var r0 []domain.LockedAccount
+	if rf, ok := ret.Get(0).(func() []domain.LockedAccount); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LockedAccount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserDetails provides a mock function with given fields: id
 func (_m *AuthUsecase) GetUserDetails(id string) (domain.User, domain.Favorite, domain.Enterprises, error) {
 	ret := _m.Called(id)
@@ -51,7 +169,6 @@ func (_m *AuthUsecase) GetUserDetails(id string) (domain.User, domain.Favorite,
 	var r1 domain.Favorite
 	if rf, ok := ret.Get(1).(func(string) domain.Favorite); ok {
 		r1 = rf(id)
-
 	} else {
 		r1 = ret.Get(1).(domain.Favorite)
 	}
@@ -75,19 +192,117 @@ func (_m *AuthUsecase) GetUserDetails(id string) (domain.User, domain.Favorite,
 	return r0, r1, r2, r3
 }
 
-// Login provides a mock function with given fields: _a0
-func (_m *AuthUsecase) Login(_a0 request.LoginRequest) (response.SuccessLogin, error) {
+// HasPermission provides a mock function with given fields: id, permission
+func (_m *AuthUsecase) HasPermission(id string, permission string) (bool, error) {
+	ret := _m.Called(id, permission)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(id, permission)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(id, permission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: _a0, actor
+func (_m *AuthUsecase) Login(_a0 request.LoginRequest, actor domain.Actor) (response.SuccessLogin, error) {
+	ret := _m.Called(_a0, actor)
+
+	var r0 response.SuccessLogin
+	if rf, ok := ret.Get(0).(func(request.LoginRequest, domain.Actor) response.SuccessLogin); ok {
+		r0 = rf(_a0, actor)
+	} else {
+		r0 = ret.Get(0).(response.SuccessLogin)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(request.LoginRequest, domain.Actor) error); ok {
+		r1 = rf(_a0, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: userID, jti, expiresAt, _a3
+func (_m *AuthUsecase) Logout(userID string, jti string, expiresAt time.Time, _a3 request.RefreshTokenRequest) error {
+	ret := _m.Called(userID, jti, expiresAt, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time, request.RefreshTokenRequest) error); ok {
+		r0 = rf(userID, jti, expiresAt, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OIDCAuthorizationURL provides a mock function with given fields: provider
+func (_m *AuthUsecase) OIDCAuthorizationURL(provider string) (string, error) {
+	ret := _m.Called(provider)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(provider)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(provider)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OIDCLogin provides a mock function with given fields: provider, _a1, actor
+func (_m *AuthUsecase) OIDCLogin(provider string, _a1 request.OIDCCallbackRequest, actor domain.Actor) (response.SuccessLogin, error) {
+	ret := _m.Called(provider, _a1, actor)
+
+	var r0 response.SuccessLogin
+	if rf, ok := ret.Get(0).(func(string, request.OIDCCallbackRequest, domain.Actor) response.SuccessLogin); ok {
+		r0 = rf(provider, _a1, actor)
+	} else {
+		r0 = ret.Get(0).(response.SuccessLogin)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, request.OIDCCallbackRequest, domain.Actor) error); ok {
+		r1 = rf(provider, _a1, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshToken provides a mock function with given fields: _a0
+func (_m *AuthUsecase) RefreshToken(_a0 request.RefreshTokenRequest) (response.SuccessLogin, error) {
 	ret := _m.Called(_a0)
 
 	var r0 response.SuccessLogin
-	if rf, ok := ret.Get(0).(func(request.LoginRequest) response.SuccessLogin); ok {
+	if rf, ok := ret.Get(0).(func(request.RefreshTokenRequest) response.SuccessLogin); ok {
 		r0 = rf(_a0)
 	} else {
 		r0 = ret.Get(0).(response.SuccessLogin)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(request.LoginRequest) error); ok {
+	if rf, ok := ret.Get(1).(func(request.RefreshTokenRequest) error); ok {
 		r1 = rf(_a0)
 	} else {
 		r1 = ret.Error(1)
@@ -96,6 +311,29 @@ func (_m *AuthUsecase) Login(_a0 request.LoginRequest) (response.SuccessLogin, e
 	return r0, r1
 }
 
+// RegenerateRecoveryCodes provides a mock function with given fields: id, _a1
+func (_m *AuthUsecase) RegenerateRecoveryCodes(id string, _a1 request.TwoFactorCodeRequest) ([]string, error) {
+	ret := _m.Called(id, _a1)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(string, request.TwoFactorCodeRequest) []string); ok {
+		r0 = rf(id, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, request.TwoFactorCodeRequest) error); ok {
+		r1 = rf(id, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Register provides a mock function with given fields: _a0
 func (_m *AuthUsecase) Register(_a0 request.UserCreateRequest) (domain.User, error) {
 	ret := _m.Called(_a0)
@@ -116,3 +354,101 @@ func (_m *AuthUsecase) Register(_a0 request.UserCreateRequest) (domain.User, err
 
 	return r0, r1
 }
+
+// ResetPassword provides a mock function with given fields: _a0
+func (_m *AuthUsecase) ResetPassword(_a0 request.ResetPasswordRequest) error {
+	ret := _m.Called(_a0)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(request.ResetPasswordRequest) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendEmailVerification provides a mock function with given fields: id
+func (_m *AuthUsecase) SendEmailVerification(id string) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetupTwoFactor provides a mock function with given fields: id
+func (_m *AuthUsecase) SetupTwoFactor(id string) (response.TwoFactorSetupResponse, error) {
+	ret := _m.Called(id)
+
+	var r0 response.TwoFactorSetupResponse
+	if rf, ok := ret.Get(0).(func(string) response.TwoFactorSetupResponse); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(response.TwoFactorSetupResponse)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnlockAccount provides a mock function with given fields: id, actor
+func (_m *AuthUsecase) UnlockAccount(id string, actor domain.Actor) error {
+	ret := _m.Called(id, actor)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.Actor) error); ok {
+		r0 = rf(id, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyEmail provides a mock function with given fields: token
+func (_m *AuthUsecase) VerifyEmail(token string) error {
+	ret := _m.Called(token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyTwoFactorLogin provides a mock function with given fields: _a0, actor
+func (_m *AuthUsecase) VerifyTwoFactorLogin(_a0 request.TwoFactorLoginRequest, actor domain.Actor) (response.SuccessLogin, error) {
+	ret := _m.Called(_a0, actor)
+
+	var r0 response.SuccessLogin
+	if rf, ok := ret.Get(0).(func(request.TwoFactorLoginRequest, domain.Actor) response.SuccessLogin); ok {
+		r0 = rf(_a0, actor)
+	} else {
+		r0 = ret.Get(0).(response.SuccessLogin)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(request.TwoFactorLoginRequest, domain.Actor) error); ok {
+		r1 = rf(_a0, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -26,29 +26,29 @@ func (_m *EnterpriseRepository) Delete(enterprise domain.Enterprise) error {
 	return r0
 }
 
-// FindAll provides a mock function with given fields: search, page, length
-func (_m *EnterpriseRepository) FindAll(search string, page int, length int) (domain.Enterprises, int, error) {
-	ret := _m.Called(search, page, length)
+// FindAll provides a mock function with given fields: filter, page
+func (_m *EnterpriseRepository) FindAll(filter domain.EnterpriseFilter, page domain.PageRequest) (domain.Enterprises, domain.PageInfo, error) {
+	ret := _m.Called(filter, page)
 
 	var r0 domain.Enterprises
-	if rf, ok := ret.Get(0).(func(string, int, int) domain.Enterprises); ok {
-		r0 = rf(search, page, length)
+	if rf, ok := ret.Get(0).(func(domain.EnterpriseFilter, domain.PageRequest) domain.Enterprises); ok {
+		r0 = rf(filter, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Enterprises)
 		}
 	}
 
-	var r1 int
-	if rf, ok := ret.Get(1).(func(string, int, int) int); ok {
-		r1 = rf(search, page, length)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(domain.EnterpriseFilter, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(filter, page)
 	} else {
-		r1 = ret.Get(1).(int)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(string, int, int) error); ok {
-		r2 = rf(search, page, length)
+	if rf, ok := ret.Get(2).(func(domain.EnterpriseFilter, domain.PageRequest) error); ok {
+		r2 = rf(filter, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -100,13 +100,13 @@ func (_m *EnterpriseRepository) FindByIDs(ids []string) (domain.Enterprises, err
 	return r0, r1
 }
 
-// FindByStatusDraft provides a mock function with given fields:
-func (_m *EnterpriseRepository) FindByStatusDraft() (domain.Enterprises, error) {
-	ret := _m.Called()
+// FindByStatus provides a mock function with given fields: status
+func (_m *EnterpriseRepository) FindByStatus(status domain.EnterpriseStatus) (domain.Enterprises, error) {
+	ret := _m.Called(status)
 
 	var r0 domain.Enterprises
-	if rf, ok := ret.Get(0).(func() domain.Enterprises); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(domain.EnterpriseStatus) domain.Enterprises); ok {
+		r0 = rf(status)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Enterprises)
@@ -114,8 +114,8 @@ func (_m *EnterpriseRepository) FindByStatusDraft() (domain.Enterprises, error)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(domain.EnterpriseStatus) error); ok {
+		r1 = rf(status)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -123,13 +123,13 @@ func (_m *EnterpriseRepository) FindByStatusDraft() (domain.Enterprises, error)
 	return r0, r1
 }
 
-// FindByStatusPublish provides a mock function with given fields:
-func (_m *EnterpriseRepository) FindByStatusPublish() (domain.Enterprises, error) {
-	ret := _m.Called()
+// FindByUserID provides a mock function with given fields: id
+func (_m *EnterpriseRepository) FindByUserID(id string) (domain.Enterprises, error) {
+	ret := _m.Called(id)
 
 	var r0 domain.Enterprises
-	if rf, ok := ret.Get(0).(func() domain.Enterprises); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(string) domain.Enterprises); ok {
+		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Enterprises)
@@ -137,8 +137,8 @@ func (_m *EnterpriseRepository) FindByStatusPublish() (domain.Enterprises, error
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -146,19 +146,42 @@ func (_m *EnterpriseRepository) FindByStatusPublish() (domain.Enterprises, error
 	return r0, r1
 }
 
-// FindByUserID provides a mock function with given fields: id
-func (_m *EnterpriseRepository) FindByUserID(id string) (domain.Enterprises, error) {
-	ret := _m.Called(id)
+// FindNearby provides a mock function with given fields: latitude, longitude, radius, tags
+func (_m *EnterpriseRepository) FindNearby(latitude float64, longitude float64, radius float64, tags []string) (domain.Enterprises, error) {
+	ret := _m.Called(latitude, longitude, radius, tags)
 
 	var r0 domain.Enterprises
-	if rf, ok := ret.Get(0).(func(string) domain.Enterprises); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(float64, float64, float64, []string) domain.Enterprises); ok {
+		r0 = rf(latitude, longitude, radius, tags)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Enterprises)
 		}
 	}
 
+	var r1 error
+	if rf, ok := ret.Get(1).(func(float64, float64, float64, []string) error); ok {
+		r1 = rf(latitude, longitude, radius, tags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindStatusHistories provides a mock function with given fields: id
+func (_m *EnterpriseRepository) FindStatusHistories(id string) (domain.EnterpriseStatusHistories, error) {
+	ret := _m.Called(id)
+
+	var r0 domain.EnterpriseStatusHistories
+	if rf, ok := ret.Get(0).(func(string) domain.EnterpriseStatusHistories); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.EnterpriseStatusHistories)
+		}
+	}
+
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
 		r1 = rf(id)
@@ -211,20 +234,20 @@ func (_m *EnterpriseRepository) Update(enterprise domain.Enterprise) (domain.Ent
 	return r0, r1
 }
 
-// UpdateStatusByID provides a mock function with given fields: id, status
-func (_m *EnterpriseRepository) UpdateStatusByID(id string, status int) (domain.Enterprise, error) {
-	ret := _m.Called(id, status)
+// UpdateStatus provides a mock function with given fields: enterprise, history
+func (_m *EnterpriseRepository) UpdateStatus(enterprise domain.Enterprise, history domain.EnterpriseStatusHistory) (domain.Enterprise, error) {
+	ret := _m.Called(enterprise, history)
 
 	var r0 domain.Enterprise
-	if rf, ok := ret.Get(0).(func(string, int) domain.Enterprise); ok {
-		r0 = rf(id, status)
+	if rf, ok := ret.Get(0).(func(domain.Enterprise, domain.EnterpriseStatusHistory) domain.Enterprise); ok {
+		r0 = rf(enterprise, history)
 	} else {
 		r0 = ret.Get(0).(domain.Enterprise)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int) error); ok {
-		r1 = rf(id, status)
+	if rf, ok := ret.Get(1).(func(domain.Enterprise, domain.EnterpriseStatusHistory) error); ok {
+		r1 = rf(enterprise, history)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -35,13 +35,13 @@ func (_m *EnterpriseUsecase) CreateNewEnterprise(_a0 request.CreateEnterpriseReq
 	return r0, r1
 }
 
-// DeleteEnterpriseByID provides a mock function with given fields: id
-func (_m *EnterpriseUsecase) DeleteEnterpriseByID(id string) error {
-	ret := _m.Called(id)
+// DeleteEnterpriseByID provides a mock function with given fields: id, actor
+func (_m *EnterpriseUsecase) DeleteEnterpriseByID(id string, actor domain.Actor) error {
+	ret := _m.Called(id, actor)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(string, domain.Actor) error); ok {
+		r0 = rf(id, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,29 +70,57 @@ func (_m *EnterpriseUsecase) GetDetailEnterpriseByID(id string) (domain.Enterpri
 	return r0, r1
 }
 
-// GetListAllEnterprise provides a mock function with given fields: search, page, length
-func (_m *EnterpriseUsecase) GetListAllEnterprise(search string, page int, length int) (domain.Enterprises, int, error) {
-	ret := _m.Called(search, page, length)
+// GetDistanceEnterprise provides a mock function with given fields: id, _a1
+func (_m *EnterpriseUsecase) GetDistanceEnterprise(id string, _a1 request.DistanceRequest) (float64, domain.Enterprise, error) {
+	ret := _m.Called(id, _a1)
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func(string, request.DistanceRequest) float64); ok {
+		r0 = rf(id, _a1)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 domain.Enterprise
+	if rf, ok := ret.Get(1).(func(string, request.DistanceRequest) domain.Enterprise); ok {
+		r1 = rf(id, _a1)
+	} else {
+		r1 = ret.Get(1).(domain.Enterprise)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, request.DistanceRequest) error); ok {
+		r2 = rf(id, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetListAllEnterprise provides a mock function with given fields: _a0, page
+func (_m *EnterpriseUsecase) GetListAllEnterprise(_a0 request.ListEnterpriseRequest, page domain.PageRequest) (domain.Enterprises, domain.PageInfo, error) {
+	ret := _m.Called(_a0, page)
 
 	var r0 domain.Enterprises
-	if rf, ok := ret.Get(0).(func(string, int, int) domain.Enterprises); ok {
-		r0 = rf(search, page, length)
+	if rf, ok := ret.Get(0).(func(request.ListEnterpriseRequest, domain.PageRequest) domain.Enterprises); ok {
+		r0 = rf(_a0, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Enterprises)
 		}
 	}
 
-	var r1 int
-	if rf, ok := ret.Get(1).(func(string, int, int) int); ok {
-		r1 = rf(search, page, length)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(request.ListEnterpriseRequest, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(_a0, page)
 	} else {
-		r1 = ret.Get(1).(int)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(string, int, int) error); ok {
-		r2 = rf(search, page, length)
+	if rf, ok := ret.Get(2).(func(request.ListEnterpriseRequest, domain.PageRequest) error); ok {
+		r2 = rf(_a0, page)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -101,11 +129,11 @@ func (_m *EnterpriseUsecase) GetListAllEnterprise(search string, page int, lengt
 }
 
 // GetListEnterpriseByStatus provides a mock function with given fields: status
-func (_m *EnterpriseUsecase) GetListEnterpriseByStatus(status int) (domain.Enterprises, error) {
+func (_m *EnterpriseUsecase) GetListEnterpriseByStatus(status domain.EnterpriseStatus) (domain.Enterprises, error) {
 	ret := _m.Called(status)
 
 	var r0 domain.Enterprises
-	if rf, ok := ret.Get(0).(func(int) domain.Enterprises); ok {
+	if rf, ok := ret.Get(0).(func(domain.EnterpriseStatus) domain.Enterprises); ok {
 		r0 = rf(status)
 	} else {
 		if ret.Get(0) != nil {
@@ -114,7 +142,7 @@ func (_m *EnterpriseUsecase) GetListEnterpriseByStatus(status int) (domain.Enter
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
+	if rf, ok := ret.Get(1).(func(domain.EnterpriseStatus) error); ok {
 		r1 = rf(status)
 	} else {
 		r1 = ret.Error(1)
@@ -123,20 +151,80 @@ func (_m *EnterpriseUsecase) GetListEnterpriseByStatus(status int) (domain.Enter
 	return r0, r1
 }
 
-// UpdateEnterpriseByID provides a mock function with given fields: id, userid, _a2
-func (_m *EnterpriseUsecase) UpdateEnterpriseByID(id string, userid string, _a2 request.CreateEnterpriseRequest) (domain.Enterprise, error) {
-	ret := _m.Called(id, userid, _a2)
+// GetNearbyEnterprises provides a mock function with given fields: _a0
+func (_m *EnterpriseUsecase) GetNearbyEnterprises(_a0 request.NearbyRequest) (domain.Enterprises, error) {
+	ret := _m.Called(_a0)
+
+	var r0 domain.Enterprises
+	if rf, ok := ret.Get(0).(func(request.NearbyRequest) domain.Enterprises); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.Enterprises)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(request.NearbyRequest) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatusHistories provides a mock function with given fields: id, actor
+func (_m *EnterpriseUsecase) GetStatusHistories(id string, actor domain.Actor) (domain.EnterpriseStatusHistories, error) {
+	ret := _m.Called(id, actor)
+
+	var r0 domain.EnterpriseStatusHistories
+	if rf, ok := ret.Get(0).(func(string, domain.Actor) domain.EnterpriseStatusHistories); ok {
+		r0 = rf(id, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.EnterpriseStatusHistories)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, domain.Actor) error); ok {
+		r1 = rf(id, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReindexSearch provides a mock function with given fields:
+func (_m *EnterpriseUsecase) ReindexSearch() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateEnterpriseByID provides a mock function with given fields: id, actor, _a2
+func (_m *EnterpriseUsecase) UpdateEnterpriseByID(id string, actor domain.Actor, _a2 request.CreateEnterpriseRequest) (domain.Enterprise, error) {
+	ret := _m.Called(id, actor, _a2)
 
 	var r0 domain.Enterprise
-	if rf, ok := ret.Get(0).(func(string, string, request.CreateEnterpriseRequest) domain.Enterprise); ok {
-		r0 = rf(id, userid, _a2)
+	if rf, ok := ret.Get(0).(func(string, domain.Actor, request.CreateEnterpriseRequest) domain.Enterprise); ok {
+		r0 = rf(id, actor, _a2)
 	} else {
 		r0 = ret.Get(0).(domain.Enterprise)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, request.CreateEnterpriseRequest) error); ok {
-		r1 = rf(id, userid, _a2)
+	if rf, ok := ret.Get(1).(func(string, domain.Actor, request.CreateEnterpriseRequest) error); ok {
+		r1 = rf(id, actor, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -144,20 +232,20 @@ func (_m *EnterpriseUsecase) UpdateEnterpriseByID(id string, userid string, _a2
 	return r0, r1
 }
 
-// UpdateStatusEnterprise provides a mock function with given fields: id, status
-func (_m *EnterpriseUsecase) UpdateStatusEnterprise(id string, status int) (domain.Enterprise, error) {
-	ret := _m.Called(id, status)
+// UpdateStatusEnterprise provides a mock function with given fields: id, actor, _a2
+func (_m *EnterpriseUsecase) UpdateStatusEnterprise(id string, actor domain.Actor, _a2 request.UpdateStatusRequest) (domain.Enterprise, error) {
+	ret := _m.Called(id, actor, _a2)
 
 	var r0 domain.Enterprise
-	if rf, ok := ret.Get(0).(func(string, int) domain.Enterprise); ok {
-		r0 = rf(id, status)
+	if rf, ok := ret.Get(0).(func(string, domain.Actor, request.UpdateStatusRequest) domain.Enterprise); ok {
+		r0 = rf(id, actor, _a2)
 	} else {
 		r0 = ret.Get(0).(domain.Enterprise)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int) error); ok {
-		r1 = rf(id, status)
+	if rf, ok := ret.Get(1).(func(string, domain.Actor, request.UpdateStatusRequest) error); ok {
+		r1 = rf(id, actor, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
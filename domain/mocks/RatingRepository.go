@@ -33,6 +33,29 @@ func (_m *RatingRepository) AddRating(rating domain.RatingEnterprise) (domain.Ra
 	return r0, r1
 }
 
+// CountByEnterprise provides a mock function with given fields:
+func (_m *RatingRepository) CountByEnterprise() (map[string]int, error) {
+	ret := _m.Called()
+
+	var r0 map[string]int
+	if rf, ok := ret.Get(0).(func() map[string]int); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteRating provides a mock function with given fields: rating
 func (_m *RatingRepository) DeleteRating(rating domain.RatingEnterprise) error {
 	ret := _m.Called(rating)
@@ -61,6 +84,29 @@ func (_m *RatingRepository) FindAvgByEnterpriseID(id string) float64 {
 	return r0
 }
 
+// FindByUserID provides a mock function with given fields: userid
+func (_m *RatingRepository) FindByUserID(userid string) (domain.RatingEnterprises, error) {
+	ret := _m.Called(userid)
+
+	var r0 domain.RatingEnterprises
+	if rf, ok := ret.Get(0).(func(string) domain.RatingEnterprises); ok {
+		r0 = rf(userid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.RatingEnterprises)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindRatingByIDUserAndEnterprise provides a mock function with given fields: id, userid
 func (_m *RatingRepository) FindRatingByIDUserAndEnterprise(id string, userid string) (domain.RatingEnterprise, error) {
 	ret := _m.Called(id, userid)
@@ -33,13 +33,13 @@ func (_m *RatingUsecase) AddNewRanting(id string, userid string, value int) (dom
 	return r0, r1
 }
 
-// DeleteRating provides a mock function with given fields: id, userid
-func (_m *RatingUsecase) DeleteRating(id string, userid string) error {
-	ret := _m.Called(id, userid)
+// DeleteRating provides a mock function with given fields: id, userid, actor
+func (_m *RatingUsecase) DeleteRating(id string, userid string, actor domain.Actor) error {
+	ret := _m.Called(id, userid, actor)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(id, userid)
+	if rf, ok := ret.Get(0).(func(string, string, domain.Actor) error); ok {
+		r0 = rf(id, userid, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -105,20 +105,20 @@ func (_m *RatingUsecase) GetAverageRatingEnterprise(id string) float64 {
 	return r0
 }
 
-// UpdateRating provides a mock function with given fields: id, userid, value
-func (_m *RatingUsecase) UpdateRating(id string, userid string, value int) (domain.RatingEnterprise, error) {
-	ret := _m.Called(id, userid, value)
+// UpdateRating provides a mock function with given fields: id, userid, actor, value
+func (_m *RatingUsecase) UpdateRating(id string, userid string, actor domain.Actor, value int) (domain.RatingEnterprise, error) {
+	ret := _m.Called(id, userid, actor, value)
 
 	var r0 domain.RatingEnterprise
-	if rf, ok := ret.Get(0).(func(string, string, int) domain.RatingEnterprise); ok {
-		r0 = rf(id, userid, value)
+	if rf, ok := ret.Get(0).(func(string, string, domain.Actor, int) domain.RatingEnterprise); ok {
+		r0 = rf(id, userid, actor, value)
 	} else {
 		r0 = ret.Get(0).(domain.RatingEnterprise)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, int) error); ok {
-		r1 = rf(id, userid, value)
+	if rf, ok := ret.Get(1).(func(string, string, domain.Actor, int) error); ok {
+		r1 = rf(id, userid, actor, value)
 	} else {
 		r1 = ret.Error(1)
 	}
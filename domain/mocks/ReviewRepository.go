@@ -47,27 +47,34 @@ func (_m *ReviewRepository) Delete(review domain.Review) error {
 	return r0
 }
 
-// FindByEnterpriseID provides a mock function with given fields: id
-func (_m *ReviewRepository) FindByEnterpriseID(id string) (domain.Reviews, error) {
-	ret := _m.Called(id)
+// FindByEnterpriseID provides a mock function with given fields: id, page
+func (_m *ReviewRepository) FindByEnterpriseID(id string, page domain.PageRequest) (domain.Reviews, domain.PageInfo, error) {
+	ret := _m.Called(id, page)
 
 	var r0 domain.Reviews
-	if rf, ok := ret.Get(0).(func(string) domain.Reviews); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(string, domain.PageRequest) domain.Reviews); ok {
+		r0 = rf(id, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Reviews)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(string, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(id, page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, domain.PageRequest) error); ok {
+		r2 = rf(id, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // FindByID provides a mock function with given fields: id
@@ -91,6 +98,29 @@ func (_m *ReviewRepository) FindByID(id string) (domain.Review, error) {
 	return r0, r1
 }
 
+// FindByUserID provides a mock function with given fields: userid
+func (_m *ReviewRepository) FindByUserID(userid string) (domain.Reviews, error) {
+	ret := _m.Called(userid)
+
+	var r0 domain.Reviews
+	if rf, ok := ret.Get(0).(func(string) domain.Reviews); ok {
+		r0 = rf(userid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.Reviews)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByUserIDAndEnterpriseID provides a mock function with given fields: enterpriseid, userid
 func (_m *ReviewRepository) FindByUserIDAndEnterpriseID(enterpriseid string, userid string) (domain.Review, error) {
 	ret := _m.Called(enterpriseid, userid)
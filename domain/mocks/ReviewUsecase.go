@@ -12,20 +12,20 @@ type ReviewUsecase struct {
 	mock.Mock
 }
 
-// AddReview provides a mock function with given fields: enterpriseid, userid, value
-func (_m *ReviewUsecase) AddReview(enterpriseid string, userid string, value string) (domain.Review, error) {
-	ret := _m.Called(enterpriseid, userid, value)
+// AddReview provides a mock function with given fields: enterpriseid, userid, actor, value
+func (_m *ReviewUsecase) AddReview(enterpriseid string, userid string, actor domain.Actor, value string) (domain.Review, error) {
+	ret := _m.Called(enterpriseid, userid, actor, value)
 
 	var r0 domain.Review
-	if rf, ok := ret.Get(0).(func(string, string, string) domain.Review); ok {
-		r0 = rf(enterpriseid, userid, value)
+	if rf, ok := ret.Get(0).(func(string, string, domain.Actor, string) domain.Review); ok {
+		r0 = rf(enterpriseid, userid, actor, value)
 	} else {
 		r0 = ret.Get(0).(domain.Review)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(enterpriseid, userid, value)
+	if rf, ok := ret.Get(1).(func(string, string, domain.Actor, string) error); ok {
+		r1 = rf(enterpriseid, userid, actor, value)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -33,13 +33,13 @@ func (_m *ReviewUsecase) AddReview(enterpriseid string, userid string, value str
 	return r0, r1
 }
 
-// DeleteReview provides a mock function with given fields: enterpriseid, userid
-func (_m *ReviewUsecase) DeleteReview(enterpriseid string, userid string) error {
-	ret := _m.Called(enterpriseid, userid)
+// DeleteReview provides a mock function with given fields: enterpriseid, userid, actor
+func (_m *ReviewUsecase) DeleteReview(enterpriseid string, userid string, actor domain.Actor) error {
+	ret := _m.Called(enterpriseid, userid, actor)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(enterpriseid, userid)
+	if rf, ok := ret.Get(0).(func(string, string, domain.Actor) error); ok {
+		r0 = rf(enterpriseid, userid, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -68,27 +68,34 @@ func (_m *ReviewUsecase) GetDetailReviewByID(id string) (domain.Review, error) {
 	return r0, r1
 }
 
-// GetListReviewsByEnterpriseID provides a mock function with given fields: id
-func (_m *ReviewUsecase) GetListReviewsByEnterpriseID(id string) ([]interface{}, error) {
-	ret := _m.Called(id)
+// GetListReviewsByEnterpriseID provides a mock function with given fields: id, page
+func (_m *ReviewUsecase) GetListReviewsByEnterpriseID(id string, page domain.PageRequest) ([]interface{}, domain.PageInfo, error) {
+	ret := _m.Called(id, page)
 
 	var r0 []interface{}
-	if rf, ok := ret.Get(0).(func(string) []interface{}); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(string, domain.PageRequest) []interface{}); ok {
+		r0 = rf(id, page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]interface{})
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(string, domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(id, page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, domain.PageRequest) error); ok {
+		r2 = rf(id, page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetReviewByUserIDAndEnterpriseID provides a mock function with given fields: enterpriseid, userid
@@ -112,20 +119,20 @@ func (_m *ReviewUsecase) GetReviewByUserIDAndEnterpriseID(enterpriseid string, u
 	return r0, r1
 }
 
-// UpdateReview provides a mock function with given fields: enterpriseid, userid, value
-func (_m *ReviewUsecase) UpdateReview(enterpriseid string, userid string, value string) (domain.Review, error) {
-	ret := _m.Called(enterpriseid, userid, value)
+// UpdateReview provides a mock function with given fields: enterpriseid, userid, actor, value
+func (_m *ReviewUsecase) UpdateReview(enterpriseid string, userid string, actor domain.Actor, value string) (domain.Review, error) {
+	ret := _m.Called(enterpriseid, userid, actor, value)
 
 	var r0 domain.Review
-	if rf, ok := ret.Get(0).(func(string, string, string) domain.Review); ok {
-		r0 = rf(enterpriseid, userid, value)
+	if rf, ok := ret.Get(0).(func(string, string, domain.Actor, string) domain.Review); ok {
+		r0 = rf(enterpriseid, userid, actor, value)
 	} else {
 		r0 = ret.Get(0).(domain.Review)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(enterpriseid, userid, value)
+	if rf, ok := ret.Get(1).(func(string, string, domain.Actor, string) error); ok {
+		r1 = rf(enterpriseid, userid, actor, value)
 	} else {
 		r1 = ret.Error(1)
 	}
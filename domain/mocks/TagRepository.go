@@ -26,27 +26,34 @@ func (_m *TagRepository) Delete(tag domain.Tag, id string) error {
 	return r0
 }
 
-// FindAllTags provides a mock function with given fields:
-func (_m *TagRepository) FindAllTags() (domain.Tags, error) {
-	ret := _m.Called()
+// FindAllTags provides a mock function with given fields: page
+func (_m *TagRepository) FindAllTags(page domain.PageRequest) (domain.Tags, domain.PageInfo, error) {
+	ret := _m.Called(page)
 
 	var r0 domain.Tags
-	if rf, ok := ret.Get(0).(func() domain.Tags); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(domain.PageRequest) domain.Tags); ok {
+		r0 = rf(page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Tags)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(domain.PageRequest) error); ok {
+		r2 = rf(page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // FindByID provides a mock function with given fields: id
@@ -35,13 +35,13 @@ func (_m *TagUsecase) CreateNewTag(_a0 request.CreateTagRequest) (domain.Tag, er
 	return r0, r1
 }
 
-// DeleteTag provides a mock function with given fields: id
-func (_m *TagUsecase) DeleteTag(id string) error {
-	ret := _m.Called(id)
+// DeleteTag provides a mock function with given fields: id, actor
+func (_m *TagUsecase) DeleteTag(id string, actor domain.Actor) error {
+	ret := _m.Called(id, actor)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(string, domain.Actor) error); ok {
+		r0 = rf(id, actor)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,25 +49,32 @@ func (_m *TagUsecase) DeleteTag(id string) error {
 	return r0
 }
 
-// GetAllTags provides a mock function with given fields:
-func (_m *TagUsecase) GetAllTags() (domain.Tags, error) {
-	ret := _m.Called()
+// GetAllTags provides a mock function with given fields: page
+func (_m *TagUsecase) GetAllTags(page domain.PageRequest) (domain.Tags, domain.PageInfo, error) {
+	ret := _m.Called(page)
 
 	var r0 domain.Tags
-	if rf, ok := ret.Get(0).(func() domain.Tags); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(domain.PageRequest) domain.Tags); ok {
+		r0 = rf(page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Tags)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(domain.PageRequest) error); ok {
+		r2 = rf(page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
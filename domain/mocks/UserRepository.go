@@ -12,27 +12,76 @@ type UserRepository struct {
 	mock.Mock
 }
 
-// FindAllUsers provides a mock function with given fields:
-func (_m *UserRepository) FindAllUsers() (domain.Users, error) {
-	ret := _m.Called()
+// AddRole provides a mock function with given fields: user, role
+func (_m *UserRepository) AddRole(user domain.User, role domain.Role) error {
+	ret := _m.Called(user, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.User, domain.Role) error); ok {
+		r0 = rf(user, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: user
+func (_m *UserRepository) Delete(user domain.User) error {
+	ret := _m.Called(user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.User) error); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Erase provides a mock function with given fields: user
+func (_m *UserRepository) Erase(user domain.User) error {
+	ret := _m.Called(user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.User) error); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAllUsers provides a mock function with given fields: page
+func (_m *UserRepository) FindAllUsers(page domain.PageRequest) (domain.Users, domain.PageInfo, error) {
+	ret := _m.Called(page)
 
 	var r0 domain.Users
-	if rf, ok := ret.Get(0).(func() domain.Users); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(domain.PageRequest) domain.Users); ok {
+		r0 = rf(page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Users)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(page)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(domain.PageInfo)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(domain.PageRequest) error); ok {
+		r2 = rf(page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // FindUserByEmail provides a mock function with given fields: email
@@ -77,6 +126,62 @@ func (_m *UserRepository) FindUserById(id string) (domain.User, error) {
 	return r0, r1
 }
 
+// FindUserByIdentity provides a mock function with given fields: provider, subject
+func (_m *UserRepository) FindUserByIdentity(provider string, subject string) (domain.User, error) {
+	ret := _m.Called(provider, subject)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string, string) domain.User); ok {
+		r0 = rf(provider, subject)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserByUsername provides a mock function with given fields: username
+func (_m *UserRepository) FindUserByUsername(username string) (domain.User, error) {
+	ret := _m.Called(username)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string) domain.User); ok {
+		r0 = rf(username)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveRole provides a mock function with given fields: user, role
+func (_m *UserRepository) RemoveRole(user domain.User, role domain.Role) error {
+	ret := _m.Called(user, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.User, domain.Role) error); ok {
+		r0 = rf(user, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: user
 func (_m *UserRepository) Save(user domain.User) (domain.User, error) {
 	ret := _m.Called(user)
@@ -97,3 +202,45 @@ func (_m *UserRepository) Save(user domain.User) (domain.User, error) {
 
 	return r0, r1
 }
+
+// SaveIdentity provides a mock function with given fields: identity
+func (_m *UserRepository) SaveIdentity(identity domain.UserIdentity) (domain.UserIdentity, error) {
+	ret := _m.Called(identity)
+
+	var r0 domain.UserIdentity
+	if rf, ok := ret.Get(0).(func(domain.UserIdentity) domain.UserIdentity); ok {
+		r0 = rf(identity)
+	} else {
+		r0 = ret.Get(0).(domain.UserIdentity)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.UserIdentity) error); ok {
+		r1 = rf(identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: user
+func (_m *UserRepository) Update(user domain.User) (domain.User, error) {
+	ret := _m.Called(user)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(domain.User) domain.User); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(domain.User) error); ok {
+		r1 = rf(user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
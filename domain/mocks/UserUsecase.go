@@ -5,6 +5,8 @@ package mocks
 import (
 	domain "github.com/nrmadi02/mini-project/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	request "github.com/nrmadi02/mini-project/web/request"
 )
 
 // UserUsecase is an autogenerated mock type for the UserUsecase type
@@ -12,22 +14,239 @@ type UserUsecase struct {
 	mock.Mock
 }
 
-// GetAllUsers provides a mock function with given fields:
-func (_m *UserUsecase) GetAllUsers() (domain.Users, error) {
-	ret := _m.Called()
+// AssignRole provides a mock function with given fields: id, actor, roleName
+func (_m *UserUsecase) AssignRole(id string, actor domain.Actor, roleName string) (domain.User, error) {
+	ret := _m.Called(id, actor, roleName)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string, domain.Actor, string) domain.User); ok {
+		r0 = rf(id, actor, roleName)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, domain.Actor, string) error); ok {
+		r1 = rf(id, actor, roleName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChangePassword provides a mock function with given fields: id, _a1
+func (_m *UserUsecase) ChangePassword(id string, _a1 request.ChangePasswordRequest) error {
+	ret := _m.Called(id, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, request.ChangePasswordRequest) error); ok {
+		r0 = rf(id, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUser provides a mock function with given fields: id, actor
+func (_m *UserUsecase) DeleteUser(id string, actor domain.Actor) error {
+	ret := _m.Called(id, actor)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.Actor) error); ok {
+		r0 = rf(id, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EraseAccount provides a mock function with given fields: id, actor, _a2
+func (_m *UserUsecase) EraseAccount(id string, actor domain.Actor, _a2 request.DeleteAccountRequest) error {
+	ret := _m.Called(id, actor, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, domain.Actor, request.DeleteAccountRequest) error); ok {
+		r0 = rf(id, actor, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportUserData provides a mock function with given fields: id
+func (_m *UserUsecase) ExportUserData(id string) (domain.UserExport, error) {
+	ret := _m.Called(id)
+
+	var r0 domain.UserExport
+	if rf, ok := ret.Get(0).(func(string) domain.UserExport); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(domain.UserExport)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForcePasswordReset provides a mock function with given fields: id, actor
+func (_m *UserUsecase) ForcePasswordReset(id string, actor domain.Actor) (domain.User, error) {
+	ret := _m.Called(id, actor)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string, domain.Actor) domain.User); ok {
+		r0 = rf(id, actor)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, domain.Actor) error); ok {
+		r1 = rf(id, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllUsers provides a mock function with given fields: page
+func (_m *UserUsecase) GetAllUsers(page domain.PageRequest) (domain.Users, domain.PageInfo, error) {
+	ret := _m.Called(page)
 
 	var r0 domain.Users
-	if rf, ok := ret.Get(0).(func() domain.Users); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(domain.PageRequest) domain.Users); ok {
+		r0 = rf(page)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(domain.Users)
 		}
 	}
 
+	var r1 domain.PageInfo
+	if rf, ok := ret.Get(1).(func(domain.PageRequest) domain.PageInfo); ok {
+		r1 = rf(page)
+	} else {
+		r1 = ret.Get(1).(domain.PageInfo)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(domain.PageRequest) error); ok {
+		r2 = rf(page)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetUserByID provides a mock function with given fields: id
+func (_m *UserUsecase) GetUserByID(id string) (domain.User, error) {
+	ret := _m.Called(id)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string) domain.User); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReactivateUser provides a mock function with given fields: id, actor
+func (_m *UserUsecase) ReactivateUser(id string, actor domain.Actor) (domain.User, error) {
+	ret := _m.Called(id, actor)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string, domain.Actor) domain.User); ok {
+		r0 = rf(id, actor)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, domain.Actor) error); ok {
+		r1 = rf(id, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRole provides a mock function with given fields: id, actor, roleName
+func (_m *UserUsecase) RevokeRole(id string, actor domain.Actor, roleName string) (domain.User, error) {
+	ret := _m.Called(id, actor, roleName)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string, domain.Actor, string) domain.User); ok {
+		r0 = rf(id, actor, roleName)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, domain.Actor, string) error); ok {
+		r1 = rf(id, actor, roleName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuspendUser provides a mock function with given fields: id, actor, _a2
+func (_m *UserUsecase) SuspendUser(id string, actor domain.Actor, _a2 request.SuspendUserRequest) (domain.User, error) {
+	ret := _m.Called(id, actor, _a2)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string, domain.Actor, request.SuspendUserRequest) domain.User); ok {
+		r0 = rf(id, actor, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, domain.Actor, request.SuspendUserRequest) error); ok {
+		r1 = rf(id, actor, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: id, _a1
+func (_m *UserUsecase) UpdateProfile(id string, _a1 request.UserUpdateRequest) (domain.User, error) {
+	ret := _m.Called(id, _a1)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(string, request.UserUpdateRequest) domain.User); ok {
+		r0 = rf(id, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(string, request.UserUpdateRequest) error); ok {
+		r1 = rf(id, _a1)
 	} else {
 		r1 = ret.Error(1)
 	}
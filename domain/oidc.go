@@ -0,0 +1,53 @@
+package domain
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// OIDCClaims is the identity an OpenID Connect provider vouches for in its ID token.
+type OIDCClaims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// OIDCProvider is an OpenID Connect provider like Google or Keycloak, used with the
+// authorization code flow and PKCE.
+type OIDCProvider interface {
+	Name() string
+	// AuthCodeURL is where the browser is sent to sign in, the provider redirects back with a code and state.
+	AuthCodeURL(state string, nonce string, codeVerifier string) (string, error)
+	// Exchange trades the code for an ID token and returns its verified claims.
+	Exchange(code string, codeVerifier string, nonce string) (OIDCClaims, error)
+}
+
+// OIDCState remembers a login started at a provider until it comes back. It is single use.
+type OIDCState struct {
+	ID           uuid.UUID  `json:"id" gorm:"PrimaryKey"`
+	Provider     string     `json:"provider" gorm:"notnull;size:50"`
+	StateHash    string     `json:"-" gorm:"notnull;size:64;unique"`
+	Nonce        string     `json:"-" gorm:"notnull;size:64"`
+	CodeVerifier string     `json:"-" gorm:"notnull;size:64"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"notnull"`
+	UsedAt       *time.Time `json:"used_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TableName keeps the initialism together, gorm would name the table o_id_c_states.
+func (OIDCState) TableName() string {
+	return "oidc_states"
+}
+
+// UserIdentity links a user to their account at a provider, the subject never changes
+// even when the email at the provider does.
+type UserIdentity struct {
+	ID        uuid.UUID `json:"id" gorm:"PrimaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"notnull;type:varchar;size:256;index"`
+	Provider  string    `json:"provider" gorm:"notnull;size:50;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string    `json:"subject" gorm:"notnull;size:255;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Cursor points at a row of a keyset paginated listing by its sort key and id.
+type Cursor struct {
+	Key interface{}
+	ID  string
+	// Backward asks for the page before the row instead of the page after it.
+	Backward bool
+}
+
+type cursorToken struct {
+	Key      interface{} `json:"k,omitempty"`
+	Time     *time.Time  `json:"t,omitempty"`
+	ID       string      `json:"i"`
+	Backward bool        `json:"b,omitempty"`
+}
+
+// Encode returns the opaque form of the cursor given to clients.
+func (c Cursor) Encode() string {
+	token := cursorToken{Key: c.Key, ID: c.ID, Backward: c.Backward}
+	if key, ok := c.Key.(time.Time); ok {
+		token.Key, token.Time = nil, &key
+	}
+	raw, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParseCursor reads a cursor made by Cursor.Encode, time keys come back as time.Time.
+func ParseCursor(value string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, errors.New("cursor invalid")
+	}
+	var token cursorToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.ID == "" {
+		return Cursor{}, errors.New("cursor invalid")
+	}
+	cursor := Cursor{Key: token.Key, ID: token.ID, Backward: token.Backward}
+	if token.Time != nil {
+		cursor.Key = *token.Time
+	}
+	return cursor, nil
+}
+
+// PageRequest asks for one page of a keyset paginated listing.
+type PageRequest struct {
+	// Cursor is nil for the first page.
+	Cursor *Cursor
+	Length int
+	// WithTotal also counts every row matching the listing, which costs a second query.
+	WithTotal bool
+}
+
+// PageInfo tells where the pages around the returned one start.
+type PageInfo struct {
+	Next *Cursor
+	Prev *Cursor
+	// Total is only set when PageRequest.WithTotal is.
+	Total *int
+}
@@ -13,7 +13,10 @@ type RatingEnterprises []RatingEnterprise
 
 type RatingRepository interface {
 	GetAllRatingByEnterpriseID(id string) (RatingEnterprises, error)
+	FindByUserID(userid string) (RatingEnterprises, error)
 	FindAvgByEnterpriseID(id string) float64
+	// CountByEnterprise returns the number of ratings of every rated enterprise by its id.
+	CountByEnterprise() (map[string]int, error)
 	FindRatingByIDUserAndEnterprise(id string, userid string) (RatingEnterprise, error)
 	UpdateRating(id string, userid string, value int) (RatingEnterprise, error)
 	DeleteRating(rating RatingEnterprise) error
@@ -24,7 +27,7 @@ type RatingUsecase interface {
 	GetAllRatingByEnterpriseID(id string) (RatingEnterprises, error)
 	GetAverageRatingEnterprise(id string) float64
 	FindRating(id, userid string) (RatingEnterprise, error)
-	UpdateRating(id, userid string, value int) (RatingEnterprise, error)
-	DeleteRating(id, userid string) error
+	UpdateRating(id, userid string, actor Actor, value int) (RatingEnterprise, error)
+	DeleteRating(id, userid string, actor Actor) error
 	AddNewRanting(id, userid string, value int) (RatingEnterprise, error)
 }
@@ -18,7 +18,8 @@ type Reviews []Review
 
 type ReviewRepository interface {
 	FindByUserIDAndEnterpriseID(enterpriseid, userid string) (Review, error)
-	FindByEnterpriseID(id string) (Reviews, error)
+	FindByEnterpriseID(id string, page PageRequest) (Reviews, PageInfo, error)
+	FindByUserID(userid string) (Reviews, error)
 	FindByID(id string) (Review, error)
 	Update(enterpriseid, userid string, value string) (Review, error)
 	Delete(review Review) error
@@ -26,10 +27,10 @@ type ReviewRepository interface {
 }
 
 type ReviewUsecase interface {
-	AddReview(enterpriseid, userid string, value string) (Review, error)
-	UpdateReview(enterpriseid, userid string, value string) (Review, error)
-	DeleteReview(enterpriseid, userid string) error
-	GetListReviewsByEnterpriseID(id string) ([]interface{}, error)
+	AddReview(enterpriseid, userid string, actor Actor, value string) (Review, error)
+	UpdateReview(enterpriseid, userid string, actor Actor, value string) (Review, error)
+	DeleteReview(enterpriseid, userid string, actor Actor) error
+	GetListReviewsByEnterpriseID(id string, page PageRequest) ([]interface{}, PageInfo, error)
 	GetReviewByUserIDAndEnterpriseID(enterpriseid, userid string) (Review, error)
 	GetDetailReviewByID(id string) (Review, error)
 }
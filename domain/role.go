@@ -1,6 +1,12 @@
 package domain
 
 type Role struct {
+	ID          int          `json:"id" gorm:"PrimaryKey"`
+	Name        string       `json:"name" gorm:"unique;notnull"`
+	Permissions []Permission `json:"permissions,omitempty" gorm:"many2many:role_permissions;"`
+}
+
+type Permission struct {
 	ID   int    `json:"id" gorm:"PrimaryKey"`
 	Name string `json:"name" gorm:"unique;notnull"`
 }
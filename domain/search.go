@@ -0,0 +1,19 @@
+package domain
+
+// SearchHit is an enterprise found by a Searcher, a higher score is more relevant.
+type SearchHit struct {
+	ID    string
+	Score float64
+}
+
+// Searcher finds enterprises by the words of their name, description, address and tag names.
+// Index, Remove and Reindex keep it in sync with the enterprises, searchers reading the database
+// directly may ignore them.
+type Searcher interface {
+	Index(enterprise Enterprise) error
+	Remove(id string) error
+	// Reindex replaces everything indexed with enterprises.
+	Reindex(enterprises Enterprises) error
+	// Search returns at most limit hits, most relevant first.
+	Search(query string, limit int) ([]SearchHit, error)
+}
@@ -0,0 +1,25 @@
+package domain
+
+type SuggestionType string
+
+const (
+	SuggestEnterprise SuggestionType = "enterprise"
+	SuggestTag        SuggestionType = "tag"
+	SuggestLocality   SuggestionType = "locality"
+)
+
+// Suggestion completes what a user is typing in the search box.
+type Suggestion struct {
+	Type SuggestionType
+	// ID is the enterprise or tag id, empty for a locality.
+	ID   string
+	Text string
+	// Popularity is the number of ratings of an enterprise, or the number of published
+	// enterprises with a tag or in a locality.
+	Popularity int
+}
+
+type SuggestUsecase interface {
+	// Suggest returns at most limit suggestions having a word that starts with prefix, most popular first.
+	Suggest(prefix string, limit int) ([]Suggestion, error)
+}
@@ -16,13 +16,13 @@ type TagRepository interface {
 	FindByName(name string) (Tag, error)
 	FindByID(id string) (Tag, error)
 	FindByIDs(ids []string) (Tags, error)
-	FindAllTags() (Tags, error)
+	FindAllTags(page PageRequest) (Tags, PageInfo, error)
 	Delete(tag Tag, id string) error
 	Save(tag Tag) (Tag, error)
 }
 
 type TagUsecase interface {
-	GetAllTags() (Tags, error)
-	DeleteTag(id string) error
+	GetAllTags(page PageRequest) (Tags, PageInfo, error)
+	DeleteTag(id string, actor Actor) error
 	CreateNewTag(request request.CreateTagRequest) (Tag, error)
 }
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// RefreshToken stores only the hash of the token handed to the client.
+// Every rotation keeps the FamilyID of the login it came from, so a reused token can end the whole chain.
+type RefreshToken struct {
+	ID           uuid.UUID  `json:"id" gorm:"PrimaryKey"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"notnull;type:varchar;size:256;index"`
+	FamilyID     uuid.UUID  `json:"family_id" gorm:"notnull;type:varchar;size:256;index"`
+	TokenHash    string     `json:"-" gorm:"notnull;size:64;unique"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"notnull"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	ReplacedByID *uuid.UUID `json:"replaced_by_id" gorm:"type:varchar;size:256"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// RevokedToken blocks an access token by its jti until the token would have expired anyway.
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"PrimaryKey;size:64"`
+	UserID    uuid.UUID `json:"user_id" gorm:"notnull;type:varchar;size:256"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"notnull;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PasswordResetToken is single use: UsedAt is set when the password is reset with it.
+type PasswordResetToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"PrimaryKey"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"notnull;type:varchar;size:256;index"`
+	TokenHash string     `json:"-" gorm:"notnull;size:64;unique"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"notnull"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// EmailVerificationToken confirms one address of the user. It stops working when the user
+// changes to another email before opening it.
+type EmailVerificationToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"PrimaryKey"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"notnull;type:varchar;size:256;index"`
+	Email     string     `json:"email" gorm:"notnull"`
+	TokenHash string     `json:"-" gorm:"notnull;size:64;unique"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"notnull"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TwoFactorChallenge is handed out by Login instead of tokens when the user has two-factor
+// authentication. It is single use and is traded with a code for the real tokens.
+type TwoFactorChallenge struct {
+	ID        uuid.UUID  `json:"id" gorm:"PrimaryKey"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"notnull;type:varchar;size:256;index"`
+	TokenHash string     `json:"-" gorm:"notnull;size:64;unique"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"notnull"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RecoveryCode can be used once instead of a TOTP code when the authenticator is lost.
+type RecoveryCode struct {
+	ID        uuid.UUID  `json:"id" gorm:"PrimaryKey"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"notnull;type:varchar;size:256;index"`
+	CodeHash  string     `json:"-" gorm:"notnull;size:64"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type TokenRepository interface {
+	SaveRefreshToken(token RefreshToken) (RefreshToken, error)
+	FindRefreshTokenByHash(hash string) (RefreshToken, error)
+	RotateRefreshToken(current RefreshToken, next RefreshToken) (RefreshToken, error)
+	RevokeRefreshTokenFamily(familyID string) error
+	RevokeUserRefreshTokens(userID string) error
+	RevokeAccessToken(token RevokedToken) error
+	IsAccessTokenRevoked(jti string) (bool, error)
+	SavePasswordResetToken(token PasswordResetToken) (PasswordResetToken, error)
+	FindPasswordResetTokenByHash(hash string) (PasswordResetToken, error)
+	UsePasswordResetToken(token PasswordResetToken) error
+	SaveEmailVerificationToken(token EmailVerificationToken) (EmailVerificationToken, error)
+	FindEmailVerificationTokenByHash(hash string) (EmailVerificationToken, error)
+	UseEmailVerificationToken(token EmailVerificationToken) error
+	SaveTwoFactorChallenge(token TwoFactorChallenge) (TwoFactorChallenge, error)
+	FindTwoFactorChallengeByHash(hash string) (TwoFactorChallenge, error)
+	UseTwoFactorChallenge(token TwoFactorChallenge) error
+	ReplaceRecoveryCodes(userID string, codes []RecoveryCode) error
+	UseRecoveryCode(userID string, hash string) error
+	SaveOIDCState(state OIDCState) (OIDCState, error)
+	FindOIDCStateByHash(hash string) (OIDCState, error)
+	UseOIDCState(state OIDCState) error
+}
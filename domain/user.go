@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	request2 "github.com/nrmadi02/mini-project/web/request"
 	"github.com/nrmadi02/mini-project/web/response"
 	uuid "github.com/satori/go.uuid"
@@ -8,36 +9,163 @@ import (
 )
 
 type User struct {
-	ID               uuid.UUID          `json:"id" gorm:"PrimaryKey"`
-	Fullname         string             `json:"fullname" gorm:"notnull"`
-	Email            string             `json:"email" gorm:"notnull"`
-	Username         string             `json:"username" gorm:"unique;notnull"`
-	Password         string             `json:"password" gorm:"notnull"`
-	Roles            []Role             `json:"roles" gorm:"many2many:user_roles;"`
-	Enterprises      []Enterprise       `json:"enterprises,omitempty" gorm:"foreignKey:UserID;references:ID"`
-	RatingEnterprise []RatingEnterprise `json:"rating_enterprise,omitempty" gorm:"foreignKey:UserID;references:ID"`
-	Reviews          []Review           `json:"reviews,omitempty" gorm:"foreignKey:UserID;references:ID"`
-	Favorite         Favorite           `json:"favorite,omitempty" gorm:"foreignKey:UserID;references:ID"`
-	CreatedAt        time.Time          `json:"created_at"`
-	UpdatedAt        time.Time          `json:"updated_at"`
+	ID                    uuid.UUID          `json:"id" gorm:"PrimaryKey"`
+	Fullname              string             `json:"fullname" gorm:"notnull"`
+	Email                 string             `json:"email" gorm:"notnull"`
+	Username              string             `json:"username" gorm:"unique;notnull"`
+	Password              string             `json:"password" gorm:"notnull"`
+	Roles                 []Role             `json:"roles" gorm:"many2many:user_roles;"`
+	Enterprises           []Enterprise       `json:"enterprises,omitempty" gorm:"foreignKey:UserID;references:ID"`
+	RatingEnterprise      []RatingEnterprise `json:"rating_enterprise,omitempty" gorm:"foreignKey:UserID;references:ID"`
+	Reviews               []Review           `json:"reviews,omitempty" gorm:"foreignKey:UserID;references:ID"`
+	Favorite              Favorite           `json:"favorite,omitempty" gorm:"foreignKey:UserID;references:ID"`
+	EmailVerifiedAt       *time.Time         `json:"email_verified_at" gorm:"null"`
+	SuspendedAt           *time.Time         `json:"suspended_at,omitempty" gorm:"null"`
+	SuspendedReason       string             `json:"suspended_reason,omitempty" gorm:"type:text"`
+	PasswordResetRequired bool               `json:"password_reset_required" gorm:"notnull;default:false"`
+	TOTPSecret            string             `json:"-" gorm:"column:totp_secret;size:64"`
+	TOTPEnabledAt         *time.Time         `json:"totp_enabled_at" gorm:"column:totp_enabled_at;null"`
+	TOTPLastStep          int64              `json:"-" gorm:"column:totp_last_step;notnull;default:0"`
+	ErasedAt              *time.Time         `json:"erased_at,omitempty" gorm:"null"`
+	CreatedAt             time.Time          `json:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at"`
 }
 
 type Users []User
 
+// CheckActive returns an error when an admin has suspended the user or the user deleted the account.
+func (u User) CheckActive() error {
+	if u.ErasedAt != nil {
+		return errors.New("account deleted")
+	}
+	if u.SuspendedAt != nil {
+		return errors.New("account suspended")
+	}
+	return nil
+}
+
+// CheckEmailVerified returns an error until the user has opened the link from the verification mail.
+func (u User) CheckEmailVerified() error {
+	if u.EmailVerifiedAt == nil {
+		return errors.New("email not verified")
+	}
+	return nil
+}
+
+// TwoFactorEnabled reports whether login needs a TOTP code after the password. A secret without
+// TOTPEnabledAt is an enrollment that was never confirmed.
+func (u User) TwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil && u.TOTPSecret != ""
+}
+
+// HasRole reports whether the user has one of the roles.
+func (u User) HasRole(names ...string) bool {
+	for _, role := range u.Roles {
+		for _, name := range names {
+			if role.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether one of the user's roles grants the permission.
+// Roles.Permissions must be preloaded.
+func (u User) HasPermission(permission string) bool {
+	for _, role := range u.Roles {
+		for _, p := range role.Permissions {
+			if p.Name == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UserExport is everything stored about a user, for the user's own copy of their data.
+type UserExport struct {
+	User        User
+	Favorite    Favorite
+	Enterprises Enterprises
+	Ratings     RatingEnterprises
+	Reviews     Reviews
+	ExportedAt  time.Time
+}
+
+// Anonymize removes the personal fields of the user, the row stays so ratings and reviews
+// keep their author without saying who it was.
+func (u User) Anonymize(now time.Time) User {
+	u.Fullname = "Deleted user"
+	u.Email = ""
+	u.Username = "deleted-" + u.ID.String()
+	u.Password = ""
+	u.Roles = nil
+	u.EmailVerifiedAt = nil
+	u.SuspendedAt = nil
+	u.SuspendedReason = ""
+	u.PasswordResetRequired = false
+	u.TOTPSecret = ""
+	u.TOTPEnabledAt = nil
+	u.TOTPLastStep = 0
+	u.ErasedAt = &now
+	return u
+}
+
 type UserRepository interface {
 	FindUserByEmail(email string) (User, error)
 	FindUserById(id string) (User, error)
+	FindUserByUsername(username string) (User, error)
 	Save(user User) (User, error)
-	FindAllUsers() (Users, error)
+	FindAllUsers(page PageRequest) (Users, PageInfo, error)
+	Update(user User) (User, error)
+	AddRole(user User, role Role) error
+	RemoveRole(user User, role Role) error
+	Delete(user User) error
+	// Erase deletes the user's enterprises, favorites, sign-in data and tokens and saves the
+	// anonymized user, ratings and reviews are kept.
+	Erase(user User) error
+	FindUserByIdentity(provider string, subject string) (User, error)
+	SaveIdentity(identity UserIdentity) (UserIdentity, error)
 }
 
 type UserUsecase interface {
-	GetAllUsers() (Users, error)
+	GetAllUsers(page PageRequest) (Users, PageInfo, error)
+	GetUserByID(id string) (User, error)
+	AssignRole(id string, actor Actor, roleName string) (User, error)
+	RevokeRole(id string, actor Actor, roleName string) (User, error)
+	SuspendUser(id string, actor Actor, request request2.SuspendUserRequest) (User, error)
+	ReactivateUser(id string, actor Actor) (User, error)
+	ForcePasswordReset(id string, actor Actor) (User, error)
+	DeleteUser(id string, actor Actor) error
+	UpdateProfile(id string, request request2.UserUpdateRequest) (User, error)
+	ChangePassword(id string, request request2.ChangePasswordRequest) error
+	ExportUserData(id string) (UserExport, error)
+	EraseAccount(id string, actor Actor, request request2.DeleteAccountRequest) error
 }
 
 type AuthUsecase interface {
-	Login(request request2.LoginRequest) (response.SuccessLogin, error)
+	Login(request request2.LoginRequest, actor Actor) (response.SuccessLogin, error)
+	RefreshToken(request request2.RefreshTokenRequest) (response.SuccessLogin, error)
+	Logout(userID string, jti string, expiresAt time.Time, request request2.RefreshTokenRequest) error
 	Register(request request2.UserCreateRequest) (User, error)
 	GetUserDetails(id string) (User, Favorite, Enterprises, error)
 	CheckIfUserIsAdmin(id string) (bool, error)
+	HasPermission(id string, permission string) (bool, error)
+	CheckUserActive(id string) error
+	ForgotPassword(request request2.ForgotPasswordRequest) error
+	ResetPassword(request request2.ResetPasswordRequest) error
+	SendEmailVerification(id string) error
+	VerifyEmail(token string) error
+	CheckEmailVerified(id string) error
+	GetLockedAccounts() ([]LockedAccount, error)
+	UnlockAccount(id string, actor Actor) error
+	VerifyTwoFactorLogin(request request2.TwoFactorLoginRequest, actor Actor) (response.SuccessLogin, error)
+	SetupTwoFactor(id string) (response.TwoFactorSetupResponse, error)
+	EnableTwoFactor(id string, request request2.TwoFactorCodeRequest) ([]string, error)
+	DisableTwoFactor(id string, request request2.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(id string, request request2.TwoFactorCodeRequest) ([]string, error)
+	CheckTwoFactor(id string) error
+	OIDCAuthorizationURL(provider string) (string, error)
+	OIDCLogin(provider string, request request2.OIDCCallbackRequest, actor Actor) (response.SuccessLogin, error)
 }
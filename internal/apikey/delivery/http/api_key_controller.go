@@ -0,0 +1,200 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/web/request"
+	"github.com/nrmadi02/mini-project/web/response"
+	"net/http"
+)
+
+type APIKeyController interface {
+	CreateAPIKey(c echo.Context) error
+	GetAPIKeys(c echo.Context) error
+	RevokeAPIKey(c echo.Context) error
+	CreateUserAPIKey(c echo.Context) error
+	GetUserAPIKeys(c echo.Context) error
+	RevokeUserAPIKey(c echo.Context) error
+}
+
+type apiKeyController struct {
+	APIKeyUsecase domain.APIKeyUsecase
+}
+
+func NewAPIKeyController(aku domain.APIKeyUsecase) APIKeyController {
+	return apiKeyController{
+		APIKeyUsecase: aku,
+	}
+}
+
+func apiKeyResponse(key domain.APIKey) response.APIKeyResponse {
+	return response.APIKeyResponse{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.ScopeList(),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Create an API key for a machine client of the logged in user. Scopes are read, write and permission names of the user's roles. The key is only returned once
+// @Tags API Key
+// @accept json
+// @Produce json
+// @Router /user/api-keys [post]
+// @Param data body request.APIKeyCreateRequest true "name and scopes are required"
+// @Success 201 {object} response.JSONSuccessResult{data=response.APIKeyCreatedResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a apiKeyController) CreateAPIKey(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	return a.createAPIKey(c, claims.UserID())
+}
+
+// GetAPIKeys godoc
+// @Summary Get API keys
+// @Description Get the API keys of the logged in user, revoked keys included
+// @Tags API Key
+// @accept json
+// @Produce json
+// @Router /user/api-keys [get]
+// @Success 200 {object} response.JSONSuccessResult{data=[]response.APIKeyResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a apiKeyController) GetAPIKeys(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	return a.getAPIKeys(c, claims.UserID())
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Revoke an API key of the logged in user, it stops working right away
+// @Tags API Key
+// @accept json
+// @Produce json
+// @Router /user/api-keys/{id} [delete]
+// @Param id path string true "api key id"
+// @Success 200 {object} response.JSONSuccessResult{}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a apiKeyController) RevokeAPIKey(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	return a.revokeAPIKey(c, claims.UserID(), c.Param("id"))
+}
+
+// CreateUserAPIKey godoc
+// @Summary Create API key for user
+// @Description Create an API key for an organisation account, requires permission user:manage. The key is only returned once
+// @Tags API Key
+// @accept json
+// @Produce json
+// @Router /users/{id}/api-keys [post]
+// @Param id path string true "user id"
+// @Param data body request.APIKeyCreateRequest true "name and scopes are required"
+// @Success 201 {object} response.JSONSuccessResult{data=response.APIKeyCreatedResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a apiKeyController) CreateUserAPIKey(c echo.Context) error {
+	return a.createAPIKey(c, c.Param("id"))
+}
+
+// GetUserAPIKeys godoc
+// @Summary Get API keys of user
+// @Description Get the API keys of a user, requires permission user:manage
+// @Tags API Key
+// @accept json
+// @Produce json
+// @Router /users/{id}/api-keys [get]
+// @Param id path string true "user id"
+// @Success 200 {object} response.JSONSuccessResult{data=[]response.APIKeyResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a apiKeyController) GetUserAPIKeys(c echo.Context) error {
+	return a.getAPIKeys(c, c.Param("id"))
+}
+
+// RevokeUserAPIKey godoc
+// @Summary Revoke API key of user
+// @Description Revoke an API key of a user, requires permission user:manage
+// @Tags API Key
+// @accept json
+// @Produce json
+// @Router /users/{id}/api-keys/{keyId} [delete]
+// @Param id path string true "user id"
+// @Param keyId path string true "api key id"
+// @Success 200 {object} response.JSONSuccessResult{}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a apiKeyController) RevokeUserAPIKey(c echo.Context) error {
+	return a.revokeAPIKey(c, c.Param("id"), c.Param("keyId"))
+}
+
+func (a apiKeyController) createAPIKey(c echo.Context, userID string) error {
+	var req request.APIKeyCreateRequest
+	if err := c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if val, err := request.ValidateAPIKeyCreate(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	key, plain, err := a.APIKeyUsecase.CreateAPIKey(userID, req)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusCreated, true, "success create api key", response.APIKeyCreatedResponse{
+		APIKeyResponse: apiKeyResponse(key),
+		Key:            plain,
+	})
+}
+
+func (a apiKeyController) getAPIKeys(c echo.Context, userID string) error {
+	keys, err := a.APIKeyUsecase.GetAPIKeys(userID)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	res := make([]response.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		res = append(res, apiKeyResponse(key))
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success get api keys", res)
+}
+
+func (a apiKeyController) revokeAPIKey(c echo.Context, userID string, id string) error {
+	if err := a.APIKeyUsecase.RevokeAPIKey(userID, id); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success revoke api key", nil)
+}
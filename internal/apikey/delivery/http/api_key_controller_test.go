@@ -0,0 +1,262 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	http2 "github.com/nrmadi02/mini-project/internal/apikey/delivery/http"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	mid "github.com/nrmadi02/mini-project/internal/user/delivery/http/middleware"
+	"github.com/nrmadi02/mini-project/web/request"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var base_path = "/api/v1"
+
+var dummyUser = domain.Users{
+	domain.User{
+		ID:       uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf891"),
+		Fullname: "user1",
+		Email:    "satu@email.com",
+		Username: "usr1",
+		Roles: []domain.Role{
+			domain.Role{
+				Name: "ROLE_ADMIN", ID: 1,
+			},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+	domain.User{
+		ID:       uuid.FromStringOrNil("0cf712fc-e631-40c7-8572-54772e698edf"),
+		Fullname: "user3",
+		Email:    "dua@email.com",
+		Username: "usr2",
+		Roles: []domain.Role{
+			domain.Role{
+				Name: "ROLE_CLIENT", ID: 1,
+			},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+}
+
+var dummyAPIKey = domain.APIKey{
+	ID:        uuid.FromStringOrNil("5b0a1c3e-7d2f-4e8a-9c61-2f3b4d5e6a70"),
+	UserID:    dummyUser[1].ID,
+	Name:      "kiosk",
+	Prefix:    "umkm_abcdef",
+	Scopes:    "read",
+	CreatedAt: time.Now(),
+}
+
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+
+func createToken() string {
+	jwtSetToken := helper.NewGoJWT(keySet, "test", "test")
+	token := jwtSetToken.CreateTokenJWT(&dummyUser[0])
+	return token
+}
+
+func middlewareToken(handlerFunc echo.HandlerFunc, c echo.Context) error {
+	err := middleware.JWTWithConfig(middleware.JWTConfig{
+		KeyFunc: keySet.Keyfunc,
+		Claims:  &helper.JWTClaims{},
+	})(handlerFunc)(c)
+	return err
+}
+
+func parseResponse(rec *httptest.ResponseRecorder) map[string]interface{} {
+	var responseBody map[string]interface{}
+	resBody := rec.Body.String()
+	_ = json.Unmarshal([]byte(resBody), &responseBody)
+	return responseBody
+}
+
+func makeRequestHttp(request string, method string, path string, isToken bool, isBind bool) (req *http.Request, rec *httptest.ResponseRecorder) {
+	req, _ = http.NewRequest(method, base_path+path, strings.NewReader(request))
+	if isBind {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	if isToken {
+		req.Header.Add(echo.HeaderAuthorization, middleware.DefaultJWTConfig.AuthScheme+" "+createToken())
+	}
+	rec = httptest.NewRecorder()
+	return req, rec
+}
+
+func TestAPIKeyController_CreateAPIKey(t *testing.T) {
+	mockAPIKeyUsecase := new(mocks.APIKeyUsecase)
+	apiKeyController := http2.NewAPIKeyController(mockAPIKeyUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		reqBody, _ := json.Marshal(request.APIKeyCreateRequest{Name: "kiosk", Scopes: []string{"read"}})
+		e := echo.New()
+		req, rec := makeRequestHttp(string(reqBody), echo.POST, "/user/api-keys", true, true)
+		c := e.NewContext(req, rec)
+		mockAPIKeyUsecase.On("CreateAPIKey", dummyUser[0].ID.String(), mock.Anything).Return(dummyAPIKey, "umkm_abcdef-secret", nil).Once()
+		err := middlewareToken(apiKeyController.CreateAPIKey, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(201), responseBody["code"])
+		data := responseBody["data"].(map[string]interface{})
+		assert.Equal(t, "umkm_abcdef-secret", data["key"])
+		assert.Equal(t, []interface{}{"read"}, data["scopes"])
+		assert.NotContains(t, data, "key_hash")
+		mockAPIKeyUsecase.AssertExpectations(t)
+	})
+
+	t.Run("error-validation", func(t *testing.T) {
+		reqBody, _ := json.Marshal(request.APIKeyCreateRequest{Name: "kiosk"})
+		e := echo.New()
+		req, rec := makeRequestHttp(string(reqBody), echo.POST, "/user/api-keys", true, true)
+		c := e.NewContext(req, rec)
+		err := middlewareToken(apiKeyController.CreateAPIKey, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		assert.Equal(t, "scopes empty", responseBody["message"])
+	})
+
+	t.Run("error-usecase", func(t *testing.T) {
+		reqBody, _ := json.Marshal(request.APIKeyCreateRequest{Name: "kiosk", Scopes: []string{"user:manage"}})
+		e := echo.New()
+		req, rec := makeRequestHttp(string(reqBody), echo.POST, "/user/api-keys", true, true)
+		c := e.NewContext(req, rec)
+		mockAPIKeyUsecase.On("CreateAPIKey", dummyUser[0].ID.String(), mock.Anything).Return(domain.APIKey{}, "", errors.New("scope user:manage not granted to the user")).Once()
+		err := middlewareToken(apiKeyController.CreateAPIKey, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAPIKeyUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAPIKeyController_GetUserAPIKeys(t *testing.T) {
+	mockAPIKeyUsecase := new(mocks.APIKeyUsecase)
+	apiKeyController := http2.NewAPIKeyController(mockAPIKeyUsecase)
+
+	e := echo.New()
+	req, rec := makeRequestHttp("", echo.GET, "/users/"+dummyUser[1].ID.String()+"/api-keys", true, false)
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(dummyUser[1].ID.String())
+	mockAPIKeyUsecase.On("GetAPIKeys", dummyUser[1].ID.String()).Return([]domain.APIKey{dummyAPIKey}, nil).Once()
+	err := middlewareToken(apiKeyController.GetUserAPIKeys, c)
+	responseBody := parseResponse(rec)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(200), responseBody["code"])
+	assert.Len(t, responseBody["data"], 1)
+	mockAPIKeyUsecase.AssertExpectations(t)
+}
+
+func TestAPIKeyController_RevokeAPIKey(t *testing.T) {
+	mockAPIKeyUsecase := new(mocks.APIKeyUsecase)
+	apiKeyController := http2.NewAPIKeyController(mockAPIKeyUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/user/api-keys/"+dummyAPIKey.ID.String(), true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyAPIKey.ID.String())
+		mockAPIKeyUsecase.On("RevokeAPIKey", dummyUser[0].ID.String(), dummyAPIKey.ID.String()).Return(nil).Once()
+		err := middlewareToken(apiKeyController.RevokeAPIKey, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAPIKeyUsecase.AssertExpectations(t)
+	})
+
+	t.Run("error-not-found", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/user/api-keys/"+dummyAPIKey.ID.String(), true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyAPIKey.ID.String())
+		mockAPIKeyUsecase.On("RevokeAPIKey", dummyUser[0].ID.String(), dummyAPIKey.ID.String()).Return(errors.New("api key not found")).Once()
+		err := middlewareToken(apiKeyController.RevokeAPIKey, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAPIKeyUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAPIKeyAuthMiddleware(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAPIKeyUsecase := new(mocks.APIKeyUsecase)
+	goMiddleware := mid.NewGoMiddleware(nil, nil, mockAuthUsecase, mockAPIKeyUsecase)
+	subject := func(c echo.Context) error {
+		claims, err := helper.GetAuthClaims(c)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, claims.UserID())
+	}
+	apiKeyRequest := func(method string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", method, "/enterprises", false, false)
+		req.Header.Set(mid.APIKeyHeader, "umkm_secret")
+		return e.NewContext(req, rec), rec
+	}
+
+	t.Run("success", func(t *testing.T) {
+		c, rec := apiKeyRequest(echo.GET)
+		mockAPIKeyUsecase.On("Authenticate", "umkm_secret").Return(dummyAPIKey, nil).Once()
+		err := goMiddleware.AuthMiddleware()(subject)(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, dummyUser[1].ID.String(), rec.Body.String())
+		mockAPIKeyUsecase.AssertExpectations(t)
+	})
+
+	t.Run("error-invalid-key", func(t *testing.T) {
+		c, rec := apiKeyRequest(echo.GET)
+		mockAPIKeyUsecase.On("Authenticate", "umkm_secret").Return(domain.APIKey{}, errors.New("invalid api key")).Once()
+		err := goMiddleware.AuthMiddleware()(subject)(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("error-write-scope", func(t *testing.T) {
+		c, rec := apiKeyRequest(echo.POST)
+		mockAPIKeyUsecase.On("Authenticate", "umkm_secret").Return(dummyAPIKey, nil).Once()
+		err := goMiddleware.AuthMiddleware()(subject)(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, "api key scope write required", parseResponse(rec)["message"])
+	})
+
+	t.Run("error-permission-scope", func(t *testing.T) {
+		c, rec := apiKeyRequest(echo.GET)
+		mockAPIKeyUsecase.On("Authenticate", "umkm_secret").Return(dummyAPIKey, nil).Once()
+		mockAuthUsecase.On("HasPermission", dummyUser[1].ID.String(), "user:manage").Return(true, nil).Once()
+		err := goMiddleware.AuthMiddleware()(goMiddleware.RequirePermission(role.UserManage)(subject))(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Equal(t, "api key scope user:manage required", parseResponse(rec)["message"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+
+	t.Run("error-session-required", func(t *testing.T) {
+		c, rec := apiKeyRequest(echo.GET)
+		mockAPIKeyUsecase.On("Authenticate", "umkm_secret").Return(dummyAPIKey, nil).Once()
+		err := goMiddleware.AuthMiddleware()(goMiddleware.RequireSession()(subject))(c)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
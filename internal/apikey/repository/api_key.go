@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+type apiKeyRepository struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) domain.APIKeyRepository {
+	return apiKeyRepository{
+		DB: db,
+	}
+}
+
+func (a apiKeyRepository) Save(key domain.APIKey) (domain.APIKey, error) {
+	err := a.DB.Create(&key).Error
+	return key, err
+}
+
+func (a apiKeyRepository) FindByHash(hash string) (key domain.APIKey, err error) {
+	err = a.DB.Where("key_hash = ?", hash).First(&key).Error
+	return key, err
+}
+
+func (a apiKeyRepository) FindByID(id string) (key domain.APIKey, err error) {
+	err = a.DB.Where("id = ?", id).First(&key).Error
+	return key, err
+}
+
+func (a apiKeyRepository) FindByUserID(userID string) (keys []domain.APIKey, err error) {
+	err = a.DB.Where("user_id = ?", userID).Order("created_at desc").Find(&keys).Error
+	return keys, err
+}
+
+// Revoke stamps revoked_at once, a second revoke keeps the first time.
+func (a apiKeyRepository) Revoke(key domain.APIKey) error {
+	result := a.DB.Model(&domain.APIKey{}).Where("id = ? AND revoked_at IS NULL", key.ID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("api key already revoked")
+	}
+	return nil
+}
+
+func (a apiKeyRepository) TouchLastUsed(key domain.APIKey, at time.Time) error {
+	return a.DB.Model(&domain.APIKey{}).Where("id = ?", key.ID).Update("last_used_at", at).Error
+}
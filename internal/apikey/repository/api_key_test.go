@@ -0,0 +1,155 @@
+package repository_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	sqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/apikey/repository"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func SetupDBMock(dbMock *sql.DB) *gorm.DB {
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      dbMock,
+		DSN:                       "sqlmock_db_0",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{PrepareStmt: false})
+	if err != nil {
+		panic(err)
+	}
+	return gormDB
+}
+
+type AnyTime struct{}
+
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+var dummyAPIKey = domain.APIKey{
+	ID:      uuid.FromStringOrNil("5b0a1c3e-7d2f-4e8a-9c61-2f3b4d5e6a70"),
+	UserID:  uuid.FromStringOrNil("0cf712fc-2c16-4a4b-8bfc-3ac2e8bb2edf"),
+	Name:    "kiosk",
+	Prefix:  "umkm_abcdef",
+	KeyHash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	Scopes:  "read,write",
+}
+
+func TestAPIKeyRepository_Save(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `api_keys` (`id`,`user_id`,`name`,`prefix`,`key_hash`,`scopes`,`expires_at`,`last_used_at`,`revoked_at`,`created_at`) VALUES (?,?,?,?,?,?,?,?,?,?)").
+		WithArgs(dummyAPIKey.ID, dummyAPIKey.UserID, dummyAPIKey.Name, dummyAPIKey.Prefix, dummyAPIKey.KeyHash, dummyAPIKey.Scopes, nil, nil, nil, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	apiKeyRepository := repository.NewAPIKeyRepository(db)
+	_, err = apiKeyRepository.Save(dummyAPIKey)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepository_FindByHash(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `api_keys` WHERE key_hash = ? ORDER BY `api_keys`.`id` LIMIT 1").
+		WithArgs(dummyAPIKey.KeyHash).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "user_id", "name", "prefix", "key_hash", "scopes"}).
+			AddRow(dummyAPIKey.ID, dummyAPIKey.UserID, dummyAPIKey.Name, dummyAPIKey.Prefix, dummyAPIKey.KeyHash, dummyAPIKey.Scopes))
+
+	apiKeyRepository := repository.NewAPIKeyRepository(db)
+	key, err := apiKeyRepository.FindByHash(dummyAPIKey.KeyHash)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyAPIKey.ID, key.ID)
+	assert.Equal(t, []string{"read", "write"}, key.ScopeList())
+}
+
+func TestAPIKeyRepository_FindByUserID(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `api_keys` WHERE user_id = ? ORDER BY created_at desc").
+		WithArgs(dummyAPIKey.UserID.String()).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "user_id", "name"}).
+			AddRow(dummyAPIKey.ID, dummyAPIKey.UserID, dummyAPIKey.Name))
+
+	apiKeyRepository := repository.NewAPIKeyRepository(db)
+	keys, err := apiKeyRepository.FindByUserID(dummyAPIKey.UserID.String())
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `api_keys` SET `revoked_at`=? WHERE id = ? AND revoked_at IS NULL").
+			WithArgs(AnyTime{}, dummyAPIKey.ID).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		apiKeyRepository := repository.NewAPIKeyRepository(db)
+		assert.NoError(t, apiKeyRepository.Revoke(dummyAPIKey))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("already revoked", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `api_keys` SET `revoked_at`=? WHERE id = ? AND revoked_at IS NULL").
+			WithArgs(AnyTime{}, dummyAPIKey.ID).
+			WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		apiKeyRepository := repository.NewAPIKeyRepository(db)
+		err = apiKeyRepository.Revoke(dummyAPIKey)
+		assert.EqualError(t, err, "api key already revoked")
+	})
+}
+
+func TestAPIKeyRepository_TouchLastUsed(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `api_keys` SET `last_used_at`=? WHERE id = ?").
+		WithArgs(now, dummyAPIKey.ID).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	apiKeyRepository := repository.NewAPIKeyRepository(db)
+	assert.NoError(t, apiKeyRepository.TouchLastUsed(dummyAPIKey, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/web/request"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+// lastUsedInterval limits the writes for LastUsedAt, a busy client would otherwise update
+// its key on every request.
+const lastUsedInterval = time.Minute
+
+type apiKeyUsecase struct {
+	apiKeyRepository domain.APIKeyRepository
+	userRepository   domain.UserRepository
+}
+
+func NewAPIKeyUsecase(ar domain.APIKeyRepository, ur domain.UserRepository) domain.APIKeyUsecase {
+	return apiKeyUsecase{
+		apiKeyRepository: ar,
+		userRepository:   ur,
+	}
+}
+
+// CreateAPIKey issues a key for the user and returns it next to the stored record, only the
+// hash is kept. Permission scopes must be granted to the user by one of their roles.
+func (a apiKeyUsecase) CreateAPIKey(userID string, request request.APIKeyCreateRequest) (domain.APIKey, string, error) {
+	user, err := a.userRepository.FindUserById(userID)
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	if err = user.CheckActive(); err != nil {
+		return domain.APIKey{}, "", err
+	}
+
+	var scopes []string
+	for _, scope := range request.Scopes {
+		if err = checkScope(user, scope); err != nil {
+			return domain.APIKey{}, "", err
+		}
+		if !contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	key, hash, err := helper.NewAPIKey()
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	apiKey, err := a.apiKeyRepository.Save(domain.APIKey{
+		ID:        uuid.NewV4(),
+		UserID:    user.ID,
+		Name:      request.Name,
+		Prefix:    key[:len(helper.APIKeyPrefix)+6],
+		KeyHash:   hash,
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: request.ExpiresAt,
+	})
+	if err != nil {
+		return domain.APIKey{}, "", err
+	}
+	return apiKey, key, nil
+}
+
+func checkScope(user domain.User, scope string) error {
+	if scope == domain.APIKeyScopeRead || scope == domain.APIKeyScopeWrite {
+		return nil
+	}
+	for _, permission := range role.Permissions {
+		if permission.String() == scope {
+			if !user.HasPermission(scope) {
+				return errors.New("scope " + scope + " not granted to the user")
+			}
+			return nil
+		}
+	}
+	return errors.New("unknown scope " + scope)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (a apiKeyUsecase) GetAPIKeys(userID string) ([]domain.APIKey, error) {
+	return a.apiKeyRepository.FindByUserID(userID)
+}
+
+// RevokeAPIKey stops the key of the user from working, revoked keys stay listed.
+func (a apiKeyUsecase) RevokeAPIKey(userID string, id string) error {
+	key, err := a.apiKeyRepository.FindByID(id)
+	if err != nil || key.UserID.String() != userID {
+		return errors.New("api key not found")
+	}
+	if key.RevokedAt != nil {
+		return errors.New("api key already revoked")
+	}
+	return a.apiKeyRepository.Revoke(key)
+}
+
+// Authenticate returns the key when it is usable and its user is active.
+func (a apiKeyUsecase) Authenticate(key string) (domain.APIKey, error) {
+	apiKey, err := a.apiKeyRepository.FindByHash(helper.HashAPIKey(key))
+	if err != nil {
+		return domain.APIKey{}, errors.New("invalid api key")
+	}
+	now := time.Now()
+	if err = apiKey.CheckUsable(now); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	user, err := a.userRepository.FindUserById(apiKey.UserID.String())
+	if err != nil {
+		return domain.APIKey{}, errors.New("invalid api key")
+	}
+	if err = user.CheckActive(); err != nil {
+		return domain.APIKey{}, err
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > lastUsedInterval {
+		// the request may go on, a missed timestamp is not worth failing it
+		if err = a.apiKeyRepository.TouchLastUsed(apiKey, now); err != nil {
+			log.WithField("api_key_id", apiKey.ID.String()).Warn("touch api key: " + err.Error())
+		} else {
+			apiKey.LastUsedAt = &now
+		}
+	}
+	return apiKey, nil
+}
@@ -0,0 +1,188 @@
+package usecase_test
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/apikey/usecase"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/web/request"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+var dummyUser = domain.User{
+	ID:       uuid.FromStringOrNil("0cf712fc-e631-40c7-8572-54772e698edf"),
+	Fullname: "user owner",
+	Email:    "owner@email.com",
+	Username: "owner",
+	Roles: []domain.Role{
+		{
+			Name: "ROLE_OWNER", ID: 3,
+			Permissions: []domain.Permission{{Name: "enterprise:create"}},
+		},
+	},
+}
+
+var dummyAPIKey = domain.APIKey{
+	ID:     uuid.FromStringOrNil("5b0a1c3e-7d2f-4e8a-9c61-2f3b4d5e6a70"),
+	UserID: dummyUser.ID,
+	Name:   "kiosk",
+	Scopes: "read",
+}
+
+func TestAPIKeyUsecase_CreateAPIKey(t *testing.T) {
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	uc := usecase.NewAPIKeyUsecase(mockAPIKeyRepository, mockUserRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", dummyUser.ID.String()).Return(dummyUser, nil).Once()
+		mockAPIKeyRepository.On("Save", mock.Anything).Return(func(key domain.APIKey) domain.APIKey {
+			return key
+		}, nil).Once()
+		key, plain, err := uc.CreateAPIKey(dummyUser.ID.String(), request.APIKeyCreateRequest{
+			Name:   "kiosk",
+			Scopes: []string{"read", "enterprise:create", "read"},
+		})
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(plain, helper.APIKeyPrefix))
+		assert.Equal(t, helper.HashAPIKey(plain), key.KeyHash)
+		assert.True(t, strings.HasPrefix(plain, key.Prefix))
+		assert.Equal(t, []string{"read", "enterprise:create"}, key.ScopeList())
+		mockAPIKeyRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-scope-not-granted", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", dummyUser.ID.String()).Return(dummyUser, nil).Once()
+		_, _, err := uc.CreateAPIKey(dummyUser.ID.String(), request.APIKeyCreateRequest{
+			Name:   "kiosk",
+			Scopes: []string{"user:manage"},
+		})
+		assert.EqualError(t, err, "scope user:manage not granted to the user")
+	})
+
+	t.Run("error-unknown-scope", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", dummyUser.ID.String()).Return(dummyUser, nil).Once()
+		_, _, err := uc.CreateAPIKey(dummyUser.ID.String(), request.APIKeyCreateRequest{
+			Name:   "kiosk",
+			Scopes: []string{"admin"},
+		})
+		assert.EqualError(t, err, "unknown scope admin")
+	})
+
+	t.Run("error-suspended", func(t *testing.T) {
+		suspended := dummyUser
+		now := time.Now()
+		suspended.SuspendedAt = &now
+		mockUserRepository.On("FindUserById", dummyUser.ID.String()).Return(suspended, nil).Once()
+		_, _, err := uc.CreateAPIKey(dummyUser.ID.String(), request.APIKeyCreateRequest{
+			Name:   "kiosk",
+			Scopes: []string{"read"},
+		})
+		assert.EqualError(t, err, "account suspended")
+	})
+}
+
+func TestAPIKeyUsecase_RevokeAPIKey(t *testing.T) {
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	uc := usecase.NewAPIKeyUsecase(mockAPIKeyRepository, new(mocks.UserRepository))
+
+	t.Run("success", func(t *testing.T) {
+		mockAPIKeyRepository.On("FindByID", dummyAPIKey.ID.String()).Return(dummyAPIKey, nil).Once()
+		mockAPIKeyRepository.On("Revoke", dummyAPIKey).Return(nil).Once()
+		err := uc.RevokeAPIKey(dummyUser.ID.String(), dummyAPIKey.ID.String())
+		assert.NoError(t, err)
+		mockAPIKeyRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-other-user", func(t *testing.T) {
+		mockAPIKeyRepository.On("FindByID", dummyAPIKey.ID.String()).Return(dummyAPIKey, nil).Once()
+		err := uc.RevokeAPIKey(uuid.NewV4().String(), dummyAPIKey.ID.String())
+		assert.EqualError(t, err, "api key not found")
+	})
+
+	t.Run("error-already-revoked", func(t *testing.T) {
+		revoked := dummyAPIKey
+		now := time.Now()
+		revoked.RevokedAt = &now
+		mockAPIKeyRepository.On("FindByID", dummyAPIKey.ID.String()).Return(revoked, nil).Once()
+		err := uc.RevokeAPIKey(dummyUser.ID.String(), dummyAPIKey.ID.String())
+		assert.EqualError(t, err, "api key already revoked")
+	})
+}
+
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	uc := usecase.NewAPIKeyUsecase(mockAPIKeyRepository, mockUserRepository)
+	plain := helper.APIKeyPrefix + "secret"
+	hash := helper.HashAPIKey(plain)
+
+	t.Run("success", func(t *testing.T) {
+		mockAPIKeyRepository.On("FindByHash", hash).Return(dummyAPIKey, nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser.ID.String()).Return(dummyUser, nil).Once()
+		mockAPIKeyRepository.On("TouchLastUsed", dummyAPIKey, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		key, err := uc.Authenticate(plain)
+		assert.NoError(t, err)
+		assert.NotNil(t, key.LastUsedAt)
+		mockAPIKeyRepository.AssertExpectations(t)
+	})
+
+	t.Run("success-recently-used", func(t *testing.T) {
+		used := dummyAPIKey
+		now := time.Now()
+		used.LastUsedAt = &now
+		// TouchLastUsed is not expected, the mock would fail on the call
+		mockAPIKeyRepository.On("FindByHash", hash).Return(used, nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser.ID.String()).Return(dummyUser, nil).Once()
+		_, err := uc.Authenticate(plain)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error-touch-failed", func(t *testing.T) {
+		mockAPIKeyRepository.On("FindByHash", hash).Return(dummyAPIKey, nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser.ID.String()).Return(dummyUser, nil).Once()
+		mockAPIKeyRepository.On("TouchLastUsed", dummyAPIKey, mock.AnythingOfType("time.Time")).Return(errors.New("db down")).Once()
+		_, err := uc.Authenticate(plain)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error-invalid", func(t *testing.T) {
+		mockAPIKeyRepository.On("FindByHash", hash).Return(domain.APIKey{}, errors.New("record not found")).Once()
+		_, err := uc.Authenticate(plain)
+		assert.EqualError(t, err, "invalid api key")
+	})
+
+	t.Run("error-expired", func(t *testing.T) {
+		expired := dummyAPIKey
+		past := time.Now().Add(-time.Hour)
+		expired.ExpiresAt = &past
+		mockAPIKeyRepository.On("FindByHash", hash).Return(expired, nil).Once()
+		_, err := uc.Authenticate(plain)
+		assert.EqualError(t, err, "api key expired")
+	})
+
+	t.Run("error-revoked", func(t *testing.T) {
+		revoked := dummyAPIKey
+		now := time.Now()
+		revoked.RevokedAt = &now
+		mockAPIKeyRepository.On("FindByHash", hash).Return(revoked, nil).Once()
+		_, err := uc.Authenticate(plain)
+		assert.EqualError(t, err, "api key revoked")
+	})
+
+	t.Run("error-user-suspended", func(t *testing.T) {
+		suspended := dummyUser
+		now := time.Now()
+		suspended.SuspendedAt = &now
+		mockAPIKeyRepository.On("FindByHash", hash).Return(dummyAPIKey, nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser.ID.String()).Return(suspended, nil).Once()
+		_, err := uc.Authenticate(plain)
+		assert.EqualError(t, err, "account suspended")
+	})
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/web/response"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditController interface {
+	GetAuditLogs(c echo.Context) error
+}
+
+type auditController struct {
+	AuditUsecase domain.AuditUsecase
+}
+
+func NewAuditController(au domain.AuditUsecase) AuditController {
+	return auditController{
+		AuditUsecase: au,
+	}
+}
+
+func auditLogResponse(log domain.AuditLog) response.AuditLogResponse {
+	res := response.AuditLogResponse{
+		ID:         log.ID,
+		ActorID:    log.ActorID,
+		Action:     log.Action,
+		TargetType: log.TargetType,
+		TargetID:   log.TargetID,
+		IP:         log.IP,
+		UserAgent:  log.UserAgent,
+		CreatedAt:  log.CreatedAt,
+	}
+	if log.Before != "" {
+		_ = json.Unmarshal([]byte(log.Before), &res.Before)
+	}
+	if log.After != "" {
+		_ = json.Unmarshal([]byte(log.After), &res.After)
+	}
+	return res
+}
+
+func parseTimeParam(c echo.Context, name string) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New(name + " must be an RFC 3339 time")
+	}
+	return &t, nil
+}
+
+// GetAuditLogs godoc
+// @Summary Get audit logs
+// @Description Get the audit trail of logins and admin actions, newest first. Requires permission audit:read
+// @Tags Audit
+// @accept json
+// @Produce json
+// @Router /audit-logs [get]
+// @Param actor_id query string false "user id of the actor"
+// @Param target_type query string false "user, enterprise, tag, rating or review"
+// @Param target_id query string false "id of the target"
+// @Param from query string false "RFC 3339 time, inclusive"
+// @Param to query string false "RFC 3339 time, exclusive"
+// @Param page query int false "page"
+// @Param length query int false "length, default 50 and at most 200"
+// @Success 200 {object} response.JSONSuccessListResult{data=[]response.AuditLogResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a auditController) GetAuditLogs(c echo.Context) error {
+	from, err := parseTimeParam(c, "from")
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	to, err := parseTimeParam(c, "to")
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	length, _ := strconv.Atoi(c.QueryParam("length"))
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+
+	filter := domain.AuditLogFilter{
+		ActorID:    c.QueryParam("actor_id"),
+		TargetType: c.QueryParam("target_type"),
+		TargetID:   c.QueryParam("target_id"),
+		From:       from,
+		To:         to,
+		Page:       page,
+		Length:     length,
+	}
+	logs, totalData, err := a.AuditUsecase.GetAuditLogs(filter)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	res := make([]response.AuditLogResponse, 0, len(logs))
+	for _, log := range logs {
+		res = append(res, auditLogResponse(log))
+	}
+
+	if page == 0 {
+		page = 1
+	}
+	pageCount := int(math.Ceil(float64(totalData) / float64(filter.PageLength())))
+	metadata := struct {
+		Length    int `json:"length"`
+		Page      int `json:"page"`
+		PageCount int `json:"page_count"`
+		TotalData int `json:"total_data"`
+	}{
+		Length:    len(logs),
+		Page:      page,
+		PageCount: pageCount,
+		TotalData: totalData,
+	}
+	return response.SuccessListResponse(c, http.StatusOK, true, "success get audit logs", res, metadata)
+}
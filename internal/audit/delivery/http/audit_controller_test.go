@@ -0,0 +1,159 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	http2 "github.com/nrmadi02/mini-project/internal/audit/delivery/http"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	mid "github.com/nrmadi02/mini-project/internal/user/delivery/http/middleware"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var base_path = "/api/v1"
+
+var dummyUser = domain.Users{
+	domain.User{
+		ID:       uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf891"),
+		Fullname: "user1",
+		Email:    "satu@email.com",
+		Username: "usr1",
+		Roles: []domain.Role{
+			domain.Role{
+				Name: "ROLE_ADMIN", ID: 1,
+			},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+}
+
+var dummyAuditLog = domain.AuditLog{
+	ID:         uuid.FromStringOrNil("8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"),
+	ActorID:    dummyUser[0].ID.String(),
+	Action:     domain.AuditEnterpriseStatus,
+	TargetType: domain.AuditTargetEnterprise,
+	TargetID:   "35d6a9a1-aa5e-41f1-9991-08878dfdf89a",
+	Before:     `{"status":"draft"}`,
+	After:      `{"status":"published"}`,
+	IP:         "127.0.0.1",
+	CreatedAt:  time.Now(),
+}
+
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+
+func createToken() string {
+	jwtSetToken := helper.NewGoJWT(keySet, "test", "test")
+	token := jwtSetToken.CreateTokenJWT(&dummyUser[0])
+	return token
+}
+
+func middlewareToken(handlerFunc echo.HandlerFunc, c echo.Context) error {
+	err := middleware.JWTWithConfig(middleware.JWTConfig{
+		KeyFunc: keySet.Keyfunc,
+		Claims:  &helper.JWTClaims{},
+	})(handlerFunc)(c)
+	return err
+}
+
+func requirePermission(authUsecase domain.AuthUsecase, permission role.Permission, handlerFunc echo.HandlerFunc) echo.HandlerFunc {
+	return mid.NewGoMiddleware(nil, nil, authUsecase, nil).RequirePermission(permission)(handlerFunc)
+}
+
+func parseResponse(rec *httptest.ResponseRecorder) map[string]interface{} {
+	var responseBody map[string]interface{}
+	resBody := rec.Body.String()
+	_ = json.Unmarshal([]byte(resBody), &responseBody)
+	return responseBody
+}
+
+func makeRequestHttp(request string, method string, path string, isToken bool, isBind bool) (req *http.Request, rec *httptest.ResponseRecorder) {
+	req, _ = http.NewRequest(method, base_path+path, strings.NewReader(request))
+	if isBind {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	if isToken {
+		req.Header.Add(echo.HeaderAuthorization, middleware.DefaultJWTConfig.AuthScheme+" "+createToken())
+	}
+	rec = httptest.NewRecorder()
+	return req, rec
+}
+
+func TestAuditController_GetAuditLogs(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockAuditUsecase := new(mocks.AuditUsecase)
+	auditController := http2.NewAuditController(mockAuditUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/audit-logs?target_type=enterprise&target_id="+dummyAuditLog.TargetID+"&from=2022-05-01T00:00:00Z&page=2&length=10", true, false)
+		c := e.NewContext(req, rec)
+		from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "audit:read").Return(true, nil).Once()
+		mockAuditUsecase.On("GetAuditLogs", mock.MatchedBy(func(filter domain.AuditLogFilter) bool {
+			return filter.TargetType == "enterprise" && filter.TargetID == dummyAuditLog.TargetID &&
+				filter.From != nil && filter.From.Equal(from) && filter.To == nil && filter.Page == 2 && filter.Length == 10
+		})).Return([]domain.AuditLog{dummyAuditLog}, 11, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.AuditRead, auditController.GetAuditLogs), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		data := responseBody["data"].([]interface{})
+		assert.Len(t, data, 1)
+		entry := data[0].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"status": "draft"}, entry["before"])
+		assert.Equal(t, map[string]interface{}{"status": "published"}, entry["after"])
+		metadata := responseBody["metadata"].(map[string]interface{})
+		assert.Equal(t, float64(2), metadata["page_count"])
+		assert.Equal(t, float64(11), metadata["total_data"])
+		mockAuditUsecase.AssertExpectations(t)
+	})
+
+	t.Run("error-time", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/audit-logs?to=yesterday", true, false)
+		c := e.NewContext(req, rec)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "audit:read").Return(true, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.AuditRead, auditController.GetAuditLogs), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		assert.Equal(t, "to must be an RFC 3339 time", responseBody["message"])
+	})
+
+	t.Run("error-usecase", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/audit-logs", true, false)
+		c := e.NewContext(req, rec)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "audit:read").Return(true, nil).Once()
+		mockAuditUsecase.On("GetAuditLogs", mock.Anything).Return(nil, 0, errors.New("error something")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.AuditRead, auditController.GetAuditLogs), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+
+	t.Run("error-forbidden", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/audit-logs", true, false)
+		c := e.NewContext(req, rec)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "audit:read").Return(false, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.AuditRead, auditController.GetAuditLogs), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(403), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
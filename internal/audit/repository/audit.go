@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) domain.AuditRepository {
+	return auditRepository{
+		DB: db,
+	}
+}
+
+// Save only inserts, the repository has no update or delete so the trail stays append-only.
+func (a auditRepository) Save(log domain.AuditLog) error {
+	return a.DB.Create(&log).Error
+}
+
+func (a auditRepository) Find(filter domain.AuditLogFilter) (logs []domain.AuditLog, totalData int, err error) {
+	page := filter.Page
+	if page == 0 {
+		page = 1
+	}
+	offset := (page - 1) * filter.Length
+
+	query := a.DB.Model(&domain.AuditLog{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var count int64
+	if err = query.Session(&gorm.Session{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	err = query.Order("created_at desc").Offset(offset).Limit(filter.Length).Find(&logs).Error
+	return logs, int(count), err
+}
@@ -0,0 +1,118 @@
+package repository_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	sqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/audit/repository"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func SetupDBMock(dbMock *sql.DB) *gorm.DB {
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      dbMock,
+		DSN:                       "sqlmock_db_0",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{PrepareStmt: false})
+	if err != nil {
+		panic(err)
+	}
+	return gormDB
+}
+
+type AnyTime struct{}
+
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+var dummyAuditLog = domain.AuditLog{
+	ID:         uuid.FromStringOrNil("8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"),
+	ActorID:    "35d6a9a1-aa5e-41f1-9991-08878dfdf891",
+	Action:     domain.AuditTagDelete,
+	TargetType: domain.AuditTargetTag,
+	TargetID:   "0cf712fc-e631-40c7-8572-54772e698edf",
+	Before:     `{"name":"Kuliner"}`,
+	IP:         "127.0.0.1",
+	UserAgent:  "curl/7.79.1",
+}
+
+func TestAuditRepository_Save(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `audit_logs` (`id`,`actor_id`,`action`,`target_type`,`target_id`,`before`,`after`,`ip`,`user_agent`,`created_at`) VALUES (?,?,?,?,?,?,?,?,?,?)").
+		WithArgs(dummyAuditLog.ID, dummyAuditLog.ActorID, dummyAuditLog.Action, dummyAuditLog.TargetType, dummyAuditLog.TargetID, dummyAuditLog.Before, "", dummyAuditLog.IP, dummyAuditLog.UserAgent, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	auditRepository := repository.NewAuditRepository(db)
+	assert.NoError(t, auditRepository.Save(dummyAuditLog))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_Find(t *testing.T) {
+	t.Run("all", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		mock.ExpectQuery("SELECT count(*) FROM `audit_logs`").
+			WillReturnRows(sqlMock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery("SELECT * FROM `audit_logs` ORDER BY created_at desc LIMIT 50").
+			WillReturnRows(sqlMock.NewRows([]string{"id", "actor_id", "action", "target_type", "target_id"}).
+				AddRow(dummyAuditLog.ID, dummyAuditLog.ActorID, dummyAuditLog.Action, dummyAuditLog.TargetType, dummyAuditLog.TargetID))
+
+		auditRepository := repository.NewAuditRepository(db)
+		logs, totalData, err := auditRepository.Find(domain.AuditLogFilter{Length: 50})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, totalData)
+		assert.Len(t, logs, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("filtered", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery("SELECT count(*) FROM `audit_logs` WHERE actor_id = ? AND target_type = ? AND target_id = ? AND created_at >= ? AND created_at < ?").
+			WithArgs(dummyAuditLog.ActorID, dummyAuditLog.TargetType, dummyAuditLog.TargetID, from, to).
+			WillReturnRows(sqlMock.NewRows([]string{"count"}).AddRow(12))
+		mock.ExpectQuery("SELECT * FROM `audit_logs` WHERE actor_id = ? AND target_type = ? AND target_id = ? AND created_at >= ? AND created_at < ? ORDER BY created_at desc LIMIT 10 OFFSET 10").
+			WithArgs(dummyAuditLog.ActorID, dummyAuditLog.TargetType, dummyAuditLog.TargetID, from, to).
+			WillReturnRows(sqlMock.NewRows([]string{"id", "actor_id", "action", "target_type", "target_id"}).
+				AddRow(dummyAuditLog.ID, dummyAuditLog.ActorID, dummyAuditLog.Action, dummyAuditLog.TargetType, dummyAuditLog.TargetID))
+
+		auditRepository := repository.NewAuditRepository(db)
+		logs, totalData, err := auditRepository.Find(domain.AuditLogFilter{
+			ActorID:    dummyAuditLog.ActorID,
+			TargetType: dummyAuditLog.TargetType,
+			TargetID:   dummyAuditLog.TargetID,
+			From:       &from,
+			To:         &to,
+			Page:       2,
+			Length:     10,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 12, totalData)
+		assert.Len(t, logs, 1)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	log "github.com/sirupsen/logrus"
+)
+
+type auditUsecase struct {
+	auditRepository domain.AuditRepository
+}
+
+func NewAuditUsecase(ar domain.AuditRepository) domain.AuditUsecase {
+	return auditUsecase{
+		auditRepository: ar,
+	}
+}
+
+func (a auditUsecase) Record(actor domain.Actor, action string, targetType string, targetID string, before map[string]interface{}, after map[string]interface{}) {
+	entry := domain.NewAuditLog(actor, action, targetType, targetID, before, after)
+	if err := a.auditRepository.Save(entry); err != nil {
+		log.WithFields(log.Fields{
+			"action":      action,
+			"actor_id":    actor.UserID,
+			"target_type": targetType,
+			"target_id":   targetID,
+		}).Warn("write audit log: " + err.Error())
+	}
+}
+
+// GetAuditLogs returns the newest entries first.
+func (a auditUsecase) GetAuditLogs(filter domain.AuditLogFilter) ([]domain.AuditLog, int, error) {
+	filter.Length = filter.PageLength()
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	logs, totalData, err := a.auditRepository.Find(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, totalData, nil
+}
@@ -0,0 +1,106 @@
+package usecase_test
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/audit/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+var dummyActor = domain.Actor{
+	UserID:    "35d6a9a1-aa5e-41f1-9991-08878dfdf891",
+	IP:        "127.0.0.1",
+	UserAgent: "curl/7.79.1",
+}
+
+func TestAuditUsecase_Record(t *testing.T) {
+	t.Run("keeps changed fields", func(t *testing.T) {
+		mockAuditRepository := new(mocks.AuditRepository)
+		mockAuditRepository.On("Save", mock.MatchedBy(func(log domain.AuditLog) bool {
+			return log.ActorID == dummyActor.UserID &&
+				log.Action == domain.AuditEnterpriseStatus &&
+				log.TargetType == domain.AuditTargetEnterprise &&
+				log.TargetID == "35d6a9a1-aa5e-41f1-9991-08878dfdf89a" &&
+				log.Before == `{"status":"draft"}` &&
+				log.After == `{"status":"published"}` &&
+				log.IP == dummyActor.IP &&
+				log.UserAgent == dummyActor.UserAgent
+		})).Return(nil).Once()
+
+		uc := usecase.NewAuditUsecase(mockAuditRepository)
+		uc.Record(dummyActor, domain.AuditEnterpriseStatus, domain.AuditTargetEnterprise, "35d6a9a1-aa5e-41f1-9991-08878dfdf89a",
+			map[string]interface{}{"status": "draft", "status_reason": ""},
+			map[string]interface{}{"status": "published", "status_reason": ""})
+		mockAuditRepository.AssertExpectations(t)
+	})
+	t.Run("deleted target", func(t *testing.T) {
+		mockAuditRepository := new(mocks.AuditRepository)
+		mockAuditRepository.On("Save", mock.MatchedBy(func(log domain.AuditLog) bool {
+			return log.Before == `{"name":"Kuliner"}` && log.After == ""
+		})).Return(nil).Once()
+
+		uc := usecase.NewAuditUsecase(mockAuditRepository)
+		uc.Record(dummyActor, domain.AuditTagDelete, domain.AuditTargetTag, "0cf712fc-e631-40c7-8572-54772e698edf",
+			map[string]interface{}{"name": "Kuliner"}, nil)
+		mockAuditRepository.AssertExpectations(t)
+	})
+	t.Run("long user agent", func(t *testing.T) {
+		mockAuditRepository := new(mocks.AuditRepository)
+		mockAuditRepository.On("Save", mock.MatchedBy(func(log domain.AuditLog) bool {
+			return len(log.UserAgent) == 512
+		})).Return(nil).Once()
+
+		actor := dummyActor
+		actor.UserAgent = strings.Repeat("a", 600)
+		uc := usecase.NewAuditUsecase(mockAuditRepository)
+		uc.Record(actor, domain.AuditLogin, domain.AuditTargetUser, dummyActor.UserID, nil, nil)
+		mockAuditRepository.AssertExpectations(t)
+	})
+	t.Run("failed write", func(t *testing.T) {
+		mockAuditRepository := new(mocks.AuditRepository)
+		mockAuditRepository.On("Save", mock.Anything).Return(errors.New("error something")).Once()
+
+		uc := usecase.NewAuditUsecase(mockAuditRepository)
+		assert.NotPanics(t, func() {
+			uc.Record(dummyActor, domain.AuditLogin, domain.AuditTargetUser, dummyActor.UserID, nil, nil)
+		})
+		mockAuditRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuditUsecase_GetAuditLogs(t *testing.T) {
+	t.Run("default length", func(t *testing.T) {
+		mockAuditRepository := new(mocks.AuditRepository)
+		mockAuditRepository.On("Find", domain.AuditLogFilter{ActorID: dummyActor.UserID, Page: 1, Length: 50}).
+			Return([]domain.AuditLog{{ActorID: dummyActor.UserID}}, 1, nil).Once()
+
+		uc := usecase.NewAuditUsecase(mockAuditRepository)
+		logs, totalData, err := uc.GetAuditLogs(domain.AuditLogFilter{ActorID: dummyActor.UserID})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, totalData)
+		assert.Len(t, logs, 1)
+		mockAuditRepository.AssertExpectations(t)
+	})
+	t.Run("length capped", func(t *testing.T) {
+		mockAuditRepository := new(mocks.AuditRepository)
+		mockAuditRepository.On("Find", domain.AuditLogFilter{Page: 3, Length: 200}).
+			Return([]domain.AuditLog{}, 0, nil).Once()
+
+		uc := usecase.NewAuditUsecase(mockAuditRepository)
+		_, _, err := uc.GetAuditLogs(domain.AuditLogFilter{Page: 3, Length: 5000})
+		assert.NoError(t, err)
+		mockAuditRepository.AssertExpectations(t)
+	})
+	t.Run("error", func(t *testing.T) {
+		mockAuditRepository := new(mocks.AuditRepository)
+		mockAuditRepository.On("Find", mock.Anything).Return(nil, 0, errors.New("error something")).Once()
+
+		uc := usecase.NewAuditUsecase(mockAuditRepository)
+		_, _, err := uc.GetAuditLogs(domain.AuditLogFilter{})
+		assert.Error(t, err)
+	})
+}
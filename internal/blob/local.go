@@ -0,0 +1,79 @@
+package blob
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nrmadi02/mini-project/domain"
+)
+
+type localStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore keeps files under dir, the router serves them at baseURL.
+func NewLocalStore(dir string, baseURL string) domain.BlobStore {
+	return localStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// path refuses keys that would leave dir.
+func (l localStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", errors.New("invalid blob key " + key)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(clean)), nil
+}
+
+func (l localStore) Put(key string, contentType string, body io.Reader, size int64) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// written aside and renamed, so a failed upload never replaces a stored file.
+	fp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fp.Name())
+	written, err := io.Copy(fp, body)
+	if closeErr := fp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != size {
+		return errors.New("blob size does not match the body")
+	}
+	if err = os.Chmod(fp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(fp.Name(), path)
+}
+
+func (l localStore) Delete(key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l localStore) URL(key string) string {
+	return l.baseURL + "/" + key
+}
@@ -0,0 +1,36 @@
+package blob_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nrmadi02/mini-project/internal/blob"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store := blob.NewLocalStore(dir, "http://localhost:8080/uploads/")
+	key := "enterprises/35d6a9a1-aa5e-41f1-9991-08878dfdf89a/logo.png"
+
+	assert.NoError(t, store.Put(key, "image/png", strings.NewReader("png"), 3))
+	content, err := os.ReadFile(filepath.Join(dir, "enterprises", "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", "logo.png"))
+	assert.NoError(t, err)
+	assert.Equal(t, "png", string(content))
+	assert.Equal(t, "http://localhost:8080/uploads/"+key, store.URL(key))
+
+	assert.Error(t, store.Put(key, "image/png", strings.NewReader("short"), 10))
+	content, _ = os.ReadFile(filepath.Join(dir, "enterprises", "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", "logo.png"))
+	assert.Equal(t, "png", string(content), "a failed put keeps the stored file")
+
+	assert.NoError(t, store.Delete(key))
+	assert.NoError(t, store.Delete(key))
+	_, err = os.Stat(filepath.Join(dir, "enterprises", "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", "logo.png"))
+	assert.True(t, os.IsNotExist(err))
+
+	for _, key := range []string{"", "../secret", "enterprises/../../secret", "/etc/passwd", "a//b"} {
+		assert.Error(t, store.Put(key, "image/png", strings.NewReader("png"), 3), key)
+	}
+}
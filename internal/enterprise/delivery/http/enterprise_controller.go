@@ -1,19 +1,18 @@
 package http
 
 import (
-	"encoding/json"
-	"github.com/golang-jwt/jwt"
+	"errors"
 	"github.com/labstack/echo/v4"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
 	"github.com/nrmadi02/mini-project/web/request"
 	"github.com/nrmadi02/mini-project/web/response"
 	uuid "github.com/satori/go.uuid"
-	"io"
-	"io/ioutil"
 	"math"
 	"net/http"
-	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type EnterpriseController interface {
@@ -21,9 +20,11 @@ type EnterpriseController interface {
 	UpdateStatusEnterprise(c echo.Context) error
 	UpdateEnterpriseByID(c echo.Context) error
 	GetEnterpriseByStatus(c echo.Context) error
+	GetStatusHistories(c echo.Context) error
 	GetDetailEnterpriseByID(c echo.Context) error
 	GetAllEnterprises(c echo.Context) error
 	GetDistance(c echo.Context) error
+	GetNearbyEnterprises(c echo.Context) error
 	DeleteEnterpriseByID(c echo.Context) error
 
 	//rating enterprise
@@ -57,19 +58,18 @@ func NewEnterpriseController(au domain.AuthUsecase, eu domain.EnterpriseUsecase,
 // @param data body request.CreateEnterpriseRequest true "required"
 // @Success 201 {object} response.JSONSuccessResult{data=interface{}}
 // @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (e enterpriseController) CreateNewEnterprise(c echo.Context) error {
 	var req request.CreateEnterpriseRequest
 	if err := c.Bind(&req); err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
-	var id string
-	if jwtBearer := c.Get("user"); jwtBearer != nil {
-		u := jwtBearer.(*jwt.Token)
-		claims := u.Claims.(jwt.MapClaims)
-		id = claims["UserID"].(string)
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
 	}
-	res, err := e.enterpriseUsecase.CreateNewEnterprise(req, id)
+	res, err := e.enterpriseUsecase.CreateNewEnterprise(req, claims.UserID())
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
@@ -84,33 +84,36 @@ func (e enterpriseController) CreateNewEnterprise(c echo.Context) error {
 
 // UpdateStatusEnterprise godoc
 // @Summary Update status enterprise
-// @Description 0 = draft, 1 = publish
+// @Description draft -> submitted -> published by the owner and admin, rejected and suspended need a reason
 // @Tags Enterprise
 // @accept json
 // @Produce json
 // @Router /enterprise/{id}/status [put]
 // @Param id path string true "enterprise id"
-// @Param status query int true "status" Enums(0, 1)
+// @Param data body request.UpdateStatusRequest true "status: draft, submitted, published, rejected, suspended, archived"
 // @Success 200 {object} response.JSONSuccessResult{data=interface{}}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (e enterpriseController) UpdateStatusEnterprise(c echo.Context) error {
 	id := c.Param("id")
-	status, _ := strconv.Atoi(c.QueryParam("status"))
-
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-
-	isAdmin, err := e.authUsecase.CheckIfUserIsAdmin(claims["UserID"].(string))
-
-	if err != nil || isAdmin == false {
+	var req request.UpdateStatusRequest
+	if err := c.Bind(&req); err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
+	if req.Status == "" {
+		req.Status = c.QueryParam("status")
+		req.Reason = c.QueryParam("reason")
+	}
 
-	_, err = e.enterpriseUsecase.UpdateStatusEnterprise(id, status)
+	if _, err := helper.GetAuthClaims(c); err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	_, err := e.enterpriseUsecase.UpdateStatusEnterprise(id, helper.GetActor(c), req)
 	if err != nil {
-		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
 	}
 
 	enterprise, err := e.enterpriseUsecase.GetDetailEnterpriseByID(id)
@@ -121,9 +124,49 @@ func (e enterpriseController) UpdateStatusEnterprise(c echo.Context) error {
 	return response.SuccessResponse(c, http.StatusOK, true, "success update status enterprise", enterprise)
 }
 
+// GetStatusHistories godoc
+// @Summary Get status history of enterprise
+// @Description only for the owner and admin
+// @Tags Enterprise
+// @accept json
+// @Produce json
+// @Router /enterprise/{id}/status/history [get]
+// @Param id path string true "enterprise id"
+// @Success 200 {object} response.JSONSuccessResult{data=[]response.StatusHistoryResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (e enterpriseController) GetStatusHistories(c echo.Context) error {
+	id := c.Param("id")
+
+	if _, err := helper.GetAuthClaims(c); err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	histories, err := e.enterpriseUsecase.GetStatusHistories(id, helper.GetActor(c))
+	if err != nil {
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
+	}
+
+	res := []response.StatusHistoryResponse{}
+	for _, history := range histories {
+		res = append(res, response.StatusHistoryResponse{
+			ID:        history.ID,
+			ActorID:   history.ActorID,
+			From:      history.From.String(),
+			To:        history.To.String(),
+			Reason:    history.Reason,
+			CreatedAt: history.CreatedAt,
+		})
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success get status history enterprise", res)
+}
+
 // GetEnterpriseByStatus godoc
 // @Summary Get list enterprise by status
-// @Description status draft and publish
+// @Description status draft, submitted, published, rejected, suspended or archived
 // @Tags Enterprise
 // @accept json
 // @Produce json
@@ -134,12 +177,8 @@ func (e enterpriseController) UpdateStatusEnterprise(c echo.Context) error {
 // @Security JWT
 func (e enterpriseController) GetEnterpriseByStatus(c echo.Context) error {
 	statusParam := c.Param("status")
-	var status int
-	if statusParam == "draft" {
-		status = 0
-	} else if statusParam == "publish" {
-		status = 1
-	} else {
+	status, err := domain.ParseEnterpriseStatus(statusParam)
+	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, "setting status not found")
 	}
 
@@ -160,13 +199,14 @@ func (e enterpriseController) GetEnterpriseByStatus(c echo.Context) error {
 			Address:     enterprise.Address,
 			Postcode:    enterprise.Postcode,
 			Description: enterprise.Description,
-			Status:      enterprise.Status,
+			Status:      int(enterprise.Status),
 			Tags:        enterprise.Tags,
 			UpdatedAt:   enterprise.UpdatedAt,
 			CreatedAt:   enterprise.CreatedAt,
 			Latitude:    enterprise.Latitude,
 			Longitude:   enterprise.Longitude,
 			Rating:      math.Round(rating*100) / 100,
+			Images:      helper.EnterpriseImages(enterprise.Images),
 		})
 	}
 
@@ -184,6 +224,8 @@ func (e enterpriseController) GetEnterpriseByStatus(c echo.Context) error {
 // @param data body request.CreateEnterpriseRequest true "required"
 // @Success 201 {object} response.JSONSuccessResult{data=interface{}}
 // @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (e enterpriseController) UpdateEnterpriseByID(c echo.Context) error {
 	var req request.CreateEnterpriseRequest
@@ -192,14 +234,13 @@ func (e enterpriseController) UpdateEnterpriseByID(c echo.Context) error {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
 
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-
-	userid := claims["UserID"].(string)
+	if _, err := helper.GetAuthClaims(c); err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
 
-	_, err := e.enterpriseUsecase.UpdateEnterpriseByID(id, userid, req)
+	_, err := e.enterpriseUsecase.UpdateEnterpriseByID(id, helper.GetActor(c), req)
 	if err != nil {
-		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
 	}
 
 	enterprise, err := e.enterpriseUsecase.GetDetailEnterpriseByID(id)
@@ -216,24 +257,39 @@ func (e enterpriseController) UpdateEnterpriseByID(c echo.Context) error {
 // @accept json
 // @Produce json
 // @Router /enterprises [get]
-// @Param search query string false "search by name"
-// @Param page query int false "page"
-// @Param length query int false "length"
-// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Param search query string false "words of the name, description, address or tags; typos are tolerated and results are sorted by relevance unless sort is set"
+// @Param cursor query string false "next_cursor or prev_cursor of the previous response"
+// @Param length query int false "page length, default 20, at most 100"
+// @Param with_total query bool false "also count every enterprise matching the filters"
+// @Param tags query string false "comma separated tag ids"
+// @Param tag_match query string false "any (default) or all of the tags"
+// @Param status query string false "status name"
+// @Param postcode query int false "postcode"
+// @Param owner query string false "owner user id"
+// @Param min_rating query number false "minimum average rating"
+// @Param created_from query string false "created on or after, YYYY-MM-DD"
+// @Param created_to query string false "created on or before, YYYY-MM-DD"
+// @Param sort query string false "name, rating, newest, distance or relevance; distance leaves out enterprises without a location, relevance needs search"
+// @Param lat query number false "latitude, needed to sort by distance"
+// @Param lon query number false "longitude, needed to sort by distance"
+// @Success 200 {object} response.JSONSuccessListResult{data=[]response.GetListByStatusResponse,metadata=response.PageMetadata}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Security JWT
 func (e enterpriseController) GetAllEnterprises(c echo.Context) error {
-	search := c.QueryParam("search")
-	length, _ := strconv.Atoi(c.QueryParam("length"))
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	enterprises, totalData, err := e.enterpriseUsecase.GetListAllEnterprise(search, page, length)
-	var pageCount int
-	if length == 0 {
-		pageCount = int(math.Ceil(float64(totalData) / float64(len(enterprises))))
-	} else {
-		pageCount = int(math.Ceil(float64(totalData) / float64(length)))
+	req, err := listEnterpriseRequest(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if val, err := request.ValidateListEnterprise(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
 
+	page, err := helper.GetPageRequest(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	enterprises, info, err := e.enterpriseUsecase.GetListAllEnterprise(req, page)
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
@@ -250,31 +306,78 @@ func (e enterpriseController) GetAllEnterprises(c echo.Context) error {
 			Address:     enterprise.Address,
 			Postcode:    enterprise.Postcode,
 			Description: enterprise.Description,
-			Status:      enterprise.Status,
+			Status:      int(enterprise.Status),
 			Tags:        enterprise.Tags,
 			UpdatedAt:   enterprise.UpdatedAt,
 			CreatedAt:   enterprise.CreatedAt,
 			Latitude:    enterprise.Latitude,
 			Longitude:   enterprise.Longitude,
 			Rating:      math.Round(rating*100) / 100,
+			Images:      helper.EnterpriseImages(enterprise.Images),
 		})
 	}
 
-	if page == 0 {
-		page = 1
+	return response.SuccessListResponse(c, http.StatusOK, true, "success get list enterprises", res, helper.PageMetadata(info, len(enterprises)))
+}
+
+// listEnterpriseRequest reads the query of GetAllEnterprises, rejecting values that do not parse.
+func listEnterpriseRequest(c echo.Context) (request.ListEnterpriseRequest, error) {
+	req := request.ListEnterpriseRequest{
+		Search:   c.QueryParam("search"),
+		TagMatch: c.QueryParam("tag_match"),
+		Status:   c.QueryParam("status"),
+		OwnerID:  c.QueryParam("owner"),
+		Sort:     c.QueryParam("sort"),
+	}
+	for _, tag := range strings.Split(c.QueryParam("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			req.Tags = append(req.Tags, tag)
+		}
+	}
+
+	if c.QueryParam("postcode") != "" {
+		postcode, err := strconv.Atoi(c.QueryParam("postcode"))
+		if err != nil {
+			return req, errors.New("postcode invalid")
+		}
+		req.Postcode = postcode
 	}
-	metadata := struct {
-		Length    int `json:"length"`
-		Page      int `json:"page"`
-		PageCount int `json:"page_count"`
-		TotalData int `json:"total_data"`
-	}{
-		Length:    len(enterprises),
-		Page:      page,
-		PageCount: pageCount,
-		TotalData: totalData,
+	if c.QueryParam("min_rating") != "" {
+		minRating, err := strconv.ParseFloat(c.QueryParam("min_rating"), 64)
+		if err != nil {
+			return req, errors.New("min_rating invalid")
+		}
+		req.MinRating = minRating
+	}
+	floats := []struct {
+		name  string
+		value **float64
+	}{{"lat", &req.Latitude}, {"lon", &req.Longitude}}
+	for _, param := range floats {
+		if c.QueryParam(param.name) == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(c.QueryParam(param.name), 64)
+		if err != nil {
+			return req, errors.New(param.name + " invalid")
+		}
+		*param.value = &parsed
+	}
+	dates := []struct {
+		name  string
+		value **time.Time
+	}{{"created_from", &req.CreatedFrom}, {"created_to", &req.CreatedTo}}
+	for _, param := range dates {
+		if c.QueryParam(param.name) == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", c.QueryParam(param.name))
+		if err != nil {
+			return req, errors.New(param.name + " invalid, use YYYY-MM-DD")
+		}
+		*param.value = &parsed
 	}
-	return response.SuccessListResponse(c, http.StatusOK, true, "success get list enterprises", res, metadata)
+	return req, nil
 }
 
 // DeleteEnterpriseByID godoc
@@ -288,33 +391,19 @@ func (e enterpriseController) GetAllEnterprises(c echo.Context) error {
 // @Success 200 {object} response.JSONSuccessDeleteResult{}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (e enterpriseController) DeleteEnterpriseByID(c echo.Context) error {
 	id := c.Param("id")
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-	userID := claims["UserID"].(string)
-
-	isAdmin, err := e.authUsecase.CheckIfUserIsAdmin(userID)
-	if err != nil {
-		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	if _, err := helper.GetAuthClaims(c); err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
 	}
 
-	enterprise, err := e.enterpriseUsecase.GetDetailEnterpriseByID(id)
+	err := e.enterpriseUsecase.DeleteEnterpriseByID(id, helper.GetActor(c))
 	if err != nil {
-		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
 	}
-
-	if isAdmin || enterprise.UserID.String() == userID {
-		err := e.enterpriseUsecase.DeleteEnterpriseByID(id)
-		if err != nil {
-			return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
-		}
-
-		return response.SuccessDeleteResponse(c, http.StatusOK, true, "success delete enterprise")
-	}
-
-	return response.FailResponse(c, http.StatusUnauthorized, false, "not current user or admin")
+	return response.SuccessDeleteResponse(c, http.StatusOK, true, "success delete enterprise")
 }
 
 // GetDetailEnterpriseByID godoc
@@ -345,13 +434,14 @@ func (e enterpriseController) GetDetailEnterpriseByID(c echo.Context) error {
 		Address:     enterprise.Address,
 		Postcode:    enterprise.Postcode,
 		Description: enterprise.Description,
-		Status:      enterprise.Status,
+		Status:      int(enterprise.Status),
 		Tags:        enterprise.Tags,
 		UpdatedAt:   enterprise.UpdatedAt,
 		CreatedAt:   enterprise.CreatedAt,
 		Latitude:    enterprise.Latitude,
 		Longitude:   enterprise.Longitude,
 		Rating:      math.Round(rating*100) / 100,
+		Images:      helper.EnterpriseImages(enterprise.Images),
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, true, "success get detail enterprise", res)
@@ -359,49 +449,151 @@ func (e enterpriseController) GetDetailEnterpriseByID(c echo.Context) error {
 
 // GetDistance godoc
 // @Summary Get distance
-// @Description get distance from you to enterprise
+// @Description get great-circle distance from you to enterprise
 // @Tags Enterprise
 // @accept json
 // @Produce json
 // @Router /enterprise/{id}/distance [get]
 // @Param id path string true "enterprise id"
-// @Param longitude query string true "longitude"
-// @Param latitude query string true "latitude"
-// @Success 200 {object} response.JSONSuccessResult{}
+// @Param longitude query number true "longitude"
+// @Param latitude query number true "latitude"
+// @Param unit query string false "unit" Enums(km, mi)
+// @Success 200 {object} response.JSONSuccessResult{data=response.DistanceResponse}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Security JWT
 func (e enterpriseController) GetDistance(c echo.Context) error {
-	longitude := c.QueryParam("longitude")
-	latitude := c.QueryParam("latitude")
 	id := c.Param("id")
+	latitude, err := strconv.ParseFloat(c.QueryParam("latitude"), 64)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, "latitude invalid")
+	}
+	longitude, err := strconv.ParseFloat(c.QueryParam("longitude"), 64)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, "longitude invalid")
+	}
 
-	enterprise, err := e.enterpriseUsecase.GetDetailEnterpriseByID(id)
+	unit := c.QueryParam("unit")
+	if unit == "" {
+		unit = "km"
+	}
+
+	req := request.DistanceRequest{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Unit:      unit,
+	}
+	if val, err := request.ValidateDistance(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	distance, enterprise, err := e.enterpriseUsecase.GetDistanceEnterprise(id, req)
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
 
-	urlPath := "https://geo-services-by-mvpc-com.p.rapidapi.com/distance?locationB=" + url.QueryEscape(latitude+","+longitude) + "&locationA=" + url.QueryEscape(enterprise.Latitude+","+enterprise.Longitude) + "&unit=kms"
-	req, _ := http.NewRequest("GET", urlPath, nil)
-	req.Header.Add("X-RapidAPI-Host", "geo-services-by-mvpc-com.p.rapidapi.com")
-	req.Header.Add("X-RapidAPI-Key", "f0e1ed1db2mshab6b204e17c54edp12a883jsne3b08398fedc")
-	res, _ := http.DefaultClient.Do(req)
+	rating := e.ratingUsecase.GetAverageRatingEnterprise(enterprise.ID.String())
+	res := response.DistanceResponse{
+		Distance: math.Round(distance*100) / 100,
+		Unit:     req.Unit,
+		Enterprise: response.GetListByStatusResponse{
+			ID:          enterprise.ID,
+			Name:        enterprise.Name,
+			NumberPhone: enterprise.NumberPhone,
+			UserID:      enterprise.UserID,
+			Address:     enterprise.Address,
+			Postcode:    enterprise.Postcode,
+			Description: enterprise.Description,
+			Status:      int(enterprise.Status),
+			Tags:        enterprise.Tags,
+			UpdatedAt:   enterprise.UpdatedAt,
+			CreatedAt:   enterprise.CreatedAt,
+			Latitude:    enterprise.Latitude,
+			Longitude:   enterprise.Longitude,
+			Rating:      math.Round(rating*100) / 100,
+			Images:      helper.EnterpriseImages(enterprise.Images),
+		},
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success get distance enterprise", res)
+}
 
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
+// GetNearbyEnterprises godoc
+// @Summary Get nearby enterprises
+// @Description get published enterprises within radius sorted by distance (km)
+// @Tags Enterprise
+// @accept json
+// @Produce json
+// @Router /enterprises/nearby [get]
+// @Param lat query number true "latitude"
+// @Param lon query number true "longitude"
+// @Param radius_km query number false "radius in km, default 5, max 100"
+// @Param tags query string false "comma separated tag ids"
+// @Success 200 {object} response.JSONSuccessResult{data=[]response.GetListByStatusResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Security JWT
+func (e enterpriseController) GetNearbyEnterprises(c echo.Context) error {
+	latitude, err := strconv.ParseFloat(c.QueryParam("lat"), 64)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, "lat invalid")
+	}
+	longitude, err := strconv.ParseFloat(c.QueryParam("lon"), 64)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, "lon invalid")
+	}
+	radius := float64(5)
+	if c.QueryParam("radius_km") != "" {
+		radius, err = strconv.ParseFloat(c.QueryParam("radius_km"), 64)
 		if err != nil {
-			//panic(err)
+			return response.FailResponse(c, http.StatusBadRequest, false, "radius_km invalid")
 		}
-	}(res.Body)
-	body, _ := ioutil.ReadAll(res.Body)
-	var resBody interface{}
-	err = json.Unmarshal(body, &resBody)
+	}
+	var tags []string
+	for _, tag := range strings.Split(c.QueryParam("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	req := request.NearbyRequest{
+		Latitude:  latitude,
+		Longitude: longitude,
+		RadiusKm:  radius,
+		Tags:      tags,
+	}
+	if val, err := request.ValidateNearby(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	enterprises, err := e.enterpriseUsecase.GetNearbyEnterprises(req)
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
-	return response.SuccessResponse(c, http.StatusOK, true, "success get distance enterprise", map[string]interface{}{
-		"distance":   resBody.(map[string]interface{})["data"],
-		"enterprise": enterprise,
-	})
+
+	res := []response.GetListByStatusResponse{}
+	for _, enterprise := range enterprises {
+		rating := e.ratingUsecase.GetAverageRatingEnterprise(enterprise.ID.String())
+		distance := math.Round(enterprise.Distance*100) / 100
+		res = append(res, response.GetListByStatusResponse{
+			ID:          enterprise.ID,
+			Name:        enterprise.Name,
+			NumberPhone: enterprise.NumberPhone,
+			UserID:      enterprise.UserID,
+			Address:     enterprise.Address,
+			Postcode:    enterprise.Postcode,
+			Description: enterprise.Description,
+			Status:      int(enterprise.Status),
+			Tags:        enterprise.Tags,
+			UpdatedAt:   enterprise.UpdatedAt,
+			CreatedAt:   enterprise.CreatedAt,
+			Latitude:    enterprise.Latitude,
+			Longitude:   enterprise.Longitude,
+			Rating:      math.Round(rating*100) / 100,
+			Distance:    &distance,
+			Images:      helper.EnterpriseImages(enterprise.Images),
+		})
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success get nearby enterprises", res)
 }
 
 // AddNewRanting godoc
@@ -418,9 +610,11 @@ func (e enterpriseController) GetDistance(c echo.Context) error {
 // @Security JWT
 func (e enterpriseController) AddNewRanting(c echo.Context) error {
 	enterpriseid := c.Param("id")
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-	userid := claims["UserID"].(string)
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	userid := claims.UserID()
 	value, _ := strconv.Atoi(c.QueryParam("value"))
 
 	isRating, _ := e.ratingUsecase.FindRating(enterpriseid, userid)
@@ -475,94 +669,90 @@ func (e enterpriseController) CekRatingUser(c echo.Context) error {
 	})
 }
 
+// ratingUser returns whose rating a deletion is about: the userid query param when it is sent,
+// otherwise the authenticated user. The usecase decides whether the actor may delete it.
+func ratingUser(c echo.Context) (string, error) {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return "", err
+	}
+	if userid := c.QueryParam("userid"); userid != "" {
+		return userid, nil
+	}
+	return claims.UserID(), nil
+}
+
 // DeleteRatingUser godoc
 // @Summary Remove rating
-// @Description remove rating user
+// @Description remove the own rating of an enterprise, users with review:moderate can remove someone else's rating with userid
 // @Tags Rating
 // @accept json
 // @Produce json
-// @Router /enterprise/{id}/rating/user/{userid} [delete]
+// @Router /enterprise/{id}/rating [delete]
 // @param id path string true "enterprise id"
-// @param userid path string true "user id"
+// @Param userid query string false "user id of the rating, defaults to the authenticated user"
 // @Success 200 {object} response.JSONSuccessDeleteResult{}
-// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Failure 404 {object} response.JSONBadRequestResult{}
 // @Security JWT
 func (e enterpriseController) DeleteRatingUser(c echo.Context) error {
+	userid, err := ratingUser(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
 	id := c.Param("id")
-	userid := c.Param("userid")
 
 	rating, err := e.ratingUsecase.FindRating(id, userid)
-	if err != nil {
-		return response.FailResponse(c, http.StatusNotFound, false, err.Error())
+	if err != nil || rating.ID == uuid.Nil {
+		return response.FailResponse(c, http.StatusNotFound, false, "rating not found")
 	}
-	if rating.ID == uuid.FromStringOrNil("") {
-		return response.FailResponse(c, http.StatusNotFound, false, err.Error())
-	}
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-	isAdmin, err := e.authUsecase.CheckIfUserIsAdmin(claims["UserID"].(string))
-	if err != nil {
-		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
-	}
-
-	if isAdmin || rating.UserID.String() == claims["UserID"].(string) {
-		err = e.ratingUsecase.DeleteRating(id, userid)
-		if err != nil {
-			return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
-		}
 
-		return response.SuccessDeleteResponse(c, http.StatusOK, true, "success remove rating")
+	err = e.ratingUsecase.DeleteRating(id, userid, helper.GetActor(c))
+	if err != nil {
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
 	}
-
-	return response.FailResponse(c, http.StatusBadRequest, false, "not current user")
+	return response.SuccessDeleteResponse(c, http.StatusOK, true, "success remove rating")
 }
 
 // UpdateRating godoc
 // @Summary Update rating
-// @Description update rating
+// @Description update the own rating of an enterprise
 // @Tags Rating
 // @accept json
 // @Produce json
-// @Router /enterprise/{id}/rating/user/{userid} [put]
+// @Router /enterprise/{id}/rating [put]
 // @param id path string true "enterprise id"
-// @param userid path string true "user id"
 // @param value query int true "value"
-// @Success 201 {object} response.JSONSuccessResult{data=interface{}}
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
 // @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 404 {object} response.JSONBadRequestResult{}
 // @Security JWT
 func (e enterpriseController) UpdateRating(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
 	id := c.Param("id")
-	userid := c.Param("userid")
+	userid := claims.UserID()
 	value, _ := strconv.Atoi(c.QueryParam("value"))
 
 	rating, err := e.ratingUsecase.FindRating(id, userid)
-	if err != nil {
-		return response.FailResponse(c, http.StatusNotFound, false, err.Error())
-	}
-	if rating.ID == uuid.FromStringOrNil("") {
-		return response.FailResponse(c, http.StatusNotFound, false, err.Error())
-	}
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-	isAdmin, err := e.authUsecase.CheckIfUserIsAdmin(claims["UserID"].(string))
-	if err != nil {
-		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	if err != nil || rating.ID == uuid.Nil {
+		return response.FailResponse(c, http.StatusNotFound, false, "rating not found")
 	}
 
-	if isAdmin || rating.UserID.String() == claims["UserID"].(string) {
-		_, err := e.ratingUsecase.UpdateRating(id, userid, value)
-		if err != nil {
-			return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
-		}
-
-		resRating, _ := e.ratingUsecase.FindRating(id, userid)
-		ratings := e.ratingUsecase.GetAverageRatingEnterprise(id)
-		finalRating := math.Round(ratings*100) / 100
-		return response.SuccessResponse(c, http.StatusOK, true, "success update rating", map[string]interface{}{
-			"rating":         resRating,
-			"rating_average": finalRating,
-		})
+	_, err = e.ratingUsecase.UpdateRating(id, userid, helper.GetActor(c), value)
+	if err != nil {
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
 	}
 
-	return response.FailResponse(c, http.StatusBadRequest, false, "not current user")
+	resRating, _ := e.ratingUsecase.FindRating(id, userid)
+	ratings := e.ratingUsecase.GetAverageRatingEnterprise(id)
+	finalRating := math.Round(ratings*100) / 100
+	return response.SuccessResponse(c, http.StatusOK, true, "success update rating", map[string]interface{}{
+		"rating":         resRating,
+		"rating_average": finalRating,
+	})
 }
@@ -63,6 +63,8 @@ var dummyUser = domain.Users{
 	},
 }
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = domain.Enterprises{
 	domain.Enterprise{
 		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
@@ -71,8 +73,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Status:      0,
 		Tags: domain.Tags{
@@ -93,8 +95,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone:      "0012798232",
 		Address:          "bjm",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -120,15 +122,19 @@ var dummyRating = domain.RatingEnterprises{
 	},
 }
 
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+
 func createToken() string {
-	jwtSetToken := helper.NewGoJWT()
+	jwtSetToken := helper.NewGoJWT(keySet, "test", "test")
 	token := jwtSetToken.CreateTokenJWT(&dummyUser[0])
 	return token
 }
 
 func middlewareToken(handlerFunc echo.HandlerFunc, c echo.Context) error {
 	err := middleware.JWTWithConfig(middleware.JWTConfig{
-		SigningKey: []byte("220220"),
+		KeyFunc: keySet.Keyfunc,
+		Claims:  &helper.JWTClaims{},
 	})(handlerFunc)(c)
 	return err
 }
@@ -161,8 +167,8 @@ func TestEnterpriseController_CreateNewEnterprise(t *testing.T) {
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 	}
@@ -236,6 +242,23 @@ func TestEnterpriseController_GetEnterpriseByStatus(t *testing.T) {
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
 
+	t.Run("get submitted list", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/submitted", true, true)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "enterprise/:status")
+		c.SetParamNames("status")
+		c.SetParamValues("submitted")
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		mockEnterpriseUsecase.On("GetListEnterpriseByStatus", domain.EnterpriseSubmitted).Return(domain.Enterprises{dummyEnterprise[0]}, nil).Once()
+		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
+		err := middlewareToken(enterpriseController.GetEnterpriseByStatus, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, int(responseBody["code"].(float64)))
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
 	t.Run("get publish list", func(t *testing.T) {
 		e := echo.New()
 		req, rec := makeRequestHttp("", echo.GET, "/enterprise/publish", true, true)
@@ -293,14 +316,13 @@ func TestEnterpriseController_UpdateStatusEnterprise(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status?status=1", true, true)
+		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status?status=published", true, true)
 		c := e.NewContext(req, rec)
 		c.SetPath(base_path + "enterprise/:id/status")
 		c.SetParamNames("id")
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockEnterpriseUsecase.On("UpdateStatusEnterprise", mock.Anything, mock.Anything).Return(dummyEnterprise[0], nil).Once()
+		mockEnterpriseUsecase.On("UpdateStatusEnterprise", mock.Anything, mock.Anything, mock.Anything).Return(dummyEnterprise[0], nil).Once()
 		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[0], nil).Once()
 		err := middlewareToken(enterpriseController.UpdateStatusEnterprise, c)
 		responseBody := parseResponse(rec)
@@ -310,31 +332,34 @@ func TestEnterpriseController_UpdateStatusEnterprise(t *testing.T) {
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
 
-	t.Run("not admin", func(t *testing.T) {
+	t.Run("status from body", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status?status=1", true, true)
+		req, rec := makeRequestHttp(`{"status":"rejected","reason":"address incomplete"}`, echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status", true, true)
 		c := e.NewContext(req, rec)
 		c.SetPath(base_path + "enterprise/:id/status")
 		c.SetParamNames("id")
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(bool(true), errors.New("error something")).Once()
+		mockEnterpriseUsecase.On("UpdateStatusEnterprise", dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, request.UpdateStatusRequest{
+			Status: "rejected", Reason: "address incomplete",
+		}).Return(dummyEnterprise[0], nil).Once()
+		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[0], nil).Once()
 		err := middlewareToken(enterpriseController.UpdateStatusEnterprise, c)
-		_ = parseResponse(rec)
+		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
+		assert.Equal(t, 200, int(responseBody["code"].(float64)))
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
 
 	t.Run("failed update status", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status?status=1", true, true)
+		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status?status=published", true, true)
 		c := e.NewContext(req, rec)
 		c.SetPath(base_path + "enterprise/:id/status")
 		c.SetParamNames("id")
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockEnterpriseUsecase.On("UpdateStatusEnterprise", mock.Anything, mock.Anything).Return(domain.Enterprise{}, errors.New("error something")).Once()
+		mockEnterpriseUsecase.On("UpdateStatusEnterprise", mock.Anything, mock.Anything, mock.Anything).Return(domain.Enterprise{}, errors.New("error something")).Once()
 		err := middlewareToken(enterpriseController.UpdateStatusEnterprise, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
@@ -342,16 +367,31 @@ func TestEnterpriseController_UpdateStatusEnterprise(t *testing.T) {
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
 
+	t.Run("forbidden status change", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status?status=published", true, true)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "enterprise/:id/status")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		mockEnterpriseUsecase.On("UpdateStatusEnterprise", mock.Anything, mock.Anything, mock.Anything).Return(domain.Enterprise{}, domain.NewForbiddenError("not allowed to change status from submitted to published")).Once()
+		err := middlewareToken(enterpriseController.UpdateStatusEnterprise, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 403, int(responseBody["code"].(float64)))
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
 	t.Run("Failed get detail", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status?status=1", true, true)
+		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status?status=published", true, true)
 		c := e.NewContext(req, rec)
 		c.SetPath(base_path + "enterprise/:id/status")
 		c.SetParamNames("id")
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockEnterpriseUsecase.On("UpdateStatusEnterprise", mock.Anything, mock.Anything).Return(dummyEnterprise[0], nil).Once()
+		mockEnterpriseUsecase.On("UpdateStatusEnterprise", mock.Anything, mock.Anything, mock.Anything).Return(dummyEnterprise[0], nil).Once()
 		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(domain.Enterprise{}, errors.New("error something")).Once()
 		err := middlewareToken(enterpriseController.UpdateStatusEnterprise, c)
 		responseBody := parseResponse(rec)
@@ -362,6 +402,67 @@ func TestEnterpriseController_UpdateStatusEnterprise(t *testing.T) {
 
 }
 
+func TestEnterpriseController_GetStatusHistories(t *testing.T) {
+	mockEnterpriseUsecase := new(mocks.EnterpriseUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	actor := domain.Actor{UserID: dummyUser[0].ID.String()}
+	histories := domain.EnterpriseStatusHistories{
+		{ID: uuid.NewV4(), EnterpriseID: dummyEnterprise[0].ID, ActorID: dummyUser[0].ID, From: domain.EnterpriseSubmitted, To: domain.EnterpriseRejected, Reason: "address incomplete"},
+	}
+
+	t.Run("success owner", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status/history", true, false)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "enterprise/:id/status/history")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		mockEnterpriseUsecase.On("GetStatusHistories", dummyEnterprise[0].ID.String(), actor).Return(histories, nil).Once()
+		err := middlewareToken(enterpriseController.GetStatusHistories, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, int(responseBody["code"].(float64)))
+		history := responseBody["data"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "submitted", history["from"])
+		assert.Equal(t, "rejected", history["to"])
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("not owner and not moderator", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[1].ID.String()+"/status/history", true, false)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "enterprise/:id/status/history")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[1].ID.String())
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		mockEnterpriseUsecase.On("GetStatusHistories", dummyEnterprise[1].ID.String(), actor).Return(nil, domain.NewForbiddenError("only the owner or users with enterprise:publish can see status history")).Once()
+		err := middlewareToken(enterpriseController.GetStatusHistories, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 403, int(responseBody["code"].(float64)))
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("failed get histories", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"/status/history", true, false)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "enterprise/:id/status/history")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		mockEnterpriseUsecase.On("GetStatusHistories", dummyEnterprise[0].ID.String(), actor).Return(nil, errors.New("error something")).Once()
+		err := middlewareToken(enterpriseController.GetStatusHistories, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, int(responseBody["code"].(float64)))
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+}
+
 func TestEnterpriseController_UpdateEnterpriseByID(t *testing.T) {
 	mockEnterpriseUsecase := new(mocks.EnterpriseUsecase)
 	mockRatingUsecase := new(mocks.RatingUsecase)
@@ -371,8 +472,8 @@ func TestEnterpriseController_UpdateEnterpriseByID(t *testing.T) {
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 	}
@@ -449,7 +550,7 @@ func TestEnterpriseController_GetAllEnterprises(t *testing.T) {
 		req, rec := makeRequestHttp("", echo.GET, "/enterprises?search=&length=1&page=1", true, true)
 		c := e.NewContext(req, rec)
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockEnterpriseUsecase.On("GetListAllEnterprise", mock.Anything, mock.Anything, mock.Anything).Return(domain.Enterprises{dummyEnterprise[0]}, 1, nil).Once()
+		mockEnterpriseUsecase.On("GetListAllEnterprise", mock.Anything, mock.Anything).Return(domain.Enterprises{dummyEnterprise[0]}, domain.PageInfo{}, nil).Once()
 		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
 		err := middlewareToken(enterpriseController.GetAllEnterprises, c)
 		responseBody := parseResponse(rec)
@@ -464,7 +565,7 @@ func TestEnterpriseController_GetAllEnterprises(t *testing.T) {
 		req, rec := makeRequestHttp("", echo.GET, "/enterprises?search=&length=1&page=1", true, true)
 		c := e.NewContext(req, rec)
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockEnterpriseUsecase.On("GetListAllEnterprise", mock.Anything, mock.Anything, mock.Anything).Return(domain.Enterprises{}, 1, nil).Once()
+		mockEnterpriseUsecase.On("GetListAllEnterprise", mock.Anything, mock.Anything).Return(domain.Enterprises{}, domain.PageInfo{}, nil).Once()
 		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
 		err := middlewareToken(enterpriseController.GetAllEnterprises, c)
 		responseBody := parseResponse(rec)
@@ -477,7 +578,7 @@ func TestEnterpriseController_GetAllEnterprises(t *testing.T) {
 		req, rec := makeRequestHttp("", echo.GET, "/enterprises?search=&length=1&page=1", true, true)
 		c := e.NewContext(req, rec)
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockEnterpriseUsecase.On("GetListAllEnterprise", mock.Anything, mock.Anything, mock.Anything).Return(domain.Enterprises{}, 1, errors.New("error something")).Once()
+		mockEnterpriseUsecase.On("GetListAllEnterprise", mock.Anything, mock.Anything).Return(domain.Enterprises{}, domain.PageInfo{}, errors.New("error something")).Once()
 		err := middlewareToken(enterpriseController.GetAllEnterprises, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
@@ -485,177 +586,365 @@ func TestEnterpriseController_GetAllEnterprises(t *testing.T) {
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
 
-	t.Run("get list page 0", func(t *testing.T) {
+	t.Run("cursor", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.GET, "/enterprises?search=&length=1&page=0", true, true)
+		cursor := domain.Cursor{Key: "enterprise satu", ID: dummyEnterprise[0].ID.String()}
+		req, rec := makeRequestHttp("", echo.GET, "/enterprises?sort=name&length=1&with_total=true&cursor="+cursor.Encode(), true, true)
 		c := e.NewContext(req, rec)
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockEnterpriseUsecase.On("GetListAllEnterprise", mock.Anything, mock.Anything, mock.Anything).Return(domain.Enterprises{dummyEnterprise[0]}, 1, nil).Once()
+		total := 3
+		next := domain.Cursor{Key: "enterprise dua", ID: dummyEnterprise[1].ID.String()}
+		mockEnterpriseUsecase.On("GetListAllEnterprise", request.ListEnterpriseRequest{Sort: "name"}, domain.PageRequest{Cursor: &cursor, Length: 1, WithTotal: true}).
+			Return(domain.Enterprises{dummyEnterprise[1]}, domain.PageInfo{Next: &next, Total: &total}, nil).Once()
 		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
 		err := middlewareToken(enterpriseController.GetAllEnterprises, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, 200, int(responseBody["code"].(float64)))
+		metadata := responseBody["metadata"].(map[string]interface{})
+		assert.Equal(t, next.Encode(), metadata["next_cursor"])
+		assert.Nil(t, metadata["prev_cursor"])
+		assert.Equal(t, float64(3), metadata["total_data"])
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprises?tags=35d6a9a1-aa5e-41f1-9991-08878dfdf89a&tag_match=all&status=published&min_rating=4&created_from=2022-05-01&sort=rating", true, true)
+		c := e.NewContext(req, rec)
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+		mockEnterpriseUsecase.On("GetListAllEnterprise", request.ListEnterpriseRequest{
+			Tags:        []string{"35d6a9a1-aa5e-41f1-9991-08878dfdf89a"},
+			TagMatch:    "all",
+			Status:      "published",
+			MinRating:   4,
+			CreatedFrom: &from,
+			Sort:        "rating",
+		}, domain.PageRequest{Length: 20}).Return(domain.Enterprises{dummyEnterprise[0]}, domain.PageInfo{}, nil).Once()
+		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(4), nil).Once()
+		err := middlewareToken(enterpriseController.GetAllEnterprises, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, int(responseBody["code"].(float64)))
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
+
+	invalid := []struct {
+		name  string
+		query string
+	}{
+		{"length too big", "length=1000"},
+		{"length zero", "length=0"},
+		{"bad cursor", "cursor=satu"},
+		{"bad with_total", "with_total=maybe"},
+		{"bad tag match", "tags=35d6a9a1-aa5e-41f1-9991-08878dfdf89a&tag_match=some"},
+		{"bad tag id", "tags=kuliner"},
+		{"bad owner", "owner=me"},
+		{"bad min rating", "min_rating=6"},
+		{"bad date", "created_from=01-05-2022"},
+		{"reversed dates", "created_from=2022-05-02&created_to=2022-05-01"},
+		{"unknown sort", "sort=popular"},
+		{"distance without location", "sort=distance"},
+		{"distance from NaN", "sort=distance&lat=NaN&lon=114.740106"},
+		{"distance from infinity", "sort=distance&lat=-3.442821&lon=Inf"},
+		{"relevance without search", "sort=relevance"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req, rec := makeRequestHttp("", echo.GET, "/enterprises?"+tt.query, true, true)
+			c := e.NewContext(req, rec)
+			enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, new(mocks.EnterpriseUsecase), mockRatingUsecase)
+			err := middlewareToken(enterpriseController.GetAllEnterprises, c)
+			responseBody := parseResponse(rec)
+			assert.NoError(t, err)
+			assert.Equal(t, 400, int(responseBody["code"].(float64)))
+		})
+	}
 }
 
 func TestEnterpriseController_DeleteEnterpriseByID(t *testing.T) {
+	actor := domain.Actor{UserID: dummyUser[0].ID.String()}
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"success", nil, 200},
+		{"forbidden", domain.NewForbiddenError("only the owner or users with enterprise:manage can delete enterprise"), 403},
+		{"error delete", errors.New("error something"), 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEnterpriseUsecase := new(mocks.EnterpriseUsecase)
+			e := echo.New()
+			req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
+			c := e.NewContext(req, rec)
+			c.SetPath(base_path + "enterprise/:id")
+			c.SetParamNames("id")
+			c.SetParamValues(dummyEnterprise[0].ID.String())
+			enterpriseController := http2.NewEnterpriseController(new(mocks.AuthUsecase), mockEnterpriseUsecase, new(mocks.RatingUsecase))
+			mockEnterpriseUsecase.On("DeleteEnterpriseByID", dummyEnterprise[0].ID.String(), actor).Return(tt.err).Once()
+			err := middlewareToken(enterpriseController.DeleteEnterpriseByID, c)
+			responseBody := parseResponse(rec)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, int(responseBody["code"].(float64)))
+			mockEnterpriseUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEnterpriseController_GetDetailEnterpriseByID(t *testing.T) {
 	mockEnterpriseUsecase := new(mocks.EnterpriseUsecase)
 	mockRatingUsecase := new(mocks.RatingUsecase)
 	mockAuthUsecase := new(mocks.AuthUsecase)
-
 	t.Run("success", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
 		c := e.NewContext(req, rec)
 		c.SetPath(base_path + "enterprise/:id")
 		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
 		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[0], nil).Once()
-		mockEnterpriseUsecase.On("DeleteEnterpriseByID", mock.Anything).Return(nil).Once()
-		err := middlewareToken(enterpriseController.DeleteEnterpriseByID, c)
+		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
+		err := middlewareToken(enterpriseController.GetDetailEnterpriseByID, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, 200, int(responseBody["code"].(float64)))
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
-
-	t.Run("not current user", func(t *testing.T) {
+	t.Run("error not found enterprise", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
 		c := e.NewContext(req, rec)
 		c.SetPath(base_path + "enterprise/:id")
 		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(false, nil).Once()
 		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[1], nil).Once()
-		err := middlewareToken(enterpriseController.DeleteEnterpriseByID, c)
+		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
+		err := middlewareToken(enterpriseController.GetDetailEnterpriseByID, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
-		assert.Equal(t, 401, int(responseBody["code"].(float64)))
+		assert.Equal(t, 404, int(responseBody["code"].(float64)))
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
+}
 
-	t.Run("error checking admin", func(t *testing.T) {
+func TestEnterpriseController_GetDistance(t *testing.T) {
+	mockEnterpriseUsecase := new(mocks.EnterpriseUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+	mockAuthUsecase := new(mocks.AuthUsecase)
+
+	t.Run("success", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"/distance?longitude=114.740106&latitude=-3.442821", true, true)
 		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id")
+		c.SetPath(base_path + "enterprise/:id/distance")
 		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(false, errors.New("error something")).Once()
-		err := middlewareToken(enterpriseController.DeleteEnterpriseByID, c)
+		mockEnterpriseUsecase.On("GetDistanceEnterprise", mock.Anything, request.DistanceRequest{
+			Latitude: -3.442821, Longitude: 114.740106, Unit: "km",
+		}).Return(21.784, dummyEnterprise[0], nil).Once()
+		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3)).Once()
+		err := middlewareToken(enterpriseController.GetDistance, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, int(responseBody["code"].(float64)))
+		data := responseBody["data"].(map[string]interface{})
+		assert.Equal(t, 21.78, data["distance"])
+		assert.Equal(t, "km", data["unit"])
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("success in miles", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"/distance?longitude=114.740106&latitude=-3.442821&unit=mi", true, true)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "enterprise/:id/distance")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		mockEnterpriseUsecase.On("GetDistanceEnterprise", mock.Anything, request.DistanceRequest{
+			Latitude: -3.442821, Longitude: 114.740106, Unit: "mi",
+		}).Return(13.536, dummyEnterprise[0], nil).Once()
+		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3)).Once()
+		err := middlewareToken(enterpriseController.GetDistance, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, int(responseBody["code"].(float64)))
+		assert.Equal(t, "mi", responseBody["data"].(map[string]interface{})["unit"])
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid coordinate", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"/distance?longitude=114.740106&latitude=-93.442821", true, true)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "enterprise/:id/distance")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		err := middlewareToken(enterpriseController.GetDistance, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, 400, int(responseBody["code"].(float64)))
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
 
-	t.Run("error get detail user", func(t *testing.T) {
+	t.Run("NaN coordinate", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"/distance?longitude=NaN&latitude=-3.442821", true, true)
 		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id")
+		c.SetPath(base_path + "enterprise/:id/distance")
 		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(domain.Enterprise{}, errors.New("error something")).Once()
-		err := middlewareToken(enterpriseController.DeleteEnterpriseByID, c)
+		err := middlewareToken(enterpriseController.GetDistance, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, 400, int(responseBody["code"].(float64)))
+		assert.Equal(t, "longitude must be between -180 and 180", responseBody["message"])
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
 
-	t.Run("error delete", func(t *testing.T) {
+	t.Run("missing coordinate", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"/distance?latitude=-3.442821", true, true)
 		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id")
+		c.SetPath(base_path + "enterprise/:id/distance")
 		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[0], nil).Once()
-		mockEnterpriseUsecase.On("DeleteEnterpriseByID", mock.Anything).Return(errors.New("error something")).Once()
-		err := middlewareToken(enterpriseController.DeleteEnterpriseByID, c)
+		err := middlewareToken(enterpriseController.GetDistance, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, 400, int(responseBody["code"].(float64)))
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
-}
 
-func TestEnterpriseController_GetDetailEnterpriseByID(t *testing.T) {
-	mockEnterpriseUsecase := new(mocks.EnterpriseUsecase)
-	mockRatingUsecase := new(mocks.RatingUsecase)
-	mockAuthUsecase := new(mocks.AuthUsecase)
-	t.Run("success", func(t *testing.T) {
+	t.Run("invalid unit", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"/distance?longitude=114.740106&latitude=-3.442821&unit=ft", true, true)
 		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id")
+		c.SetPath(base_path + "enterprise/:id/distance")
 		c.SetParamNames("id")
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[0], nil).Once()
-		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
-		err := middlewareToken(enterpriseController.GetDetailEnterpriseByID, c)
+		err := middlewareToken(enterpriseController.GetDistance, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
-		assert.Equal(t, 200, int(responseBody["code"].(float64)))
+		assert.Equal(t, 400, int(responseBody["code"].(float64)))
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
-	t.Run("error not found enterprise", func(t *testing.T) {
+
+	t.Run("error get enterprise", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String(), true, true)
+		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"/distance?longitude=114.740106&latitude=-3.442821", true, true)
 		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id")
+		c.SetPath(base_path + "enterprise/:id/distance")
 		c.SetParamNames("id")
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[1], nil).Once()
-		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
-		err := middlewareToken(enterpriseController.GetDetailEnterpriseByID, c)
+		mockEnterpriseUsecase.On("GetDistanceEnterprise", mock.Anything, mock.Anything).Return(float64(0), domain.Enterprise{}, errors.New("error something")).Once()
+		err := middlewareToken(enterpriseController.GetDistance, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
-		assert.Equal(t, 404, int(responseBody["code"].(float64)))
+		assert.Equal(t, 400, int(responseBody["code"].(float64)))
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
 }
 
-func TestEnterpriseController_GetDistance(t *testing.T) {
+func TestEnterpriseController_GetNearbyEnterprises(t *testing.T) {
 	mockEnterpriseUsecase := new(mocks.EnterpriseUsecase)
 	mockRatingUsecase := new(mocks.RatingUsecase)
 	mockAuthUsecase := new(mocks.AuthUsecase)
 
 	t.Run("success", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"distance?longitude=114.740106&latitude=-3.442821", true, true)
+		req, rec := makeRequestHttp("", echo.GET, "/enterprises/nearby?lat=-3.442821&lon=114.740106&radius_km=10&tags=a,b", true, true)
 		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id")
-		c.SetParamNames("id")
-		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[0], nil).Once()
-		err := middlewareToken(enterpriseController.GetDistance, c)
+		near := dummyEnterprise[0]
+		near.Distance = 1.234
+		mockEnterpriseUsecase.On("GetNearbyEnterprises", request.NearbyRequest{
+			Latitude: -3.442821, Longitude: 114.740106, RadiusKm: 10, Tags: []string{"a", "b"},
+		}).Return(domain.Enterprises{near}, nil).Once()
+		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3)).Once()
+		err := middlewareToken(enterpriseController.GetNearbyEnterprises, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, 200, int(responseBody["code"].(float64)))
+		data := responseBody["data"].([]interface{})
+		assert.Equal(t, 1.23, data[0].(map[string]interface{})["distance"])
 		mockEnterpriseUsecase.AssertExpectations(t)
 	})
 
-	t.Run("error get enterprise", func(t *testing.T) {
+	t.Run("success default radius", func(t *testing.T) {
 		e := echo.New()
-		req, rec := makeRequestHttp("", echo.GET, "/enterprise/"+dummyEnterprise[0].ID.String()+"distance?longitude=114.740106&latitude=-3.442821", true, true)
+		req, rec := makeRequestHttp("", echo.GET, "/enterprises/nearby?lat=-3.442821&lon=114.740106", true, true)
 		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id")
-		c.SetParamNames("id")
-		c.SetParamValues(dummyEnterprise[0].ID.String())
 		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[0], errors.New("error something")).Once()
-		err := middlewareToken(enterpriseController.GetDistance, c)
+		mockEnterpriseUsecase.On("GetNearbyEnterprises", request.NearbyRequest{
+			Latitude: -3.442821, Longitude: 114.740106, RadiusKm: 5,
+		}).Return(domain.Enterprises{}, nil).Once()
+		err := middlewareToken(enterpriseController.GetNearbyEnterprises, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, int(responseBody["code"].(float64)))
+		assert.Len(t, responseBody["data"], 0)
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid radius", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprises/nearby?lat=-3.442821&lon=114.740106&radius_km=500", true, true)
+		c := e.NewContext(req, rec)
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		err := middlewareToken(enterpriseController.GetNearbyEnterprises, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, int(responseBody["code"].(float64)))
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
+	for _, query := range []string{"lat=NaN&lon=114.740106", "lat=-3.442821&lon=NaN", "lat=-3.442821&lon=114.740106&radius_km=NaN"} {
+		t.Run("NaN in "+query, func(t *testing.T) {
+			e := echo.New()
+			req, rec := makeRequestHttp("", echo.GET, "/enterprises/nearby?"+query, true, true)
+			c := e.NewContext(req, rec)
+			enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+			err := middlewareToken(enterpriseController.GetNearbyEnterprises, c)
+			responseBody := parseResponse(rec)
+			assert.NoError(t, err)
+			assert.Equal(t, 400, int(responseBody["code"].(float64)))
+			mockEnterpriseUsecase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("missing lat", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprises/nearby?lon=114.740106", true, true)
+		c := e.NewContext(req, rec)
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		err := middlewareToken(enterpriseController.GetNearbyEnterprises, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, int(responseBody["code"].(float64)))
+		mockEnterpriseUsecase.AssertExpectations(t)
+	})
+
+	t.Run("error get nearby", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/enterprises/nearby?lat=-3.442821&lon=114.740106", true, true)
+		c := e.NewContext(req, rec)
+		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
+		mockEnterpriseUsecase.On("GetNearbyEnterprises", mock.Anything).Return(domain.Enterprises{}, errors.New("error something")).Once()
+		err := middlewareToken(enterpriseController.GetNearbyEnterprises, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, 400, int(responseBody["code"].(float64)))
@@ -771,121 +1060,93 @@ func TestEnterpriseController_CekRatingUser(t *testing.T) {
 }
 
 func TestEnterpriseController_DeleteRatingUser(t *testing.T) {
-	mockEnterpriseUsecase := new(mocks.EnterpriseUsecase)
-	mockRatingUsecase := new(mocks.RatingUsecase)
-	mockAuthUsecase := new(mocks.AuthUsecase)
-	t.Run("success", func(t *testing.T) {
-		e := echo.New()
-		req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String()+"/rating/user/"+dummyEnterprise[0].UserID.String(), true, true)
-		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id/rating/user/:userid")
-		c.SetParamNames("id", "userid")
-		c.SetParamValues(dummyEnterprise[0].ID.String(), dummyEnterprise[0].UserID.String())
-		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockRatingUsecase.On("FindRating", mock.Anything, mock.Anything).Return(dummyRating[0], nil).Once()
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockRatingUsecase.On("DeleteRating", mock.Anything, mock.Anything).Return(nil).Once()
-		err := middlewareToken(enterpriseController.DeleteRatingUser, c)
-		responseBody := parseResponse(rec)
-		assert.NoError(t, err)
-		assert.Equal(t, 200, int(responseBody["code"].(float64)))
-		mockRatingUsecase.AssertExpectations(t)
-		mockAuthUsecase.AssertExpectations(t)
-	})
-	t.Run("error find rating", func(t *testing.T) {
-		e := echo.New()
-		req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String()+"/rating/user/"+dummyEnterprise[0].UserID.String(), true, true)
-		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id/rating/user/:userid")
-		c.SetParamNames("id", "userid")
-		c.SetParamValues(dummyEnterprise[0].ID.String(), dummyEnterprise[0].UserID.String())
-		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockRatingUsecase.On("FindRating", mock.Anything, mock.Anything).Return(domain.RatingEnterprise{}, errors.New("error something")).Once()
-		err := middlewareToken(enterpriseController.DeleteRatingUser, c)
-		responseBody := parseResponse(rec)
-		assert.NoError(t, err)
-		assert.Equal(t, 404, int(responseBody["code"].(float64)))
-		mockRatingUsecase.AssertExpectations(t)
-	})
-	t.Run("error find rating", func(t *testing.T) {
-		e := echo.New()
-		req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String()+"/rating/user/"+dummyEnterprise[0].UserID.String(), true, true)
-		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id/rating/user/:userid")
-		c.SetParamNames("id", "userid")
-		c.SetParamValues(dummyEnterprise[0].ID.String(), dummyEnterprise[0].UserID.String())
-		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockRatingUsecase.On("FindRating", mock.Anything, mock.Anything).Return(dummyRating[0], nil).Once()
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, errors.New("error something")).Once()
-		err := middlewareToken(enterpriseController.DeleteRatingUser, c)
-		responseBody := parseResponse(rec)
-		assert.NoError(t, err)
-		assert.Equal(t, 400, int(responseBody["code"].(float64)))
-		mockRatingUsecase.AssertExpectations(t)
-	})
+	actor := domain.Actor{UserID: dummyUser[0].ID.String()}
+	own := dummyRating[0]
+	own.UserID = dummyUser[0].ID
+	tests := []struct {
+		name      string
+		userid    string
+		rating    domain.RatingEnterprise
+		findErr   error
+		deleteErr error
+		wantCode  int
+	}{
+		{"success", "", own, nil, nil, 200},
+		{"success moderator", dummyRating[0].UserID.String(), dummyRating[0], nil, nil, 200},
+		{"error find rating", "", domain.RatingEnterprise{}, errors.New("error something"), nil, 404},
+		{"rating not found", "", domain.RatingEnterprise{}, nil, nil, 404},
+		{"forbidden", dummyRating[0].UserID.String(), dummyRating[0], nil, domain.NewForbiddenError("only the owner or users with review:moderate can delete rating"), 403},
+		{"error delete", "", own, nil, errors.New("error something"), 400},
+	}
 
-	t.Run("success", func(t *testing.T) {
-		e := echo.New()
-		req, rec := makeRequestHttp("", echo.DELETE, "/enterprise/"+dummyEnterprise[0].ID.String()+"/rating/user/"+dummyEnterprise[0].UserID.String(), true, true)
-		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id/rating/user/:userid")
-		c.SetParamNames("id", "userid")
-		c.SetParamValues(dummyEnterprise[0].ID.String(), dummyEnterprise[1].UserID.String())
-		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockRatingUsecase.On("FindRating", mock.Anything, mock.Anything).Return(dummyRating[0], nil).Once()
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockRatingUsecase.On("DeleteRating", mock.Anything, mock.Anything).Return(errors.New("error something")).Once()
-		err := middlewareToken(enterpriseController.DeleteRatingUser, c)
-		responseBody := parseResponse(rec)
-		assert.NoError(t, err)
-		assert.Equal(t, 400, int(responseBody["code"].(float64)))
-		mockRatingUsecase.AssertExpectations(t)
-		mockAuthUsecase.AssertExpectations(t)
-	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userid := tt.userid
+			path := "/enterprise/" + dummyEnterprise[0].ID.String() + "/rating"
+			if userid != "" {
+				path += "?userid=" + userid
+			} else {
+				userid = actor.UserID
+			}
+			mockRatingUsecase := new(mocks.RatingUsecase)
+			e := echo.New()
+			req, rec := makeRequestHttp("", echo.DELETE, path, true, true)
+			c := e.NewContext(req, rec)
+			c.SetPath(base_path + "enterprise/:id/rating")
+			c.SetParamNames("id")
+			c.SetParamValues(dummyEnterprise[0].ID.String())
+			enterpriseController := http2.NewEnterpriseController(new(mocks.AuthUsecase), new(mocks.EnterpriseUsecase), mockRatingUsecase)
+			mockRatingUsecase.On("FindRating", dummyEnterprise[0].ID.String(), userid).Return(tt.rating, tt.findErr).Once()
+			if tt.rating.ID != uuid.Nil {
+				mockRatingUsecase.On("DeleteRating", dummyEnterprise[0].ID.String(), userid, actor).Return(tt.deleteErr).Once()
+			}
+			err := middlewareToken(enterpriseController.DeleteRatingUser, c)
+			responseBody := parseResponse(rec)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, int(responseBody["code"].(float64)))
+			mockRatingUsecase.AssertExpectations(t)
+		})
+	}
 }
 
 func TestEnterpriseController_UpdateRating(t *testing.T) {
-	mockEnterpriseUsecase := new(mocks.EnterpriseUsecase)
-	mockRatingUsecase := new(mocks.RatingUsecase)
-	mockAuthUsecase := new(mocks.AuthUsecase)
-	t.Run("success", func(t *testing.T) {
-		e := echo.New()
-		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/rating/user/"+dummyEnterprise[0].UserID.String()+"?value=3", true, true)
-		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id/rating/user/:userid")
-		c.SetParamNames("id", "userid")
-		c.SetParamValues(dummyEnterprise[0].ID.String(), dummyEnterprise[0].UserID.String())
-		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockRatingUsecase.On("FindRating", mock.Anything, mock.Anything).Return(dummyRating[0], nil).Once()
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockRatingUsecase.On("UpdateRating", mock.Anything, mock.Anything, mock.Anything).Return(domain.RatingEnterprise{}, nil).Once()
-		mockRatingUsecase.On("FindRating", mock.Anything, mock.Anything).Return(dummyRating[0], nil).Once()
-		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
-		err := middlewareToken(enterpriseController.UpdateRating, c)
-		responseBody := parseResponse(rec)
-		assert.NoError(t, err)
-		assert.Equal(t, 200, int(responseBody["code"].(float64)))
-		mockRatingUsecase.AssertExpectations(t)
-		mockAuthUsecase.AssertExpectations(t)
-	})
-	t.Run("success", func(t *testing.T) {
-		e := echo.New()
-		req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/rating/user/"+dummyEnterprise[0].UserID.String()+"?value=3", true, true)
-		c := e.NewContext(req, rec)
-		c.SetPath(base_path + "enterprise/:id/rating/user/:userid")
-		c.SetParamNames("id", "userid")
-		c.SetParamValues(dummyEnterprise[0].ID.String(), dummyEnterprise[0].UserID.String())
-		enterpriseController := http2.NewEnterpriseController(mockAuthUsecase, mockEnterpriseUsecase, mockRatingUsecase)
-		mockRatingUsecase.On("FindRating", mock.Anything, mock.Anything).Return(dummyRating[1], nil).Once()
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockRatingUsecase.On("UpdateRating", mock.Anything, mock.Anything, mock.Anything).Return(domain.RatingEnterprise{}, nil).Once()
-		mockRatingUsecase.On("FindRating", mock.Anything, mock.Anything).Return(dummyRating[0], nil).Once()
-		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
-		err := middlewareToken(enterpriseController.UpdateRating, c)
-		responseBody := parseResponse(rec)
-		assert.NoError(t, err)
-		assert.Equal(t, 200, int(responseBody["code"].(float64)))
-		mockRatingUsecase.AssertExpectations(t)
-		mockAuthUsecase.AssertExpectations(t)
-	})
+	actor := domain.Actor{UserID: dummyUser[0].ID.String()}
+	own := dummyRating[0]
+	own.UserID = dummyUser[0].ID
+	tests := []struct {
+		name      string
+		rating    domain.RatingEnterprise
+		updateErr error
+		wantCode  int
+	}{
+		{"success", own, nil, 200},
+		{"rating not found", domain.RatingEnterprise{}, nil, 404},
+		{"error update", own, errors.New("error something"), 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRatingUsecase := new(mocks.RatingUsecase)
+			e := echo.New()
+			// the userid query param is not an override here, the rating is always the caller's.
+			req, rec := makeRequestHttp("", echo.PUT, "/enterprise/"+dummyEnterprise[0].ID.String()+"/rating?value=3&userid="+dummyRating[0].UserID.String(), true, true)
+			c := e.NewContext(req, rec)
+			c.SetPath(base_path + "enterprise/:id/rating")
+			c.SetParamNames("id")
+			c.SetParamValues(dummyEnterprise[0].ID.String())
+			enterpriseController := http2.NewEnterpriseController(new(mocks.AuthUsecase), new(mocks.EnterpriseUsecase), mockRatingUsecase)
+			mockRatingUsecase.On("FindRating", dummyEnterprise[0].ID.String(), actor.UserID).Return(tt.rating, nil)
+			if tt.rating.ID != uuid.Nil {
+				mockRatingUsecase.On("UpdateRating", dummyEnterprise[0].ID.String(), actor.UserID, actor, 3).Return(tt.rating, tt.updateErr).Once()
+			}
+			if tt.updateErr == nil && tt.rating.ID != uuid.Nil {
+				mockRatingUsecase.On("GetAverageRatingEnterprise", dummyEnterprise[0].ID.String()).Return(float64(3)).Once()
+			}
+			err := middlewareToken(enterpriseController.UpdateRating, c)
+			responseBody := parseResponse(rec)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCode, int(responseBody["code"].(float64)))
+			mockRatingUsecase.AssertExpectations(t)
+		})
+	}
 }
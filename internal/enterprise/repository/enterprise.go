@@ -1,9 +1,14 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/enterprise/utils"
+	"github.com/nrmadi02/mini-project/internal/pagination"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"strings"
 )
 
 type enterpriseRepository struct {
@@ -16,38 +21,154 @@ func NewEnterpriseRepository(db *gorm.DB) domain.EnterpriseRepository {
 	}
 }
 
-func (e enterpriseRepository) FindAll(search string, page, length int) (enterprises domain.Enterprises, totalData int, err error) {
-	if page == 0 {
-		page = 1
+func (e enterpriseRepository) FindAll(filter domain.EnterpriseFilter, page domain.PageRequest) (enterprises domain.Enterprises, info domain.PageInfo, err error) {
+	listed := e.filter(e.DB.Model(&domain.Enterprise{}), filter)
+	keyset := pagination.Keyset{Column: "id"}
+	key := func(enterprise domain.Enterprise) interface{} { return nil }
+	switch filter.Sort {
+	case domain.SortEnterpriseName:
+		keyset = pagination.Keyset{Column: "name"}
+		key = func(enterprise domain.Enterprise) interface{} { return enterprise.Name }
+	case domain.SortEnterpriseNewest:
+		keyset = pagination.Keyset{Column: "created_at", Desc: true}
+		key = func(enterprise domain.Enterprise) interface{} { return enterprise.CreatedAt }
+	case domain.SortEnterpriseRating:
+		listed = listed.Select("enterprises.*, " + averageRating + " AS average_rating")
+		keyset = pagination.Keyset{Column: "average_rating", Desc: true}
+		key = func(enterprise domain.Enterprise) interface{} { return enterprise.AverageRating }
+	case domain.SortEnterpriseDistance:
+		listed = listed.Select("enterprises.*, "+haversine+" AS distance", map[string]interface{}{
+			"earth": geo.Kilometer.Radius(),
+			"lat":   filter.Latitude,
+			"lon":   filter.Longitude,
+		}).Where("latitude IS NOT NULL AND longitude IS NOT NULL")
+		keyset = pagination.Keyset{Column: "distance"}
+		key = func(enterprise domain.Enterprise) interface{} { return enterprise.Distance }
+	case domain.SortEnterpriseRelevance:
+		if len(filter.IDs) > 0 {
+			rank, args := searchRank(filter.IDs)
+			listed = listed.Select("enterprises.*, "+rank+" AS search_rank", args...)
+			keyset = pagination.Keyset{Column: "search_rank"}
+			key = func(enterprise domain.Enterprise) interface{} { return enterprise.SearchRank }
+		}
 	}
-	offset := (page - 1) * length
-	if search != "" {
-		totalData = int(e.DB.Preload("Tags").Where("name LIKE ?", "%"+search+"%").Find(&enterprises).RowsAffected)
-		err = e.DB.Preload("Tags").Offset(offset).Limit(length).Where("name LIKE ?", "%"+search+"%").Find(&enterprises).Error
-	} else {
-		totalData = int(e.DB.Preload("Tags").Find(&enterprises).RowsAffected)
-		err = e.DB.Preload("Tags").Offset(offset).Limit(length).Find(&enterprises).Error
+
+	// the sort keys are columns of the derived table, so the keyset compares them like any other column.
+	err = keyset.Apply(e.DB.Preload("Tags").Preload("Images", orderedImages).Table("(?) AS enterprises", listed), page).Find(&enterprises).Error
+	if err != nil {
+		return enterprises, info, err
+	}
+	enterprises, info = pagination.Page(enterprises, page, func(enterprise domain.Enterprise) domain.Cursor {
+		return domain.Cursor{Key: key(enterprise), ID: enterprise.ID.String()}
+	})
+	err = pagination.Count(e.DB.Table("(?) AS enterprises", listed), page, &info)
+	return enterprises, info, err
+}
+
+// orderedImages keeps the gallery in upload order.
+func orderedImages(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at")
+}
+
+// averageRating is the average rating of the enterprise in the current row, 0 when it has none.
+const averageRating = "(SELECT COALESCE(AVG(rating), 0) FROM rating_enterprises WHERE rating_enterprises.enterprise_id = enterprises.id)"
+
+// searchRank is the position of the enterprise in the current row among ids, counting from 1.
+func searchRank(ids []string) (string, []interface{}) {
+	var rank strings.Builder
+	args := make([]interface{}, 0, len(ids))
+	rank.WriteString("CASE id")
+	for i, id := range ids {
+		rank.WriteString(fmt.Sprintf(" WHEN ? THEN %d", i+1))
+		args = append(args, id)
+	}
+	rank.WriteString(" END")
+	return rank.String(), args
+}
+
+func (e enterpriseRepository) filter(db *gorm.DB, filter domain.EnterpriseFilter) *gorm.DB {
+	if len(filter.IDs) > 0 {
+		db = db.Where("id IN ?", filter.IDs)
+	}
+	if len(filter.TagIDs) > 0 {
+		tagged := e.DB.Table("enterprise_tags").Select("enterprise_id").Where("tag_id IN ?", filter.TagIDs)
+		if filter.AllTags {
+			tagged = tagged.Group("enterprise_id").Having("COUNT(DISTINCT tag_id) = ?", len(filter.TagIDs))
+		}
+		db = db.Where("id IN (?)", tagged)
 	}
-	return enterprises, totalData, err
+	if filter.Status != nil {
+		db = db.Where("status = ?", *filter.Status)
+	}
+	if filter.Postcode != 0 {
+		db = db.Where("postcode = ?", filter.Postcode)
+	}
+	if filter.OwnerID != "" {
+		db = db.Where("user_id = ?", filter.OwnerID)
+	}
+	if filter.MinRating > 0 {
+		db = db.Where(averageRating+" >= ?", filter.MinRating)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		db = db.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		db = db.Where("created_at < ?", filter.CreatedTo)
+	}
+	return db
 }
 
-func (e enterpriseRepository) FindByStatusDraft() (enterprises domain.Enterprises, err error) {
-	err = e.DB.Preload("Tags").Where("status = ? ", 0).Find(&enterprises).Error
+func (e enterpriseRepository) FindByStatus(status domain.EnterpriseStatus) (enterprises domain.Enterprises, err error) {
+	err = e.DB.Preload("Tags").Preload("Images", orderedImages).Where("status = ? ", status).Find(&enterprises).Error
 	return enterprises, err
 }
 
-func (e enterpriseRepository) FindByStatusPublish() (enterprises domain.Enterprises, err error) {
-	err = e.DB.Preload("Tags").Where("status = ? ", 1).Find(&enterprises).Error
+func (e enterpriseRepository) FindStatusHistories(id string) (histories domain.EnterpriseStatusHistories, err error) {
+	err = e.DB.Where("enterprise_id = ? ", id).Order("created_at").Find(&histories).Error
+	return histories, err
+}
+
+// haversine mirrors geo.Distance in SQL; the CASE clamps rounding errors that would push ASIN out of its domain.
+var haversine = fmt.Sprintf("2 * @earth * ASIN(SQRT(CASE WHEN %[1]s > 1 THEN 1 ELSE %[1]s END))",
+	"POWER(SIN(RADIANS(latitude - @lat) / 2), 2) + COS(RADIANS(@lat)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - @lon) / 2), 2)")
+
+func (e enterpriseRepository) FindNearby(latitude, longitude, radius float64, tags []string) (enterprises domain.Enterprises, err error) {
+	minLat, maxLat, minLon, maxLon := geo.BoundingBox(latitude, longitude, radius, geo.Kilometer)
+	nearby := e.DB.Model(&domain.Enterprise{}).
+		Select("enterprises.*, "+haversine+" AS distance", map[string]interface{}{
+			"earth": geo.Kilometer.Radius(),
+			"lat":   latitude,
+			"lon":   longitude,
+		}).
+		Where("status = ?", domain.EnterprisePublished).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", minLat, maxLat, minLon, maxLon)
+	if len(tags) > 0 {
+		nearby = nearby.Where("id IN (?)", e.DB.Table("enterprise_tags").Select("enterprise_id").Where("tag_id IN ?", tags))
+	}
+
+	err = e.DB.Preload("Tags").Preload("Images", orderedImages).Table("(?) AS nearby", nearby).Where("distance <= ?", radius).Order("distance").Find(&enterprises).Error
 	return enterprises, err
 }
 
 func (e enterpriseRepository) FindByID(id string) (enterprise domain.Enterprise, err error) {
-	err = e.DB.Preload("Tags").Where("id = ?", id).Find(&enterprise).Error
+	err = e.DB.Preload("Tags").Preload("Images", orderedImages).Where("id = ?", id).Find(&enterprise).Error
 	return enterprise, err
 }
 
-func (e enterpriseRepository) UpdateStatusByID(id string, status int) (enterprise domain.Enterprise, err error) {
-	err = e.DB.Model(&enterprise).Where("id = ? ", id).Update("status", status).Error
+// UpdateStatus only applies when the stored status still equals history.From, so two moderators
+// acting on the same enterprise cannot both succeed.
+func (e enterpriseRepository) UpdateStatus(enterprise domain.Enterprise, history domain.EnterpriseStatusHistory) (domain.Enterprise, error) {
+	err := e.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Enterprise{}).Where("id = ? AND status = ?", enterprise.ID, history.From).
+			Updates(map[string]interface{}{"status": history.To, "status_reason": enterprise.StatusReason})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("enterprise status has been changed")
+		}
+		return tx.Create(&history).Error
+	})
 	return enterprise, err
 }
 
@@ -56,9 +177,18 @@ func (e enterpriseRepository) Save(enterprise domain.Enterprise) (domain.Enterpr
 	return enterprise, err
 }
 
+// Update writes the fields an owner edits, a nil latitude and longitude clear the location.
+// The status is changed with UpdateStatus.
 func (e enterpriseRepository) Update(enterprise domain.Enterprise) (domain.Enterprise, error) {
-	err := e.DB.Model(&enterprise).Where("id = ? ", enterprise.ID).Updates(&enterprise).Error
-	err = e.DB.Model(&enterprise).Association("Tags").Replace(&enterprise.Tags)
+	err := e.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&enterprise).
+			Select("name", "number_phone", "address", "postcode", "latitude", "longitude", "description", "updated_at").
+			Updates(&enterprise).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&enterprise).Association("Tags").Replace(&enterprise.Tags)
+	})
 	return enterprise, err
 }
 
@@ -68,11 +198,11 @@ func (e enterpriseRepository) Delete(enterprise domain.Enterprise) error {
 }
 
 func (e enterpriseRepository) FindByIDs(ids []string) (enterprises domain.Enterprises, err error) {
-	err = e.DB.Preload("Tags").Where("id IN ? ", ids).Find(&enterprises).Error
+	err = e.DB.Preload("Tags").Preload("Images", orderedImages).Where("id IN ? ", ids).Find(&enterprises).Error
 	return enterprises, err
 }
 
 func (e enterpriseRepository) FindByUserID(id string) (enterprises domain.Enterprises, err error) {
-	err = e.DB.Preload("Tags").Where("user_id = ? ", id).Find(&enterprises).Error
+	err = e.DB.Preload("Tags").Preload("Images", orderedImages).Where("user_id = ? ", id).Find(&enterprises).Error
 	return enterprises, err
 }
@@ -3,6 +3,7 @@ package repository_test
 import (
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	sqlMock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/nrmadi02/mini-project/domain"
 	"github.com/nrmadi02/mini-project/internal/enterprise/repository"
@@ -36,6 +37,8 @@ func SetupDBMock(dbMock *sql.DB) *gorm.DB {
 var created_at, _ = time.Parse("2022-05-07 18:11:36.681 +0800 WITA", "2022-05-07 18:11:36.681 +0800 WITA")
 var updated_at, _ = time.Parse("2022-05-07 18:11:36.681 +0800 WITA", "2022-05-07 18:11:36.681 +0800 WITA")
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = []domain.Enterprise{
 	domain.Enterprise{
 		ID:               uuid.FromStringOrNil("2"),
@@ -44,8 +47,8 @@ var dummyEnterprise = []domain.Enterprise{
 		NumberPhone:      "0012798232",
 		Address:          "bjb",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -61,8 +64,8 @@ var dummyEnterprise = []domain.Enterprise{
 		NumberPhone:      "0012798232",
 		Address:          "bjb",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -80,198 +83,168 @@ func TestEnterpriseRepository_FindAll(t *testing.T) {
 	}
 	db := SetupDBMock(dbMock)
 
-	mock.ExpectQuery("SELECT * FROM `enterprises`").
+	mock.ExpectQuery("SELECT * FROM (SELECT * FROM `enterprises`) AS enterprises ORDER BY id ASC LIMIT 2").
 		WillReturnRows(sqlMock.
 			NewRows([]string{"id", "name", "user_id", "number_phone",
 				"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-			AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
+			AddRow(dummyEnterprise[0].ID.String(), dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
 				dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
 				dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-			AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
-				dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
-				dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
-
-	mock.ExpectQuery("SELECT * FROM `enterprises` LIMIT 2").
-		WillReturnRows(sqlMock.
-			NewRows([]string{"id", "name", "user_id", "number_phone",
-				"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-			AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-				dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-				dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-			AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
+			AddRow(dummyEnterprise[1].ID.String(), dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
 				dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
 				dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
+	mock.ExpectQuery("SELECT count(*) FROM (SELECT * FROM `enterprises`) AS enterprises").
+		WillReturnRows(sqlMock.NewRows([]string{"count"}).AddRow(3))
 
 	enterpriseRepository := repository.NewEnterpriseRepository(db)
-	enterprises, totalData, err := enterpriseRepository.FindAll("", 1, 2)
-	if err != nil {
-		assert.Error(t, err)
-	}
+	enterprises, info, err := enterpriseRepository.FindAll(domain.EnterpriseFilter{}, domain.PageRequest{Length: 1, WithTotal: true})
 	assert.NoError(t, err)
-	assert.NotNil(t, totalData)
-	assert.NotNil(t, enterprises)
+	assert.Len(t, enterprises, 1)
+	assert.Equal(t, domain.Cursor{ID: dummyEnterprise[0].ID.String()}, *info.Next)
+	assert.Nil(t, info.Prev)
+	assert.Equal(t, 3, *info.Total)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestEnterpriseRepository_FindAllSearch(t *testing.T) {
+func TestEnterpriseRepository_FindAllBackward(t *testing.T) {
 	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	db := SetupDBMock(dbMock)
-	var page int
-	page = 1
-	if page == 1 {
-		mock.ExpectQuery("SELECT * FROM `enterprises` WHERE name LIKE ?").
-			WithArgs("%e%").
-			WillReturnRows(sqlMock.
-				NewRows([]string{"id", "name", "user_id", "number_phone",
-					"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-				AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-					dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-					dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-				AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
-					dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
-					dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
-
-		mock.ExpectQuery("SELECT * FROM `enterprises` WHERE name LIKE ? LIMIT 1").
-			WithArgs("%e%").
-			WillReturnRows(sqlMock.
-				NewRows([]string{"id", "name", "user_id", "number_phone",
-					"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-				AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-					dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-					dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-				AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
-					dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
-					dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
 
-		enterpriseRepository := repository.NewEnterpriseRepository(db)
-		enterprises, totalData, err := enterpriseRepository.FindAll("e", 1, 1)
-		if err != nil {
-			assert.Error(t, err)
-		}
-		assert.NoError(t, err)
-		assert.NotNil(t, totalData)
-		assert.NotNil(t, enterprises)
-	}
+	// going back from "enterprise dua" reads the names before it in reverse.
+	cursorID, firstID, secondID := uuid.NewV4().String(), uuid.NewV4().String(), uuid.NewV4().String()
+	mock.ExpectQuery("SELECT * FROM (SELECT * FROM `enterprises` WHERE id IN (?,?)) AS enterprises "+
+		"WHERE (name < ? OR (name = ? AND id < ?)) ORDER BY name DESC,id DESC LIMIT 2").
+		WithArgs(firstID, secondID, "enterprise dua", "enterprise dua", cursorID).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "name"}).
+			AddRow(secondID, "enterprise b").
+			AddRow(firstID, "enterprise a"))
+	mock.ExpectQuery("SELECT * FROM `enterprise_images` WHERE `enterprise_images`.`enterprise_id` IN (?,?) ORDER BY created_at").
+		WithArgs(secondID, firstID).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "enterprise_id"}))
+	mock.ExpectQuery("SELECT * FROM `enterprise_tags` WHERE `enterprise_tags`.`enterprise_id` IN (?,?)").
+		WithArgs(secondID, firstID).
+		WillReturnRows(sqlMock.NewRows([]string{"enterprise_id", "tag_id"}))
 
-	page = 2
-	if page == 2 {
-		mock.ExpectQuery("SELECT * FROM `enterprises` WHERE name LIKE ?").
-			WithArgs("%e%").
-			WillReturnRows(sqlMock.
-				NewRows([]string{"id", "name", "user_id", "number_phone",
-					"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-				AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-					dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-					dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-				AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
-					dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
-					dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
-
-		mock.ExpectQuery("SELECT * FROM `enterprises` WHERE name LIKE ? LIMIT 1 OFFSET 1").
-			WithArgs("%e%").
-			WillReturnRows(sqlMock.
-				NewRows([]string{"id", "name", "user_id", "number_phone",
-					"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-				AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-					dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-					dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-				AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
-					dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
-					dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
+	enterpriseRepository := repository.NewEnterpriseRepository(db)
+	enterprises, info, err := enterpriseRepository.FindAll(domain.EnterpriseFilter{IDs: []string{firstID, secondID}, Sort: domain.SortEnterpriseName}, domain.PageRequest{
+		Cursor: &domain.Cursor{Key: "enterprise dua", ID: cursorID, Backward: true},
+		Length: 1,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, enterprises, 1)
+	assert.Equal(t, "enterprise b", enterprises[0].Name)
+	assert.Equal(t, domain.Cursor{Key: "enterprise b", ID: secondID, Backward: true}, *info.Prev)
+	assert.Equal(t, domain.Cursor{Key: "enterprise b", ID: secondID}, *info.Next)
+	assert.Nil(t, info.Total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-		enterpriseRepository := repository.NewEnterpriseRepository(db)
-		enterprises, totalData, err := enterpriseRepository.FindAll("e", 2, 1)
-		if err != nil {
-			assert.Error(t, err)
-		}
-		assert.NoError(t, err)
-		assert.NotNil(t, totalData)
-		assert.NotNil(t, enterprises)
+func TestEnterpriseRepository_FindAllRelevance(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
+	db := SetupDBMock(dbMock)
+
+	// the second hit of the search is past the cursor on the first.
+	firstID, secondID := uuid.NewV4().String(), uuid.NewV4().String()
+	mock.ExpectQuery("SELECT * FROM (SELECT enterprises.*, CASE id WHEN ? THEN 1 WHEN ? THEN 2 END AS search_rank FROM `enterprises` "+
+		"WHERE id IN (?,?)) AS enterprises "+
+		"WHERE (search_rank > ? OR (search_rank = ? AND id > ?)) ORDER BY search_rank ASC,id ASC LIMIT 21").
+		WithArgs(firstID, secondID, firstID, secondID, float64(1), float64(1), firstID).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "name", "search_rank"}).
+			AddRow(secondID, "warung dua", 2))
+	mock.ExpectQuery("SELECT * FROM `enterprise_images` WHERE `enterprise_images`.`enterprise_id` = ? ORDER BY created_at").
+		WithArgs(secondID).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "enterprise_id"}))
+	mock.ExpectQuery("SELECT * FROM `enterprise_tags` WHERE `enterprise_tags`.`enterprise_id` = ?").
+		WithArgs(secondID).
+		WillReturnRows(sqlMock.NewRows([]string{"enterprise_id", "tag_id"}))
+
+	enterpriseRepository := repository.NewEnterpriseRepository(db)
+	enterprises, info, err := enterpriseRepository.FindAll(domain.EnterpriseFilter{
+		IDs:  []string{firstID, secondID},
+		Sort: domain.SortEnterpriseRelevance,
+	}, domain.PageRequest{Cursor: &domain.Cursor{Key: float64(1), ID: firstID}, Length: 20})
+	assert.NoError(t, err)
+	assert.Len(t, enterprises, 1)
+	assert.Equal(t, 2, enterprises[0].SearchRank)
+	assert.Equal(t, domain.Cursor{Key: 2, ID: secondID, Backward: true}, *info.Prev)
+	assert.Nil(t, info.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestEnterpriseRepository_FindAllPage(t *testing.T) {
+func TestEnterpriseRepository_FindAllFilters(t *testing.T) {
 	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	db := SetupDBMock(dbMock)
-	var page int
-	page = 1
-	if page == 1 {
-		mock.ExpectQuery("SELECT * FROM `enterprises` WHERE name LIKE ?").
-			WithArgs("%e%").
-			WillReturnRows(sqlMock.
-				NewRows([]string{"id", "name", "user_id", "number_phone",
-					"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-				AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-					dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-					dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-				AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
-					dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
-					dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
-
-		mock.ExpectQuery("SELECT * FROM `enterprises` WHERE name LIKE ? LIMIT 1").
-			WithArgs("%e%").
-			WillReturnRows(sqlMock.
-				NewRows([]string{"id", "name", "user_id", "number_phone",
-					"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-				AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-					dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-					dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-				AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
-					dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
-					dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
 
-		enterpriseRepository := repository.NewEnterpriseRepository(db)
-		enterprises, totalData, err := enterpriseRepository.FindAll("e", 0, 1)
-		if err != nil {
-			assert.Error(t, err)
-		}
-		assert.NoError(t, err)
-		assert.NotNil(t, totalData)
-		assert.NotNil(t, enterprises)
-	}
+	published := domain.EnterprisePublished
+	from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	where := "WHERE id IN (SELECT enterprise_id FROM `enterprise_tags` WHERE tag_id IN (?,?) GROUP BY `enterprise_id` HAVING COUNT(DISTINCT tag_id) = ?) " +
+		"AND status = ? AND postcode = ? AND user_id = ? AND " + averageRating + " >= ? AND created_at >= ? AND created_at < ?"
+	args := []driver.Value{"tag-1", "tag-2", 2, published, 70722, dummyEnterprise[0].UserID.String(), 4.0, from, to}
 
-	page = 2
-	if page == 2 {
-		mock.ExpectQuery("SELECT * FROM `enterprises` WHERE name LIKE ?").
-			WithArgs("%e%").
-			WillReturnRows(sqlMock.
-				NewRows([]string{"id", "name", "user_id", "number_phone",
-					"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-				AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-					dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-					dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-				AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
-					dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
-					dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
-
-		mock.ExpectQuery("SELECT * FROM `enterprises` WHERE name LIKE ? LIMIT 1 OFFSET 1").
-			WithArgs("%e%").
-			WillReturnRows(sqlMock.
-				NewRows([]string{"id", "name", "user_id", "number_phone",
-					"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-				AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-					dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-					dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description).
-				AddRow(dummyEnterprise[1].ID, dummyEnterprise[1].Name, dummyEnterprise[1].UserID, dummyEnterprise[1].NumberPhone,
-					dummyEnterprise[1].Address, dummyEnterprise[1].Status, dummyEnterprise[1].Postcode, dummyEnterprise[1].Longitude,
-					dummyEnterprise[1].Latitude, dummyEnterprise[1].CreatedAt, dummyEnterprise[1].UpdatedAt, dummyEnterprise[1].Description))
+	mock.ExpectQuery("SELECT * FROM (SELECT enterprises.*, " + averageRating + " AS average_rating FROM `enterprises` " + where + ") AS enterprises " +
+		"ORDER BY average_rating DESC,id DESC LIMIT 21").
+		WithArgs(args...).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "name", "average_rating"}).
+			AddRow(dummyEnterprise[0].ID.String(), dummyEnterprise[0].Name, 4.5))
 
-		enterpriseRepository := repository.NewEnterpriseRepository(db)
-		enterprises, totalData, err := enterpriseRepository.FindAll("e", 2, 1)
-		if err != nil {
-			assert.Error(t, err)
-		}
-		assert.NoError(t, err)
-		assert.NotNil(t, totalData)
-		assert.NotNil(t, enterprises)
+	enterpriseRepository := repository.NewEnterpriseRepository(db)
+	enterprises, info, err := enterpriseRepository.FindAll(domain.EnterpriseFilter{
+		TagIDs:      []string{"tag-1", "tag-2"},
+		AllTags:     true,
+		Status:      &published,
+		Postcode:    70722,
+		OwnerID:     dummyEnterprise[0].UserID.String(),
+		MinRating:   4,
+		CreatedFrom: from,
+		CreatedTo:   to,
+		Sort:        domain.SortEnterpriseRating,
+	}, domain.PageRequest{Length: 20})
+	assert.NoError(t, err)
+	assert.Len(t, enterprises, 1)
+	assert.Equal(t, 4.5, enterprises[0].AverageRating)
+	assert.Nil(t, info.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnterpriseRepository_FindAllDistance(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM (SELECT enterprises.*, "+nearbyHaversine+" AS distance FROM `enterprises` "+
+		"WHERE id IN (SELECT enterprise_id FROM `enterprise_tags` WHERE tag_id IN (?)) AND (latitude IS NOT NULL AND longitude IS NOT NULL)) AS enterprises "+
+		"WHERE (distance > ? OR (distance = ? AND id > ?)) ORDER BY distance ASC,id ASC LIMIT 21").
+		WithArgs(6371.0088, -3.442821, -3.442821, 114.740106, -3.442821, -3.442821, 114.740106, "tag-1", 1.5, 1.5, dummyEnterprise[0].ID.String()).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "name", "distance"}))
+
+	enterpriseRepository := repository.NewEnterpriseRepository(db)
+	enterprises, info, err := enterpriseRepository.FindAll(domain.EnterpriseFilter{
+		TagIDs:    []string{"tag-1"},
+		Sort:      domain.SortEnterpriseDistance,
+		Latitude:  -3.442821,
+		Longitude: 114.740106,
+	}, domain.PageRequest{Cursor: &domain.Cursor{Key: 1.5, ID: dummyEnterprise[0].ID.String()}, Length: 20})
+	assert.NoError(t, err)
+	assert.Len(t, enterprises, 0)
+	assert.Nil(t, info.Next)
+	assert.Nil(t, info.Prev)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+const averageRating = "(SELECT COALESCE(AVG(rating), 0) FROM rating_enterprises WHERE rating_enterprises.enterprise_id = enterprises.id)"
+
 func TestEnterpriseRepository_FindByID(t *testing.T) {
 	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	if err != nil {
@@ -350,7 +323,7 @@ func TestEnterpriseRepository_FindByIDs(t *testing.T) {
 	assert.NotNil(t, enterprises)
 }
 
-func TestEnterpriseRepository_FindByStatusDraft(t *testing.T) {
+func TestEnterpriseRepository_FindByStatus(t *testing.T) {
 	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
@@ -358,46 +331,40 @@ func TestEnterpriseRepository_FindByStatusDraft(t *testing.T) {
 	db := SetupDBMock(dbMock)
 
 	mock.ExpectQuery("SELECT * FROM `enterprises` WHERE status = ?").
-		WithArgs(0).
+		WithArgs(domain.EnterpriseSubmitted).
 		WillReturnRows(sqlMock.
 			NewRows([]string{"id", "name", "user_id", "number_phone",
 				"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
 			AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-				dummyEnterprise[0].Address, dummyEnterprise[0].Status, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
+				dummyEnterprise[0].Address, domain.EnterpriseSubmitted, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
 				dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description))
 
 	enterpriseRepository := repository.NewEnterpriseRepository(db)
-	enterprise, err := enterpriseRepository.FindByStatusDraft()
-	if err != nil {
-		assert.Error(t, err)
-	}
+	enterprises, err := enterpriseRepository.FindByStatus(domain.EnterpriseSubmitted)
 	assert.NoError(t, err)
-	assert.NotNil(t, enterprise)
+	assert.Len(t, enterprises, 1)
+	assert.Equal(t, domain.EnterpriseSubmitted, enterprises[0].Status)
 }
 
-func TestEnterpriseRepository_FindByStatusPublish(t *testing.T) {
+func TestEnterpriseRepository_FindStatusHistories(t *testing.T) {
 	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	db := SetupDBMock(dbMock)
 
-	mock.ExpectQuery("SELECT * FROM `enterprises` WHERE status = ?").
-		WithArgs(1).
+	mock.ExpectQuery("SELECT * FROM `enterprise_status_histories` WHERE enterprise_id = ? ORDER BY created_at").
+		WithArgs(dummyEnterprise[0].ID.String()).
 		WillReturnRows(sqlMock.
-			NewRows([]string{"id", "name", "user_id", "number_phone",
-				"address", "status", "postcode", "longitude", "latitude", "created_at", "updated_at", "description"}).
-			AddRow(dummyEnterprise[0].ID, dummyEnterprise[0].Name, dummyEnterprise[0].UserID, dummyEnterprise[0].NumberPhone,
-				dummyEnterprise[0].Address, 1, dummyEnterprise[0].Postcode, dummyEnterprise[0].Longitude,
-				dummyEnterprise[0].Latitude, dummyEnterprise[0].CreatedAt, dummyEnterprise[0].UpdatedAt, dummyEnterprise[0].Description))
+			NewRows([]string{"id", "enterprise_id", "actor_id", "from", "to", "reason", "created_at"}).
+			AddRow(uuid.NewV4(), dummyEnterprise[0].ID, dummyEnterprise[0].UserID, domain.EnterpriseSubmitted, domain.EnterpriseRejected, "incomplete address", created_at))
 
 	enterpriseRepository := repository.NewEnterpriseRepository(db)
-	enterprise, err := enterpriseRepository.FindByStatusPublish()
-	if err != nil {
-		assert.Error(t, err)
-	}
+	histories, err := enterpriseRepository.FindStatusHistories(dummyEnterprise[0].ID.String())
 	assert.NoError(t, err)
-	assert.NotNil(t, enterprise)
+	assert.Len(t, histories, 1)
+	assert.Equal(t, domain.EnterpriseRejected, histories[0].To)
+	assert.Equal(t, "incomplete address", histories[0].Reason)
 }
 
 func TestEnterpriseRepository_Save(t *testing.T) {
@@ -408,10 +375,10 @@ func TestEnterpriseRepository_Save(t *testing.T) {
 	db := SetupDBMock(dbMock)
 
 	mock.ExpectBegin()
-	mock.ExpectExec("INSERT INTO `enterprises` (`id`,`user_id`,`name`,`number_phone`,`address`,`postcode`,`latitude`,`longitude`,`description`,`status`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?)").
+	mock.ExpectExec("INSERT INTO `enterprises` (`id`,`user_id`,`name`,`number_phone`,`address`,`postcode`,`latitude`,`longitude`,`description`,`status`,`status_reason`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)").
 		WithArgs(dummyEnterprise[0].ID, dummyEnterprise[0].UserID, dummyEnterprise[0].Name, dummyEnterprise[0].NumberPhone,
 			dummyEnterprise[0].Address, int(dummyEnterprise[0].Postcode),
-			dummyEnterprise[0].Latitude, dummyEnterprise[0].Longitude, dummyEnterprise[0].Description, int(dummyEnterprise[0].Status), AnyTime{},
+			dummyEnterprise[0].Latitude, dummyEnterprise[0].Longitude, dummyEnterprise[0].Description, int(dummyEnterprise[0].Status), dummyEnterprise[0].StatusReason, AnyTime{},
 			AnyTime{}).WillReturnResult(sqlMock.NewErrorResult(nil))
 	mock.ExpectCommit()
 	mock.ExpectClose()
@@ -424,25 +391,59 @@ func TestEnterpriseRepository_Save(t *testing.T) {
 	assert.NotNil(t, enterprise)
 }
 
-func TestEnterpriseRepository_UpdateStatusByID(t *testing.T) {
-	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
-	if err != nil {
-		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+func TestEnterpriseRepository_UpdateStatus(t *testing.T) {
+	enterprise := dummyEnterprise[0]
+	enterprise.Status = domain.EnterpriseRejected
+	enterprise.StatusReason = "incomplete address"
+	history := domain.EnterpriseStatusHistory{
+		ID:           uuid.NewV4(),
+		EnterpriseID: enterprise.ID,
+		ActorID:      enterprise.UserID,
+		From:         domain.EnterpriseSubmitted,
+		To:           domain.EnterpriseRejected,
+		Reason:       "incomplete address",
 	}
-	db := SetupDBMock(dbMock)
 
-	mock.ExpectBegin()
-	mock.ExpectExec("UPDATE `enterprises` SET `status`=?,`updated_at`=? WHERE id = ?").
-		WithArgs(1, AnyTime{}, dummyEnterprise[0].ID).WillReturnResult(sqlMock.NewErrorResult(nil))
-	mock.ExpectCommit()
-	mock.ExpectClose()
-	enterpriseRepository := repository.NewEnterpriseRepository(db)
-	enterprise, err := enterpriseRepository.UpdateStatusByID(dummyEnterprise[0].ID.String(), 1)
-	if err != nil {
-		assert.Error(t, err)
-	}
-	assert.NoError(t, err)
-	assert.NotNil(t, enterprise)
+	t.Run("success", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `enterprises` SET `status`=?,`status_reason`=?,`updated_at`=? WHERE id = ? AND status = ?").
+			WithArgs(domain.EnterpriseRejected, "incomplete address", AnyTime{}, enterprise.ID, domain.EnterpriseSubmitted).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `enterprise_status_histories` (`id`,`enterprise_id`,`actor_id`,`from`,`to`,`reason`,`created_at`) VALUES (?,?,?,?,?,?,?)").
+			WithArgs(history.ID, enterprise.ID, enterprise.UserID, domain.EnterpriseSubmitted, domain.EnterpriseRejected, "incomplete address", AnyTime{}).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		enterpriseRepository := repository.NewEnterpriseRepository(db)
+		res, err := enterpriseRepository.UpdateStatus(enterprise, history)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.EnterpriseRejected, res.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("status changed by someone else", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `enterprises` SET `status`=?,`status_reason`=?,`updated_at`=? WHERE id = ? AND status = ?").
+			WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		enterpriseRepository := repository.NewEnterpriseRepository(db)
+		_, err = enterpriseRepository.UpdateStatus(enterprise, history)
+		assert.EqualError(t, err, "enterprise status has been changed")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestEnterpriseRepository_Delete(t *testing.T) {
@@ -467,24 +468,125 @@ func TestEnterpriseRepository_Delete(t *testing.T) {
 }
 
 func TestEnterpriseRepository_Update(t *testing.T) {
+	enterprise := dummyEnterprise[0]
+	enterprise.ID = uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a")
+	update := "UPDATE `enterprises` SET `name`=?,`number_phone`=?,`address`=?,`postcode`=?,`latitude`=?,`longitude`=?,`description`=?,`updated_at`=? WHERE `id` = ?"
+
+	t.Run("success", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(update).
+			WithArgs(enterprise.Name, enterprise.NumberPhone, enterprise.Address,
+				int64(enterprise.Postcode), enterprise.Latitude, enterprise.Longitude, enterprise.Description,
+				AnyTime{}, enterprise.ID).WillReturnResult(sqlMock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE `enterprises` SET `updated_at`=? WHERE `id` = ?").
+			WithArgs(AnyTime{}, enterprise.ID).WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM `enterprise_tags` WHERE `enterprise_tags`.`enterprise_id` = ?").
+			WithArgs(enterprise.ID).WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		enterpriseRepository := repository.NewEnterpriseRepository(db)
+		_, err = enterpriseRepository.Update(enterprise)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("clears coordinates", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+		enterprise := enterprise
+		enterprise.Latitude, enterprise.Longitude = nil, nil
+
+		mock.ExpectBegin()
+		mock.ExpectExec(update).
+			WithArgs(enterprise.Name, enterprise.NumberPhone, enterprise.Address,
+				int64(enterprise.Postcode), nil, nil, enterprise.Description, AnyTime{}, enterprise.ID).
+			WillReturnResult(sqlMock.NewResult(1, 1))
+		mock.ExpectExec("UPDATE `enterprises` SET `updated_at`=? WHERE `id` = ?").
+			WithArgs(AnyTime{}, enterprise.ID).WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectExec("DELETE FROM `enterprise_tags` WHERE `enterprise_tags`.`enterprise_id` = ?").
+			WithArgs(enterprise.ID).WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		enterpriseRepository := repository.NewEnterpriseRepository(db)
+		_, err = enterpriseRepository.Update(enterprise)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("failed update keeps the tags", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(update).WillReturnError(errors.New("database down"))
+		mock.ExpectRollback()
+
+		enterpriseRepository := repository.NewEnterpriseRepository(db)
+		_, err = enterpriseRepository.Update(enterprise)
+		assert.EqualError(t, err, "database down")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+const nearbyHaversine = "2 * ? * ASIN(SQRT(CASE WHEN POWER(SIN(RADIANS(latitude - ?) / 2), 2) + COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2) > 1 " +
+	"THEN 1 ELSE POWER(SIN(RADIANS(latitude - ?) / 2), 2) + COS(RADIANS(?)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - ?) / 2), 2) END))"
+
+func TestEnterpriseRepository_FindNearby(t *testing.T) {
 	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	if err != nil {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	db := SetupDBMock(dbMock)
 
-	mock.ExpectBegin()
-	mock.ExpectExec("UPDATE `enterprises` SET `name`=?,`number_phone`=?,`address`=?,`postcode`=?,`latitude`=?,`longitude`=?,`description`=?,`updated_at`=? WHERE id = ?").
-		WithArgs(dummyEnterprise[0].Name, dummyEnterprise[0].NumberPhone, dummyEnterprise[0].Address,
-			int64(dummyEnterprise[0].Postcode), dummyEnterprise[0].Latitude, dummyEnterprise[0].Longitude, dummyEnterprise[0].Description,
-			AnyTime{}, dummyEnterprise[0].ID).WillReturnResult(sqlMock.NewResult(1, 1))
-	mock.ExpectCommit()
+	mock.ExpectQuery("SELECT * FROM (SELECT enterprises.*, "+nearbyHaversine+" AS distance FROM `enterprises` "+
+		"WHERE status = ? AND (latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?)) AS nearby WHERE distance <= ? ORDER BY distance").
+		WithArgs(6371.0088, -3.442821, -3.442821, 114.740106, -3.442821, -3.442821, 114.740106,
+			1, sqlMock.AnyArg(), sqlMock.AnyArg(), sqlMock.AnyArg(), sqlMock.AnyArg(), float64(25)).
+		WillReturnRows(sqlMock.
+			NewRows([]string{"id", "name", "user_id", "status", "longitude", "latitude", "distance"}).
+			AddRow(uuid.NewV4(), "near", dummyEnterprise[1].UserID, 1, 114.741, -3.443, 0.1).
+			AddRow(uuid.NewV4(), "far", dummyEnterprise[0].UserID, 1, 114.590111, -3.316694, 21.78))
+	mock.ExpectQuery("SELECT * FROM `enterprise_images` WHERE `enterprise_images`.`enterprise_id` IN (?,?) ORDER BY created_at").
+		WillReturnRows(sqlMock.NewRows([]string{"id", "enterprise_id"}))
+	mock.ExpectQuery("SELECT * FROM `enterprise_tags` WHERE `enterprise_tags`.`enterprise_id` IN (?,?)").
+		WillReturnRows(sqlMock.NewRows([]string{"enterprise_id", "tag_id"}))
 
 	enterpriseRepository := repository.NewEnterpriseRepository(db)
-	_, err = enterpriseRepository.Update(dummyEnterprise[0])
+	enterprises, err := enterpriseRepository.FindNearby(-3.442821, 114.740106, 25, nil)
+	assert.NoError(t, err)
+	assert.Len(t, enterprises, 2)
+	assert.Equal(t, "near", enterprises[0].Name)
+	assert.Equal(t, "far", enterprises[1].Name)
+	assert.Equal(t, -3.316694, *enterprises[1].Latitude)
+	assert.Equal(t, 21.78, enterprises[1].Distance)
+}
+
+func TestEnterpriseRepository_FindNearbyTags(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	if err != nil {
-		assert.Error(t, err)
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
-	//assert.NoError(t, err)
-	//assert.NotNil(t, enterprise)
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM (SELECT enterprises.*, " + nearbyHaversine + " AS distance FROM `enterprises` " +
+		"WHERE status = ? AND (latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?) " +
+		"AND id IN (SELECT enterprise_id FROM `enterprise_tags` WHERE tag_id IN (?))) AS nearby WHERE distance <= ? ORDER BY distance").
+		WillReturnRows(sqlMock.NewRows([]string{"id", "name", "user_id", "status", "longitude", "latitude", "distance"}))
+
+	enterpriseRepository := repository.NewEnterpriseRepository(db)
+	enterprises, err := enterpriseRepository.FindNearby(-3.442821, 114.740106, 25, []string{"tag-1"})
+	assert.NoError(t, err)
+	assert.Len(t, enterprises, 0)
 }
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+)
+
+type transitions map[domain.EnterpriseStatus][]domain.EnterpriseStatus
+
+// ownerTransitions are the moves the owner of an enterprise may make on it.
+var ownerTransitions = transitions{
+	domain.EnterpriseDraft:     {domain.EnterpriseSubmitted, domain.EnterpriseArchived},
+	domain.EnterpriseSubmitted: {domain.EnterpriseDraft},
+	domain.EnterpriseRejected:  {domain.EnterpriseSubmitted, domain.EnterpriseArchived},
+	domain.EnterprisePublished: {domain.EnterpriseArchived},
+	domain.EnterpriseArchived:  {domain.EnterpriseDraft},
+}
+
+// moderatorTransitions are the moves open to users with enterprise:publish; a draft that was never submitted cannot be published.
+var moderatorTransitions = transitions{
+	domain.EnterpriseDraft:     {domain.EnterpriseArchived},
+	domain.EnterpriseSubmitted: {domain.EnterprisePublished, domain.EnterpriseRejected, domain.EnterpriseArchived},
+	domain.EnterpriseRejected:  {domain.EnterpriseArchived},
+	domain.EnterprisePublished: {domain.EnterpriseSuspended, domain.EnterpriseArchived},
+	domain.EnterpriseSuspended: {domain.EnterprisePublished, domain.EnterpriseArchived},
+}
+
+func (t transitions) allows(from, to domain.EnterpriseStatus) bool {
+	for _, next := range t[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+func reasonRequired(status domain.EnterpriseStatus) bool {
+	return status == domain.EnterpriseRejected || status == domain.EnterpriseSuspended
+}
+
+// checkTransition allows the move when the actor is the owner or a moderator and that side may make it.
+// A move that only the other side may make is forbidden, a move nobody may make is a bad request.
+func checkTransition(actor domain.User, enterprise domain.Enterprise, to domain.EnterpriseStatus) error {
+	byOwner := ownerTransitions.allows(enterprise.Status, to)
+	byModerator := moderatorTransitions.allows(enterprise.Status, to)
+	if enterprise.UserID == actor.ID && byOwner {
+		return nil
+	}
+	if actor.HasPermission(role.EnterprisePublish.String()) && byModerator {
+		return nil
+	}
+	if byOwner || byModerator {
+		return domain.NewForbiddenError("not allowed to change status from " + enterprise.Status.String() + " to " + to.String())
+	}
+	return errors.New("cannot change status from " + enterprise.Status.String() + " to " + to.String())
+}
@@ -3,25 +3,42 @@ package usecase
 import (
 	"errors"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/enterprise/utils"
+	"github.com/nrmadi02/mini-project/internal/policy"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
 	request2 "github.com/nrmadi02/mini-project/web/request"
 	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"strings"
 )
 
 type enterpriseUsecase struct {
 	enterpriseRepository domain.EnterpriseRepository
 	tagRepository        domain.TagRepository
 	userRepository       domain.UserRepository
+	searcher             domain.Searcher
+	blobs                domain.BlobStore
+	audit                domain.AuditUsecase
+	policy               policy.Policy
 }
 
-func NewEnterpriseUsecase(er domain.EnterpriseRepository, tr domain.TagRepository, ur domain.UserRepository) domain.EnterpriseUsecase {
+func NewEnterpriseUsecase(er domain.EnterpriseRepository, tr domain.TagRepository, ur domain.UserRepository, searcher domain.Searcher, blobs domain.BlobStore, audit domain.AuditUsecase) domain.EnterpriseUsecase {
 	return enterpriseUsecase{
 		enterpriseRepository: er,
 		tagRepository:        tr,
 		userRepository:       ur,
+		searcher:             searcher,
+		blobs:                blobs,
+		audit:                audit,
+		policy:               policy.NewPolicy(ur),
 	}
 }
 
 func (e enterpriseUsecase) CreateNewEnterprise(request request2.CreateEnterpriseRequest, userid string) (domain.Enterprise, error) {
+	if err := validateCoordinate(request.Latitude, request.Longitude); err != nil {
+		return domain.Enterprise{}, err
+	}
+
 	tagsList, err := e.tagRepository.FindByIDs(request.Tags)
 	if err != nil {
 		return domain.Enterprise{}, err
@@ -42,7 +59,7 @@ func (e enterpriseUsecase) CreateNewEnterprise(request request2.CreateEnterprise
 		Description: request.Description,
 		Latitude:    request.Latitude,
 		Longitude:   request.Longitude,
-		Status:      0,
+		Status:      domain.EnterpriseDraft,
 		Tags:        tagsList,
 	}
 
@@ -50,17 +67,76 @@ func (e enterpriseUsecase) CreateNewEnterprise(request request2.CreateEnterprise
 	if err != nil {
 		return domain.Enterprise{}, err
 	}
+	e.index(res)
 	return res, err
 }
 
-func (e enterpriseUsecase) UpdateStatusEnterprise(id string, status int) (domain.Enterprise, error) {
-	res, err := e.enterpriseRepository.UpdateStatusByID(id, status)
+func (e enterpriseUsecase) UpdateStatusEnterprise(id string, actor domain.Actor, request request2.UpdateStatusRequest) (domain.Enterprise, error) {
+	status, err := domain.ParseEnterpriseStatus(request.Status)
+	if err != nil {
+		return domain.Enterprise{}, err
+	}
+	reason := strings.TrimSpace(request.Reason)
+	if reasonRequired(status) && reason == "" {
+		return domain.Enterprise{}, errors.New("reason is required for status " + status.String())
+	}
+
+	enterprise, err := e.enterpriseRepository.FindByID(id)
+	if err != nil {
+		return domain.Enterprise{}, err
+	}
+	if enterprise.ID == uuid.FromStringOrNil("") {
+		return domain.Enterprise{}, errors.New("enterprise not found")
+	}
+
+	actorUser, err := e.userRepository.FindUserById(actor.UserID)
+	if err != nil {
+		return domain.Enterprise{}, err
+	}
+	if err = checkTransition(actorUser, enterprise, status); err != nil {
+		return domain.Enterprise{}, err
+	}
+
+	history := domain.EnterpriseStatusHistory{
+		ID:           uuid.NewV4(),
+		EnterpriseID: enterprise.ID,
+		ActorID:      actorUser.ID,
+		From:         enterprise.Status,
+		To:           status,
+		Reason:       reason,
+	}
+	before := map[string]interface{}{"status": enterprise.Status.String(), "status_reason": enterprise.StatusReason}
+	enterprise.Status = status
+	enterprise.StatusReason = reason
+
+	res, err := e.enterpriseRepository.UpdateStatus(enterprise, history)
 	if err != nil {
 		return domain.Enterprise{}, err
 	}
+	e.audit.Record(actor, domain.AuditEnterpriseStatus, domain.AuditTargetEnterprise, id, before,
+		map[string]interface{}{"status": status.String(), "status_reason": reason})
 	return res, err
 }
 
+func (e enterpriseUsecase) GetStatusHistories(id string, actor domain.Actor) (domain.EnterpriseStatusHistories, error) {
+	enterprise, err := e.enterpriseRepository.FindByID(id)
+	if err != nil {
+		return domain.EnterpriseStatusHistories{}, err
+	}
+	if enterprise.ID == uuid.FromStringOrNil("") {
+		return domain.EnterpriseStatusHistories{}, errors.New("enterprise not found")
+	}
+	if err = e.policy.OwnerOr(actor, enterprise.UserID, role.EnterprisePublish, "see status history"); err != nil {
+		return domain.EnterpriseStatusHistories{}, err
+	}
+
+	histories, err := e.enterpriseRepository.FindStatusHistories(id)
+	if err != nil {
+		return domain.EnterpriseStatusHistories{}, err
+	}
+	return histories, err
+}
+
 func (e enterpriseUsecase) GetDetailEnterpriseByID(id string) (domain.Enterprise, error) {
 	enterprise, err := e.enterpriseRepository.FindByID(id)
 	if err != nil {
@@ -69,17 +145,46 @@ func (e enterpriseUsecase) GetDetailEnterpriseByID(id string) (domain.Enterprise
 	return enterprise, err
 }
 
-func (e enterpriseUsecase) GetListEnterpriseByStatus(status int) (domain.Enterprises, error) {
-	var enterprises domain.Enterprises
-	var err error
-	if status == 0 {
-		enterprises, err = e.enterpriseRepository.FindByStatusDraft()
-	} else if status == 1 {
-		enterprises, err = e.enterpriseRepository.FindByStatusPublish()
-	} else {
-		return domain.Enterprises{}, errors.New("something wrong")
+func (e enterpriseUsecase) GetDistanceEnterprise(id string, request request2.DistanceRequest) (float64, domain.Enterprise, error) {
+	unit, err := geo.ParseUnit(request.Unit)
+	if err != nil {
+		return 0, domain.Enterprise{}, err
 	}
 
+	enterprise, err := e.enterpriseRepository.FindByID(id)
+	if err != nil {
+		return 0, domain.Enterprise{}, err
+	}
+	if enterprise.ID == uuid.FromStringOrNil("") {
+		return 0, domain.Enterprise{}, errors.New("enterprise not found")
+	}
+
+	if enterprise.Latitude == nil || enterprise.Longitude == nil {
+		return 0, domain.Enterprise{}, errors.New("enterprise coordinate not set")
+	}
+
+	distance := geo.Distance(request.Latitude, request.Longitude, *enterprise.Latitude, *enterprise.Longitude, unit)
+	return distance, enterprise, nil
+}
+
+func (e enterpriseUsecase) GetNearbyEnterprises(request request2.NearbyRequest) (domain.Enterprises, error) {
+	if !geo.ValidCoordinate(request.Latitude, request.Longitude) || request.RadiusKm <= 0 {
+		return domain.Enterprises{}, errors.New("coordinate or radius invalid")
+	}
+
+	enterprises, err := e.enterpriseRepository.FindNearby(request.Latitude, request.Longitude, request.RadiusKm, request.Tags)
+	if err != nil {
+		return domain.Enterprises{}, err
+	}
+	return enterprises, nil
+}
+
+func (e enterpriseUsecase) GetListEnterpriseByStatus(status domain.EnterpriseStatus) (domain.Enterprises, error) {
+	if status.String() == "" {
+		return domain.Enterprises{}, errors.New("status not found")
+	}
+
+	enterprises, err := e.enterpriseRepository.FindByStatus(status)
 	if err != nil {
 		return domain.Enterprises{}, err
 	}
@@ -87,7 +192,11 @@ func (e enterpriseUsecase) GetListEnterpriseByStatus(status int) (domain.Enterpr
 	return enterprises, err
 }
 
-func (e enterpriseUsecase) UpdateEnterpriseByID(id string, userid string, request request2.CreateEnterpriseRequest) (domain.Enterprise, error) {
+func (e enterpriseUsecase) UpdateEnterpriseByID(id string, actor domain.Actor, request request2.CreateEnterpriseRequest) (domain.Enterprise, error) {
+	if err := validateCoordinate(request.Latitude, request.Longitude); err != nil {
+		return domain.Enterprise{}, err
+	}
+
 	tagsList, err := e.tagRepository.FindByIDs(request.Tags)
 	if err != nil {
 		return domain.Enterprise{}, err
@@ -98,8 +207,11 @@ func (e enterpriseUsecase) UpdateEnterpriseByID(id string, userid string, reques
 		return domain.Enterprise{}, err
 	}
 
-	if enterpriseByID.UserID.String() != userid {
-		return domain.Enterprise{}, errors.New("to update enterprise must current user")
+	if enterpriseByID.ID == uuid.FromStringOrNil("") {
+		return domain.Enterprise{}, errors.New("enterprise not found")
+	}
+	if err = e.policy.OwnerOr(actor, enterpriseByID.UserID, role.EnterpriseManage, "update enterprise"); err != nil {
+		return domain.Enterprise{}, err
 	}
 	enterpriseByID = domain.Enterprise{
 		ID:          enterpriseByID.ID,
@@ -115,27 +227,136 @@ func (e enterpriseUsecase) UpdateEnterpriseByID(id string, userid string, reques
 		Tags:        tagsList,
 	}
 	res, err := e.enterpriseRepository.Update(enterpriseByID)
+	if err != nil {
+		return res, err
+	}
+	e.index(res)
 	return res, err
 }
 
-func (e enterpriseUsecase) GetListAllEnterprise(search string, page, length int) (enterprises domain.Enterprises, totalData int, err error) {
-	enterprises, totalData, err = e.enterpriseRepository.FindAll(search, page, length)
+// index brings the enterprise up to date in the searcher. A failure only leaves search results
+// stale until the next change or reindex, so it does not fail the request.
+func (e enterpriseUsecase) index(enterprise domain.Enterprise) {
+	if err := e.searcher.Index(enterprise); err != nil {
+		log.WithField("enterprise_id", enterprise.ID.String()).Warn("index enterprise: " + err.Error())
+	}
+}
+
+func (e enterpriseUsecase) ReindexSearch() error {
+	var all domain.Enterprises
+	page := domain.PageRequest{Length: 100}
+	for {
+		enterprises, info, err := e.enterpriseRepository.FindAll(domain.EnterpriseFilter{}, page)
+		if err != nil {
+			return err
+		}
+		all = append(all, enterprises...)
+		if info.Next == nil {
+			return e.searcher.Reindex(all)
+		}
+		page.Cursor = info.Next
+	}
+}
+
+// maxSearchHits bounds the enterprises a search lists, the rest are too far down to be looked at.
+const maxSearchHits = 1000
+
+func (e enterpriseUsecase) GetListAllEnterprise(request request2.ListEnterpriseRequest, page domain.PageRequest) (domain.Enterprises, domain.PageInfo, error) {
+	filter := domain.EnterpriseFilter{
+		TagIDs:    request.Tags,
+		AllTags:   request.TagMatch == "all",
+		Postcode:  request.Postcode,
+		OwnerID:   request.OwnerID,
+		MinRating: request.MinRating,
+		Sort:      domain.EnterpriseSort(request.Sort),
+	}
+	if request.Status != "" {
+		status, err := domain.ParseEnterpriseStatus(request.Status)
+		if err != nil {
+			return domain.Enterprises{}, domain.PageInfo{}, err
+		}
+		filter.Status = &status
+	}
+	if request.CreatedFrom != nil {
+		filter.CreatedFrom = *request.CreatedFrom
+	}
+	if request.CreatedTo != nil {
+		// created_to is a whole day, the filter bound is exclusive.
+		filter.CreatedTo = request.CreatedTo.AddDate(0, 0, 1)
+	}
+	if request.Latitude != nil && request.Longitude != nil {
+		filter.Latitude, filter.Longitude = *request.Latitude, *request.Longitude
+	}
+	if strings.TrimSpace(request.Search) != "" {
+		hits, err := e.searcher.Search(request.Search, maxSearchHits)
+		if err != nil {
+			return domain.Enterprises{}, domain.PageInfo{}, err
+		}
+		if len(hits) == 0 {
+			return domain.Enterprises{}, domain.PageInfo{}, nil
+		}
+		for _, hit := range hits {
+			filter.IDs = append(filter.IDs, hit.ID)
+		}
+		if filter.Sort == "" {
+			filter.Sort = domain.SortEnterpriseRelevance
+		}
+	}
+
+	enterprises, info, err := e.enterpriseRepository.FindAll(filter, page)
 	if err != nil {
-		return domain.Enterprises{}, 0, err
+		return domain.Enterprises{}, domain.PageInfo{}, err
 	}
-	return enterprises, totalData, err
+	return enterprises, info, nil
 }
 
-func (e enterpriseUsecase) DeleteEnterpriseByID(id string) error {
+func (e enterpriseUsecase) DeleteEnterpriseByID(id string, actor domain.Actor) error {
 	enterprise, err := e.enterpriseRepository.FindByID(id)
 	if err != nil {
 		return err
 	}
+	if enterprise.ID == uuid.FromStringOrNil("") {
+		return errors.New("enterprise not found")
+	}
+	if err = e.policy.OwnerOr(actor, enterprise.UserID, role.EnterpriseManage, "delete enterprise"); err != nil {
+		return err
+	}
 
 	err = e.enterpriseRepository.Delete(enterprise)
 	if err != nil {
 		return err
 	}
+	if err := e.searcher.Remove(id); err != nil {
+		log.WithField("enterprise_id", id).Warn("remove enterprise from search: " + err.Error())
+	}
+	// the image rows went with the enterprise, a file left behind only costs storage.
+	for _, image := range enterprise.Images {
+		if err := e.blobs.Delete(image.Key); err != nil {
+			log.WithField("key", image.Key).Warn("delete image file: " + err.Error())
+		}
+	}
 
+	e.audit.Record(actor, domain.AuditEnterpriseDelete, domain.AuditTargetEnterprise, id, map[string]interface{}{
+		"name":    enterprise.Name,
+		"user_id": enterprise.UserID.String(),
+		"status":  enterprise.Status.String(),
+	}, nil)
 	return err
 }
+
+// validateCoordinate allows an enterprise without location, but never half of one.
+func validateCoordinate(latitude, longitude *float64) error {
+	if latitude == nil && longitude == nil {
+		return nil
+	}
+	if latitude == nil || longitude == nil {
+		return errors.New("latitude and longitude must be set together")
+	}
+	if *latitude < -90 || *latitude > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if *longitude < -180 || *longitude > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	return nil
+}
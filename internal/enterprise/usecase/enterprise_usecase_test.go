@@ -54,6 +54,8 @@ var dummyUser = domain.Users{
 	},
 }
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = domain.Enterprises{
 	domain.Enterprise{
 		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
@@ -62,8 +64,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Status:      0,
 		Tags: domain.Tags{
@@ -84,8 +86,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone:      "0012798232",
 		Address:          "bjm",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -96,6 +98,29 @@ var dummyEnterprise = domain.Enterprises{
 	},
 }
 
+// newAuditUsecase accepts every audit entry, tests of an entry assert it on their own mock.
+func newAuditUsecase() *mocks.AuditUsecase {
+	auditUsecase := new(mocks.AuditUsecase)
+	auditUsecase.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return auditUsecase
+}
+
+// newSearcher accepts any index change, searches have to be expected by the test.
+func newSearcher() *mocks.Searcher {
+	searcher := new(mocks.Searcher)
+	searcher.On("Index", mock.Anything).Return(nil).Maybe()
+	searcher.On("Remove", mock.Anything).Return(nil).Maybe()
+	searcher.On("Reindex", mock.Anything).Return(nil).Maybe()
+	return searcher
+}
+
+// newBlobStore accepts every file deletion.
+func newBlobStore() *mocks.BlobStore {
+	blobs := new(mocks.BlobStore)
+	blobs.On("Delete", mock.Anything).Return(nil).Maybe()
+	return blobs
+}
+
 func TestEnterpriseUsecase_CreateNewEnterprise(t *testing.T) {
 	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
 	mockTagRepository := new(mocks.TagRepository)
@@ -107,11 +132,11 @@ func TestEnterpriseUsecase_CreateNewEnterprise(t *testing.T) {
 			NumberPhone: "0012798232",
 			Address:     "bjb",
 			Postcode:    707722,
-			Latitude:    "4235,23",
-			Longitude:   "4225,233231",
+			Latitude:    &dummyLatitude,
+			Longitude:   &dummyLongitude,
 			Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 		}
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{
 			domain.Tag{
 				ID:   uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b"),
@@ -132,11 +157,11 @@ func TestEnterpriseUsecase_CreateNewEnterprise(t *testing.T) {
 			NumberPhone: "0012798232",
 			Address:     "bjb",
 			Postcode:    707722,
-			Latitude:    "4235,23",
-			Longitude:   "4225,233231",
+			Latitude:    &dummyLatitude,
+			Longitude:   &dummyLongitude,
 			Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 		}
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{}, errors.New("tag not found")).Once()
 		_, err := uc.CreateNewEnterprise(req, dummyEnterprise[0].UserID.String())
 		assert.Error(t, err)
@@ -149,11 +174,11 @@ func TestEnterpriseUsecase_CreateNewEnterprise(t *testing.T) {
 			NumberPhone: "0012798232",
 			Address:     "bjb",
 			Postcode:    707722,
-			Latitude:    "4235,23",
-			Longitude:   "4225,233231",
+			Latitude:    &dummyLatitude,
+			Longitude:   &dummyLongitude,
 			Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 		}
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{
 			domain.Tag{
 				ID:   uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b"),
@@ -172,11 +197,11 @@ func TestEnterpriseUsecase_CreateNewEnterprise(t *testing.T) {
 			NumberPhone: "0012798232",
 			Address:     "bjb",
 			Postcode:    707722,
-			Latitude:    "4235,23",
-			Longitude:   "4225,233231",
+			Latitude:    &dummyLatitude,
+			Longitude:   &dummyLongitude,
 			Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 		}
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{
 			domain.Tag{
 				ID:   uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b"),
@@ -190,6 +215,25 @@ func TestEnterpriseUsecase_CreateNewEnterprise(t *testing.T) {
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 
+	t.Run("invalid coordinate", func(t *testing.T) {
+		outOfRange := 91.5
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		_, err := uc.CreateNewEnterprise(request.CreateEnterpriseRequest{
+			Name:      "enterprise satu",
+			Latitude:  &outOfRange,
+			Longitude: &dummyLongitude,
+		}, dummyEnterprise[0].UserID.String())
+		assert.EqualError(t, err, "latitude must be between -90 and 90")
+
+		_, err = uc.CreateNewEnterprise(request.CreateEnterpriseRequest{
+			Name:     "enterprise satu",
+			Latitude: &dummyLatitude,
+		}, dummyEnterprise[0].UserID.String())
+		assert.EqualError(t, err, "latitude and longitude must be set together")
+		mockEnterpriseRepository.AssertExpectations(t)
+		mockTagRepository.AssertExpectations(t)
+	})
+
 }
 
 func TestEnterpriseUsecase_DeleteEnterpriseByID(t *testing.T) {
@@ -198,30 +242,70 @@ func TestEnterpriseUsecase_DeleteEnterpriseByID(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockEnterpriseRepository.On("Delete", mock.AnythingOfType("domain.Enterprise")).Return(nil).Once()
-		err := uc.DeleteEnterpriseByID(dummyEnterprise[0].UserID.String())
+		err := uc.DeleteEnterpriseByID(dummyEnterprise[0].UserID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()})
 		assert.NoError(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 
 	t.Run("enterprise not found", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("enterprise not found")).Once()
-		err := uc.DeleteEnterpriseByID(dummyEnterprise[0].UserID.String())
+		err := uc.DeleteEnterpriseByID(dummyEnterprise[0].UserID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()})
 		assert.Error(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 
 	t.Run("failed delete", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockEnterpriseRepository.On("Delete", mock.AnythingOfType("domain.Enterprise")).Return(errors.New("failed delete")).Once()
-		err := uc.DeleteEnterpriseByID(dummyEnterprise[0].UserID.String())
+		err := uc.DeleteEnterpriseByID(dummyEnterprise[0].UserID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()})
 		assert.Error(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
+
+	t.Run("success manager", func(t *testing.T) {
+		manager := domain.User{ID: uuid.NewV4(), Roles: []domain.Role{{Name: "ROLE_ADMIN", ID: 1, Permissions: []domain.Permission{{ID: 3, Name: "enterprise:manage"}}}}}
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise[0].ID.String()).Return(dummyEnterprise[0], nil).Once()
+		mockUserRepository.On("FindUserById", manager.ID.String()).Return(manager, nil).Once()
+		mockEnterpriseRepository.On("Delete", dummyEnterprise[0]).Return(nil).Once()
+		err := uc.DeleteEnterpriseByID(dummyEnterprise[0].ID.String(), domain.Actor{UserID: manager.ID.String()})
+		assert.NoError(t, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise[0].ID.String()).Return(dummyEnterprise[0], nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		err := uc.DeleteEnterpriseByID(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyUser[1].ID.String()})
+		assert.IsType(t, &domain.ForbiddenError{}, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("deletes image files", func(t *testing.T) {
+		enterprise := dummyEnterprise[0]
+		enterprise.Images = []domain.EnterpriseImage{
+			{ID: uuid.NewV4(), EnterpriseID: enterprise.ID, Kind: domain.ImageLogo, Key: "enterprises/" + enterprise.ID.String() + "/logo.png"},
+			{ID: uuid.NewV4(), EnterpriseID: enterprise.ID, Kind: domain.ImageGallery, Key: "enterprises/" + enterprise.ID.String() + "/gallery.jpg"},
+		}
+		blobs := new(mocks.BlobStore)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), blobs, newAuditUsecase())
+		mockEnterpriseRepository.On("FindByID", enterprise.ID.String()).Return(enterprise, nil).Once()
+		mockEnterpriseRepository.On("Delete", enterprise).Return(nil).Once()
+		blobs.On("Delete", enterprise.Images[0].Key).Return(nil).Once()
+		// a file that cannot be deleted does not fail the deletion.
+		blobs.On("Delete", enterprise.Images[1].Key).Return(errors.New("store unavailable")).Once()
+		err := uc.DeleteEnterpriseByID(enterprise.ID.String(), domain.Actor{UserID: enterprise.UserID.String()})
+		assert.NoError(t, err)
+		blobs.AssertExpectations(t)
+	})
 }
 
 func TestEnterpriseUsecase_GetDetailEnterpriseByID(t *testing.T) {
@@ -230,7 +314,7 @@ func TestEnterpriseUsecase_GetDetailEnterpriseByID(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		enterprise, err := uc.GetDetailEnterpriseByID(dummyEnterprise[0].ID.String())
 		assert.NoError(t, err)
@@ -239,7 +323,7 @@ func TestEnterpriseUsecase_GetDetailEnterpriseByID(t *testing.T) {
 	})
 
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("error something")).Once()
 		_, err := uc.GetDetailEnterpriseByID(dummyEnterprise[0].ID.String())
 		assert.Error(t, err)
@@ -253,66 +337,137 @@ func TestEnterpriseUsecase_GetListAllEnterprise(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
-		mockEnterpriseRepository.On("FindAll", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("int")).Return(domain.Enterprises{
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindAll", domain.EnterpriseFilter{}, domain.PageRequest{Length: 20}).Return(domain.Enterprises{
 			dummyEnterprise[0],
-		}, 1, nil).Once()
-		enterprise, totalData, err := uc.GetListAllEnterprise("satu", 1, 1)
+		}, domain.PageInfo{}, nil).Once()
+		enterprise, _, err := uc.GetListAllEnterprise(request.ListEnterpriseRequest{}, domain.PageRequest{Length: 20})
 		assert.NoError(t, err)
 		assert.NotNil(t, enterprise)
-		assert.NotNil(t, totalData)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
-		mockEnterpriseRepository.On("FindAll", mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("int")).Return(domain.Enterprises{}, 1, errors.New("error something")).Once()
-		_, _, err := uc.GetListAllEnterprise("satu", 1, 1)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindAll", mock.AnythingOfType("domain.EnterpriseFilter"), domain.PageRequest{Length: 20}).Return(domain.Enterprises{}, domain.PageInfo{}, errors.New("error something")).Once()
+		_, _, err := uc.GetListAllEnterprise(request.ListEnterpriseRequest{}, domain.PageRequest{Length: 20})
 		assert.Error(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
-}
 
-func TestEnterpriseUsecase_GetListEnterpriseByStatus(t *testing.T) {
-	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
-	mockTagRepository := new(mocks.TagRepository)
-	mockUserRepository := new(mocks.UserRepository)
+	t.Run("search by relevance", func(t *testing.T) {
+		searcher := newSearcher()
+		searcher.On("Search", "warung satu", 1000).Return([]domain.SearchHit{
+			{ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", Score: 2.5},
+			{ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89b", Score: 1.2},
+		}, nil).Once()
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, searcher, newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindAll", domain.EnterpriseFilter{
+			IDs:  []string{"35d6a9a1-aa5e-41f1-9991-08878dfdf89a", "35d6a9a1-aa5e-41f1-9991-08878dfdf89b"},
+			Sort: domain.SortEnterpriseRelevance,
+		}, domain.PageRequest{Length: 20}).Return(domain.Enterprises{dummyEnterprise[0]}, domain.PageInfo{}, nil).Once()
+		enterprises, _, err := uc.GetListAllEnterprise(request.ListEnterpriseRequest{Search: "warung satu"}, domain.PageRequest{Length: 20})
+		assert.NoError(t, err)
+		assert.Len(t, enterprises, 1)
+		mockEnterpriseRepository.AssertExpectations(t)
+		searcher.AssertExpectations(t)
+	})
 
-	t.Run("success get list draft", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
-		mockEnterpriseRepository.On("FindByStatusDraft").Return(dummyEnterprise, nil).Once()
-		enterprises, err := uc.GetListEnterpriseByStatus(0)
+	t.Run("search keeps sort", func(t *testing.T) {
+		searcher := newSearcher()
+		searcher.On("Search", "satu", 1000).Return([]domain.SearchHit{{ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", Score: 1}}, nil).Once()
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, searcher, newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindAll", domain.EnterpriseFilter{
+			IDs:  []string{"35d6a9a1-aa5e-41f1-9991-08878dfdf89a"},
+			Sort: domain.SortEnterpriseNewest,
+		}, domain.PageRequest{Length: 20}).Return(domain.Enterprises{dummyEnterprise[0]}, domain.PageInfo{}, nil).Once()
+		_, _, err := uc.GetListAllEnterprise(request.ListEnterpriseRequest{Search: "satu", Sort: "newest"}, domain.PageRequest{Length: 20})
 		assert.NoError(t, err)
-		assert.NotNil(t, enterprises)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
-	t.Run("failed get list draft", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
-		mockEnterpriseRepository.On("FindByStatusDraft").Return(domain.Enterprises{}, errors.New("error something")).Once()
-		_, err := uc.GetListEnterpriseByStatus(0)
-		assert.Error(t, err)
+
+	t.Run("search without hits", func(t *testing.T) {
+		searcher := newSearcher()
+		searcher.On("Search", "tidak ada", 1000).Return([]domain.SearchHit{}, nil).Once()
+		// FindAll is not expected, calling it fails the test.
+		uc := usecase.NewEnterpriseUsecase(new(mocks.EnterpriseRepository), mockTagRepository, mockUserRepository, searcher, newBlobStore(), newAuditUsecase())
+		enterprises, info, err := uc.GetListAllEnterprise(request.ListEnterpriseRequest{Search: "tidak ada"}, domain.PageRequest{Length: 20})
+		assert.NoError(t, err)
+		assert.Empty(t, enterprises)
+		assert.Nil(t, info.Next)
+	})
+
+	t.Run("search failed", func(t *testing.T) {
+		searcher := newSearcher()
+		searcher.On("Search", "satu", 1000).Return(nil, errors.New("index closed")).Once()
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, searcher, newBlobStore(), newAuditUsecase())
+		_, _, err := uc.GetListAllEnterprise(request.ListEnterpriseRequest{Search: "satu"}, domain.PageRequest{Length: 20})
+		assert.EqualError(t, err, "index closed")
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2022, 5, 31, 0, 0, 0, 0, time.UTC)
+		lat, lon := -3.44, 114.74
+		published := domain.EnterprisePublished
+		mockEnterpriseRepository.On("FindAll", domain.EnterpriseFilter{
+			TagIDs:      []string{"tag"},
+			AllTags:     true,
+			Status:      &published,
+			MinRating:   4,
+			CreatedFrom: from,
+			CreatedTo:   time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+			Sort:        domain.SortEnterpriseDistance,
+			Latitude:    lat,
+			Longitude:   lon,
+		}, domain.PageRequest{Length: 20}).Return(domain.Enterprises{dummyEnterprise[0]}, domain.PageInfo{}, nil).Once()
+		_, _, err := uc.GetListAllEnterprise(request.ListEnterpriseRequest{
+			Tags:        []string{"tag"},
+			TagMatch:    "all",
+			Status:      "published",
+			MinRating:   4,
+			CreatedFrom: &from,
+			CreatedTo:   &to,
+			Sort:        "distance",
+			Latitude:    &lat,
+			Longitude:   &lon,
+		}, domain.PageRequest{Length: 20})
+		assert.NoError(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 
-	t.Run("success get list publish", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
-		mockEnterpriseRepository.On("FindByStatusPublish").Return(dummyEnterprise, nil).Once()
-		enterprises, err := uc.GetListEnterpriseByStatus(1)
+	t.Run("unknown status", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		_, _, err := uc.GetListAllEnterprise(request.ListEnterpriseRequest{Status: "hidden"}, domain.PageRequest{Length: 20})
+		assert.Error(t, err)
+	})
+}
+
+func TestEnterpriseUsecase_GetListEnterpriseByStatus(t *testing.T) {
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTagRepository := new(mocks.TagRepository)
+	mockUserRepository := new(mocks.UserRepository)
+
+	t.Run("success get list submitted", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByStatus", domain.EnterpriseSubmitted).Return(dummyEnterprise, nil).Once()
+		enterprises, err := uc.GetListEnterpriseByStatus(domain.EnterpriseSubmitted)
 		assert.NoError(t, err)
 		assert.NotNil(t, enterprises)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
-	t.Run("failed get list publish", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
-		mockEnterpriseRepository.On("FindByStatusPublish").Return(domain.Enterprises{}, errors.New("error something")).Once()
-		_, err := uc.GetListEnterpriseByStatus(1)
+	t.Run("failed get list draft", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByStatus", domain.EnterpriseDraft).Return(domain.Enterprises{}, errors.New("error something")).Once()
+		_, err := uc.GetListEnterpriseByStatus(domain.EnterpriseDraft)
 		assert.Error(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 
-	t.Run("failed get list", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
-		_, err := uc.GetListEnterpriseByStatus(2)
+	t.Run("unknown status", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		_, err := uc.GetListEnterpriseByStatus(domain.EnterpriseStatus(42))
 		assert.Error(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
@@ -329,11 +484,11 @@ func TestEnterpriseUsecase_UpdateEnterpriseByID(t *testing.T) {
 			NumberPhone: "0012798232",
 			Address:     "bjb",
 			Postcode:    707722,
-			Latitude:    "4235,23",
-			Longitude:   "4225,233231",
+			Latitude:    &dummyLatitude,
+			Longitude:   &dummyLongitude,
 			Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 		}
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{
 			domain.Tag{
 				ID:   uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b"),
@@ -342,23 +497,54 @@ func TestEnterpriseUsecase_UpdateEnterpriseByID(t *testing.T) {
 		}, nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockEnterpriseRepository.On("Update", mock.AnythingOfType("domain.Enterprise")).Return(dummyEnterprise[0], nil).Once()
-		enterprise, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), dummyEnterprise[0].UserID.String(), req)
+		enterprise, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()}, req)
 		assert.NoError(t, err)
 		assert.NotNil(t, enterprise)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 
+	t.Run("clears coordinates", func(t *testing.T) {
+		located := dummyEnterprise[0]
+		located.Latitude, located.Longitude = &dummyLatitude, &dummyLongitude
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockTagRepository.On("FindByIDs", []string(nil)).Return(domain.Tags{}, nil).Once()
+		mockEnterpriseRepository.On("FindByID", located.ID.String()).Return(located, nil).Once()
+		mockEnterpriseRepository.On("Update", mock.MatchedBy(func(enterprise domain.Enterprise) bool {
+			return enterprise.ID == located.ID && enterprise.Latitude == nil && enterprise.Longitude == nil
+		})).Return(func(enterprise domain.Enterprise) domain.Enterprise { return enterprise }, nil).Once()
+		enterprise, err := uc.UpdateEnterpriseByID(located.ID.String(), domain.Actor{UserID: located.UserID.String()}, request.CreateEnterpriseRequest{
+			Name:    "enterprise satu",
+			Address: "bjb",
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, enterprise.Latitude)
+		assert.Nil(t, enterprise.Longitude)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+
+	t.Run("invalid coordinate", func(t *testing.T) {
+		outOfRange := -180.5
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		_, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()}, request.CreateEnterpriseRequest{
+			Name:      "enterprise satu",
+			Latitude:  &dummyLatitude,
+			Longitude: &outOfRange,
+		})
+		assert.EqualError(t, err, "longitude must be between -180 and 180")
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+
 	t.Run("error not current user", func(t *testing.T) {
 		req := request.CreateEnterpriseRequest{
 			Name:        "enterprise satu",
 			NumberPhone: "0012798232",
 			Address:     "bjb",
 			Postcode:    707722,
-			Latitude:    "4235,23",
-			Longitude:   "4225,233231",
+			Latitude:    &dummyLatitude,
+			Longitude:   &dummyLongitude,
 			Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 		}
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{
 			domain.Tag{
 				ID:   uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b"),
@@ -366,8 +552,9 @@ func TestEnterpriseUsecase_UpdateEnterpriseByID(t *testing.T) {
 			},
 		}, nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[1], nil).Once()
-		_, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), dummyEnterprise[0].UserID.String(), req)
-		assert.Error(t, err)
+		mockUserRepository.On("FindUserById", dummyEnterprise[0].UserID.String()).Return(dummyUser[0], nil).Once()
+		_, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()}, req)
+		assert.IsType(t, &domain.ForbiddenError{}, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 
@@ -377,13 +564,13 @@ func TestEnterpriseUsecase_UpdateEnterpriseByID(t *testing.T) {
 			NumberPhone: "0012798232",
 			Address:     "bjb",
 			Postcode:    707722,
-			Latitude:    "4235,23",
-			Longitude:   "4225,233231",
+			Latitude:    &dummyLatitude,
+			Longitude:   &dummyLongitude,
 			Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 		}
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{}, errors.New("not found list tags")).Once()
-		_, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), dummyEnterprise[0].UserID.String(), req)
+		_, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()}, req)
 		assert.Error(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
@@ -394,11 +581,11 @@ func TestEnterpriseUsecase_UpdateEnterpriseByID(t *testing.T) {
 			NumberPhone: "0012798232",
 			Address:     "bjb",
 			Postcode:    707722,
-			Latitude:    "4235,23",
-			Longitude:   "4225,233231",
+			Latitude:    &dummyLatitude,
+			Longitude:   &dummyLongitude,
 			Tags:        []string{uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b").String()},
 		}
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
 		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{
 			domain.Tag{
 				ID:   uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b"),
@@ -406,31 +593,304 @@ func TestEnterpriseUsecase_UpdateEnterpriseByID(t *testing.T) {
 			},
 		}, nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("enterprise not found")).Once()
-		_, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), dummyEnterprise[0].UserID.String(), req)
+		_, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()}, req)
 		assert.Error(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 }
 
 func TestEnterpriseUsecase_UpdateStatusEnterprise(t *testing.T) {
+	admin := dummyUser[0]
+	admin.Roles = []domain.Role{{Name: "ROLE_ADMIN", ID: 1, Permissions: []domain.Permission{{ID: 2, Name: "enterprise:publish"}}}}
+	moderator := domain.User{ID: uuid.NewV4(), Roles: []domain.Role{{Name: "ROLE_MODERATOR", ID: 4, Permissions: []domain.Permission{{ID: 2, Name: "enterprise:publish"}}}}}
+	owner := dummyUser[1]
+	stranger := domain.User{ID: uuid.NewV4(), Roles: []domain.Role{{Name: "ROLE_CLIENT", ID: 2}}}
+
+	tests := []struct {
+		name      string
+		actor     domain.User
+		from      domain.EnterpriseStatus
+		request   request.UpdateStatusRequest
+		wantErr   string
+		forbidden bool
+	}{
+		{"owner submits draft", owner, domain.EnterpriseDraft, request.UpdateStatusRequest{Status: "submitted"}, "", false},
+		{"owner resubmits rejected", owner, domain.EnterpriseRejected, request.UpdateStatusRequest{Status: "submitted"}, "", false},
+		{"owner archives published", owner, domain.EnterprisePublished, request.UpdateStatusRequest{Status: "archived"}, "", false},
+		{"owner cannot approve", owner, domain.EnterpriseSubmitted, request.UpdateStatusRequest{Status: "published"}, "not allowed to change status from submitted to published", true},
+		{"admin approves submission", admin, domain.EnterpriseSubmitted, request.UpdateStatusRequest{Status: "approved"}, "", false},
+		{"admin rejects submission", admin, domain.EnterpriseSubmitted, request.UpdateStatusRequest{Status: "rejected", Reason: "address incomplete"}, "", false},
+		{"admin suspends published", admin, domain.EnterprisePublished, request.UpdateStatusRequest{Status: "suspended", Reason: "spam"}, "", false},
+		{"moderator approves submission", moderator, domain.EnterpriseSubmitted, request.UpdateStatusRequest{Status: "published"}, "", false},
+		{"admin role without permission cannot approve", dummyUser[0], domain.EnterpriseSubmitted, request.UpdateStatusRequest{Status: "published"}, "not allowed to change status from submitted to published", true},
+		{"admin cannot publish draft", admin, domain.EnterpriseDraft, request.UpdateStatusRequest{Status: "published"}, "cannot change status from draft to published", false},
+		{"stranger cannot submit", stranger, domain.EnterpriseDraft, request.UpdateStatusRequest{Status: "submitted"}, "not allowed to change status from draft to submitted", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+			mockTagRepository := new(mocks.TagRepository)
+			mockUserRepository := new(mocks.UserRepository)
+			enterprise := dummyEnterprise[1]
+			enterprise.UserID = owner.ID
+			enterprise.Status = tt.from
+
+			uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+			mockEnterpriseRepository.On("FindByID", enterprise.ID.String()).Return(enterprise, nil).Once()
+			mockUserRepository.On("FindUserById", tt.actor.ID.String()).Return(tt.actor, nil).Once()
+			if tt.wantErr == "" {
+				mockEnterpriseRepository.On("UpdateStatus", mock.AnythingOfType("domain.Enterprise"), mock.MatchedBy(func(history domain.EnterpriseStatusHistory) bool {
+					return history.From == tt.from && history.ActorID == tt.actor.ID && history.Reason == tt.request.Reason
+				})).Return(func(enterprise domain.Enterprise, _ domain.EnterpriseStatusHistory) domain.Enterprise {
+					return enterprise
+				}, nil).Once()
+			}
+
+			res, err := uc.UpdateStatusEnterprise(enterprise.ID.String(), domain.Actor{UserID: tt.actor.ID.String()}, tt.request)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				var forbidden *domain.ForbiddenError
+				assert.Equal(t, tt.forbidden, errors.As(err, &forbidden))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.request.Reason, res.StatusReason)
+			}
+			mockEnterpriseRepository.AssertExpectations(t)
+			mockUserRepository.AssertExpectations(t)
+		})
+	}
+
+	t.Run("reason required", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, new(mocks.TagRepository), new(mocks.UserRepository), newSearcher(), newBlobStore(), newAuditUsecase())
+		_, err := uc.UpdateStatusEnterprise(dummyEnterprise[0].ID.String(), domain.Actor{UserID: admin.ID.String()}, request.UpdateStatusRequest{Status: "rejected"})
+		assert.EqualError(t, err, "reason is required for status rejected")
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, new(mocks.TagRepository), new(mocks.UserRepository), newSearcher(), newBlobStore(), newAuditUsecase())
+		_, err := uc.UpdateStatusEnterprise(dummyEnterprise[0].ID.String(), domain.Actor{UserID: admin.ID.String()}, request.UpdateStatusRequest{Status: "1"})
+		assert.Error(t, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+
+	t.Run("failed to save", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		mockUserRepository := new(mocks.UserRepository)
+		enterprise := dummyEnterprise[0]
+		enterprise.Status = domain.EnterpriseSubmitted
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, new(mocks.TagRepository), mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(enterprise, nil).Once()
+		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(admin, nil).Once()
+		mockEnterpriseRepository.On("UpdateStatus", mock.AnythingOfType("domain.Enterprise"), mock.AnythingOfType("domain.EnterpriseStatusHistory")).Return(domain.Enterprise{}, errors.New("enterprise status has been changed")).Once()
+		_, err := uc.UpdateStatusEnterprise(enterprise.ID.String(), domain.Actor{UserID: admin.ID.String()}, request.UpdateStatusRequest{Status: "published"})
+		assert.Error(t, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+}
+
+func TestEnterpriseUsecase_GetStatusHistories(t *testing.T) {
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, new(mocks.TagRepository), mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+
+	mockEnterpriseRepository.On("FindByID", dummyEnterprise[0].ID.String()).Return(dummyEnterprise[0], nil).Times(3)
+	mockEnterpriseRepository.On("FindStatusHistories", dummyEnterprise[0].ID.String()).Return(domain.EnterpriseStatusHistories{
+		{ID: uuid.NewV4(), EnterpriseID: dummyEnterprise[0].ID, From: domain.EnterpriseDraft, To: domain.EnterpriseSubmitted},
+	}, nil).Once()
+	histories, err := uc.GetStatusHistories(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()})
+	assert.NoError(t, err)
+	assert.Len(t, histories, 1)
+
+	mockEnterpriseRepository.On("FindStatusHistories", dummyEnterprise[0].ID.String()).Return(nil, errors.New("error something")).Once()
+	_, err = uc.GetStatusHistories(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()})
+	assert.Error(t, err)
+
+	mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+	_, err = uc.GetStatusHistories(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyUser[1].ID.String()})
+	assert.IsType(t, &domain.ForbiddenError{}, err)
+	mockEnterpriseRepository.AssertExpectations(t)
+	mockUserRepository.AssertExpectations(t)
+}
+
+func TestEnterpriseUsecase_GetDistanceEnterprise(t *testing.T) {
 	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
 	mockTagRepository := new(mocks.TagRepository)
 	mockUserRepository := new(mocks.UserRepository)
+	latitude, longitude := -3.316694, 114.590111
+	located := dummyEnterprise[0]
+	located.Latitude = &latitude
+	located.Longitude = &longitude
 
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
-		mockEnterpriseRepository.On("UpdateStatusByID", mock.AnythingOfType("string"), mock.AnythingOfType("int")).Return(dummyEnterprise[0], nil).Once()
-		enterprise, err := uc.UpdateStatusEnterprise(dummyEnterprise[0].ID.String(), 1)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(located, nil).Once()
+		distance, enterprise, err := uc.GetDistanceEnterprise(located.ID.String(), request.DistanceRequest{
+			Latitude: -3.442821, Longitude: 114.740106, Unit: "km",
+		})
 		assert.NoError(t, err)
-		assert.NotNil(t, enterprise)
+		assert.InDelta(t, 21.78, distance, 0.05)
+		assert.Equal(t, located.ID, enterprise.ID)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
 
-	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository)
-		mockEnterpriseRepository.On("UpdateStatusByID", mock.AnythingOfType("string"), mock.AnythingOfType("int")).Return(domain.Enterprise{}, errors.New("error change status")).Once()
-		_, err := uc.UpdateStatusEnterprise(dummyEnterprise[0].ID.String(), 1)
+	t.Run("success in miles", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(located, nil).Once()
+		distance, _, err := uc.GetDistanceEnterprise(located.ID.String(), request.DistanceRequest{
+			Latitude: -3.442821, Longitude: 114.740106, Unit: "mi",
+		})
+		assert.NoError(t, err)
+		assert.InDelta(t, 13.53, distance, 0.05)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+
+	t.Run("invalid unit", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		_, _, err := uc.GetDistanceEnterprise(located.ID.String(), request.DistanceRequest{
+			Latitude: -3.442821, Longitude: 114.740106, Unit: "ft",
+		})
+		assert.Error(t, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+
+	t.Run("enterprise not found", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, nil).Once()
+		_, _, err := uc.GetDistanceEnterprise(located.ID.String(), request.DistanceRequest{
+			Latitude: -3.442821, Longitude: 114.740106, Unit: "km",
+		})
+		assert.Error(t, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+
+	t.Run("error find enterprise", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("error something")).Once()
+		_, _, err := uc.GetDistanceEnterprise(located.ID.String(), request.DistanceRequest{
+			Latitude: -3.442821, Longitude: 114.740106, Unit: "km",
+		})
+		assert.Error(t, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+
+	t.Run("enterprise coordinate not set", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		unlocated := located
+		unlocated.Latitude = nil
+		unlocated.Longitude = nil
+		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(unlocated, nil).Once()
+		_, _, err := uc.GetDistanceEnterprise(dummyEnterprise[0].ID.String(), request.DistanceRequest{
+			Latitude: -3.442821, Longitude: 114.740106, Unit: "km",
+		})
+		assert.Error(t, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+}
+
+func TestEnterpriseUsecase_GetNearbyEnterprises(t *testing.T) {
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTagRepository := new(mocks.TagRepository)
+	mockUserRepository := new(mocks.UserRepository)
+
+	t.Run("success", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindNearby", -3.442821, 114.740106, float64(10), []string{"tag"}).Return(dummyEnterprise, nil).Once()
+		enterprises, err := uc.GetNearbyEnterprises(request.NearbyRequest{
+			Latitude: -3.442821, Longitude: 114.740106, RadiusKm: 10, Tags: []string{"tag"},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, enterprises, 2)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+
+	t.Run("invalid radius", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		_, err := uc.GetNearbyEnterprises(request.NearbyRequest{
+			Latitude: -3.442821, Longitude: 114.740106, RadiusKm: 0,
+		})
 		assert.Error(t, err)
 		mockEnterpriseRepository.AssertExpectations(t)
 	})
+
+	t.Run("error find nearby", func(t *testing.T) {
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, newSearcher(), newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindNearby", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(domain.Enterprises{}, errors.New("error something")).Once()
+		_, err := uc.GetNearbyEnterprises(request.NearbyRequest{
+			Latitude: -3.442821, Longitude: 114.740106, RadiusKm: 5,
+		})
+		assert.Error(t, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+	})
+}
+
+func TestEnterpriseUsecase_SearchSync(t *testing.T) {
+	t.Run("create indexes", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		mockTagRepository := new(mocks.TagRepository)
+		mockUserRepository := new(mocks.UserRepository)
+		searcher := new(mocks.Searcher)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, mockUserRepository, searcher, newBlobStore(), newAuditUsecase())
+		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{}, nil).Once()
+		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
+		mockEnterpriseRepository.On("Save", mock.AnythingOfType("domain.Enterprise")).Return(dummyEnterprise[0], nil).Once()
+		searcher.On("Index", dummyEnterprise[0]).Return(nil).Once()
+		_, err := uc.CreateNewEnterprise(request.CreateEnterpriseRequest{Name: "enterprise satu"}, dummyUser[0].ID.String())
+		assert.NoError(t, err)
+		searcher.AssertExpectations(t)
+	})
+
+	t.Run("index failure does not fail update", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		mockTagRepository := new(mocks.TagRepository)
+		searcher := new(mocks.Searcher)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, mockTagRepository, new(mocks.UserRepository), searcher, newBlobStore(), newAuditUsecase())
+		mockTagRepository.On("FindByIDs", mock.AnythingOfType("[]string")).Return(domain.Tags{}, nil).Once()
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise[0].ID.String()).Return(dummyEnterprise[0], nil).Once()
+		mockEnterpriseRepository.On("Update", mock.AnythingOfType("domain.Enterprise")).Return(dummyEnterprise[0], nil).Once()
+		searcher.On("Index", dummyEnterprise[0]).Return(errors.New("disk full")).Once()
+		_, err := uc.UpdateEnterpriseByID(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()}, request.CreateEnterpriseRequest{Name: "enterprise satu"})
+		assert.NoError(t, err)
+		searcher.AssertExpectations(t)
+	})
+
+	t.Run("delete removes", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		searcher := new(mocks.Searcher)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, new(mocks.TagRepository), new(mocks.UserRepository), searcher, newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise[0].ID.String()).Return(dummyEnterprise[0], nil).Once()
+		mockEnterpriseRepository.On("Delete", dummyEnterprise[0]).Return(nil).Once()
+		searcher.On("Remove", dummyEnterprise[0].ID.String()).Return(nil).Once()
+		err := uc.DeleteEnterpriseByID(dummyEnterprise[0].ID.String(), domain.Actor{UserID: dummyEnterprise[0].UserID.String()})
+		assert.NoError(t, err)
+		searcher.AssertExpectations(t)
+	})
+
+	t.Run("reindex pages through every enterprise", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		searcher := new(mocks.Searcher)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, new(mocks.TagRepository), new(mocks.UserRepository), searcher, newBlobStore(), newAuditUsecase())
+		next := &domain.Cursor{ID: dummyEnterprise[0].ID.String()}
+		mockEnterpriseRepository.On("FindAll", domain.EnterpriseFilter{}, domain.PageRequest{Length: 100}).
+			Return(domain.Enterprises{dummyEnterprise[0]}, domain.PageInfo{Next: next}, nil).Once()
+		mockEnterpriseRepository.On("FindAll", domain.EnterpriseFilter{}, domain.PageRequest{Cursor: next, Length: 100}).
+			Return(domain.Enterprises{dummyEnterprise[1]}, domain.PageInfo{}, nil).Once()
+		searcher.On("Reindex", domain.Enterprises{dummyEnterprise[0], dummyEnterprise[1]}).Return(nil).Once()
+		assert.NoError(t, uc.ReindexSearch())
+		mockEnterpriseRepository.AssertExpectations(t)
+		searcher.AssertExpectations(t)
+	})
+
+	t.Run("reindex failure", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		searcher := new(mocks.Searcher)
+		uc := usecase.NewEnterpriseUsecase(mockEnterpriseRepository, new(mocks.TagRepository), new(mocks.UserRepository), searcher, newBlobStore(), newAuditUsecase())
+		mockEnterpriseRepository.On("FindAll", domain.EnterpriseFilter{}, domain.PageRequest{Length: 100}).
+			Return(domain.Enterprises{dummyEnterprise[0]}, domain.PageInfo{}, nil).Once()
+		searcher.On("Reindex", domain.Enterprises{dummyEnterprise[0]}).Return(errors.New("disk full")).Once()
+		assert.EqualError(t, uc.ReindexSearch(), "disk full")
+	})
 }
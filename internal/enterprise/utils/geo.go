@@ -0,0 +1,86 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Unit struct {
+	slug   string
+	radius float64
+}
+
+func (u Unit) String() string {
+	return u.slug
+}
+
+// Radius is the mean earth radius expressed in the unit.
+func (u Unit) Radius() float64 {
+	return u.radius
+}
+
+var (
+	Kilometer = Unit{"km", 6371.0088}
+	Mile      = Unit{"mi", 3958.7613}
+)
+
+func ParseUnit(slug string) (Unit, error) {
+	switch slug {
+	case "", Kilometer.slug:
+		return Kilometer, nil
+	case Mile.slug:
+		return Mile, nil
+	}
+	return Unit{}, errors.New("unit must be km or mi")
+}
+
+func ValidCoordinate(latitude, longitude float64) bool {
+	if math.IsNaN(latitude) || math.IsNaN(longitude) {
+		return false
+	}
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// ParseCoordinate accepts both dot and comma decimal separators, e.g. "-3.442821" or "-3,442821".
+func ParseCoordinate(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// BoundingBox returns the latitude and longitude range that contains every point within radius of the center.
+func BoundingBox(latitude, longitude, radius float64, unit Unit) (minLatitude, maxLatitude, minLongitude, maxLongitude float64) {
+	deltaLat := radius / unit.radius * 180 / math.Pi
+	minLatitude = math.Max(latitude-deltaLat, -90)
+	maxLatitude = math.Min(latitude+deltaLat, 90)
+	if minLatitude == -90 || maxLatitude == 90 {
+		return minLatitude, maxLatitude, -180, 180
+	}
+
+	deltaLon := math.Asin(math.Min(1, math.Sin(radius/unit.radius)/math.Cos(toRadians(latitude)))) * 180 / math.Pi
+	minLongitude = longitude - deltaLon
+	maxLongitude = longitude + deltaLon
+	if minLongitude < -180 || maxLongitude > 180 {
+		return minLatitude, maxLatitude, -180, 180
+	}
+	return minLatitude, maxLatitude, minLongitude, maxLongitude
+}
+
+// Distance returns the great-circle distance between two points using the haversine formula.
+func Distance(fromLatitude, fromLongitude, toLatitude, toLongitude float64, unit Unit) float64 {
+	lat1 := toRadians(fromLatitude)
+	lat2 := toRadians(toLatitude)
+	deltaLat := toRadians(toLatitude - fromLatitude)
+	deltaLon := toRadians(toLongitude - fromLongitude)
+
+	h := math.Pow(math.Sin(deltaLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(deltaLon/2), 2)
+	return 2 * unit.radius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func toRadians(degree float64) float64 {
+	return degree * math.Pi / 180
+}
@@ -0,0 +1,84 @@
+package geo_test
+
+import (
+	"github.com/nrmadi02/mini-project/internal/enterprise/utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	t.Run("banjarbaru to banjarmasin in km", func(t *testing.T) {
+		distance := geo.Distance(-3.442821, 114.740106, -3.316694, 114.590111, geo.Kilometer)
+		assert.InDelta(t, 21.78, distance, 0.05)
+	})
+
+	t.Run("banjarbaru to banjarmasin in mi", func(t *testing.T) {
+		distance := geo.Distance(-3.442821, 114.740106, -3.316694, 114.590111, geo.Mile)
+		assert.InDelta(t, 13.53, distance, 0.05)
+	})
+
+	t.Run("same point", func(t *testing.T) {
+		distance := geo.Distance(-3.442821, 114.740106, -3.442821, 114.740106, geo.Kilometer)
+		assert.Equal(t, float64(0), distance)
+	})
+
+	t.Run("antipodal points", func(t *testing.T) {
+		distance := geo.Distance(0, 0, 0, 180, geo.Kilometer)
+		assert.InDelta(t, 20015.09, distance, 0.1)
+	})
+}
+
+func TestParseUnit(t *testing.T) {
+	unit, err := geo.ParseUnit("")
+	assert.NoError(t, err)
+	assert.Equal(t, geo.Kilometer, unit)
+
+	unit, err = geo.ParseUnit("mi")
+	assert.NoError(t, err)
+	assert.Equal(t, geo.Mile, unit)
+
+	_, err = geo.ParseUnit("parsec")
+	assert.Error(t, err)
+}
+
+func TestValidCoordinate(t *testing.T) {
+	assert.True(t, geo.ValidCoordinate(-3.442821, 114.740106))
+	assert.True(t, geo.ValidCoordinate(90, -180))
+	assert.False(t, geo.ValidCoordinate(91, 0))
+	assert.False(t, geo.ValidCoordinate(0, 180.5))
+}
+
+func TestParseCoordinate(t *testing.T) {
+	value, err := geo.ParseCoordinate(" -3.442821 ")
+	assert.NoError(t, err)
+	assert.Equal(t, -3.442821, value)
+
+	value, err = geo.ParseCoordinate("114,740106")
+	assert.NoError(t, err)
+	assert.Equal(t, 114.740106, value)
+
+	_, err = geo.ParseCoordinate("")
+	assert.Error(t, err)
+}
+
+func TestBoundingBox(t *testing.T) {
+	t.Run("contains radius", func(t *testing.T) {
+		minLat, maxLat, minLon, maxLon := geo.BoundingBox(-3.442821, 114.740106, 25, geo.Kilometer)
+		assert.True(t, minLat < -3.316694 && -3.316694 < maxLat)
+		assert.True(t, minLon < 114.590111 && 114.590111 < maxLon)
+		assert.InDelta(t, 25, geo.Distance(-3.442821, 114.740106, maxLat, 114.740106, geo.Kilometer), 0.01)
+	})
+
+	t.Run("near pole covers every longitude", func(t *testing.T) {
+		_, maxLat, minLon, maxLon := geo.BoundingBox(89.9, 0, 50, geo.Kilometer)
+		assert.Equal(t, float64(90), maxLat)
+		assert.Equal(t, float64(-180), minLon)
+		assert.Equal(t, float64(180), maxLon)
+	})
+
+	t.Run("crossing antimeridian covers every longitude", func(t *testing.T) {
+		_, _, minLon, maxLon := geo.BoundingBox(0, 179.9, 50, geo.Kilometer)
+		assert.Equal(t, float64(-180), minLon)
+		assert.Equal(t, float64(180), maxLon)
+	})
+}
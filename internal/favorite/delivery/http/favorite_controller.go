@@ -1,9 +1,9 @@
 package http
 
 import (
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
 	"github.com/nrmadi02/mini-project/web/response"
 	uuid "github.com/satori/go.uuid"
 	"math"
@@ -44,14 +44,16 @@ func NewFavoriteController(fu domain.FavoriteUsecase, au domain.AuthUsecase, ru
 // @Failure 404 {object} response.JSONBadRequestResult{}
 // @Security JWT
 func (f favoriteController) AddFavoriteEnterprise(c echo.Context) error {
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-	userid := claims["UserID"].(string)
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	userid := claims.UserID()
 	var req []string
 	if err := c.Bind(&req); err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
-	_, err := f.favoriteUsecase.AddFavorite(req, userid)
+	_, err = f.favoriteUsecase.AddFavorite(req, userid)
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
@@ -76,14 +78,16 @@ func (f favoriteController) AddFavoriteEnterprise(c echo.Context) error {
 // @Failure 404 {object} response.JSONBadRequestResult{}
 // @Security JWT
 func (f favoriteController) RemoveFavoriteEnterprise(c echo.Context) error {
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-	userid := claims["UserID"].(string)
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	userid := claims.UserID()
 	var req []string
 	if err := c.Bind(&req); err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
-	_, err := f.favoriteUsecase.RemoveFavorite(req, userid)
+	_, err = f.favoriteUsecase.RemoveFavorite(req, userid)
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
@@ -107,9 +111,11 @@ func (f favoriteController) RemoveFavoriteEnterprise(c echo.Context) error {
 // @Failure 404 {object} response.JSONBadRequestResult{}
 // @Security JWT
 func (f favoriteController) GetDetailFavoriteEnterprise(c echo.Context) error {
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-	userid := claims["UserID"].(string)
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	userid := claims.UserID()
 
 	favorite, err := f.favoriteUsecase.GetDetailByUserID(userid)
 	if err != nil {
@@ -129,12 +135,13 @@ func (f favoriteController) GetDetailFavoriteEnterprise(c echo.Context) error {
 			Address:     enterprise.Address,
 			Postcode:    enterprise.Postcode,
 			Description: enterprise.Description,
-			Status:      enterprise.Status,
+			Status:      int(enterprise.Status),
 			UpdatedAt:   enterprise.UpdatedAt,
 			CreatedAt:   enterprise.CreatedAt,
 			Latitude:    enterprise.Latitude,
 			Longitude:   enterprise.Longitude,
 			Rating:      finalRating,
+			Images:      helper.EnterpriseImages(enterprise.Images),
 		})
 	}
 	resFinal := struct {
@@ -62,6 +62,8 @@ var dummyUser = domain.Users{
 	},
 }
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = domain.Enterprises{
 	domain.Enterprise{
 		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
@@ -70,8 +72,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Status:      0,
 		Tags: domain.Tags{
@@ -92,8 +94,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone:      "0012798232",
 		Address:          "bjm",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -140,15 +142,19 @@ var dummyRating = domain.RatingEnterprises{
 	},
 }
 
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+
 func createToken() string {
-	jwtSetToken := helper.NewGoJWT()
+	jwtSetToken := helper.NewGoJWT(keySet, "test", "test")
 	token := jwtSetToken.CreateTokenJWT(&dummyUser[0])
 	return token
 }
 
 func middlewareToken(handlerFunc echo.HandlerFunc, c echo.Context) error {
 	err := middleware.JWTWithConfig(middleware.JWTConfig{
-		SigningKey: []byte("220220"),
+		KeyFunc: keySet.Keyfunc,
+		Claims:  &helper.JWTClaims{},
 	})(handlerFunc)(c)
 	return err
 }
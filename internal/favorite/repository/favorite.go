@@ -22,7 +22,9 @@ func (f favoriteRepository) FindAll() (favorites domain.Favorites, err error) {
 }
 
 func (f favoriteRepository) FindByUserID(id string) (favorite domain.Favorite, err error) {
-	err = f.DB.Preload(clause.Associations).Where("user_id = ? ", id).Find(&favorite).Error
+	err = f.DB.Preload(clause.Associations).Preload("Enterprises.Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).Where("user_id = ? ", id).Find(&favorite).Error
 	return favorite, err
 }
 
@@ -33,6 +33,8 @@ func (a AnyTime) Match(v driver.Value) bool {
 	return ok
 }
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = []domain.Enterprise{
 	domain.Enterprise{
 		ID:               uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
@@ -41,8 +43,8 @@ var dummyEnterprise = []domain.Enterprise{
 		NumberPhone:      "0012798232",
 		Address:          "bjb",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -192,10 +194,10 @@ func TestFavoriteRepository_Update(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec("UPDATE `favorites` SET `updated_at`=? WHERE `id` = ?").
 			WithArgs(AnyTime{}, dummyFavorite2[0].ID).WillReturnResult(sqlMock.NewResult(1, 1))
-		mock.ExpectExec("INSERT INTO `enterprises` (`id`,`user_id`,`name`,`number_phone`,`address`,`postcode`,`latitude`,`longitude`,`description`,`status`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?) ON DUPLICATE KEY UPDATE `id`=`id`").
+		mock.ExpectExec("INSERT INTO `enterprises` (`id`,`user_id`,`name`,`number_phone`,`address`,`postcode`,`latitude`,`longitude`,`description`,`status`,`status_reason`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?) ON DUPLICATE KEY UPDATE `id`=`id`").
 			WithArgs(dummyEnterprise[0].ID, dummyEnterprise[0].UserID, dummyEnterprise[0].Name, dummyEnterprise[0].NumberPhone,
 				dummyEnterprise[0].Address, int(dummyEnterprise[0].Postcode),
-				dummyEnterprise[0].Latitude, dummyEnterprise[0].Longitude, dummyEnterprise[0].Description, int(dummyEnterprise[0].Status), AnyTime{}, AnyTime{}).
+				dummyEnterprise[0].Latitude, dummyEnterprise[0].Longitude, dummyEnterprise[0].Description, int(dummyEnterprise[0].Status), dummyEnterprise[0].StatusReason, AnyTime{}, AnyTime{}).
 			WillReturnResult(sqlMock.NewResult(1, 1))
 		mock.ExpectExec("INSERT INTO `enterprise_favorites` (`favorite_id`,`enterprise_id`) VALUES (?,?) ON DUPLICATE KEY UPDATE `favorite_id`=`favorite_id`").
 			WithArgs(dummyFavorite2[0].ID, dummyEnterprise[0].ID).
@@ -53,6 +53,8 @@ var dummyUser = domain.Users{
 	},
 }
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = domain.Enterprises{
 	domain.Enterprise{
 		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
@@ -61,8 +63,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Status:      0,
 		Tags: domain.Tags{
@@ -83,8 +85,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone:      "0012798232",
 		Address:          "bjm",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/web/response"
+	"net/http"
+)
+
+// maxUploadBody leaves room for the multipart headers around the largest image.
+const maxUploadBody = domain.MaxImageSize + 1<<20
+
+type ImageController interface {
+	UploadImage(c echo.Context) error
+	DeleteImage(c echo.Context) error
+}
+
+type imageController struct {
+	imageUsecase domain.ImageUsecase
+}
+
+func NewImageController(iu domain.ImageUsecase) ImageController {
+	return imageController{
+		imageUsecase: iu,
+	}
+}
+
+// UploadImage godoc
+// @Summary Upload enterprise image
+// @Description upload a logo, cover or gallery image of an enterprise as JPEG, PNG or WebP of at most 5 MB, a new logo or cover replaces the old one and an enterprise has at most 10 gallery images
+// @Tags Enterprise
+// @accept multipart/form-data
+// @Produce json
+// @Router /enterprise/{id}/images/{kind} [post]
+// @Param id path string true "enterprise id"
+// @Param kind path string true "logo, cover or gallery"
+// @Param image formData file true "image file"
+// @Success 201 {object} response.JSONSuccessResult{data=response.ImageResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (i imageController) UploadImage(c echo.Context) error {
+	if _, err := helper.GetAuthClaims(c); err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	kind, err := domain.ParseImageKind(c.Param("image"))
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxUploadBody)
+	header, err := c.FormFile("image")
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, "image file is required, at most 5 MB")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	defer file.Close()
+
+	image, err := i.imageUsecase.UploadImage(c.Param("id"), helper.GetActor(c), kind, file)
+	if err != nil {
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
+	}
+	return response.SuccessResponse(c, http.StatusCreated, true, "success upload image", response.ImageResponse{
+		ID:  image.ID,
+		URL: image.URL,
+	})
+}
+
+// DeleteImage godoc
+// @Summary Delete enterprise image
+// @Description delete a logo, cover or gallery image of an enterprise
+// @Tags Enterprise
+// @accept json
+// @Produce json
+// @Router /enterprise/{id}/images/{imageId} [delete]
+// @Param id path string true "enterprise id"
+// @Param imageId path string true "image id"
+// @Success 200 {object} response.JSONSuccessDeleteResult{}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (i imageController) DeleteImage(c echo.Context) error {
+	if _, err := helper.GetAuthClaims(c); err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	err := i.imageUsecase.DeleteImage(c.Param("id"), c.Param("image"), helper.GetActor(c))
+	if err != nil {
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
+	}
+	return response.SuccessDeleteResponse(c, http.StatusOK, true, "success delete image")
+}
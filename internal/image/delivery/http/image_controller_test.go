@@ -0,0 +1,189 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	http2 "github.com/nrmadi02/mini-project/internal/image/delivery/http"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var base_path = "/api/v1"
+
+var dummyUser = domain.User{
+	ID:       uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf891"),
+	Fullname: "user1",
+	Email:    "satu@email.com",
+	Username: "usr1",
+	Roles:    []domain.Role{{Name: "ROLE_CLIENT", ID: 2}},
+}
+
+var dummyImage = domain.EnterpriseImage{
+	ID:           uuid.FromStringOrNil("8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"),
+	EnterpriseID: uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
+	Kind:         domain.ImageLogo,
+	URL:          "/uploads/enterprises/35d6a9a1-aa5e-41f1-9991-08878dfdf89a/8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b.png",
+}
+
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+
+func createToken() string {
+	jwtSetToken := helper.NewGoJWT(keySet, "test", "test")
+	return jwtSetToken.CreateTokenJWT(&dummyUser)
+}
+
+func middlewareToken(handlerFunc echo.HandlerFunc, c echo.Context) error {
+	return middleware.JWTWithConfig(middleware.JWTConfig{
+		KeyFunc: keySet.Keyfunc,
+		Claims:  &helper.JWTClaims{},
+	})(handlerFunc)(c)
+}
+
+func parseResponse(rec *httptest.ResponseRecorder) map[string]interface{} {
+	var responseBody map[string]interface{}
+	_ = json.Unmarshal(rec.Body.Bytes(), &responseBody)
+	return responseBody
+}
+
+// makeUploadRequest sends file as the image field, no file at all when it is nil.
+func makeUploadRequest(path string, file []byte) (*http.Request, *httptest.ResponseRecorder) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if file != nil {
+		part, _ := writer.CreateFormFile("image", "logo.png")
+		_, _ = part.Write(file)
+	}
+	_ = writer.Close()
+	req, _ := http.NewRequest(echo.POST, base_path+path, &body)
+	req.Header.Add(echo.HeaderContentType, writer.FormDataContentType())
+	req.Header.Add(echo.HeaderAuthorization, middleware.DefaultJWTConfig.AuthScheme+" "+createToken())
+	return req, httptest.NewRecorder()
+}
+
+func uploadContext(e *echo.Echo, req *http.Request, rec *httptest.ResponseRecorder, kind string) echo.Context {
+	c := e.NewContext(req, rec)
+	c.SetPath(base_path + "/enterprise/:id/images/:image")
+	c.SetParamNames("id", "image")
+	c.SetParamValues(dummyImage.EnterpriseID.String(), kind)
+	return c
+}
+
+func TestImageController_UploadImage(t *testing.T) {
+	actor := domain.Actor{UserID: dummyUser.ID.String()}
+
+	t.Run("success", func(t *testing.T) {
+		mockImageUsecase := new(mocks.ImageUsecase)
+		e := echo.New()
+		req, rec := makeUploadRequest("/enterprise/"+dummyImage.EnterpriseID.String()+"/images/logo", png)
+		c := uploadContext(e, req, rec, "logo")
+		mockImageUsecase.On("UploadImage", dummyImage.EnterpriseID.String(), actor, domain.ImageLogo, mock.MatchedBy(func(file io.Reader) bool {
+			body, _ := io.ReadAll(file)
+			return bytes.Equal(body, png)
+		})).Return(dummyImage, nil).Once()
+
+		err := middlewareToken(http2.NewImageController(mockImageUsecase).UploadImage, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(201), responseBody["code"])
+		assert.Equal(t, dummyImage.URL, responseBody["data"].(map[string]interface{})["url"])
+		mockImageUsecase.AssertExpectations(t)
+	})
+	t.Run("unknown kind", func(t *testing.T) {
+		mockImageUsecase := new(mocks.ImageUsecase)
+		e := echo.New()
+		req, rec := makeUploadRequest("/enterprise/"+dummyImage.EnterpriseID.String()+"/images/banner", png)
+		c := uploadContext(e, req, rec, "banner")
+
+		err := middlewareToken(http2.NewImageController(mockImageUsecase).UploadImage, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockImageUsecase.AssertNotCalled(t, "UploadImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("missing file", func(t *testing.T) {
+		mockImageUsecase := new(mocks.ImageUsecase)
+		e := echo.New()
+		req, rec := makeUploadRequest("/enterprise/"+dummyImage.EnterpriseID.String()+"/images/logo", nil)
+		c := uploadContext(e, req, rec, "logo")
+
+		err := middlewareToken(http2.NewImageController(mockImageUsecase).UploadImage, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+	t.Run("body too large", func(t *testing.T) {
+		mockImageUsecase := new(mocks.ImageUsecase)
+		e := echo.New()
+		req, rec := makeUploadRequest("/enterprise/"+dummyImage.EnterpriseID.String()+"/images/logo", make([]byte, domain.MaxImageSize+2<<20))
+		c := uploadContext(e, req, rec, "logo")
+
+		err := middlewareToken(http2.NewImageController(mockImageUsecase).UploadImage, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockImageUsecase.AssertNotCalled(t, "UploadImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("forbidden", func(t *testing.T) {
+		mockImageUsecase := new(mocks.ImageUsecase)
+		e := echo.New()
+		req, rec := makeUploadRequest("/enterprise/"+dummyImage.EnterpriseID.String()+"/images/logo", png)
+		c := uploadContext(e, req, rec, "logo")
+		mockImageUsecase.On("UploadImage", dummyImage.EnterpriseID.String(), actor, domain.ImageLogo, mock.Anything).
+			Return(domain.EnterpriseImage{}, domain.NewForbiddenError("only the owner or users with enterprise:manage can upload image")).Once()
+
+		err := middlewareToken(http2.NewImageController(mockImageUsecase).UploadImage, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(403), responseBody["code"])
+	})
+}
+
+func TestImageController_DeleteImage(t *testing.T) {
+	deleteContext := func(e *echo.Echo) (echo.Context, *httptest.ResponseRecorder) {
+		req, _ := http.NewRequest(echo.DELETE, base_path+"/enterprise/"+dummyImage.EnterpriseID.String()+"/images/"+dummyImage.ID.String(), nil)
+		req.Header.Add(echo.HeaderAuthorization, middleware.DefaultJWTConfig.AuthScheme+" "+createToken())
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "/enterprise/:id/images/:image")
+		c.SetParamNames("id", "image")
+		c.SetParamValues(dummyImage.EnterpriseID.String(), dummyImage.ID.String())
+		return c, rec
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockImageUsecase := new(mocks.ImageUsecase)
+		c, rec := deleteContext(echo.New())
+		mockImageUsecase.On("DeleteImage", dummyImage.EnterpriseID.String(), dummyImage.ID.String(), domain.Actor{UserID: dummyUser.ID.String()}).Return(nil).Once()
+
+		err := middlewareToken(http2.NewImageController(mockImageUsecase).DeleteImage, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockImageUsecase.AssertExpectations(t)
+	})
+	t.Run("failed delete", func(t *testing.T) {
+		mockImageUsecase := new(mocks.ImageUsecase)
+		c, rec := deleteContext(echo.New())
+		mockImageUsecase.On("DeleteImage", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("image not found")).Once()
+
+		err := middlewareToken(http2.NewImageController(mockImageUsecase).DeleteImage, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/nrmadi02/mini-project/domain"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type imageRepository struct {
+	DB *gorm.DB
+}
+
+func NewImageRepository(db *gorm.DB) domain.ImageRepository {
+	return imageRepository{
+		DB: db,
+	}
+}
+
+func (i imageRepository) FindByID(id string) (image domain.EnterpriseImage, err error) {
+	err = i.DB.Where("id = ?", id).Find(&image).Error
+	return image, err
+}
+
+func (i imageRepository) FindByEnterpriseID(id string) (images domain.EnterpriseImages, err error) {
+	err = i.DB.Where("enterprise_id = ?", id).Order("created_at").Find(&images).Error
+	return images, err
+}
+
+func (i imageRepository) FindByUserID(id string) (images domain.EnterpriseImages, err error) {
+	owned := i.DB.Model(&domain.Enterprise{}).Select("id").Where("user_id = ?", id)
+	err = i.DB.Where("enterprise_id IN (?)", owned).Find(&images).Error
+	return images, err
+}
+
+func (i imageRepository) Save(image domain.EnterpriseImage) (domain.EnterpriseImage, error) {
+	err := i.DB.Create(&image).Error
+	return image, err
+}
+
+func (i imageRepository) SaveGallery(image domain.EnterpriseImage, max int) (domain.EnterpriseImage, error) {
+	err := i.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockEnterprise(tx, image.EnterpriseID); err != nil {
+			return err
+		}
+		var count int64
+		err := tx.Model(&domain.EnterpriseImage{}).Where("enterprise_id = ? AND kind = ?", image.EnterpriseID, domain.ImageGallery).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count >= int64(max) {
+			return fmt.Errorf("an enterprise can have at most %d gallery images", max)
+		}
+		return tx.Create(&image).Error
+	})
+	return image, err
+}
+
+func (i imageRepository) ReplaceImage(image domain.EnterpriseImage) (domain.EnterpriseImage, domain.EnterpriseImages, error) {
+	var replaced domain.EnterpriseImages
+	err := i.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockEnterprise(tx, image.EnterpriseID); err != nil {
+			return err
+		}
+		err := tx.Where("enterprise_id = ? AND kind = ?", image.EnterpriseID, image.Kind).Find(&replaced).Error
+		if err != nil {
+			return err
+		}
+		if len(replaced) > 0 {
+			if err = tx.Delete(&replaced).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&image).Error
+	})
+	if err != nil {
+		return domain.EnterpriseImage{}, nil, err
+	}
+	return image, replaced, nil
+}
+
+// lockEnterprise locks the enterprise row until tx ends, it queues concurrent uploads to the
+// same enterprise until the first one has written its image.
+func lockEnterprise(tx *gorm.DB, id uuid.UUID) error {
+	var enterprise domain.Enterprise
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", id).Take(&enterprise).Error
+}
+
+func (i imageRepository) Delete(image domain.EnterpriseImage) error {
+	return i.DB.Where("id = ?", image.ID).Delete(&domain.EnterpriseImage{}).Error
+}
@@ -0,0 +1,255 @@
+package repository_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	sqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/image/repository"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func SetupDBMock(dbMock *sql.DB) *gorm.DB {
+	gormDB, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      dbMock,
+		DSN:                       "sqlmock_db_0",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{PrepareStmt: false})
+	if err != nil {
+		panic(err)
+	}
+	return gormDB
+}
+
+type AnyTime struct{}
+
+func (a AnyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+var dummyImage = domain.EnterpriseImage{
+	ID:           uuid.FromStringOrNil("8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"),
+	EnterpriseID: uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
+	Kind:         domain.ImageLogo,
+	Key:          "enterprises/35d6a9a1-aa5e-41f1-9991-08878dfdf89a/8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b.png",
+	URL:          "/uploads/enterprises/35d6a9a1-aa5e-41f1-9991-08878dfdf89a/8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b.png",
+	ContentType:  "image/png",
+	Size:         2048,
+}
+
+func TestImageRepository_Save(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `enterprise_images` (`id`,`enterprise_id`,`kind`,`key`,`url`,`content_type`,`size`,`created_at`) VALUES (?,?,?,?,?,?,?,?)").
+		WithArgs(dummyImage.ID, dummyImage.EnterpriseID, dummyImage.Kind, dummyImage.Key, dummyImage.URL, dummyImage.ContentType, dummyImage.Size, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	imageRepository := repository.NewImageRepository(db)
+	image, err := imageRepository.Save(dummyImage)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyImage.ID, image.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImageRepository_SaveGallery(t *testing.T) {
+	gallery := dummyImage
+	gallery.Kind = domain.ImageGallery
+
+	expectCount := func(mock sqlMock.Sqlmock, count int) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id` FROM `enterprises` WHERE id = ? LIMIT 1 FOR UPDATE").
+			WithArgs(gallery.EnterpriseID).
+			WillReturnRows(sqlMock.NewRows([]string{"id"}).AddRow(gallery.EnterpriseID))
+		mock.ExpectQuery("SELECT count(*) FROM `enterprise_images` WHERE enterprise_id = ? AND kind = ?").
+			WithArgs(gallery.EnterpriseID, domain.ImageGallery).
+			WillReturnRows(sqlMock.NewRows([]string{"count(*)"}).AddRow(count))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		expectCount(mock, 9)
+		mock.ExpectExec("INSERT INTO `enterprise_images` (`id`,`enterprise_id`,`kind`,`key`,`url`,`content_type`,`size`,`created_at`) VALUES (?,?,?,?,?,?,?,?)").
+			WithArgs(gallery.ID, gallery.EnterpriseID, gallery.Kind, gallery.Key, gallery.URL, gallery.ContentType, gallery.Size, AnyTime{}).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		imageRepository := repository.NewImageRepository(db)
+		image, err := imageRepository.SaveGallery(gallery, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, gallery.ID, image.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("full", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		expectCount(mock, 10)
+		mock.ExpectRollback()
+
+		imageRepository := repository.NewImageRepository(db)
+		_, err = imageRepository.SaveGallery(gallery, 10)
+		assert.EqualError(t, err, "an enterprise can have at most 10 gallery images")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestImageRepository_ReplaceImage(t *testing.T) {
+	logo := dummyImage
+	logo.ID = uuid.FromStringOrNil("8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4c")
+
+	expectReplaced := func(mock sqlMock.Sqlmock, rows *sqlMock.Rows) {
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT `id` FROM `enterprises` WHERE id = ? LIMIT 1 FOR UPDATE").
+			WithArgs(logo.EnterpriseID).
+			WillReturnRows(sqlMock.NewRows([]string{"id"}).AddRow(logo.EnterpriseID))
+		mock.ExpectQuery("SELECT * FROM `enterprise_images` WHERE enterprise_id = ? AND kind = ?").
+			WithArgs(logo.EnterpriseID, domain.ImageLogo).
+			WillReturnRows(rows)
+	}
+	expectInsert := func(mock sqlMock.Sqlmock) {
+		mock.ExpectExec("INSERT INTO `enterprise_images` (`id`,`enterprise_id`,`kind`,`key`,`url`,`content_type`,`size`,`created_at`) VALUES (?,?,?,?,?,?,?,?)").
+			WithArgs(logo.ID, logo.EnterpriseID, logo.Kind, logo.Key, logo.URL, logo.ContentType, logo.Size, AnyTime{}).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+
+	t.Run("replaces", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		expectReplaced(mock, sqlMock.NewRows([]string{"id", "enterprise_id", "kind", "key", "url"}).
+			AddRow(dummyImage.ID, dummyImage.EnterpriseID, dummyImage.Kind, dummyImage.Key, dummyImage.URL))
+		mock.ExpectExec("DELETE FROM `enterprise_images` WHERE `enterprise_images`.`id` = ?").
+			WithArgs(dummyImage.ID).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		expectInsert(mock)
+
+		imageRepository := repository.NewImageRepository(db)
+		image, replaced, err := imageRepository.ReplaceImage(logo)
+		assert.NoError(t, err)
+		assert.Equal(t, logo.ID, image.ID)
+		if assert.Len(t, replaced, 1) {
+			assert.Equal(t, dummyImage.Key, replaced[0].Key)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("first of its kind", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		expectReplaced(mock, sqlMock.NewRows([]string{"id", "enterprise_id", "kind", "key", "url"}))
+		expectInsert(mock)
+
+		imageRepository := repository.NewImageRepository(db)
+		_, replaced, err := imageRepository.ReplaceImage(logo)
+		assert.NoError(t, err)
+		assert.Empty(t, replaced)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestImageRepository_FindByEnterpriseID(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `enterprise_images` WHERE enterprise_id = ? ORDER BY created_at").
+		WithArgs(dummyImage.EnterpriseID.String()).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "enterprise_id", "kind", "key", "url"}).
+			AddRow(dummyImage.ID, dummyImage.EnterpriseID, dummyImage.Kind, dummyImage.Key, dummyImage.URL))
+
+	imageRepository := repository.NewImageRepository(db)
+	images, err := imageRepository.FindByEnterpriseID(dummyImage.EnterpriseID.String())
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, dummyImage.Key, images[0].Key)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImageRepository_FindByUserID(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	userID := "35d6a9a1-aa5e-41f1-9991-08878dfdf891"
+	mock.ExpectQuery("SELECT * FROM `enterprise_images` WHERE enterprise_id IN (SELECT `id` FROM `enterprises` WHERE user_id = ?)").
+		WithArgs(userID).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "enterprise_id", "key"}).
+			AddRow(dummyImage.ID, dummyImage.EnterpriseID, dummyImage.Key))
+
+	imageRepository := repository.NewImageRepository(db)
+	images, err := imageRepository.FindByUserID(userID)
+	assert.NoError(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, dummyImage.Key, images[0].Key)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImageRepository_FindByID(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `enterprise_images` WHERE id = ?").
+		WithArgs(dummyImage.ID.String()).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "enterprise_id", "kind"}).
+			AddRow(dummyImage.ID, dummyImage.EnterpriseID, dummyImage.Kind))
+
+	imageRepository := repository.NewImageRepository(db)
+	image, err := imageRepository.FindByID(dummyImage.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ImageLogo, image.Kind)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestImageRepository_Delete(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `enterprise_images` WHERE id = ?").
+		WithArgs(dummyImage.ID).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	imageRepository := repository.NewImageRepository(db)
+	assert.NoError(t, imageRepository.Delete(dummyImage))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
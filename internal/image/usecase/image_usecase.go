@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/policy"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+)
+
+// maxGalleryImages is how many gallery images an enterprise can have.
+const maxGalleryImages = 10
+
+// imageExtensions are the accepted content types, sniffed from the file rather than trusted from the client.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+type imageUsecase struct {
+	imageRepository      domain.ImageRepository
+	enterpriseRepository domain.EnterpriseRepository
+	blobs                domain.BlobStore
+	policy               policy.Policy
+}
+
+func NewImageUsecase(ir domain.ImageRepository, er domain.EnterpriseRepository, ur domain.UserRepository, blobs domain.BlobStore) domain.ImageUsecase {
+	return imageUsecase{
+		imageRepository:      ir,
+		enterpriseRepository: er,
+		blobs:                blobs,
+		policy:               policy.NewPolicy(ur),
+	}
+}
+
+// UploadImage stores the image, its owner and users with enterprise:manage may.
+func (i imageUsecase) UploadImage(enterpriseID string, actor domain.Actor, kind domain.ImageKind, file io.Reader) (domain.EnterpriseImage, error) {
+	enterprise, err := i.enterpriseRepository.FindByID(enterpriseID)
+	if err != nil {
+		return domain.EnterpriseImage{}, err
+	}
+	if enterprise.ID == uuid.Nil {
+		return domain.EnterpriseImage{}, errors.New("enterprise not found")
+	}
+	if err = i.policy.OwnerOr(actor, enterprise.UserID, role.EnterpriseManage, "upload image"); err != nil {
+		return domain.EnterpriseImage{}, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(file, domain.MaxImageSize+1))
+	if err != nil {
+		return domain.EnterpriseImage{}, err
+	}
+	if len(body) > domain.MaxImageSize {
+		return domain.EnterpriseImage{}, errors.New("image must be at most 5 MB")
+	}
+	contentType := http.DetectContentType(body)
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		return domain.EnterpriseImage{}, errors.New("image must be a JPEG, PNG or WebP")
+	}
+
+	// checked here so a full gallery stores no file, SaveGallery enforces the limit against
+	// concurrent uploads.
+	if kind == domain.ImageGallery {
+		existing, err := i.imageRepository.FindByEnterpriseID(enterpriseID)
+		if err != nil {
+			return domain.EnterpriseImage{}, err
+		}
+		gallery := 0
+		for _, image := range existing {
+			if image.Kind == domain.ImageGallery {
+				gallery++
+			}
+		}
+		if gallery >= maxGalleryImages {
+			return domain.EnterpriseImage{}, errors.New("an enterprise can have at most 10 gallery images")
+		}
+	}
+
+	id := uuid.NewV4()
+	key := "enterprises/" + enterprise.ID.String() + "/" + id.String() + extension
+	if err = i.blobs.Put(key, contentType, bytes.NewReader(body), int64(len(body))); err != nil {
+		return domain.EnterpriseImage{}, err
+	}
+	image := domain.EnterpriseImage{
+		ID:           id,
+		EnterpriseID: enterprise.ID,
+		Kind:         kind,
+		Key:          key,
+		URL:          i.blobs.URL(key),
+		ContentType:  contentType,
+		Size:         int64(len(body)),
+	}
+	var replaced domain.EnterpriseImages
+	if kind == domain.ImageGallery {
+		image, err = i.imageRepository.SaveGallery(image, maxGalleryImages)
+	} else {
+		image, replaced, err = i.imageRepository.ReplaceImage(image)
+	}
+	if err != nil {
+		i.deleteBlob(key)
+		return domain.EnterpriseImage{}, err
+	}
+
+	// the rows of the replaced images are gone with the commit, only their files are left.
+	for _, old := range replaced {
+		i.deleteBlob(old.Key)
+	}
+	return image, nil
+}
+
+// DeleteImage removes an image of the enterprise, its owner and users with enterprise:manage may.
+func (i imageUsecase) DeleteImage(enterpriseID string, imageID string, actor domain.Actor) error {
+	enterprise, err := i.enterpriseRepository.FindByID(enterpriseID)
+	if err != nil {
+		return err
+	}
+	if enterprise.ID == uuid.Nil {
+		return errors.New("enterprise not found")
+	}
+	if err = i.policy.OwnerOr(actor, enterprise.UserID, role.EnterpriseManage, "delete image"); err != nil {
+		return err
+	}
+
+	image, err := i.imageRepository.FindByID(imageID)
+	if err != nil {
+		return err
+	}
+	if image.ID == uuid.Nil || image.EnterpriseID != enterprise.ID {
+		return errors.New("image not found")
+	}
+	if err = i.imageRepository.Delete(image); err != nil {
+		return err
+	}
+	i.deleteBlob(image.Key)
+	return nil
+}
+
+// deleteBlob only logs a failure, an orphaned file is not worth failing the request that left it.
+func (i imageUsecase) deleteBlob(key string) {
+	if err := i.blobs.Delete(key); err != nil {
+		log.WithField("key", key).Warn("delete image file: " + err.Error())
+	}
+}
@@ -0,0 +1,235 @@
+package usecase_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/image/usecase"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+var dummyOwner = domain.User{
+	ID:    uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf891"),
+	Roles: []domain.Role{{Name: "ROLE_CLIENT", ID: 2}},
+}
+
+var dummyStranger = domain.User{
+	ID:    uuid.FromStringOrNil("0cf712fc-e631-40c7-8572-54772e698edf"),
+	Roles: []domain.Role{{Name: "ROLE_CLIENT", ID: 2}},
+}
+
+var dummyEnterprise = domain.Enterprise{
+	ID:     uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
+	UserID: dummyOwner.ID,
+	Name:   "enterprise satu",
+}
+
+var dummyLogo = domain.EnterpriseImage{
+	ID:           uuid.FromStringOrNil("8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"),
+	EnterpriseID: dummyEnterprise.ID,
+	Kind:         domain.ImageLogo,
+	Key:          "enterprises/35d6a9a1-aa5e-41f1-9991-08878dfdf89a/8c1f2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b.png",
+}
+
+// png is enough of a PNG for content sniffing.
+var png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+var ownerActor = domain.Actor{UserID: dummyOwner.ID.String()}
+
+func newBlobStore() *mocks.BlobStore {
+	blobs := new(mocks.BlobStore)
+	blobs.On("URL", mock.AnythingOfType("string")).Return(func(key string) string { return "/uploads/" + key }).Maybe()
+	return blobs
+}
+
+func TestImageUsecase_UploadImage(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockImageRepository := new(mocks.ImageRepository)
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		blobs := newBlobStore()
+		uc := usecase.NewImageUsecase(mockImageRepository, mockEnterpriseRepository, new(mocks.UserRepository), blobs)
+
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+		mockImageRepository.On("FindByEnterpriseID", dummyEnterprise.ID.String()).Return(domain.EnterpriseImages{}, nil).Once()
+		blobs.On("Put", mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "enterprises/"+dummyEnterprise.ID.String()+"/") && strings.HasSuffix(key, ".png")
+		}), "image/png", mock.Anything, int64(len(png))).Return(nil).Once()
+		mockImageRepository.On("SaveGallery", mock.AnythingOfType("domain.EnterpriseImage"), 10).Return(func(image domain.EnterpriseImage, max int) domain.EnterpriseImage {
+			return image
+		}, nil).Once()
+
+		image, err := uc.UploadImage(dummyEnterprise.ID.String(), ownerActor, domain.ImageGallery, bytes.NewReader(png))
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ImageGallery, image.Kind)
+		assert.Equal(t, "image/png", image.ContentType)
+		assert.Equal(t, "/uploads/"+image.Key, image.URL)
+		mockImageRepository.AssertExpectations(t)
+		blobs.AssertExpectations(t)
+	})
+
+	t.Run("replaces-logo", func(t *testing.T) {
+		mockImageRepository := new(mocks.ImageRepository)
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		blobs := newBlobStore()
+		uc := usecase.NewImageUsecase(mockImageRepository, mockEnterpriseRepository, new(mocks.UserRepository), blobs)
+
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+		blobs.On("Put", mock.AnythingOfType("string"), "image/png", mock.Anything, int64(len(png))).Return(nil).Once()
+		mockImageRepository.On("ReplaceImage", mock.AnythingOfType("domain.EnterpriseImage")).Return(func(image domain.EnterpriseImage) domain.EnterpriseImage {
+			return image
+		}, domain.EnterpriseImages{dummyLogo}, nil).Once()
+		blobs.On("Delete", dummyLogo.Key).Return(nil).Once()
+
+		image, err := uc.UploadImage(dummyEnterprise.ID.String(), ownerActor, domain.ImageLogo, bytes.NewReader(png))
+		assert.NoError(t, err)
+		assert.NotEqual(t, dummyLogo.ID, image.ID)
+		mockImageRepository.AssertExpectations(t)
+		blobs.AssertExpectations(t)
+	})
+
+	t.Run("save-failed-deletes-file", func(t *testing.T) {
+		mockImageRepository := new(mocks.ImageRepository)
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		blobs := newBlobStore()
+		uc := usecase.NewImageUsecase(mockImageRepository, mockEnterpriseRepository, new(mocks.UserRepository), blobs)
+
+		var stored string
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+		blobs.On("Put", mock.AnythingOfType("string"), "image/png", mock.Anything, int64(len(png))).Run(func(args mock.Arguments) {
+			stored = args.String(0)
+		}).Return(nil).Once()
+		mockImageRepository.On("ReplaceImage", mock.AnythingOfType("domain.EnterpriseImage")).Return(domain.EnterpriseImage{}, nil, errors.New("database down")).Once()
+		blobs.On("Delete", mock.AnythingOfType("string")).Return(nil).Once()
+
+		_, err := uc.UploadImage(dummyEnterprise.ID.String(), ownerActor, domain.ImageCover, bytes.NewReader(png))
+		assert.Error(t, err)
+		blobs.AssertCalled(t, "Delete", stored)
+	})
+
+	t.Run("not-an-image", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		blobs := newBlobStore()
+		uc := usecase.NewImageUsecase(new(mocks.ImageRepository), mockEnterpriseRepository, new(mocks.UserRepository), blobs)
+
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+
+		_, err := uc.UploadImage(dummyEnterprise.ID.String(), ownerActor, domain.ImageLogo, strings.NewReader("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+		assert.EqualError(t, err, "image must be a JPEG, PNG or WebP")
+		blobs.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		uc := usecase.NewImageUsecase(new(mocks.ImageRepository), mockEnterpriseRepository, new(mocks.UserRepository), newBlobStore())
+
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+
+		large := append(append([]byte{}, png...), make([]byte, domain.MaxImageSize)...)
+		_, err := uc.UploadImage(dummyEnterprise.ID.String(), ownerActor, domain.ImageLogo, bytes.NewReader(large))
+		assert.EqualError(t, err, "image must be at most 5 MB")
+	})
+
+	t.Run("gallery-full", func(t *testing.T) {
+		mockImageRepository := new(mocks.ImageRepository)
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		uc := usecase.NewImageUsecase(mockImageRepository, mockEnterpriseRepository, new(mocks.UserRepository), newBlobStore())
+
+		gallery := make(domain.EnterpriseImages, 10)
+		for i := range gallery {
+			gallery[i] = domain.EnterpriseImage{ID: uuid.NewV4(), EnterpriseID: dummyEnterprise.ID, Kind: domain.ImageGallery}
+		}
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+		mockImageRepository.On("FindByEnterpriseID", dummyEnterprise.ID.String()).Return(gallery, nil).Once()
+
+		_, err := uc.UploadImage(dummyEnterprise.ID.String(), ownerActor, domain.ImageGallery, bytes.NewReader(png))
+		assert.EqualError(t, err, "an enterprise can have at most 10 gallery images")
+	})
+
+	t.Run("gallery-filled-meanwhile", func(t *testing.T) {
+		mockImageRepository := new(mocks.ImageRepository)
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		blobs := newBlobStore()
+		uc := usecase.NewImageUsecase(mockImageRepository, mockEnterpriseRepository, new(mocks.UserRepository), blobs)
+
+		var stored string
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+		mockImageRepository.On("FindByEnterpriseID", dummyEnterprise.ID.String()).Return(domain.EnterpriseImages{}, nil).Once()
+		blobs.On("Put", mock.AnythingOfType("string"), "image/png", mock.Anything, int64(len(png))).Run(func(args mock.Arguments) {
+			stored = args.String(0)
+		}).Return(nil).Once()
+		mockImageRepository.On("SaveGallery", mock.AnythingOfType("domain.EnterpriseImage"), 10).
+			Return(domain.EnterpriseImage{}, errors.New("an enterprise can have at most 10 gallery images")).Once()
+		blobs.On("Delete", mock.AnythingOfType("string")).Return(nil).Once()
+
+		_, err := uc.UploadImage(dummyEnterprise.ID.String(), ownerActor, domain.ImageGallery, bytes.NewReader(png))
+		assert.EqualError(t, err, "an enterprise can have at most 10 gallery images")
+		blobs.AssertCalled(t, "Delete", stored)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		mockUserRepository := new(mocks.UserRepository)
+		uc := usecase.NewImageUsecase(new(mocks.ImageRepository), mockEnterpriseRepository, mockUserRepository, newBlobStore())
+
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+		mockUserRepository.On("FindUserById", dummyStranger.ID.String()).Return(dummyStranger, nil).Once()
+
+		_, err := uc.UploadImage(dummyEnterprise.ID.String(), domain.Actor{UserID: dummyStranger.ID.String()}, domain.ImageLogo, bytes.NewReader(png))
+		var forbidden *domain.ForbiddenError
+		assert.ErrorAs(t, err, &forbidden)
+	})
+}
+
+func TestImageUsecase_DeleteImage(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockImageRepository := new(mocks.ImageRepository)
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		blobs := newBlobStore()
+		uc := usecase.NewImageUsecase(mockImageRepository, mockEnterpriseRepository, new(mocks.UserRepository), blobs)
+
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+		mockImageRepository.On("FindByID", dummyLogo.ID.String()).Return(dummyLogo, nil).Once()
+		mockImageRepository.On("Delete", dummyLogo).Return(nil).Once()
+		blobs.On("Delete", dummyLogo.Key).Return(nil).Once()
+
+		err := uc.DeleteImage(dummyEnterprise.ID.String(), dummyLogo.ID.String(), ownerActor)
+		assert.NoError(t, err)
+		mockImageRepository.AssertExpectations(t)
+		blobs.AssertExpectations(t)
+	})
+
+	t.Run("other-enterprise", func(t *testing.T) {
+		mockImageRepository := new(mocks.ImageRepository)
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		uc := usecase.NewImageUsecase(mockImageRepository, mockEnterpriseRepository, new(mocks.UserRepository), newBlobStore())
+
+		other := dummyLogo
+		other.EnterpriseID = uuid.NewV4()
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+		mockImageRepository.On("FindByID", dummyLogo.ID.String()).Return(other, nil).Once()
+
+		err := uc.DeleteImage(dummyEnterprise.ID.String(), dummyLogo.ID.String(), ownerActor)
+		assert.EqualError(t, err, "image not found")
+		mockImageRepository.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("file-delete-failed", func(t *testing.T) {
+		mockImageRepository := new(mocks.ImageRepository)
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		blobs := newBlobStore()
+		uc := usecase.NewImageUsecase(mockImageRepository, mockEnterpriseRepository, new(mocks.UserRepository), blobs)
+
+		mockEnterpriseRepository.On("FindByID", dummyEnterprise.ID.String()).Return(dummyEnterprise, nil).Once()
+		mockImageRepository.On("FindByID", dummyLogo.ID.String()).Return(dummyLogo, nil).Once()
+		mockImageRepository.On("Delete", dummyLogo).Return(nil).Once()
+		blobs.On("Delete", dummyLogo.Key).Return(errors.New("store unavailable")).Once()
+
+		err := uc.DeleteImage(dummyEnterprise.ID.String(), dummyLogo.ID.String(), ownerActor)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,33 @@
+package mail
+
+import (
+	"fmt"
+	"github.com/nrmadi02/mini-project/domain"
+	"os"
+	"sync"
+	"time"
+)
+
+type fileMailer struct {
+	path string
+	mu   *sync.Mutex
+}
+
+// NewFileMailer appends every mail to a file instead of sending it, for development without an SMTP server.
+func NewFileMailer(path string) domain.Mailer {
+	return fileMailer{path: path, mu: &sync.Mutex{}}
+}
+
+func (m fileMailer) Send(to string, subject string, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fp, err := os.OpenFile(m.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	_, err = fmt.Fprintf(fp, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), to, subject, body)
+	return err
+}
@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type smtpMailer struct {
+	address string
+	auth    smtp.Auth
+	from    string
+}
+
+// NewSMTPMailer sends through an SMTP server. Without username the server must accept mail
+// without authentication, PLAIN auth is only used over TLS or to localhost.
+func NewSMTPMailer(host string, port string, username string, password string, from string) domain.Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return smtpMailer{
+		address: net.JoinHostPort(host, port),
+		auth:    auth,
+		from:    from,
+	}
+}
+
+func (m smtpMailer) Send(to string, subject string, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return errors.New("mail header must not contain a line break")
+	}
+	message := "From: " + m.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body
+	return smtp.SendMail(m.address, m.auth, m.from, []string{to}, []byte(message))
+}
@@ -0,0 +1,293 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// keysRefreshInterval limits fetching the provider keys again for tokens with an unknown kid.
+	keysRefreshInterval = time.Minute
+	// clockSkew is accepted on exp and iat of the ID token.
+	clockSkew = time.Minute
+)
+
+// Provider talks to an OpenID Connect provider with the authorization code flow and PKCE.
+// The endpoints are discovered from the issuer on first use.
+type Provider struct {
+	name         string
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	client       *http.Client
+
+	mu            sync.Mutex
+	metadata      *metadata
+	keys          helper.JSONWebKeySet
+	keysFetchedAt time.Time
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProvider creates a provider, client may be nil for a client with a 10 second timeout.
+func NewProvider(name string, issuer string, clientID string, clientSecret string, redirectURL string, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{
+		name:         name,
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		client:       client,
+	}
+}
+
+func (p *Provider) Name() string {
+	return p.name
+}
+
+func (p *Provider) AuthCodeURL(state string, nonce string, codeVerifier string) (string, error) {
+	meta, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.clientID},
+		"redirect_uri":          {p.redirectURL},
+		"scope":                 {"openid email profile"},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return meta.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (p *Provider) Exchange(code string, codeVerifier string, nonce string) (domain.OIDCClaims, error) {
+	meta, err := p.discover()
+	if err != nil {
+		return domain.OIDCClaims{}, err
+	}
+
+	res, err := p.client.PostForm(meta.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"code_verifier": {codeVerifier},
+		"client_id":     {p.clientID},
+		"client_secret": {p.clientSecret},
+	})
+	if err != nil {
+		return domain.OIDCClaims{}, fmt.Errorf("oidc %s token request: %v", p.name, err)
+	}
+	defer res.Body.Close()
+
+	var token tokenResponse
+	if err = json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return domain.OIDCClaims{}, fmt.Errorf("oidc %s token response: %v", p.name, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		if token.ErrorDescription != "" {
+			token.Error += ", " + token.ErrorDescription
+		}
+		return domain.OIDCClaims{}, fmt.Errorf("oidc %s token request failed: %s", p.name, token.Error)
+	}
+	if token.IDToken == "" {
+		return domain.OIDCClaims{}, fmt.Errorf("oidc %s token response has no id_token", p.name)
+	}
+	return p.verify(token.IDToken, nonce)
+}
+
+// verify checks the signature, issuer, audience, expiry and nonce of the ID token.
+func (p *Provider) verify(idToken string, nonce string) (domain.OIDCClaims, error) {
+	var claims idTokenClaims
+	parser := jwt.Parser{ValidMethods: []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}}
+	if _, err := parser.ParseWithClaims(idToken, &claims, p.keyfunc); err != nil {
+		return domain.OIDCClaims{}, fmt.Errorf("oidc %s id token: %v", p.name, err)
+	}
+	if claims.Issuer != p.issuer {
+		return domain.OIDCClaims{}, fmt.Errorf("oidc %s id token: unexpected issuer %s", p.name, claims.Issuer)
+	}
+	if !claims.Audience.contains(p.clientID) {
+		return domain.OIDCClaims{}, fmt.Errorf("oidc %s id token: not issued for this client", p.name)
+	}
+	if claims.Nonce != nonce {
+		return domain.OIDCClaims{}, fmt.Errorf("oidc %s id token: nonce mismatch", p.name)
+	}
+	if claims.Subject == "" {
+		return domain.OIDCClaims{}, fmt.Errorf("oidc %s id token: subject missing", p.name)
+	}
+
+	return domain.OIDCClaims{
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     bool(claims.EmailVerified),
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+func (p *Provider) keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	keys, err := p.jsonWebKeys(kid)
+	if err != nil {
+		return nil, err
+	}
+	return keys.Keyfunc(t)
+}
+
+// jsonWebKeys returns the cached keys of the provider. They are fetched again for an unknown
+// kid, the provider has rotated its keys then.
+func (p *Provider) jsonWebKeys(kid string) (helper.JSONWebKeySet, error) {
+	meta, err := p.discover()
+	if err != nil {
+		return helper.JSONWebKeySet{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.keysFetchedAt.IsZero() && (p.keys.HasKey(kid) || time.Since(p.keysFetchedAt) < keysRefreshInterval) {
+		return p.keys, nil
+	}
+
+	var keys helper.JSONWebKeySet
+	if err = p.getJSON(meta.JWKSURI, &keys); err != nil {
+		return helper.JSONWebKeySet{}, err
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	return keys, nil
+}
+
+// discover reads the provider metadata once, a failed attempt is tried again on the next login.
+func (p *Provider) discover() (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var meta metadata
+	if err := p.getJSON(p.issuer+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, err
+	}
+	if meta.Issuer != p.issuer {
+		return nil, fmt.Errorf("oidc %s discovery: issuer %s does not match %s", p.name, meta.Issuer, p.issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc %s discovery: endpoints missing", p.name)
+	}
+	p.metadata = &meta
+	return p.metadata, nil
+}
+
+func (p *Provider) getJSON(target string, v interface{}) error {
+	res, err := p.client.Get(target)
+	if err != nil {
+		return fmt.Errorf("oidc %s: %v", p.name, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc %s: %s returned %s", p.name, target, res.Status)
+	}
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("oidc %s: %v", p.name, err)
+	}
+	return nil
+}
+
+// idTokenClaims are the ID token claims from OpenID Connect Core 1.0 section 2 and 5.1 that
+// are used here.
+type idTokenClaims struct {
+	Issuer            string    `json:"iss"`
+	Subject           string    `json:"sub"`
+	Audience          audience  `json:"aud"`
+	ExpiresAt         int64     `json:"exp"`
+	IssuedAt          int64     `json:"iat"`
+	Nonce             string    `json:"nonce"`
+	Email             string    `json:"email"`
+	EmailVerified     boolClaim `json:"email_verified"`
+	Name              string    `json:"name"`
+	PreferredUsername string    `json:"preferred_username"`
+}
+
+func (c *idTokenClaims) Valid() error {
+	now := time.Now()
+	if c.ExpiresAt == 0 || now.After(time.Unix(c.ExpiresAt, 0).Add(clockSkew)) {
+		return errors.New("token is expired")
+	}
+	if c.IssuedAt != 0 && now.Add(clockSkew).Before(time.Unix(c.IssuedAt, 0)) {
+		return errors.New("token used before issued")
+	}
+	return nil
+}
+
+// audience is a single string or a list of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+func (a audience) contains(value string) bool {
+	for _, v := range a {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// boolClaim accepts "true" as well, some providers send email_verified as a string.
+type boolClaim bool
+
+func (b *boolClaim) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true", `"true"`:
+		*b = true
+	default:
+		*b = false
+	}
+	return nil
+}
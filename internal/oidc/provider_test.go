@@ -0,0 +1,91 @@
+package oidc_test
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/oidc"
+	"github.com/nrmadi02/mini-project/internal/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+const redirectURL = "http://localhost:3000/login/callback"
+
+var dummyClaims = domain.OIDCClaims{
+	Subject:           "248289761001",
+	Email:             "satu@email.com",
+	EmailVerified:     true,
+	Name:              "User Satu",
+	PreferredUsername: "usr1",
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	server := oidctest.NewServer("umkm", "client-secret")
+	defer server.Close()
+	server.SetUser(dummyClaims)
+
+	t.Run("success", func(t *testing.T) {
+		provider := oidc.NewProvider("keycloak", server.Issuer()+"/", "umkm", "client-secret", redirectURL, nil)
+		authURL, err := provider.AuthCodeURL("state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+		assert.NoError(t, err)
+		parsed, _ := url.Parse(authURL)
+		assert.Equal(t, redirectURL, parsed.Query().Get("redirect_uri"))
+		assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+
+		code, state, err := server.Authorize(authURL)
+		assert.NoError(t, err)
+		assert.Equal(t, "state-1", state)
+
+		claims, err := provider.Exchange(code, "verifier-verifier-verifier-verifier-verifier", "nonce-1")
+		assert.NoError(t, err)
+		assert.Equal(t, dummyClaims, claims)
+	})
+
+	t.Run("error-nonce-mismatch", func(t *testing.T) {
+		provider := oidc.NewProvider("keycloak", server.Issuer(), "umkm", "client-secret", redirectURL, nil)
+		authURL, _ := provider.AuthCodeURL("state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+		code, _, err := server.Authorize(authURL)
+		assert.NoError(t, err)
+
+		_, err = provider.Exchange(code, "verifier-verifier-verifier-verifier-verifier", "nonce-2")
+		assert.EqualError(t, err, "oidc keycloak id token: nonce mismatch")
+	})
+
+	t.Run("error-code-verifier", func(t *testing.T) {
+		provider := oidc.NewProvider("keycloak", server.Issuer(), "umkm", "client-secret", redirectURL, nil)
+		authURL, _ := provider.AuthCodeURL("state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+		code, _, err := server.Authorize(authURL)
+		assert.NoError(t, err)
+
+		_, err = provider.Exchange(code, "another-verifier", "nonce-1")
+		assert.Error(t, err)
+	})
+
+	t.Run("error-code-reused", func(t *testing.T) {
+		provider := oidc.NewProvider("keycloak", server.Issuer(), "umkm", "client-secret", redirectURL, nil)
+		authURL, _ := provider.AuthCodeURL("state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+		code, _, err := server.Authorize(authURL)
+		assert.NoError(t, err)
+
+		_, err = provider.Exchange(code, "verifier-verifier-verifier-verifier-verifier", "nonce-1")
+		assert.NoError(t, err)
+		_, err = provider.Exchange(code, "verifier-verifier-verifier-verifier-verifier", "nonce-1")
+		assert.Error(t, err)
+	})
+
+	t.Run("error-client-secret", func(t *testing.T) {
+		provider := oidc.NewProvider("keycloak", server.Issuer(), "umkm", "wrong-secret", redirectURL, nil)
+		authURL, _ := provider.AuthCodeURL("state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+		code, _, err := server.Authorize(authURL)
+		assert.NoError(t, err)
+
+		_, err = provider.Exchange(code, "verifier-verifier-verifier-verifier-verifier", "nonce-1")
+		assert.EqualError(t, err, "oidc keycloak token request failed: invalid_client")
+	})
+
+	t.Run("error-discovery", func(t *testing.T) {
+		provider := oidc.NewProvider("keycloak", server.Issuer()+"/realms/unknown", "umkm", "client-secret", redirectURL, nil)
+		_, err := provider.AuthCodeURL("state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,86 @@
+package pagination
+
+import (
+	"fmt"
+
+	"github.com/nrmadi02/mini-project/domain"
+	"gorm.io/gorm"
+)
+
+// Keyset pages a query on a sort column and the id, so each page is found from the cursor row
+// instead of skipping the rows before it. Column "id" sorts on the id alone.
+type Keyset struct {
+	Column string
+	Desc   bool
+}
+
+// Apply narrows db to the rows past the cursor in page order, fetching one row more than the page
+// so Page can tell whether another page follows.
+func (k Keyset) Apply(db *gorm.DB, page domain.PageRequest) *gorm.DB {
+	desc := k.Desc
+	if page.Cursor != nil && page.Cursor.Backward {
+		desc = !desc
+	}
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+
+	if page.Cursor != nil {
+		if k.Column == "id" {
+			db = db.Where("id "+op+" ?", page.Cursor.ID)
+		} else {
+			db = db.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", k.Column, op),
+				page.Cursor.Key, page.Cursor.Key, page.Cursor.ID)
+		}
+	}
+	if k.Column != "id" {
+		db = db.Order(k.Column + " " + dir)
+	}
+	return db.Order("id " + dir).Limit(page.Length + 1)
+}
+
+// Page trims the extra row fetched by Apply, puts the rows back in listing order and sets the
+// cursors of the neighbouring pages.
+func Page[T any](rows []T, page domain.PageRequest, cursor func(T) domain.Cursor) ([]T, domain.PageInfo) {
+	var info domain.PageInfo
+	backward := page.Cursor != nil && page.Cursor.Backward
+	more := len(rows) > page.Length
+	if more {
+		rows = rows[:page.Length]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+	if len(rows) == 0 {
+		return rows, info
+	}
+
+	// going back there is always a next page, the one the cursor came from.
+	if more || backward {
+		next := cursor(rows[len(rows)-1])
+		info.Next = &next
+	}
+	if (more && backward) || (page.Cursor != nil && !backward) {
+		prev := cursor(rows[0])
+		prev.Backward = true
+		info.Prev = &prev
+	}
+	return rows, info
+}
+
+// Count sets info.Total to the number of rows of db when the page asks for it.
+func Count(db *gorm.DB, page domain.PageRequest, info *domain.PageInfo) error {
+	if !page.WithTotal {
+		return nil
+	}
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return err
+	}
+	totalData := int(total)
+	info.Total = &totalData
+	return nil
+}
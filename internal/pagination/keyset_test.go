@@ -0,0 +1,64 @@
+package pagination_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/pagination"
+	"github.com/stretchr/testify/assert"
+)
+
+func cursorOf(n int) domain.Cursor {
+	return domain.Cursor{Key: float64(n), ID: string(rune('a' + n))}
+}
+
+func TestPage(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     []int
+		cursor   *domain.Cursor
+		wantRows []int
+		wantNext *domain.Cursor
+		wantPrev *domain.Cursor
+	}{
+		{name: "first page", rows: []int{1, 2, 3}, wantRows: []int{1, 2}, wantNext: &domain.Cursor{Key: float64(2), ID: "c"}},
+		{name: "only page", rows: []int{1, 2}, wantRows: []int{1, 2}},
+		{name: "empty", rows: []int{}, wantRows: []int{}},
+		{name: "middle page", rows: []int{3, 4, 5}, cursor: &domain.Cursor{Key: float64(2), ID: "c"}, wantRows: []int{3, 4},
+			wantNext: &domain.Cursor{Key: float64(4), ID: "e"}, wantPrev: &domain.Cursor{Key: float64(3), ID: "d", Backward: true}},
+		{name: "last page", rows: []int{5}, cursor: &domain.Cursor{Key: float64(4), ID: "e"}, wantRows: []int{5},
+			wantPrev: &domain.Cursor{Key: float64(5), ID: "f", Backward: true}},
+		{name: "back to middle", rows: []int{4, 3, 2}, cursor: &domain.Cursor{Key: float64(5), ID: "f", Backward: true}, wantRows: []int{3, 4},
+			wantNext: &domain.Cursor{Key: float64(4), ID: "e"}, wantPrev: &domain.Cursor{Key: float64(3), ID: "d", Backward: true}},
+		{name: "back to first", rows: []int{2, 1}, cursor: &domain.Cursor{Key: float64(3), ID: "d", Backward: true}, wantRows: []int{1, 2},
+			wantNext: &domain.Cursor{Key: float64(2), ID: "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, info := pagination.Page(tt.rows, domain.PageRequest{Cursor: tt.cursor, Length: 2}, cursorOf)
+			assert.Equal(t, tt.wantRows, rows)
+			assert.Equal(t, tt.wantNext, info.Next)
+			assert.Equal(t, tt.wantPrev, info.Prev)
+		})
+	}
+}
+
+func TestCursor_Encode(t *testing.T) {
+	createdAt := time.Date(2022, 5, 7, 18, 11, 36, 681000000, time.UTC)
+	for _, cursor := range []domain.Cursor{
+		{Key: "enterprise satu", ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89a"},
+		{Key: 4.5, ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", Backward: true},
+		{Key: createdAt, ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89a"},
+		{ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89a"},
+	} {
+		parsed, err := domain.ParseCursor(cursor.Encode())
+		assert.NoError(t, err)
+		assert.Equal(t, cursor, parsed)
+	}
+
+	for _, value := range []string{"satu", "e30", "!!!"} {
+		_, err := domain.ParseCursor(value)
+		assert.Error(t, err)
+	}
+}
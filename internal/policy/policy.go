@@ -0,0 +1,57 @@
+package policy
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Policy decides who may act on a resource that belongs to a user. The owner may, anyone else
+// needs the permission through one of their roles: admins have every permission, moderators have
+// enterprise:publish and review:moderate (see db/seeds). Denials are domain.ForbiddenError.
+type Policy struct {
+	userRepository domain.UserRepository
+}
+
+func NewPolicy(ur domain.UserRepository) Policy {
+	return Policy{userRepository: ur}
+}
+
+// Owner allows only the owner of the resource, there is no override.
+func (p Policy) Owner(actor domain.Actor, ownerID uuid.UUID, action string) error {
+	if isOwner(actor, ownerID) {
+		return nil
+	}
+	return domain.NewForbiddenError("only the owner can " + action)
+}
+
+// OwnerOr allows the owner and users with permission. The actor is only loaded when it is not the owner.
+func (p Policy) OwnerOr(actor domain.Actor, ownerID uuid.UUID, permission role.Permission, action string) error {
+	if isOwner(actor, ownerID) {
+		return nil
+	}
+	allowed, err := p.Can(actor, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.NewForbiddenError("only the owner or users with " + permission.String() + " can " + action)
+	}
+	return nil
+}
+
+// Can reports whether the actor has permission through its roles.
+func (p Policy) Can(actor domain.Actor, permission role.Permission) (bool, error) {
+	if actor.UserID == "" {
+		return false, nil
+	}
+	user, err := p.userRepository.FindUserById(actor.UserID)
+	if err != nil {
+		return false, err
+	}
+	return user.HasPermission(permission.String()), nil
+}
+
+func isOwner(actor domain.Actor, ownerID uuid.UUID) bool {
+	return actor.UserID != "" && ownerID != uuid.Nil && actor.UserID == ownerID.String()
+}
@@ -0,0 +1,85 @@
+package policy_test
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/policy"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var (
+	owner     = domain.User{ID: uuid.NewV4(), Roles: []domain.Role{{Name: "ROLE_CLIENT", ID: 2}}}
+	admin     = domain.User{ID: uuid.NewV4(), Roles: []domain.Role{{Name: "ROLE_ADMIN", ID: 1, Permissions: []domain.Permission{{ID: 3, Name: "enterprise:manage"}, {ID: 4, Name: "review:moderate"}}}}}
+	moderator = domain.User{ID: uuid.NewV4(), Roles: []domain.Role{{Name: "ROLE_MODERATOR", ID: 4, Permissions: []domain.Permission{{ID: 4, Name: "review:moderate"}}}}}
+	stranger  = domain.User{ID: uuid.NewV4(), Roles: []domain.Role{{Name: "ROLE_CLIENT", ID: 2}}}
+)
+
+func TestPolicy_Owner(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   domain.Actor
+		wantErr bool
+	}{
+		{"owner", domain.Actor{UserID: owner.ID.String()}, false},
+		{"admin", domain.Actor{UserID: admin.ID.String()}, true},
+		{"anonymous", domain.Actor{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := policy.NewPolicy(new(mocks.UserRepository))
+			err := p.Owner(tt.actor, owner.ID, "update rating")
+			if tt.wantErr {
+				assert.EqualError(t, err, "only the owner can update rating")
+				assert.IsType(t, &domain.ForbiddenError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPolicy_OwnerOr(t *testing.T) {
+	tests := []struct {
+		name       string
+		actor      domain.User
+		permission role.Permission
+		wantErr    bool
+	}{
+		{"owner", owner, role.ReviewModerate, false},
+		{"admin", admin, role.EnterpriseManage, false},
+		{"moderator", moderator, role.ReviewModerate, false},
+		{"moderator without permission", moderator, role.EnterpriseManage, true},
+		{"stranger", stranger, role.ReviewModerate, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepository := new(mocks.UserRepository)
+			if tt.actor.ID != owner.ID {
+				mockUserRepository.On("FindUserById", tt.actor.ID.String()).Return(tt.actor, nil).Once()
+			}
+			p := policy.NewPolicy(mockUserRepository)
+			err := p.OwnerOr(domain.Actor{UserID: tt.actor.ID.String()}, owner.ID, tt.permission, "delete review")
+			if tt.wantErr {
+				assert.EqualError(t, err, "only the owner or users with "+tt.permission.String()+" can delete review")
+				assert.IsType(t, &domain.ForbiddenError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockUserRepository.AssertExpectations(t)
+		})
+	}
+
+	t.Run("error find actor", func(t *testing.T) {
+		mockUserRepository := new(mocks.UserRepository)
+		mockUserRepository.On("FindUserById", stranger.ID.String()).Return(domain.User{}, errors.New("record not found")).Once()
+		p := policy.NewPolicy(mockUserRepository)
+		err := p.OwnerOr(domain.Actor{UserID: stranger.ID.String()}, owner.ID, role.ReviewModerate, "delete review")
+		assert.EqualError(t, err, "record not found")
+	})
+}
@@ -20,6 +20,11 @@ func (r ratingRepository) GetAllRatingByEnterpriseID(id string) (ratings domain.
 	return ratings, err
 }
 
+func (r ratingRepository) FindByUserID(userid string) (ratings domain.RatingEnterprises, err error) {
+	err = r.DB.Where("user_id = ?", userid).Find(&ratings).Error
+	return ratings, err
+}
+
 func (r ratingRepository) FindRatingByIDUserAndEnterprise(id string, userid string) (rating domain.RatingEnterprise, err error) {
 	err = r.DB.Where("enterprise_id = ? AND user_id = ?", id, userid).Find(&rating).Error
 	return rating, err
@@ -46,3 +51,19 @@ func (r ratingRepository) FindAvgByEnterpriseID(id string) float64 {
 	row.Scan(&result)
 	return result
 }
+
+func (r ratingRepository) CountByEnterprise() (map[string]int, error) {
+	var rows []struct {
+		EnterpriseID string
+		Total        int
+	}
+	err := r.DB.Table("rating_enterprises").Select("enterprise_id, COUNT(*) AS total").Group("enterprise_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.EnterpriseID] = row.Total
+	}
+	return counts, nil
+}
@@ -91,6 +91,24 @@ func TestRatingRepository_GetAllRatingByEnterpriseID(t *testing.T) {
 	assert.NotNil(t, ratings)
 }
 
+func TestRatingRepository_FindByUserID(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `rating_enterprises` WHERE user_id = ?").
+		WithArgs(dummyRating[0].UserID).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "rating", "enterprise_id", "user_id"}).
+			AddRow(dummyRating[0].ID, dummyRating[0].Rating, dummyRating[0].EnterpriseID, dummyRating[0].UserID))
+
+	ratingRepository := repository.NewRatingRepository(db)
+	ratings, err := ratingRepository.FindByUserID(dummyRating[0].UserID.String())
+	assert.NoError(t, err)
+	assert.Len(t, ratings, 1)
+}
+
 func TestRatingRepository_AddRating(t *testing.T) {
 	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	if err != nil {
@@ -152,3 +170,21 @@ func TestRatingRepository_DeleteRating(t *testing.T) {
 	}
 	assert.NoError(t, err)
 }
+
+func TestRatingRepository_CountByEnterprise(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT enterprise_id, COUNT(*) AS total FROM `rating_enterprises` GROUP BY `enterprise_id`").
+		WillReturnRows(sqlMock.NewRows([]string{"enterprise_id", "total"}).
+			AddRow(dummyRating[0].EnterpriseID.String(), 3))
+
+	ratingRepository := repository.NewRatingRepository(db)
+	counts, err := ratingRepository.CountByEnterprise()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{dummyRating[0].EnterpriseID.String(): 3}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/policy"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -9,21 +11,29 @@ type ratingUsecase struct {
 	userRepository       domain.UserRepository
 	enterpriseRepository domain.EnterpriseRepository
 	ratingRepository     domain.RatingRepository
+	audit                domain.AuditUsecase
+	policy               policy.Policy
 }
 
-func NewRatingUsecase(ur domain.UserRepository, er domain.EnterpriseRepository, rr domain.RatingRepository) domain.RatingUsecase {
+func NewRatingUsecase(ur domain.UserRepository, er domain.EnterpriseRepository, rr domain.RatingRepository, audit domain.AuditUsecase) domain.RatingUsecase {
 	return ratingUsecase{
 		userRepository:       ur,
 		enterpriseRepository: er,
 		ratingRepository:     rr,
+		audit:                audit,
+		policy:               policy.NewPolicy(ur),
 	}
 }
 
-func (r ratingUsecase) UpdateRating(id, userid string, value int) (domain.RatingEnterprise, error) {
+// UpdateRating changes the value of a rating, only its own user may.
+func (r ratingUsecase) UpdateRating(id, userid string, actor domain.Actor, value int) (domain.RatingEnterprise, error) {
 	user, err := r.userRepository.FindUserById(userid)
 	if err != nil {
 		return domain.RatingEnterprise{}, err
 	}
+	if err = r.policy.Owner(actor, user.ID, "update rating"); err != nil {
+		return domain.RatingEnterprise{}, err
+	}
 	enterprise, err := r.enterpriseRepository.FindByID(id)
 	if err != nil {
 		return domain.RatingEnterprise{}, err
@@ -37,11 +47,15 @@ func (r ratingUsecase) UpdateRating(id, userid string, value int) (domain.Rating
 	return rating, err
 }
 
-func (r ratingUsecase) DeleteRating(id, userid string) error {
+// DeleteRating removes a rating, its own user and users with review:moderate may.
+func (r ratingUsecase) DeleteRating(id, userid string, actor domain.Actor) error {
 	user, err := r.userRepository.FindUserById(userid)
 	if err != nil {
 		return err
 	}
+	if err = r.policy.OwnerOr(actor, user.ID, role.ReviewModerate, "delete rating"); err != nil {
+		return err
+	}
 	enterprise, err := r.enterpriseRepository.FindByID(id)
 	if err != nil {
 		return err
@@ -56,6 +70,11 @@ func (r ratingUsecase) DeleteRating(id, userid string) error {
 		return err
 	}
 
+	r.audit.Record(actor, domain.AuditRatingDelete, domain.AuditTargetRating, rating.ID.String(), map[string]interface{}{
+		"enterprise_id": rating.EnterpriseID.String(),
+		"user_id":       rating.UserID.String(),
+		"rating":        rating.Rating,
+	}, nil)
 	return nil
 }
 
@@ -53,6 +53,8 @@ var dummyUser = domain.Users{
 	},
 }
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = domain.Enterprises{
 	domain.Enterprise{
 		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
@@ -61,8 +63,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Status:      0,
 		Tags: domain.Tags{
@@ -83,8 +85,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone:      "0012798232",
 		Address:          "bjm",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -110,12 +112,19 @@ var dummyRating = domain.RatingEnterprises{
 	},
 }
 
+// newAuditUsecase accepts every audit entry, tests of an entry assert it on their own mock.
+func newAuditUsecase() *mocks.AuditUsecase {
+	auditUsecase := new(mocks.AuditUsecase)
+	auditUsecase.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return auditUsecase
+}
+
 func TestRatingUsecase_AddNewRanting(t *testing.T) {
 	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
 	mockRatingRepository := new(mocks.RatingRepository)
 	mockUserRepository := new(mocks.UserRepository)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("AddRating", mock.AnythingOfType("domain.RatingEnterprise")).Return(dummyRating[0], nil).Once()
@@ -124,20 +133,20 @@ func TestRatingUsecase_AddNewRanting(t *testing.T) {
 		assert.NotNil(t, ranting)
 	})
 	t.Run("user not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(domain.User{}, errors.New("error something")).Once()
 		_, err := uc.AddNewRanting(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), 3)
 		assert.Error(t, err)
 	})
 	t.Run("enterprise not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("error something")).Once()
 		_, err := uc.AddNewRanting(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), 3)
 		assert.Error(t, err)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("AddRating", mock.AnythingOfType("domain.RatingEnterprise")).Return(domain.RatingEnterprise{}, errors.New("error something")).Once()
@@ -151,7 +160,7 @@ func TestRatingUsecase_FindRating(t *testing.T) {
 	mockRatingRepository := new(mocks.RatingRepository)
 	mockUserRepository := new(mocks.UserRepository)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("FindRatingByIDUserAndEnterprise", mock.AnythingOfType("string"),
@@ -161,20 +170,20 @@ func TestRatingUsecase_FindRating(t *testing.T) {
 		assert.NotNil(t, ranting)
 	})
 	t.Run("user not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(domain.User{}, errors.New("error something")).Once()
 		_, err := uc.FindRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
 		assert.Error(t, err)
 	})
 	t.Run("enterprise not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("error something")).Once()
 		_, err := uc.FindRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
 		assert.Error(t, err)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("FindRatingByIDUserAndEnterprise", mock.AnythingOfType("string"),
@@ -189,7 +198,7 @@ func TestRatingUsecase_GetAllRatingByEnterpriseID(t *testing.T) {
 	mockRatingRepository := new(mocks.RatingRepository)
 	mockUserRepository := new(mocks.UserRepository)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("GetAllRatingByEnterpriseID", mock.AnythingOfType("string"),
 			mock.AnythingOfType("string")).Return(domain.RatingEnterprises{
@@ -200,13 +209,13 @@ func TestRatingUsecase_GetAllRatingByEnterpriseID(t *testing.T) {
 		assert.NotNil(t, rantings)
 	})
 	t.Run("enterprise not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("error something")).Once()
 		_, err := uc.GetAllRatingByEnterpriseID(dummyEnterprise[0].ID.String())
 		assert.Error(t, err)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("GetAllRatingByEnterpriseID", mock.AnythingOfType("string"),
 			mock.AnythingOfType("string")).Return(domain.RatingEnterprises{}, errors.New("error something")).Once()
@@ -220,37 +229,43 @@ func TestRatingUsecase_UpdateRating(t *testing.T) {
 	mockRatingRepository := new(mocks.RatingRepository)
 	mockUserRepository := new(mocks.UserRepository)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("UpdateRating", mock.AnythingOfType("string"),
 			mock.AnythingOfType("string"), mock.AnythingOfType("int")).Return(dummyRating[0], nil).Once()
-		ranting, err := uc.UpdateRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), 3)
+		ranting, err := uc.UpdateRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, 3)
 		assert.NoError(t, err)
 		assert.NotNil(t, ranting)
 	})
 	t.Run("user not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(domain.User{}, errors.New("error something")).Once()
-		_, err := uc.UpdateRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), 3)
+		_, err := uc.UpdateRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, 3)
 		assert.Error(t, err)
 	})
 	t.Run("enterprise not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("error something")).Once()
-		_, err := uc.UpdateRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), 3)
+		_, err := uc.UpdateRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, 3)
 		assert.Error(t, err)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("UpdateRating", mock.AnythingOfType("string"),
 			mock.AnythingOfType("string"), mock.AnythingOfType("int")).Return(domain.RatingEnterprise{}, errors.New("error something")).Once()
-		_, err := uc.UpdateRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), 3)
+		_, err := uc.UpdateRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, 3)
 		assert.Error(t, err)
 	})
+	t.Run("not the owner", func(t *testing.T) {
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[0].ID.String()).Return(dummyUser[0], nil).Once()
+		_, err := uc.UpdateRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[1].ID.String()}, 3)
+		assert.IsType(t, &domain.ForbiddenError{}, err)
+	})
 }
 
 func TestRatingUsecase_DeleteRating(t *testing.T) {
@@ -258,42 +273,60 @@ func TestRatingUsecase_DeleteRating(t *testing.T) {
 	mockRatingRepository := new(mocks.RatingRepository)
 	mockUserRepository := new(mocks.UserRepository)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("FindRatingByIDUserAndEnterprise", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyRating[0], nil).Once()
 		mockRatingRepository.On("DeleteRating", mock.AnythingOfType("domain.RatingEnterprise")).Return(nil).Once()
-		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
+		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()})
 		assert.NoError(t, err)
 	})
 	t.Run("user not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(domain.User{}, errors.New("error something")).Once()
-		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
+		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()})
 		assert.Error(t, err)
 	})
 	t.Run("enterprise not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("error something")).Once()
-		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
+		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()})
 		assert.Error(t, err)
 	})
 	t.Run("rating not found", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("FindRatingByIDUserAndEnterprise", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(domain.RatingEnterprise{}, errors.New("error something")).Once()
-		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
+		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()})
 		assert.Error(t, err)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository)
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockRatingRepository.On("FindRatingByIDUserAndEnterprise", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyRating[0], nil).Once()
 		mockRatingRepository.On("DeleteRating", mock.AnythingOfType("domain.RatingEnterprise")).Return(errors.New("error something")).Once()
-		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
+		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()})
 		assert.Error(t, err)
 	})
+	t.Run("moderator", func(t *testing.T) {
+		moderator := domain.User{ID: uuid.NewV4(), Roles: []domain.Role{{Name: "ROLE_MODERATOR", ID: 4, Permissions: []domain.Permission{{ID: 4, Name: "review:moderate"}}}}}
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[0].ID.String()).Return(dummyUser[0], nil).Once()
+		mockUserRepository.On("FindUserById", moderator.ID.String()).Return(moderator, nil).Once()
+		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
+		mockRatingRepository.On("FindRatingByIDUserAndEnterprise", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyRating[0], nil).Once()
+		mockRatingRepository.On("DeleteRating", mock.AnythingOfType("domain.RatingEnterprise")).Return(nil).Once()
+		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: moderator.ID.String()})
+		assert.NoError(t, err)
+	})
+	t.Run("not the owner", func(t *testing.T) {
+		uc := usecase.NewRatingUsecase(mockUserRepository, mockEnterpriseRepository, mockRatingRepository, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[0].ID.String()).Return(dummyUser[0], nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		err := uc.DeleteRating(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[1].ID.String()})
+		assert.IsType(t, &domain.ForbiddenError{}, err)
+	})
 }
@@ -3,6 +3,7 @@ package http
 import (
 	"github.com/labstack/echo/v4"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
 	"github.com/nrmadi02/mini-project/web/response"
 	uuid "github.com/satori/go.uuid"
 	"net/http"
@@ -34,6 +35,20 @@ func NewReviewController(ru domain.ReviewUsecase, eu domain.EnterpriseUsecase, a
 	}
 }
 
+// reviewUser returns whose review a delete is about: the userid query param when it is sent,
+// otherwise the authenticated user. The usecase decides whether the actor may delete it.
+// Reviews are only added and updated by their own user, those take the user from the token.
+func reviewUser(c echo.Context) (string, error) {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return "", err
+	}
+	if userid := c.QueryParam("userid"); userid != "" {
+		return userid, nil
+	}
+	return claims.UserID(), nil
+}
+
 // AddReviewEnterprise godoc
 // @Summary Add Review
 // @Description add review enterprise
@@ -42,14 +57,18 @@ func NewReviewController(ru domain.ReviewUsecase, eu domain.EnterpriseUsecase, a
 // @Produce json
 // @Router /review/enterprise/{id} [post]
 // @Param id path string true "enterprise id"
-// @Param userid query string true "user id"
 // @param data body ReviewValue true "value review"
 // @Success 201 {object} response.JSONSuccessResult{data=interface{}}
 // @Failure 400 {object} response.JSONBadRequestResult{}
-// @Failure 404 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (r reviewController) AddReviewEnterprise(c echo.Context) error {
-	userid := c.QueryParam("userid")
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	userid := claims.UserID()
 	enterpriseid := c.Param("id")
 	var value ReviewValue
 	if err := c.Bind(&value); err != nil {
@@ -62,9 +81,9 @@ func (r reviewController) AddReviewEnterprise(c echo.Context) error {
 	if isReview.ID != uuid.FromStringOrNil("") {
 		return response.FailResponse(c, http.StatusBadRequest, false, "remove old review")
 	}
-	review, err := r.reviewUsecase.AddReview(enterpriseid, userid, value.Review)
+	review, err := r.reviewUsecase.AddReview(enterpriseid, userid, helper.GetActor(c), value.Review)
 	if err != nil {
-		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
 	}
 	return response.SuccessResponse(c, http.StatusCreated, true, "success add review", review)
 }
@@ -77,17 +96,24 @@ func (r reviewController) AddReviewEnterprise(c echo.Context) error {
 // @Produce json
 // @Router /review/enterprise/{id} [get]
 // @Param id path string true "enterprise id"
-// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Param cursor query string false "next_cursor or prev_cursor of the previous response"
+// @Param length query int false "page length, default 20, at most 100"
+// @Param with_total query bool false "also count every row"
+// @Success 200 {object} response.JSONSuccessListResult{data=interface{},metadata=response.PageMetadata}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Failure 404 {object} response.JSONBadRequestResult{}
 // @Security JWT
 func (r reviewController) GetListReviewByEnterpriseID(c echo.Context) error {
 	enterpriseid := c.Param("id")
+	page, err := helper.GetPageRequest(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
 	enterprise, _ := r.enterpriseUsecase.GetDetailEnterpriseByID(enterpriseid)
 	if enterprise.ID == uuid.FromStringOrNil("") {
 		return response.FailResponse(c, http.StatusBadRequest, false, "enterprise null")
 	}
-	reviews, err := r.reviewUsecase.GetListReviewsByEnterpriseID(enterpriseid)
+	reviews, info, err := r.reviewUsecase.GetListReviewsByEnterpriseID(enterpriseid, page)
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
@@ -97,7 +123,7 @@ func (r reviewController) GetListReviewByEnterpriseID(c echo.Context) error {
 		Reviews    []interface{} `json:"reviews"`
 	}{enterprise, reviews}
 
-	return response.SuccessResponse(c, http.StatusOK, true, "success get list review", resFinal)
+	return response.SuccessListResponse(c, http.StatusOK, true, "success get list review", resFinal, helper.PageMetadata(info, len(reviews)))
 }
 
 // UpdateReviewEnterprise godoc
@@ -108,14 +134,18 @@ func (r reviewController) GetListReviewByEnterpriseID(c echo.Context) error {
 // @Produce json
 // @Router /review/enterprise/{id} [put]
 // @Param id path string true "enterprise id"
-// @Param userid query string true "user id"
 // @param data body ReviewValue true "value review"
 // @Success 200 {object} response.JSONSuccessResult{data=interface{}}
 // @Failure 400 {object} response.JSONBadRequestResult{}
-// @Failure 404 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (r reviewController) UpdateReviewEnterprise(c echo.Context) error {
-	userid := c.QueryParam("userid")
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	userid := claims.UserID()
 	enterpriseid := c.Param("id")
 	var value ReviewValue
 	if err := c.Bind(&value); err != nil {
@@ -129,9 +159,9 @@ func (r reviewController) UpdateReviewEnterprise(c echo.Context) error {
 		return response.FailResponse(c, http.StatusBadRequest, false, "review not current")
 	}
 
-	_, err := r.reviewUsecase.UpdateReview(enterpriseid, userid, value.Review)
+	_, err = r.reviewUsecase.UpdateReview(enterpriseid, userid, helper.GetActor(c), value.Review)
 	if err != nil {
-		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+		return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
 	}
 	review, err := r.reviewUsecase.GetReviewByUserIDAndEnterpriseID(enterpriseid, userid)
 	if err != nil {
@@ -142,30 +172,34 @@ func (r reviewController) UpdateReviewEnterprise(c echo.Context) error {
 
 // DeleteReviewEnterprise godoc
 // @Summary Delete Review
-// @Description delete review enterprise
+// @Description delete the own review of an enterprise, users with review:moderate can delete someone else's review with userid
 // @Tags Review
 // @accept json
 // @Produce json
 // @Router /review/enterprise/{id} [delete]
 // @Param id path string true "enterprise id"
-// @Param userid query string true "user id"
+// @Param userid query string false "user id of the review, defaults to the authenticated user"
 // @Success 200 {object} response.JSONSuccessDeleteResult{}
 // @Failure 400 {object} response.JSONBadRequestResult{}
-// @Failure 404 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (r reviewController) DeleteReviewEnterprise(c echo.Context) error {
-	userid := c.QueryParam("userid")
+	userid, err := reviewUser(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
 	enterpriseid := c.Param("id")
 	isReview, _ := r.reviewUsecase.GetReviewByUserIDAndEnterpriseID(enterpriseid, userid)
 	if isReview.ID == uuid.FromStringOrNil("") {
 		return response.FailResponse(c, http.StatusBadRequest, false, "review not current")
 	}
 
-	err := r.reviewUsecase.DeleteReview(enterpriseid, userid)
+	err = r.reviewUsecase.DeleteReview(enterpriseid, userid, helper.GetActor(c))
 	if err == nil {
 		return response.SuccessDeleteResponse(c, http.StatusOK, true, "success delete review")
 	}
-	return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	return response.FailResponse(c, helper.StatusCode(err, http.StatusBadRequest), false, err.Error())
 }
 
 // GetDetailReviewByID godoc
@@ -63,6 +63,8 @@ var dummyUser = domain.Users{
 	},
 }
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = domain.Enterprises{
 	domain.Enterprise{
 		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
@@ -71,8 +73,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Status:      0,
 		Tags: domain.Tags{
@@ -93,8 +95,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone:      "0012798232",
 		Address:          "bjm",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -139,15 +141,19 @@ var dummyRating = domain.RatingEnterprises{
 	},
 }
 
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+
 func createToken() string {
-	jwtSetToken := helper.NewGoJWT()
+	jwtSetToken := helper.NewGoJWT(keySet, "test", "test")
 	token := jwtSetToken.CreateTokenJWT(&dummyUser[0])
 	return token
 }
 
 func middlewareToken(handlerFunc echo.HandlerFunc, c echo.Context) error {
 	err := middleware.JWTWithConfig(middleware.JWTConfig{
-		SigningKey: []byte("220220"),
+		KeyFunc: keySet.Keyfunc,
+		Claims:  &helper.JWTClaims{},
 	})(handlerFunc)(c)
 	return err
 }
@@ -188,8 +194,8 @@ func TestReviewController_AddReviewEnterprise(t *testing.T) {
 		c.SetParamNames("id")
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
-		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", mock.Anything, mock.Anything).Return(domain.Review{}, nil).Once()
-		mockReviewUsecase.On("AddReview", mock.Anything, mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
+		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", dummyEnterprise[0].ID.String(), dummyUser[0].ID.String()).Return(domain.Review{}, nil).Once()
+		mockReviewUsecase.On("AddReview", dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, reqBody.Review).Return(dummyReview[0], nil).Once()
 		err := middlewareToken(reviewController.AddReviewEnterprise, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
@@ -252,13 +258,29 @@ func TestReviewController_AddReviewEnterprise(t *testing.T) {
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
 		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", mock.Anything, mock.Anything).Return(domain.Review{}, nil).Once()
-		mockReviewUsecase.On("AddReview", mock.Anything, mock.Anything, mock.Anything).Return(domain.Review{}, errors.New("error something")).Once()
+		mockReviewUsecase.On("AddReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(domain.Review{}, errors.New("error something")).Once()
 		err := middlewareToken(reviewController.AddReviewEnterprise, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(400), responseBody["code"])
 		mockReviewUsecase.AssertExpectations(t)
 	})
+	t.Run("userid of another user is ignored", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestReview), echo.POST, "/review/enterprise/"+dummyEnterprise[0].ID.String()+"?userid="+dummyUser[1].ID.String(), true, true)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "/review/enterprise/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
+		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
+		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", dummyEnterprise[0].ID.String(), dummyUser[0].ID.String()).Return(domain.Review{}, nil).Once()
+		mockReviewUsecase.On("AddReview", dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, reqBody.Review).Return(dummyReview[0], nil).Once()
+		err := middlewareToken(reviewController.AddReviewEnterprise, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(201), responseBody["code"])
+		mockReviewUsecase.AssertExpectations(t)
+	})
 }
 
 func TestReviewController_GetListReviewByEnterpriseID(t *testing.T) {
@@ -275,7 +297,7 @@ func TestReviewController_GetListReviewByEnterpriseID(t *testing.T) {
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
 		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[0], nil).Once()
-		mockReviewUsecase.On("GetListReviewsByEnterpriseID", mock.Anything).Return([]interface{}{
+		mockReviewUsecase.On("GetListReviewsByEnterpriseID", mock.Anything, domain.PageRequest{Length: 20}).Return([]interface{}{
 			struct {
 				Review   interface{} `json:"review"`
 				FromUser interface{} `json:"from_user"`
@@ -285,7 +307,7 @@ func TestReviewController_GetListReviewByEnterpriseID(t *testing.T) {
 					ID: dummyUser[0].ID, Email: dummyUser[0].Email, Fullname: dummyUser[0].Fullname, Username: dummyUser[0].Username, CreatedAt: dummyUser[0].CreatedAt, UpdatedAt: dummyUser[0].UpdatedAt,
 				},
 			},
-		}, nil).Once()
+		}, domain.PageInfo{}, nil).Once()
 		err := middlewareToken(reviewController.GetListReviewByEnterpriseID, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
@@ -316,7 +338,7 @@ func TestReviewController_GetListReviewByEnterpriseID(t *testing.T) {
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
 		mockEnterpriseUsecase.On("GetDetailEnterpriseByID", mock.Anything).Return(dummyEnterprise[0], nil).Once()
-		mockReviewUsecase.On("GetListReviewsByEnterpriseID", mock.Anything).Return([]interface{}{}, errors.New("error something")).Once()
+		mockReviewUsecase.On("GetListReviewsByEnterpriseID", mock.Anything, mock.Anything).Return([]interface{}{}, domain.PageInfo{}, errors.New("error something")).Once()
 		err := middlewareToken(reviewController.GetListReviewByEnterpriseID, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
@@ -343,7 +365,7 @@ func TestReviewController_UpdateReviewEnterprise(t *testing.T) {
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
 		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
-		mockReviewUsecase.On("UpdateReview", mock.Anything, mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
+		mockReviewUsecase.On("UpdateReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
 		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
 		err := middlewareToken(reviewController.UpdateReviewEnterprise, c)
 		responseBody := parseResponse(rec)
@@ -407,7 +429,7 @@ func TestReviewController_UpdateReviewEnterprise(t *testing.T) {
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
 		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
-		mockReviewUsecase.On("UpdateReview", mock.Anything, mock.Anything, mock.Anything).Return(dummyReview[0], errors.New("error something")).Once()
+		mockReviewUsecase.On("UpdateReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(dummyReview[0], errors.New("error something")).Once()
 		err := middlewareToken(reviewController.UpdateReviewEnterprise, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
@@ -423,7 +445,7 @@ func TestReviewController_UpdateReviewEnterprise(t *testing.T) {
 		c.SetParamValues(dummyEnterprise[0].ID.String())
 		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
 		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
-		mockReviewUsecase.On("UpdateReview", mock.Anything, mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
+		mockReviewUsecase.On("UpdateReview", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
 		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", mock.Anything, mock.Anything).Return(domain.Review{}, errors.New("error something")).Once()
 		err := middlewareToken(reviewController.UpdateReviewEnterprise, c)
 		responseBody := parseResponse(rec)
@@ -431,6 +453,22 @@ func TestReviewController_UpdateReviewEnterprise(t *testing.T) {
 		assert.Equal(t, float64(400), responseBody["code"])
 		mockReviewUsecase.AssertExpectations(t)
 	})
+	t.Run("userid of another user is ignored", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestReview), echo.PUT, "/review/enterprise/"+dummyEnterprise[0].ID.String()+"?userid="+dummyUser[1].ID.String(), true, true)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "/review/enterprise/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyEnterprise[0].ID.String())
+		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
+		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", dummyEnterprise[0].ID.String(), dummyUser[0].ID.String()).Return(domain.Review{}, nil).Once()
+		err := middlewareToken(reviewController.UpdateReviewEnterprise, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		assert.Equal(t, "review not current", responseBody["message"])
+		mockReviewUsecase.AssertExpectations(t)
+	})
 }
 
 func TestReviewController_DeleteReviewEnterprise(t *testing.T) {
@@ -447,7 +485,7 @@ func TestReviewController_DeleteReviewEnterprise(t *testing.T) {
 		c.SetParamValues(dummyReview[0].EnterpriseID.String())
 		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
 		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
-		mockReviewUsecase.On("DeleteReview", mock.Anything, mock.Anything).Return(nil).Once()
+		mockReviewUsecase.On("DeleteReview", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 		err := middlewareToken(reviewController.DeleteReviewEnterprise, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
@@ -463,7 +501,7 @@ func TestReviewController_DeleteReviewEnterprise(t *testing.T) {
 		c.SetParamValues(dummyReview[0].EnterpriseID.String())
 		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
 		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", mock.Anything, mock.Anything).Return(dummyReview[0], nil).Once()
-		mockReviewUsecase.On("DeleteReview", mock.Anything, mock.Anything).Return(errors.New("error something")).Once()
+		mockReviewUsecase.On("DeleteReview", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error something")).Once()
 		err := middlewareToken(reviewController.DeleteReviewEnterprise, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
@@ -485,6 +523,38 @@ func TestReviewController_DeleteReviewEnterprise(t *testing.T) {
 		assert.Equal(t, float64(400), responseBody["code"])
 		mockReviewUsecase.AssertExpectations(t)
 	})
+	t.Run("success moderator", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/review/enterprise/"+dummyReview[1].EnterpriseID.String()+"?userid="+dummyReview[1].UserID.String(), true, true)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "/review/enterprise/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyReview[1].EnterpriseID.String())
+		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
+		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", dummyReview[1].EnterpriseID.String(), dummyReview[1].UserID.String()).Return(dummyReview[1], nil).Once()
+		mockReviewUsecase.On("DeleteReview", dummyReview[1].EnterpriseID.String(), dummyReview[1].UserID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}).Return(nil).Once()
+		err := middlewareToken(reviewController.DeleteReviewEnterprise, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockReviewUsecase.AssertExpectations(t)
+	})
+	t.Run("error another user", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/review/enterprise/"+dummyReview[1].EnterpriseID.String()+"?userid="+dummyReview[1].UserID.String(), true, true)
+		c := e.NewContext(req, rec)
+		c.SetPath(base_path + "/review/enterprise/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(dummyReview[1].EnterpriseID.String())
+		reviewController := http2.NewReviewController(mockReviewUsecase, mockEnterpriseUsecase, mockAuthUsecase)
+		mockReviewUsecase.On("GetReviewByUserIDAndEnterpriseID", dummyReview[1].EnterpriseID.String(), dummyReview[1].UserID.String()).Return(dummyReview[1], nil).Once()
+		mockReviewUsecase.On("DeleteReview", dummyReview[1].EnterpriseID.String(), dummyReview[1].UserID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}).Return(domain.NewForbiddenError("only the owner or users with review:moderate can delete review")).Once()
+		err := middlewareToken(reviewController.DeleteReviewEnterprise, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(403), responseBody["code"])
+		mockReviewUsecase.AssertExpectations(t)
+	})
 }
 
 func TestReviewController_GetDetailReviewByID(t *testing.T) {
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/pagination"
 	"gorm.io/gorm"
 )
 
@@ -20,8 +21,21 @@ func (r reviewRepository) FindByUserIDAndEnterpriseID(enterpriseid, userid strin
 	return review, err
 }
 
-func (r reviewRepository) FindByEnterpriseID(id string) (reviews domain.Reviews, err error) {
-	err = r.DB.Where("enterprise_id = ?", id).Find(&reviews).Error
+// FindByEnterpriseID pages the reviews of the enterprise from the newest.
+func (r reviewRepository) FindByEnterpriseID(id string, page domain.PageRequest) (reviews domain.Reviews, info domain.PageInfo, err error) {
+	err = pagination.Keyset{Column: "created_at", Desc: true}.Apply(r.DB.Where("enterprise_id = ?", id), page).Find(&reviews).Error
+	if err != nil {
+		return reviews, info, err
+	}
+	reviews, info = pagination.Page(reviews, page, func(review domain.Review) domain.Cursor {
+		return domain.Cursor{Key: review.CreatedAt, ID: review.ID.String()}
+	})
+	err = pagination.Count(r.DB.Model(&domain.Review{}).Where("enterprise_id = ?", id), page, &info)
+	return reviews, info, err
+}
+
+func (r reviewRepository) FindByUserID(userid string) (reviews domain.Reviews, err error) {
+	err = r.DB.Where("user_id = ?", userid).Order("created_at").Find(&reviews).Error
 	return reviews, err
 }
 
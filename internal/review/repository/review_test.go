@@ -80,13 +80,13 @@ func TestReviewRepository_FindByEnterpriseID(t *testing.T) {
 	}
 	db := SetupDBMock(dbMock)
 
-	mock.ExpectQuery("SELECT * FROM `reviews` WHERE enterprise_id = ?").
+	mock.ExpectQuery("SELECT * FROM `reviews` WHERE enterprise_id = ? ORDER BY created_at DESC,id DESC LIMIT 21").
 		WithArgs(dummyReview[0].EnterpriseID).
 		WillReturnRows(sqlMock.NewRows([]string{"id", "review", "enterprise_id", "user_id", "created_at", "updated_at"}).
 			AddRow(dummyReview[0].ID, dummyReview[0].Review, dummyReview[0].EnterpriseID, dummyReview[0].UserID, dummyReview[0].CreatedAt, dummyReview[0].UpdatedAt))
 
 	reviewRepository := repository.NewReviewRepository(db)
-	review, err := reviewRepository.FindByEnterpriseID(dummyReview[0].EnterpriseID.String())
+	review, _, err := reviewRepository.FindByEnterpriseID(dummyReview[0].EnterpriseID.String(), domain.PageRequest{Length: 20})
 	if err != nil {
 		assert.Error(t, err)
 	}
@@ -94,6 +94,24 @@ func TestReviewRepository_FindByEnterpriseID(t *testing.T) {
 	assert.NotNil(t, review)
 }
 
+func TestReviewRepository_FindByUserID(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `reviews` WHERE user_id = ? ORDER BY created_at").
+		WithArgs(dummyReview[0].UserID).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "review", "enterprise_id", "user_id", "created_at", "updated_at"}).
+			AddRow(dummyReview[0].ID, dummyReview[0].Review, dummyReview[0].EnterpriseID, dummyReview[0].UserID, dummyReview[0].CreatedAt, dummyReview[0].UpdatedAt))
+
+	reviewRepository := repository.NewReviewRepository(db)
+	reviews, err := reviewRepository.FindByUserID(dummyReview[0].UserID.String())
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 1)
+}
+
 func TestReviewRepository_FindByUserIDAndEnterpriseID(t *testing.T) {
 	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
 	if err != nil {
@@ -3,6 +3,8 @@ package usecase
 import (
 	"errors"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/policy"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
 	"github.com/nrmadi02/mini-project/web/response"
 	uuid "github.com/satori/go.uuid"
 )
@@ -12,18 +14,26 @@ type reviewUsecase struct {
 	userRepository       domain.UserRepository
 	reviewRepository     domain.ReviewRepository
 	authUsecase          domain.AuthUsecase
+	audit                domain.AuditUsecase
+	policy               policy.Policy
 }
 
-func NewReviewUsecase(er domain.EnterpriseRepository, ur domain.UserRepository, rr domain.ReviewRepository, au domain.AuthUsecase) domain.ReviewUsecase {
+func NewReviewUsecase(er domain.EnterpriseRepository, ur domain.UserRepository, rr domain.ReviewRepository, au domain.AuthUsecase, audit domain.AuditUsecase) domain.ReviewUsecase {
 	return reviewUsecase{
 		enterpriseRepository: er,
 		userRepository:       ur,
 		reviewRepository:     rr,
 		authUsecase:          au,
+		audit:                audit,
+		policy:               policy.NewPolicy(ur),
 	}
 }
 
-func (r reviewUsecase) AddReview(enterpriseid, userid string, value string) (domain.Review, error) {
+// AddReview posts a review as userid, users can only review as themselves.
+func (r reviewUsecase) AddReview(enterpriseid, userid string, actor domain.Actor, value string) (domain.Review, error) {
+	if err := r.policy.Owner(actor, uuid.FromStringOrNil(userid), "add a review as this user"); err != nil {
+		return domain.Review{}, err
+	}
 	enterprise, _ := r.enterpriseRepository.FindByID(enterpriseid)
 	if enterprise.ID == uuid.FromStringOrNil("") {
 		return domain.Review{}, errors.New("enterprise not found")
@@ -48,11 +58,15 @@ func (r reviewUsecase) AddReview(enterpriseid, userid string, value string) (dom
 	return add, nil
 }
 
-func (r reviewUsecase) UpdateReview(enterpriseid, userid string, value string) (domain.Review, error) {
+// UpdateReview changes the text of a review, only its own user may.
+func (r reviewUsecase) UpdateReview(enterpriseid, userid string, actor domain.Actor, value string) (domain.Review, error) {
 	review, _ := r.reviewRepository.FindByUserIDAndEnterpriseID(enterpriseid, userid)
 	if review.ID == uuid.FromStringOrNil("") {
 		return domain.Review{}, errors.New("request enterprise and user")
 	}
+	if err := r.policy.Owner(actor, review.UserID, "update review"); err != nil {
+		return domain.Review{}, err
+	}
 
 	update, err := r.reviewRepository.Update(review.EnterpriseID.String(), review.UserID.String(), value)
 	if err != nil {
@@ -61,20 +75,32 @@ func (r reviewUsecase) UpdateReview(enterpriseid, userid string, value string) (
 	return update, nil
 }
 
-func (r reviewUsecase) DeleteReview(enterpriseid, userid string) error {
+// DeleteReview removes a review, its own user and users with review:moderate may.
+func (r reviewUsecase) DeleteReview(enterpriseid, userid string, actor domain.Actor) error {
 	review, _ := r.reviewRepository.FindByUserIDAndEnterpriseID(enterpriseid, userid)
 	if review.ID == uuid.FromStringOrNil("") {
 		return errors.New("request enterprise and user")
 	}
+	if err := r.policy.OwnerOr(actor, review.UserID, role.ReviewModerate, "delete review"); err != nil {
+		return err
+	}
 
 	err := r.reviewRepository.Delete(review)
-	return err
+	if err != nil {
+		return err
+	}
+	r.audit.Record(actor, domain.AuditReviewDelete, domain.AuditTargetReview, review.ID.String(), map[string]interface{}{
+		"enterprise_id": review.EnterpriseID.String(),
+		"user_id":       review.UserID.String(),
+		"review":        review.Review,
+	}, nil)
+	return nil
 }
 
-func (r reviewUsecase) GetListReviewsByEnterpriseID(id string) ([]interface{}, error) {
-	reviews, err := r.reviewRepository.FindByEnterpriseID(id)
+func (r reviewUsecase) GetListReviewsByEnterpriseID(id string, page domain.PageRequest) ([]interface{}, domain.PageInfo, error) {
+	reviews, info, err := r.reviewRepository.FindByEnterpriseID(id, page)
 	if err != nil {
-		return nil, err
+		return nil, domain.PageInfo{}, err
 	}
 
 	var resReview []interface{}
@@ -92,7 +118,7 @@ func (r reviewUsecase) GetListReviewsByEnterpriseID(id string) ([]interface{}, e
 		})
 	}
 
-	return resReview, nil
+	return resReview, info, nil
 }
 
 func (r reviewUsecase) GetReviewByUserIDAndEnterpriseID(enterpriseid, userid string) (domain.Review, error) {
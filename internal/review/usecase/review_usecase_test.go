@@ -53,6 +53,8 @@ var dummyUser = domain.Users{
 	},
 }
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = domain.Enterprises{
 	domain.Enterprise{
 		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
@@ -61,8 +63,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Status:      0,
 		Tags: domain.Tags{
@@ -83,8 +85,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone:      "0012798232",
 		Address:          "bjm",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -114,41 +116,53 @@ var dummyReview = domain.Reviews{
 	},
 }
 
+// newAuditUsecase accepts every audit entry, tests of an entry assert it on their own mock.
+func newAuditUsecase() *mocks.AuditUsecase {
+	auditUsecase := new(mocks.AuditUsecase)
+	auditUsecase.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return auditUsecase
+}
+
 func TestReviewUsecase_AddReview(t *testing.T) {
 	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
 	mockReviewRepository := new(mocks.ReviewRepository)
 	mockUserRepository := new(mocks.UserRepository)
 	mockAuthUsecase := new(mocks.AuthUsecase)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockReviewRepository.On("Add", mock.AnythingOfType("domain.Review")).Return(dummyReview[0], nil).Once()
-		review, err := uc.AddReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), "baguss")
+		review, err := uc.AddReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "baguss")
 		assert.NoError(t, err)
 		assert.NotNil(t, review)
 	})
 	t.Run("enterprise not found", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Enterprise{}, errors.New("error something")).Once()
-		_, err := uc.AddReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), "baguss")
+		_, err := uc.AddReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "baguss")
 		assert.Error(t, err)
 	})
 	t.Run("user not found", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(domain.User{}, errors.New("error something")).Once()
-		_, err := uc.AddReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), "baguss")
+		_, err := uc.AddReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "baguss")
 		assert.Error(t, err)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockEnterpriseRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyEnterprise[0], nil).Once()
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockReviewRepository.On("Add", mock.AnythingOfType("domain.Review")).Return(domain.Review{}, errors.New("error something")).Once()
-		_, err := uc.AddReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), "baguss")
+		_, err := uc.AddReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "baguss")
 		assert.Error(t, err)
 	})
+	t.Run("as another user", func(t *testing.T) {
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
+		_, err := uc.AddReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[1].ID.String()}, "baguss")
+		assert.IsType(t, &domain.ForbiddenError{}, err)
+	})
 }
 
 func TestReviewUsecase_UpdateReview(t *testing.T) {
@@ -157,28 +171,34 @@ func TestReviewUsecase_UpdateReview(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 	mockAuthUsecase := new(mocks.AuthUsecase)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyReview[0], nil).Once()
 		mockReviewRepository.On("Update", mock.AnythingOfType("string"),
 			mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyReview[0], nil).Once()
-		review, err := uc.UpdateReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), "baguss")
+		review, err := uc.UpdateReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "baguss")
 		assert.NoError(t, err)
 		assert.NotNil(t, review)
 	})
 	t.Run("request user and enterprise not found", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(domain.Review{}, errors.New("error something")).Once()
-		_, err := uc.UpdateReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), "baguss")
+		_, err := uc.UpdateReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "baguss")
 		assert.Error(t, err)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyReview[0], nil).Once()
 		mockReviewRepository.On("Update", mock.AnythingOfType("string"),
 			mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(domain.Review{}, errors.New("error something")).Once()
-		_, err := uc.UpdateReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), "baguss")
+		_, err := uc.UpdateReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "baguss")
 		assert.Error(t, err)
 	})
+	t.Run("not the owner", func(t *testing.T) {
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
+		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyReview[0], nil).Once()
+		_, err := uc.UpdateReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[1].ID.String()}, "baguss")
+		assert.IsType(t, &domain.ForbiddenError{}, err)
+	})
 }
 
 func TestReviewUsecase_DeleteReview(t *testing.T) {
@@ -187,18 +207,34 @@ func TestReviewUsecase_DeleteReview(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 	mockAuthUsecase := new(mocks.AuthUsecase)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyReview[0], nil).Once()
 		mockReviewRepository.On("Delete", mock.AnythingOfType("domain.Review")).Return(nil).Once()
-		err := uc.DeleteReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
+		err := uc.DeleteReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()})
 		assert.NoError(t, err)
 	})
 	t.Run("request user and enterprise not found", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(domain.Review{}, errors.New("error something")).Once()
-		err := uc.DeleteReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
+		err := uc.DeleteReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()})
 		assert.Error(t, err)
 	})
+	t.Run("moderator", func(t *testing.T) {
+		moderator := domain.User{ID: uuid.NewV4(), Roles: []domain.Role{{Name: "ROLE_MODERATOR", ID: 4, Permissions: []domain.Permission{{ID: 4, Name: "review:moderate"}}}}}
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
+		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyReview[0], nil).Once()
+		mockUserRepository.On("FindUserById", moderator.ID.String()).Return(moderator, nil).Once()
+		mockReviewRepository.On("Delete", dummyReview[0]).Return(nil).Once()
+		err := uc.DeleteReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: moderator.ID.String()})
+		assert.NoError(t, err)
+	})
+	t.Run("not the owner", func(t *testing.T) {
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
+		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyReview[0], nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		err := uc.DeleteReview(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[1].ID.String()})
+		assert.IsType(t, &domain.ForbiddenError{}, err)
+	})
 }
 
 func TestReviewUsecase_GetListReviewsByEnterpriseID(t *testing.T) {
@@ -207,17 +243,17 @@ func TestReviewUsecase_GetListReviewsByEnterpriseID(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 	mockAuthUsecase := new(mocks.AuthUsecase)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
-		mockReviewRepository.On("FindByEnterpriseID", mock.AnythingOfType("string")).Return(domain.Reviews{dummyReview[0]}, nil).Once()
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
+		mockReviewRepository.On("FindByEnterpriseID", mock.AnythingOfType("string"), domain.PageRequest{Length: 20}).Return(domain.Reviews{dummyReview[0]}, domain.PageInfo{}, nil).Once()
 		mockAuthUsecase.On("GetUserDetails", mock.Anything).Return(dummyUser[0], domain.Favorite{}, domain.Enterprises{}, nil).Once()
-		reviews, err := uc.GetListReviewsByEnterpriseID(dummyEnterprise[0].ID.String())
+		reviews, _, err := uc.GetListReviewsByEnterpriseID(dummyEnterprise[0].ID.String(), domain.PageRequest{Length: 20})
 		assert.NoError(t, err)
 		assert.NotNil(t, reviews)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
-		mockReviewRepository.On("FindByEnterpriseID", mock.AnythingOfType("string")).Return(domain.Reviews{}, errors.New("error something")).Once()
-		_, err := uc.GetListReviewsByEnterpriseID(dummyEnterprise[0].ID.String())
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
+		mockReviewRepository.On("FindByEnterpriseID", mock.AnythingOfType("string"), domain.PageRequest{Length: 20}).Return(domain.Reviews{}, domain.PageInfo{}, errors.New("error something")).Once()
+		_, _, err := uc.GetListReviewsByEnterpriseID(dummyEnterprise[0].ID.String(), domain.PageRequest{Length: 20})
 		assert.Error(t, err)
 	})
 }
@@ -228,14 +264,14 @@ func TestReviewUsecase_GetDetailReviewByID(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 	mockAuthUsecase := new(mocks.AuthUsecase)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockReviewRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyReview[0], nil).Once()
 		review, err := uc.GetDetailReviewByID(dummyReview[0].ID.String())
 		assert.NoError(t, err)
 		assert.NotNil(t, review)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockReviewRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Review{}, errors.New("error something")).Once()
 		_, err := uc.GetDetailReviewByID(dummyReview[0].ID.String())
 		assert.Error(t, err)
@@ -248,14 +284,14 @@ func TestReviewUsecase_GetReviewByUserIDAndEnterpriseID(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 	mockAuthUsecase := new(mocks.AuthUsecase)
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(dummyReview[0], nil).Once()
 		review, err := uc.GetReviewByUserIDAndEnterpriseID(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
 		assert.NoError(t, err)
 		assert.NotNil(t, review)
 	})
 	t.Run("failed", func(t *testing.T) {
-		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase)
+		uc := usecase.NewReviewUsecase(mockEnterpriseRepository, mockUserRepository, mockReviewRepository, mockAuthUsecase, newAuditUsecase())
 		mockReviewRepository.On("FindByUserIDAndEnterpriseID", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(domain.Review{}, errors.New("error something")).Once()
 		_, err := uc.GetReviewByUserIDAndEnterpriseID(dummyEnterprise[0].ID.String(), dummyUser[0].ID.String())
 		assert.Error(t, err)
@@ -0,0 +1,31 @@
+package role
+
+// Permission is granted to users through their roles, names are resource:action.
+type Permission struct {
+	slug string
+}
+
+func (p Permission) String() string {
+	return p.slug
+}
+
+var (
+	EnterpriseCreate  = Permission{"enterprise:create"}
+	EnterprisePublish = Permission{"enterprise:publish"}
+	EnterpriseManage  = Permission{"enterprise:manage"}
+	ReviewModerate    = Permission{"review:moderate"}
+	TagManage         = Permission{"tag:manage"}
+	UserManage        = Permission{"user:manage"}
+	AuditRead         = Permission{"audit:read"}
+)
+
+// Permissions lists every permission known to the application.
+var Permissions = []Permission{
+	EnterpriseCreate,
+	EnterprisePublish,
+	EnterpriseManage,
+	ReviewModerate,
+	TagManage,
+	UserManage,
+	AuditRead,
+}
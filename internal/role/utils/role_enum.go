@@ -11,6 +11,8 @@ func (r Role) String() string {
 var prefix = "ROLE_"
 
 var (
-	Admin  = Role{prefix + "ADMIN"}
-	Client = Role{prefix + "CLIENT"}
+	Admin     = Role{prefix + "ADMIN"}
+	Client    = Role{prefix + "CLIENT"}
+	Owner     = Role{prefix + "OWNER"}
+	Moderator = Role{prefix + "MODERATOR"}
 )
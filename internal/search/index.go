@@ -0,0 +1,377 @@
+package search
+
+import (
+	"encoding/gob"
+	"errors"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nrmadi02/mini-project/domain"
+	log "github.com/sirupsen/logrus"
+)
+
+// Field boosts: a word in the name says more about an enterprise than one in its description.
+const (
+	nameBoost = 3
+	tagBoost  = 2
+	textBoost = 1
+)
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+type document struct {
+	// Terms holds the boosted frequency of the words and stems of the enterprise.
+	Terms  map[string]float64
+	Length float64
+}
+
+// Index is an embedded inverted index of the enterprises ranked with BM25. Query words also
+// match indexed words they are a prefix of or one or two typos away from, at a lower score.
+// Typo matching scans the vocabulary, which is fine for the few thousand enterprises of a region.
+type Index struct {
+	mu       sync.RWMutex
+	path     string
+	docs     map[string]document
+	postings map[string]map[string]float64
+	length   float64
+	// dirty marks changes that are not in the file yet, saving that a background save runs.
+	dirty  bool
+	saving bool
+	// writeMu lets one save at a time write the file.
+	writeMu sync.Mutex
+}
+
+// NewIndex returns an index kept in memory only.
+func NewIndex() *Index {
+	return &Index{docs: map[string]document{}, postings: map[string]map[string]float64{}}
+}
+
+// OpenIndex loads the index saved at path, or starts an empty one when there is no file yet.
+// Changes are written back to path in the background.
+func OpenIndex(path string) (*Index, error) {
+	index := NewIndex()
+	index.path = path
+
+	fp, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+
+	var docs map[string]document
+	if err := gob.NewDecoder(fp).Decode(&docs); err != nil {
+		return nil, errors.New("read search index " + path + ": " + err.Error())
+	}
+	for id, doc := range docs {
+		index.add(id, doc)
+	}
+	return index, nil
+}
+
+// Len returns the number of indexed enterprises.
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.docs)
+}
+
+func (i *Index) Index(enterprise domain.Enterprise) error {
+	doc := newDocument(enterprise)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	id := enterprise.ID.String()
+	i.remove(id)
+	i.add(id, doc)
+	i.changed()
+	return nil
+}
+
+// Reindex drops every document and indexes enterprises, the file is written once at the end.
+// Enterprises deleted while the index was stale are gone afterwards.
+func (i *Index) Reindex(enterprises domain.Enterprises) error {
+	docs := make(map[string]document, len(enterprises))
+	for _, enterprise := range enterprises {
+		docs[enterprise.ID.String()] = newDocument(enterprise)
+	}
+
+	i.mu.Lock()
+	i.docs = map[string]document{}
+	i.postings = map[string]map[string]float64{}
+	i.length = 0
+	for id, doc := range docs {
+		i.add(id, doc)
+	}
+	i.dirty = true
+	i.mu.Unlock()
+	return i.Flush()
+}
+
+func newDocument(enterprise domain.Enterprise) document {
+	doc := document{Terms: map[string]float64{}}
+	addText := func(text string, boost float64) {
+		for _, token := range Analyze(text) {
+			doc.Terms[token.Stem] += boost
+			if token.Word != token.Stem {
+				doc.Terms[token.Word] += boost
+			}
+			doc.Length += boost
+		}
+	}
+	addText(enterprise.Name, nameBoost)
+	for _, tag := range enterprise.Tags {
+		addText(tag.Name, tagBoost)
+	}
+	addText(enterprise.Address, textBoost)
+	addText(enterprise.Description, textBoost)
+	return doc
+}
+
+func (i *Index) Remove(id string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.docs[id]; !ok {
+		return nil
+	}
+	i.remove(id)
+	i.changed()
+	return nil
+}
+
+func (i *Index) add(id string, doc document) {
+	i.docs[id] = doc
+	i.length += doc.Length
+	for term, frequency := range doc.Terms {
+		if i.postings[term] == nil {
+			i.postings[term] = map[string]float64{}
+		}
+		i.postings[term][id] = frequency
+	}
+}
+
+func (i *Index) remove(id string) {
+	doc, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	delete(i.docs, id)
+	i.length -= doc.Length
+	for term := range doc.Terms {
+		delete(i.postings[term], id)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+		}
+	}
+}
+
+// changed starts a background save unless one is running, that one saves the change as
+// well. A change costs the writer no file I/O and searches go on while the file is written.
+// The caller holds mu.
+func (i *Index) changed() {
+	if i.path == "" {
+		return
+	}
+	i.dirty = true
+	if !i.saving {
+		i.saving = true
+		go i.saveInBackground()
+	}
+}
+
+// saveInBackground saves until no change is left. A failed save is tried again with the next change.
+func (i *Index) saveInBackground() {
+	for {
+		i.mu.Lock()
+		if !i.dirty {
+			i.saving = false
+			i.mu.Unlock()
+			return
+		}
+		i.mu.Unlock()
+
+		if err := i.Flush(); err != nil {
+			log.Warn("save search index: " + err.Error())
+			i.mu.Lock()
+			i.saving = false
+			i.mu.Unlock()
+			return
+		}
+	}
+}
+
+// Flush writes the changes not saved yet to the file. The documents are copied under the
+// lock and encoded outside of it, the file is replaced through a rename so a crash never
+// leaves half an index behind.
+func (i *Index) Flush() error {
+	i.writeMu.Lock()
+	defer i.writeMu.Unlock()
+
+	i.mu.Lock()
+	if i.path == "" || !i.dirty {
+		i.mu.Unlock()
+		return nil
+	}
+	// documents are never changed once added, copying the map is enough.
+	docs := make(map[string]document, len(i.docs))
+	for id, doc := range i.docs {
+		docs[id] = doc
+	}
+	i.dirty = false
+	i.mu.Unlock()
+
+	if err := writeDocs(i.path, docs); err != nil {
+		i.mu.Lock()
+		i.dirty = true
+		i.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func writeDocs(path string, docs map[string]document) error {
+	tmp := path + ".tmp"
+	fp, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(fp).Encode(docs); err != nil {
+		fp.Close()
+		return err
+	}
+	if err := fp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (i *Index) Search(query string, limit int) ([]domain.SearchHit, error) {
+	tokens := Analyze(query)
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if len(tokens) == 0 || len(i.docs) == 0 {
+		return nil, nil
+	}
+
+	total := float64(len(i.docs))
+	avgLength := i.length / total
+	scores := map[string]float64{}
+	for _, token := range tokens {
+		// a query word counts once per enterprise, through its best matching term. Its rarity is
+		// that of all the enterprises it matches, a typo must not score higher for being rare.
+		best := map[string]float64{}
+		for term, postings := range i.postings {
+			weight := matchWeight(token, term)
+			if weight == 0 {
+				continue
+			}
+			for id, frequency := range postings {
+				norm := 1 - b + b*i.docs[id].Length/avgLength
+				score := weight * frequency * (k1 + 1) / (frequency + k1*norm)
+				if score > best[id] {
+					best[id] = score
+				}
+			}
+		}
+		n := float64(len(best))
+		idf := math.Log(1 + (total-n+0.5)/(n+0.5))
+		for id, score := range best {
+			scores[id] += idf * score
+		}
+	}
+
+	hits := make([]domain.SearchHit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, domain.SearchHit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].ID < hits[b].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits, nil
+}
+
+// matchWeight scores how well an indexed term matches a query token: 1 for the word or its
+// stem, less for a prefix or a typo and 0 for no match.
+func matchWeight(token Token, term string) float64 {
+	if term == token.Stem || term == token.Word {
+		return 1
+	}
+	weight := 0.0
+	for _, form := range []string{token.Word, token.Stem} {
+		switch {
+		case len(form) >= 3 && strings.HasPrefix(term, form):
+			weight = math.Max(weight, 0.7)
+		case len(form) >= 4 && editDistance(form, term, 1) == 1:
+			weight = math.Max(weight, 0.5)
+		case len(form) >= 7 && editDistance(form, term, 2) == 2:
+			weight = math.Max(weight, 0.3)
+		}
+	}
+	return weight
+}
+
+// editDistance counts the insertions, deletions, substitutions and swaps of neighbouring letters
+// between a and b, giving up with max+1 once it is known to be more than max.
+func editDistance(a, b string, max int) int {
+	if a == b {
+		return 0
+	}
+	if diff := len(a) - len(b); diff > max || -diff > max {
+		return max + 1
+	}
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = minInt(curr[j], prev2[j-2]+1)
+			}
+			rowMin = minInt(rowMin, curr[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	if prev[len(b)] > max {
+		return max + 1
+	}
+	return prev[len(b)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+	return min
+}
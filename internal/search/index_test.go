@@ -0,0 +1,169 @@
+package search_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/search"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	warung = domain.Enterprise{
+		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
+		Name:        "Warung Makan Bu Sri",
+		Address:     "Jl. Ahmad Yani Km 5, Banjarmasin",
+		Description: "Menjual nasi kuning dan soto banjar setiap pagi",
+		Tags:        []domain.Tag{{Name: "Kuliner"}},
+	}
+	kerajinan = domain.Enterprise{
+		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b"),
+		Name:        "Sasirangan Kerajinan Tangan",
+		Address:     "Jl. Sultan Adam, Banjarmasin",
+		Description: "Pembuatan kain sasirangan dan tas anyaman",
+		Tags:        []domain.Tag{{Name: "Fashion"}, {Name: "Kerajinan"}},
+	}
+	bengkel = domain.Enterprise{
+		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89c"),
+		Name:        "Bengkel Motor Jaya",
+		Address:     "Jl. Panglima Batur, Banjarbaru",
+		Description: "Servis motor dan jual makanan ringan di ruang tunggu",
+	}
+)
+
+func newIndex(t *testing.T) *search.Index {
+	index := search.NewIndex()
+	for _, enterprise := range []domain.Enterprise{warung, kerajinan, bengkel} {
+		assert.NoError(t, index.Index(enterprise))
+	}
+	return index
+}
+
+func hitIDs(hits []domain.SearchHit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := newIndex(t)
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"name ranks above description", "makan", []string{warung.ID.String(), bengkel.ID.String()}},
+		{"stemmed form", "pembuat kain", []string{kerajinan.ID.String()}},
+		{"tag name", "kuliner", []string{warung.ID.String()}},
+		{"address", "banjarbaru", []string{bengkel.ID.String()}},
+		{"prefix", "sasir", []string{kerajinan.ID.String()}},
+		{"typo", "bengkle", []string{bengkel.ID.String()}},
+		{"two typos in a long word", "kerjainn", []string{kerajinan.ID.String()}},
+		{"every word counts", "soto banjarmasin", []string{warung.ID.String(), kerajinan.ID.String()}},
+		{"stop words only", "yang dan di", []string{}},
+		{"no match", "laundry", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := index.Search(tt.query, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, hitIDs(hits))
+		})
+	}
+
+	hits, err := index.Search("banjarmasin", 1)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+}
+
+func TestIndex_Update(t *testing.T) {
+	index := newIndex(t)
+
+	renamed := bengkel
+	renamed.Name = "Cuci Motor Jaya"
+	assert.NoError(t, index.Index(renamed))
+	hits, err := index.Search("bengkel", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	assert.Equal(t, 3, index.Len())
+
+	assert.NoError(t, index.Remove(warung.ID.String()))
+	assert.NoError(t, index.Remove(warung.ID.String()))
+	hits, err = index.Search("kuliner", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	assert.Equal(t, 2, index.Len())
+}
+
+func TestIndex_Reindex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.idx")
+	index, err := search.OpenIndex(path)
+	assert.NoError(t, err)
+	assert.NoError(t, index.Index(warung))
+	assert.NoError(t, index.Index(bengkel))
+
+	// bengkel was deleted while the index was stale
+	renamed := warung
+	renamed.Name = "Depot Soto Bu Sri"
+	assert.NoError(t, index.Reindex(domain.Enterprises{renamed, kerajinan}))
+	assert.Equal(t, 2, index.Len())
+	hits, err := index.Search("motor", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	hits, err = index.Search("warung", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	hits, err = index.Search("depot", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{warung.ID.String()}, hitIDs(hits))
+
+	reopened, err := search.OpenIndex(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reopened.Len())
+	hits, err = reopened.Search("sasirangan", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{kerajinan.ID.String()}, hitIDs(hits))
+}
+
+func TestIndex_SavesInBackground(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.idx")
+	index, err := search.OpenIndex(path)
+	assert.NoError(t, err)
+	assert.NoError(t, index.Index(warung))
+	assert.NoError(t, index.Index(kerajinan))
+
+	assert.Eventually(t, func() bool {
+		reopened, err := search.OpenIndex(path)
+		return err == nil && reopened.Len() == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, index.Flush())
+}
+
+func TestOpenIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.idx")
+
+	index, err := search.OpenIndex(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, index.Len())
+	assert.NoError(t, index.Index(warung))
+	assert.NoError(t, index.Index(kerajinan))
+	assert.NoError(t, index.Remove(kerajinan.ID.String()))
+	assert.NoError(t, index.Flush())
+
+	reopened, err := search.OpenIndex(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reopened.Len())
+	hits, err := reopened.Search("soto", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{warung.ID.String()}, hitIDs(hits))
+
+	assert.NoError(t, os.WriteFile(path, []byte("not an index"), 0600))
+	_, err = search.OpenIndex(path)
+	assert.Error(t, err)
+}
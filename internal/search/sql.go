@@ -0,0 +1,62 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/nrmadi02/mini-project/domain"
+	"gorm.io/gorm"
+)
+
+type sqlSearcher struct {
+	DB *gorm.DB
+}
+
+// NewSQLSearcher searches the enterprises table with LIKE, weighting the fields like Index does.
+// It needs no syncing but has no typo tolerance and reads every row on each search.
+func NewSQLSearcher(db *gorm.DB) domain.Searcher {
+	return sqlSearcher{
+		DB: db,
+	}
+}
+
+func (s sqlSearcher) Index(enterprise domain.Enterprise) error {
+	return nil
+}
+
+func (s sqlSearcher) Remove(id string) error {
+	return nil
+}
+
+func (s sqlSearcher) Reindex(enterprises domain.Enterprises) error {
+	return nil
+}
+
+const tagLike = "EXISTS (SELECT 1 FROM enterprise_tags JOIN tags ON tags.id = enterprise_tags.tag_id " +
+	"WHERE enterprise_tags.enterprise_id = enterprises.id AND tags.name LIKE ?)"
+
+func (s sqlSearcher) Search(query string, limit int) (hits []domain.SearchHit, err error) {
+	tokens := Analyze(query)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	var terms []string
+	var args []interface{}
+	for _, token := range tokens {
+		// the stem also finds the other forms of the word, unless stemming changed its start.
+		word := token.Stem
+		if !strings.Contains(token.Word, word) {
+			word = token.Word
+		}
+		like := "%" + word + "%"
+		terms = append(terms, "CASE WHEN name LIKE ? THEN 3 ELSE 0 END",
+			"CASE WHEN "+tagLike+" THEN 2 ELSE 0 END",
+			"CASE WHEN address LIKE ? THEN 1 ELSE 0 END",
+			"CASE WHEN description LIKE ? THEN 1 ELSE 0 END")
+		args = append(args, like, like, like, like)
+	}
+
+	scored := s.DB.Model(&domain.Enterprise{}).Select("id, "+strings.Join(terms, " + ")+" AS score", args...)
+	err = s.DB.Table("(?) AS scored", scored).Where("score > 0").Order("score DESC, id").Limit(limit).Scan(&hits).Error
+	return hits, err
+}
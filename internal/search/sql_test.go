@@ -0,0 +1,49 @@
+package search_test
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	sqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/search"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestSQLSearcher_Search(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: dbMock, SkipInitializeWithVersion: true}), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	score := "CASE WHEN name LIKE ? THEN 3 ELSE 0 END + " +
+		"CASE WHEN EXISTS (SELECT 1 FROM enterprise_tags JOIN tags ON tags.id = enterprise_tags.tag_id " +
+		"WHERE enterprise_tags.enterprise_id = enterprises.id AND tags.name LIKE ?) THEN 2 ELSE 0 END + " +
+		"CASE WHEN address LIKE ? THEN 1 ELSE 0 END + CASE WHEN description LIKE ? THEN 1 ELSE 0 END"
+	// makanan is looked up by its stem, menyapu by the word as its stem sapu is not part of it.
+	args := []driver.Value{"%makan%", "%makan%", "%makan%", "%makan%", "%menyapu%", "%menyapu%", "%menyapu%", "%menyapu%"}
+	mock.ExpectQuery("SELECT * FROM (SELECT id, " + score + " + " + score + " AS score FROM `enterprises`) AS scored " +
+		"WHERE score > 0 ORDER BY score DESC, id LIMIT 10").
+		WithArgs(args...).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "score"}).
+			AddRow("35d6a9a1-aa5e-41f1-9991-08878dfdf89a", 4).
+			AddRow("35d6a9a1-aa5e-41f1-9991-08878dfdf89b", 1))
+
+	hits, err := search.NewSQLSearcher(db).Search("makanan yang menyapu", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.SearchHit{
+		{ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", Score: 4},
+		{ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89b", Score: 1},
+	}, hits)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	hits, err = search.NewSQLSearcher(db).Search("yang dan di", 10)
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+}
@@ -0,0 +1,128 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are Indonesian function words that say nothing about an enterprise.
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`ada adalah agar akan aku anda antara apa atau bagi bahwa
+		banyak baru belum bisa dalam dan dari dengan di dia hanya harus ia ini itu jadi jika juga
+		kalau kami kamu karena ke kita lagi lain lebih maka masih mereka namun oleh pada para
+		per pun saat saja sampai sangat saya se sebagai sedang sejak seperti serta sudah supaya
+		tanpa tapi telah tentang tersebut tetapi tidak untuk yaitu yang`) {
+		stopWords[word] = true
+	}
+}
+
+// minStem keeps affix removal from cutting words down to meaningless stubs. The -kan and -an
+// suffixes need a longer rest, makan is a root and not mak-an.
+const (
+	minStem       = 3
+	minSuffixStem = 4
+)
+
+// Token is a word of a text with its stem, the word is kept so prefixes of it still match
+// when stemming changed its start.
+type Token struct {
+	Word string
+	Stem string
+}
+
+// Analyze splits text into lowercase tokens without stop words, the same way for documents
+// and queries.
+func Analyze(text string) []Token {
+	var tokens []Token
+	for _, word := range words(text) {
+		if stopWords[word] {
+			continue
+		}
+		tokens = append(tokens, Token{Word: word, Stem: Stem(word)})
+	}
+	return tokens
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Stem removes Indonesian particles, possessives, up to two prefixes and a suffix with the
+// rules of Nazief and Adriani, without the dictionary lookups: both sides of a search are
+// stemmed alike, so an imperfect stem still matches.
+func Stem(word string) string {
+	if len(word) <= minStem || !isWord(word) {
+		return word
+	}
+	word = trimSuffix(word, minStem, "lah", "kah", "tah", "pun")
+	word = trimSuffix(word, minStem, "nya", "ku", "mu")
+	for i := 0; i < 2; i++ {
+		stemmed := trimPrefix(word)
+		if stemmed == word {
+			break
+		}
+		word = stemmed
+	}
+	return trimSuffix(word, minSuffixStem, "kan", "an")
+}
+
+func isWord(word string) bool {
+	for _, r := range word {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func trimSuffix(word string, min int, suffixes ...string) string {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= min {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aiueo", b) >= 0
+}
+
+// trimPrefix removes one prefix, putting back the first letter that me- and pe- melt
+// (menulis to tulis, memukul to pukul, menyapu to sapu).
+func trimPrefix(word string) string {
+	stem := func(rest string) string {
+		if len(rest) < minStem {
+			return word
+		}
+		return rest
+	}
+	switch {
+	case strings.HasPrefix(word, "di"), strings.HasPrefix(word, "ke"), strings.HasPrefix(word, "se"):
+		return stem(word[2:])
+	case strings.HasPrefix(word, "ber"), strings.HasPrefix(word, "ter"), strings.HasPrefix(word, "per"):
+		return stem(word[3:])
+	case strings.HasPrefix(word, "be"), strings.HasPrefix(word, "te"):
+		return stem(word[2:])
+	case strings.HasPrefix(word, "me"), strings.HasPrefix(word, "pe"):
+		rest := word[2:]
+		switch {
+		case strings.HasPrefix(rest, "ng"):
+			return stem(rest[2:])
+		case strings.HasPrefix(rest, "ny") && len(rest) > 2 && isVowel(rest[2]):
+			return stem("s" + rest[2:])
+		case strings.HasPrefix(rest, "m") && len(rest) > 1 && isVowel(rest[1]):
+			return stem("p" + rest[1:])
+		case strings.HasPrefix(rest, "n") && len(rest) > 1 && isVowel(rest[1]):
+			return stem("t" + rest[1:])
+		case strings.HasPrefix(rest, "m"), strings.HasPrefix(rest, "n"):
+			return stem(rest[1:])
+		}
+		return stem(rest)
+	}
+	return word
+}
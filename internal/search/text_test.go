@@ -0,0 +1,50 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/nrmadi02/mini-project/internal/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStem(t *testing.T) {
+	tests := map[string]string{
+		"makanan":     "makan",
+		"minuman":     "minum",
+		"menulis":     "tulis",
+		"memukul":     "pukul",
+		"menyapu":     "sapu",
+		"mengambil":   "ambil",
+		"pembuatan":   "buat",
+		"dijual":      "jual",
+		"bajunya":     "baju",
+		"berjualan":   "jual",
+		"kerajinan":   "rajin",
+		"diperbaiki":  "baiki",
+		"makan":       "makan",
+		"dimakan":     "makan",
+		"makanannya":  "makan",
+		"ikan":        "ikan",
+		"toko":        "toko",
+		"kue":         "kue",
+		"bakso99":     "bakso99",
+		"pelaminan":   "lamin",
+		"perdagangan": "dagang",
+	}
+	for word, stem := range tests {
+		assert.Equal(t, stem, search.Stem(word), word)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	tokens := search.Analyze("Warung Makan dan Minuman yang Enak, di Banjarbaru!")
+	assert.Equal(t, []search.Token{
+		{Word: "warung", Stem: "warung"},
+		{Word: "makan", Stem: "makan"},
+		{Word: "minuman", Stem: "minum"},
+		{Word: "enak", Stem: "enak"},
+		{Word: "banjarbaru", Stem: "banjarbaru"},
+	}, tokens)
+
+	assert.Empty(t, search.Analyze("yang dan di ke"))
+}
@@ -0,0 +1,109 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/nrmadi02/mini-project/domain"
+)
+
+// MaxSuggestions is the most a Trie lookup returns, every node keeps that many of its best entries
+// so a lookup never walks the subtree below the prefix.
+const MaxSuggestions = 20
+
+type trieNode struct {
+	children map[rune]*trieNode
+	// best are indexes in Trie.entries, most popular first.
+	best []int
+}
+
+// Trie completes prefixes of the text of suggestions. Every word starts a key, so "mak" finds
+// "Warung Makan" and "warung mak" finds it too.
+type Trie struct {
+	root    *trieNode
+	entries []domain.Suggestion
+}
+
+func NewTrie(entries []domain.Suggestion) *Trie {
+	t := &Trie{root: &trieNode{}, entries: entries}
+	for i, entry := range entries {
+		keys := words(entry.Text)
+		for start := range keys {
+			t.insert(strings.Join(keys[start:], " "), i)
+		}
+	}
+	return t
+}
+
+func (t *Trie) insert(key string, entry int) {
+	node := t.root
+	for _, r := range key {
+		if node.children == nil {
+			node.children = map[rune]*trieNode{}
+		}
+		child, ok := node.children[r]
+		if !ok {
+			child = &trieNode{}
+			node.children[r] = child
+		}
+		node = child
+		t.keep(node, entry)
+	}
+}
+
+// keep adds entry to the best of node when it ranks high enough.
+func (t *Trie) keep(node *trieNode, entry int) {
+	at := len(node.best)
+	for i, other := range node.best {
+		if other == entry {
+			return
+		}
+		if at == len(node.best) && t.before(entry, other) {
+			at = i
+		}
+	}
+	if at == MaxSuggestions {
+		return
+	}
+	node.best = append(node.best, 0)
+	copy(node.best[at+1:], node.best[at:])
+	node.best[at] = entry
+	if len(node.best) > MaxSuggestions {
+		node.best = node.best[:MaxSuggestions]
+	}
+}
+
+func (t *Trie) before(a, b int) bool {
+	x, y := t.entries[a], t.entries[b]
+	if x.Popularity != y.Popularity {
+		return x.Popularity > y.Popularity
+	}
+	if x.Text != y.Text {
+		return x.Text < y.Text
+	}
+	return a < b
+}
+
+// Find returns at most limit entries with a word starting with prefix, most popular first.
+func (t *Trie) Find(prefix string, limit int) []domain.Suggestion {
+	key := strings.Join(words(prefix), " ")
+	if key == "" {
+		return nil
+	}
+	node := t.root
+	for _, r := range key {
+		node = node.children[r]
+		if node == nil {
+			return nil
+		}
+	}
+
+	best := node.best
+	if limit < len(best) {
+		best = best[:limit]
+	}
+	suggestions := make([]domain.Suggestion, 0, len(best))
+	for _, entry := range best {
+		suggestions = append(suggestions, t.entries[entry])
+	}
+	return suggestions
+}
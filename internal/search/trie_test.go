@@ -0,0 +1,61 @@
+package search_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func suggestionTexts(suggestions []domain.Suggestion) []string {
+	texts := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		texts = append(texts, suggestion.Text)
+	}
+	return texts
+}
+
+func TestTrie_Find(t *testing.T) {
+	trie := search.NewTrie([]domain.Suggestion{
+		{Type: domain.SuggestEnterprise, ID: "1", Text: "Warung Makan Bu Sri", Popularity: 4},
+		{Type: domain.SuggestEnterprise, ID: "2", Text: "Makmur Jaya Motor", Popularity: 9},
+		{Type: domain.SuggestTag, ID: "3", Text: "Makanan", Popularity: 4},
+		{Type: domain.SuggestLocality, Text: "Banjarmasin", Popularity: 12},
+		{Type: domain.SuggestEnterprise, ID: "4", Text: "Sasirangan Banjarmasin", Popularity: 1},
+	})
+
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"any word, most popular first", "mak", 10, []string{"Makmur Jaya Motor", "Makanan", "Warung Makan Bu Sri"}},
+		{"limit", "mak", 1, []string{"Makmur Jaya Motor"}},
+		{"several words", "warung ma", 10, []string{"Warung Makan Bu Sri"}},
+		{"case and punctuation", "  BANJAR-", 10, []string{"Banjarmasin", "Sasirangan Banjarmasin"}},
+		{"word order matters", "makan warung", 10, []string{}},
+		{"no match", "xyz", 10, []string{}},
+		{"empty", " ", 10, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, suggestionTexts(trie.Find(tt.prefix, tt.limit)))
+		})
+	}
+}
+
+func TestTrie_FindKeepsTheMostPopular(t *testing.T) {
+	var entries []domain.Suggestion
+	for i := 0; i < 50; i++ {
+		entries = append(entries, domain.Suggestion{Type: domain.SuggestEnterprise, Text: fmt.Sprintf("Toko %02d", i), Popularity: i})
+	}
+	trie := search.NewTrie(entries)
+
+	found := trie.Find("toko", 100)
+	assert.Len(t, found, search.MaxSuggestions)
+	assert.Equal(t, "Toko 49", found[0].Text)
+	assert.Equal(t, "Toko 30", found[search.MaxSuggestions-1].Text)
+}
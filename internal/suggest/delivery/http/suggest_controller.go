@@ -0,0 +1,61 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/web/response"
+	"net/http"
+	"strconv"
+)
+
+type SuggestController interface {
+	Suggest(c echo.Context) error
+}
+
+type suggestController struct {
+	suggestUsecase domain.SuggestUsecase
+}
+
+func NewSuggestController(su domain.SuggestUsecase) SuggestController {
+	return suggestController{
+		suggestUsecase: su,
+	}
+}
+
+// Suggest godoc
+// @Summary Search suggestions
+// @Description complete the search box with published enterprise names, tag names and localities having a word that starts with q, most popular first
+// @Tags Search
+// @accept json
+// @Produce json
+// @Router /search/suggest [get]
+// @Param q query string true "what the user typed so far"
+// @Param limit query int false "number of suggestions, default 10, at most 20"
+// @Success 200 {object} response.JSONSuccessResult{data=[]response.SuggestionResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Security JWT
+func (s suggestController) Suggest(c echo.Context) error {
+	limit := 10
+	if c.QueryParam("limit") != "" {
+		value, err := strconv.Atoi(c.QueryParam("limit"))
+		if err != nil || value < 1 || value > 20 {
+			return response.FailResponse(c, http.StatusBadRequest, false, "limit must be between 1 and 20")
+		}
+		limit = value
+	}
+
+	suggestions, err := s.suggestUsecase.Suggest(c.QueryParam("q"), limit)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	res := make([]response.SuggestionResponse, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		res = append(res, response.SuggestionResponse{
+			Type: string(suggestion.Type),
+			ID:   suggestion.ID,
+			Text: suggestion.Text,
+		})
+	}
+	return response.SuccessResponse(c, http.StatusOK, true, "success get suggestions", res)
+}
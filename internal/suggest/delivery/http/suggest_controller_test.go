@@ -0,0 +1,64 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	http2 "github.com/nrmadi02/mini-project/internal/suggest/delivery/http"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func suggest(controller http2.SuggestController, query string) (int, map[string]interface{}) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search/suggest?"+query, nil)
+	rec := httptest.NewRecorder()
+	_ = controller.Suggest(e.NewContext(req, rec))
+	var body map[string]interface{}
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	return rec.Code, body
+}
+
+func TestSuggestController_Suggest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockSuggestUsecase := new(mocks.SuggestUsecase)
+		mockSuggestUsecase.On("Suggest", "war", 10).Return([]domain.Suggestion{
+			{Type: domain.SuggestEnterprise, ID: "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", Text: "Warung Makan Bu Sri", Popularity: 4},
+			{Type: domain.SuggestLocality, Text: "Warukin", Popularity: 2},
+		}, nil).Once()
+		code, body := suggest(http2.NewSuggestController(mockSuggestUsecase), "q=war")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"type": "enterprise", "id": "35d6a9a1-aa5e-41f1-9991-08878dfdf89a", "text": "Warung Makan Bu Sri"},
+			map[string]interface{}{"type": "locality", "text": "Warukin"},
+		}, body["data"])
+		mockSuggestUsecase.AssertExpectations(t)
+	})
+
+	t.Run("no suggestions", func(t *testing.T) {
+		mockSuggestUsecase := new(mocks.SuggestUsecase)
+		mockSuggestUsecase.On("Suggest", "", 5).Return(nil, nil).Once()
+		code, body := suggest(http2.NewSuggestController(mockSuggestUsecase), "limit=5")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []interface{}{}, body["data"])
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		for _, limit := range []string{"0", "21", "ten"} {
+			code, _ := suggest(http2.NewSuggestController(new(mocks.SuggestUsecase)), "q=war&limit="+limit)
+			assert.Equal(t, http.StatusBadRequest, code, limit)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		mockSuggestUsecase := new(mocks.SuggestUsecase)
+		mockSuggestUsecase.On("Suggest", "war", 10).Return(nil, errors.New("error something")).Once()
+		code, body := suggest(http2.NewSuggestController(mockSuggestUsecase), "q=war")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "error something", body["message"])
+	})
+}
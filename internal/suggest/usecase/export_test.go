@@ -0,0 +1,15 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/nrmadi02/mini-project/domain"
+)
+
+// Expire makes the next Suggest on uc start a rebuild of its trie.
+func Expire(uc domain.SuggestUsecase) {
+	s := uc.(*suggestUsecase)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.builtAt = time.Time{}
+}
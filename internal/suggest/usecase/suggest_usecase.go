@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/search"
+	log "github.com/sirupsen/logrus"
+)
+
+// suggestRefresh is how long suggestions are served from the same trie, new enterprises and
+// tags show up after at most this long.
+const suggestRefresh = time.Minute
+
+type suggestUsecase struct {
+	enterpriseRepository domain.EnterpriseRepository
+	tagRepository        domain.TagRepository
+	ratingRepository     domain.RatingRepository
+
+	// first lets one request build the very first trie while the others wait for it.
+	first      sync.Mutex
+	mu         sync.Mutex
+	trie       *search.Trie
+	builtAt    time.Time
+	rebuilding bool
+}
+
+func NewSuggestUsecase(er domain.EnterpriseRepository, tr domain.TagRepository, rr domain.RatingRepository) domain.SuggestUsecase {
+	return &suggestUsecase{
+		enterpriseRepository: er,
+		tagRepository:        tr,
+		ratingRepository:     rr,
+	}
+}
+
+func (s *suggestUsecase) Suggest(prefix string, limit int) ([]domain.Suggestion, error) {
+	trie, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > search.MaxSuggestions {
+		limit = search.MaxSuggestions
+	}
+	return trie.Find(prefix, limit), nil
+}
+
+// current returns the trie without waiting for a rebuild. Once it is older than
+// suggestRefresh one rebuild is started in the background and the old trie is served until it
+// is done. A failed rebuild keeps the old trie, stale suggestions beat none in a search box.
+// Only the first trie is built inside the request, there is nothing to serve before it.
+func (s *suggestUsecase) current() (*search.Trie, error) {
+	s.mu.Lock()
+	trie := s.trie
+	if trie != nil && time.Since(s.builtAt) >= suggestRefresh && !s.rebuilding {
+		s.rebuilding = true
+		go s.rebuild()
+	}
+	s.mu.Unlock()
+	if trie != nil {
+		return trie, nil
+	}
+
+	s.first.Lock()
+	defer s.first.Unlock()
+	s.mu.Lock()
+	trie = s.trie
+	s.mu.Unlock()
+	if trie != nil {
+		return trie, nil
+	}
+	trie, err := s.build()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.trie, s.builtAt = trie, time.Now()
+	s.mu.Unlock()
+	return trie, nil
+}
+
+func (s *suggestUsecase) rebuild() {
+	trie, err := s.build()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rebuilding = false
+	if err != nil {
+		log.Warn("rebuild search suggestions: " + err.Error())
+		return
+	}
+	s.trie, s.builtAt = trie, time.Now()
+}
+
+// build suggests published enterprises, the tags they use and the localities they are in.
+func (s *suggestUsecase) build() (*search.Trie, error) {
+	enterprises, err := s.enterpriseRepository.FindByStatus(domain.EnterprisePublished)
+	if err != nil {
+		return nil, err
+	}
+	ratings, err := s.ratingRepository.CountByEnterprise()
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []domain.Suggestion
+	tagCounts := map[string]int{}
+	localities := map[string]*domain.Suggestion{}
+	var localityOrder []string
+	for _, enterprise := range enterprises {
+		suggestions = append(suggestions, domain.Suggestion{
+			Type:       domain.SuggestEnterprise,
+			ID:         enterprise.ID.String(),
+			Text:       enterprise.Name,
+			Popularity: ratings[enterprise.ID.String()],
+		})
+		for _, tag := range enterprise.Tags {
+			tagCounts[tag.ID.String()]++
+		}
+		if name := locality(enterprise.Address); name != "" {
+			key := strings.ToLower(name)
+			if localities[key] == nil {
+				localities[key] = &domain.Suggestion{Type: domain.SuggestLocality, Text: name}
+				localityOrder = append(localityOrder, key)
+			}
+			localities[key].Popularity++
+		}
+	}
+	for _, key := range localityOrder {
+		suggestions = append(suggestions, *localities[key])
+	}
+
+	page := domain.PageRequest{Length: 100}
+	for {
+		tags, info, err := s.tagRepository.FindAllTags(page)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			// a tag without published enterprises would lead to an empty list.
+			if count := tagCounts[tag.ID.String()]; count > 0 {
+				suggestions = append(suggestions, domain.Suggestion{Type: domain.SuggestTag, ID: tag.ID.String(), Text: tag.Name, Popularity: count})
+			}
+		}
+		if info.Next == nil {
+			break
+		}
+		page.Cursor = info.Next
+	}
+	return search.NewTrie(suggestions), nil
+}
+
+// locality is the last comma separated part of an address without a trailing postcode, the
+// city or district in addresses like "Jl. Ahmad Yani Km 5, Banjarmasin 70234". Addresses
+// without a comma have none.
+func locality(address string) string {
+	at := strings.LastIndex(address, ",")
+	if at < 0 {
+		return ""
+	}
+	fields := strings.Fields(address[at+1:])
+	for len(fields) > 0 && strings.Trim(fields[len(fields)-1], "0123456789") == "" {
+		fields = fields[:len(fields)-1]
+	}
+	return strings.Join(fields, " ")
+}
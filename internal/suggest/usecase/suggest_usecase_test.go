@@ -0,0 +1,123 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/suggest/usecase"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var kuliner = domain.Tag{ID: uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf8a1"), Name: "Kuliner"}
+var kerajinan = domain.Tag{ID: uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf8a2"), Name: "Kerajinan"}
+
+var published = domain.Enterprises{
+	{
+		ID:      uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
+		Name:    "Warung Kuning Bu Sri",
+		Address: "Jl. Ahmad Yani Km 5, Banjarmasin 70234",
+		Tags:    []domain.Tag{kuliner},
+	},
+	{
+		ID:      uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89b"),
+		Name:    "Kue Kering Banjar",
+		Address: "Jl. Sultan Adam, banjarmasin",
+		Tags:    []domain.Tag{kuliner},
+	},
+	{
+		ID:      uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89c"),
+		Name:    "Kursi Rotan",
+		Address: "Amuntai",
+	},
+}
+
+func TestSuggestUsecase_Suggest(t *testing.T) {
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTagRepository := new(mocks.TagRepository)
+	mockRatingRepository := new(mocks.RatingRepository)
+	uc := usecase.NewSuggestUsecase(mockEnterpriseRepository, mockTagRepository, mockRatingRepository)
+
+	mockEnterpriseRepository.On("FindByStatus", domain.EnterprisePublished).Return(published, nil).Once()
+	mockRatingRepository.On("CountByEnterprise").Return(map[string]int{published[1].ID.String(): 7, published[2].ID.String(): 1}, nil).Once()
+	next := &domain.Cursor{Key: "Kerajinan", ID: kerajinan.ID.String()}
+	mockTagRepository.On("FindAllTags", domain.PageRequest{Length: 100}).Return(domain.Tags{kerajinan}, domain.PageInfo{Next: next}, nil).Once()
+	mockTagRepository.On("FindAllTags", domain.PageRequest{Cursor: next, Length: 100}).Return(domain.Tags{kuliner}, domain.PageInfo{}, nil).Once()
+
+	suggestions, err := uc.Suggest("ku", 10)
+	assert.NoError(t, err)
+	// Kerajinan has no published enterprise, Amuntai has no comma to find a locality in.
+	assert.Equal(t, []domain.Suggestion{
+		{Type: domain.SuggestEnterprise, ID: published[1].ID.String(), Text: "Kue Kering Banjar", Popularity: 7},
+		{Type: domain.SuggestTag, ID: kuliner.ID.String(), Text: "Kuliner", Popularity: 2},
+		{Type: domain.SuggestEnterprise, ID: published[2].ID.String(), Text: "Kursi Rotan", Popularity: 1},
+		{Type: domain.SuggestEnterprise, ID: published[0].ID.String(), Text: "Warung Kuning Bu Sri", Popularity: 0},
+	}, suggestions)
+
+	// later keystrokes are served from the same trie.
+	suggestions, err = uc.Suggest("banjarm", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Suggestion{{Type: domain.SuggestLocality, Text: "Banjarmasin", Popularity: 2}}, suggestions)
+
+	suggestions, err = uc.Suggest("ker", 10)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "Kue Kering Banjar", suggestions[0].Text)
+
+	mockEnterpriseRepository.AssertExpectations(t)
+	mockTagRepository.AssertExpectations(t)
+	mockRatingRepository.AssertExpectations(t)
+}
+
+func TestSuggestUsecase_SuggestFailed(t *testing.T) {
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockRatingRepository := new(mocks.RatingRepository)
+	uc := usecase.NewSuggestUsecase(mockEnterpriseRepository, new(mocks.TagRepository), mockRatingRepository)
+
+	mockEnterpriseRepository.On("FindByStatus", domain.EnterprisePublished).Return(published, nil).Once()
+	mockRatingRepository.On("CountByEnterprise").Return(nil, errors.New("error something")).Once()
+
+	_, err := uc.Suggest("ku", 10)
+	assert.EqualError(t, err, "error something")
+}
+
+func TestSuggestUsecase_SuggestRebuild(t *testing.T) {
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTagRepository := new(mocks.TagRepository)
+	mockRatingRepository := new(mocks.RatingRepository)
+	uc := usecase.NewSuggestUsecase(mockEnterpriseRepository, mockTagRepository, mockRatingRepository)
+
+	mockEnterpriseRepository.On("FindByStatus", domain.EnterprisePublished).Return(published[:1], nil).Once()
+	mockRatingRepository.On("CountByEnterprise").Return(map[string]int{}, nil).Twice()
+	mockTagRepository.On("FindAllTags", domain.PageRequest{Length: 100}).Return(domain.Tags{}, domain.PageInfo{}, nil).Twice()
+
+	suggestions, err := uc.Suggest("ku", 10)
+	assert.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+
+	release := make(chan struct{})
+	mockEnterpriseRepository.On("FindByStatus", domain.EnterprisePublished).Return(published, nil).Once().
+		Run(func(mock.Arguments) { <-release })
+	usecase.Expire(uc)
+
+	// the old trie is served while the single rebuild waits for the repository.
+	for i := 0; i < 3; i++ {
+		suggestions, err = uc.Suggest("ku", 10)
+		assert.NoError(t, err)
+		assert.Len(t, suggestions, 1)
+	}
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		suggestions, err = uc.Suggest("ku", 10)
+		return err == nil && len(suggestions) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	mockEnterpriseRepository.AssertExpectations(t)
+	mockTagRepository.AssertExpectations(t)
+	mockRatingRepository.AssertExpectations(t)
+}
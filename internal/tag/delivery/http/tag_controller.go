@@ -1,9 +1,9 @@
 package http
 
 import (
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
 	"github.com/nrmadi02/mini-project/web/request"
 	"github.com/nrmadi02/mini-project/web/response"
 	"net/http"
@@ -34,12 +34,19 @@ func NewTagController(au domain.AuthUsecase, tu domain.TagUsecase) TagController
 // @accept json
 // @Produce json
 // @Router /tags [get]
-// @Success 200 {object} response.JSONSuccessResult{data=[]response.TagsListResponse}
+// @Param cursor query string false "next_cursor or prev_cursor of the previous response"
+// @Param length query int false "page length, default 20, at most 100"
+// @Param with_total query bool false "also count every row"
+// @Success 200 {object} response.JSONSuccessListResult{data=[]response.TagsListResponse,metadata=response.PageMetadata}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Security JWT
 func (t tagController) GetTagsList(c echo.Context) error {
+	page, err := helper.GetPageRequest(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
 
-	tags, err := t.tagUsecase.GetAllTags()
+	tags, info, err := t.tagUsecase.GetAllTags(page)
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
@@ -52,12 +59,12 @@ func (t tagController) GetTagsList(c echo.Context) error {
 		})
 	}
 
-	return response.SuccessResponse(c, http.StatusOK, true, "success get list tags", res)
+	return response.SuccessListResponse(c, http.StatusOK, true, "success get list tags", res, helper.PageMetadata(info, len(tags)))
 }
 
 // DeleteTag godoc
 // @Summary Delete tag by id
-// @Description delete tag can access only admin
+// @Description delete tag, requires permission tag:manage
 // @Tags Tag
 // @accept json
 // @Produce json
@@ -66,19 +73,12 @@ func (t tagController) GetTagsList(c echo.Context) error {
 // @Success 200 {object} response.JSONSuccessDeleteResult{}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (t tagController) DeleteTag(c echo.Context) error {
 	id := c.Param("id")
 
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-
-	isAdmin, err := t.authUsecase.CheckIfUserIsAdmin(claims["UserID"].(string))
-	if err != nil || !isAdmin {
-		return response.FailResponse(c, http.StatusUnauthorized, false, "only access admin")
-	}
-
-	err = t.tagUsecase.DeleteTag(id)
+	err := t.tagUsecase.DeleteTag(id, helper.GetActor(c))
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
@@ -88,7 +88,7 @@ func (t tagController) DeleteTag(c echo.Context) error {
 
 // CreateTag godoc
 // @Summary Create tag
-// @Description create tag can access only admin
+// @Description create tag, requires permission tag:manage
 // @Tags Tag
 // @accept json
 // @Produce json
@@ -97,6 +97,7 @@ func (t tagController) DeleteTag(c echo.Context) error {
 // @Success 200 {object} response.JSONSuccessResult{data=domain.Tag}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (t tagController) CreateTag(c echo.Context) error {
 	var req request.CreateTagRequest
@@ -108,14 +109,6 @@ func (t tagController) CreateTag(c echo.Context) error {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
 
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-
-	isAdmin, err := t.authUsecase.CheckIfUserIsAdmin(claims["UserID"].(string))
-	if err != nil || !isAdmin {
-		return response.FailResponse(c, http.StatusUnauthorized, false, "only access admin")
-	}
-
 	res, err := t.tagUsecase.CreateNewTag(req)
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
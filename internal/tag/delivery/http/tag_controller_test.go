@@ -7,8 +7,10 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/nrmadi02/mini-project/domain"
 	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
 	http2 "github.com/nrmadi02/mini-project/internal/tag/delivery/http"
 	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	mid "github.com/nrmadi02/mini-project/internal/user/delivery/http/middleware"
 	"github.com/nrmadi02/mini-project/web/request"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
@@ -74,19 +76,27 @@ var dummyTag = domain.Tags{
 	},
 }
 
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+
 func createToken() string {
-	jwtSetToken := helper.NewGoJWT()
+	jwtSetToken := helper.NewGoJWT(keySet, "test", "test")
 	token := jwtSetToken.CreateTokenJWT(&dummyUser[0])
 	return token
 }
 
 func middlewareToken(handlerFunc echo.HandlerFunc, c echo.Context) error {
 	err := middleware.JWTWithConfig(middleware.JWTConfig{
-		SigningKey: []byte("220220"),
+		KeyFunc: keySet.Keyfunc,
+		Claims:  &helper.JWTClaims{},
 	})(handlerFunc)(c)
 	return err
 }
 
+func requirePermission(authUsecase domain.AuthUsecase, permission role.Permission, handlerFunc echo.HandlerFunc) echo.HandlerFunc {
+	return mid.NewGoMiddleware(nil, nil, authUsecase, nil).RequirePermission(permission)(handlerFunc)
+}
+
 func parseResponse(rec *httptest.ResponseRecorder) map[string]interface{} {
 	var responseBody map[string]interface{}
 	resBody := rec.Body.String()
@@ -118,25 +128,27 @@ func TestTagController_CreateTag(t *testing.T) {
 		req, rec := makeRequestHttp(string(requestTag), echo.POST, "/tag", true, true)
 		c := e.NewContext(req, rec)
 		tagController := http2.NewTagController(mockAuthUsecase, mockTagUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "tag:manage").Return(true, nil).Once()
 		mockTagUsecase.On("CreateNewTag", mock.Anything).Return(dummyTag[0], nil).Once()
-		err := middlewareToken(tagController.CreateTag, c)
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.TagManage, tagController.CreateTag), c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(201), responseBody["code"])
 		mockTagUsecase.AssertExpectations(t)
+		mockAuthUsecase.AssertExpectations(t)
 	})
 	t.Run("error not admin", func(t *testing.T) {
 		e := echo.New()
 		req, rec := makeRequestHttp(string(requestTag), echo.POST, "/tag", true, true)
 		c := e.NewContext(req, rec)
 		tagController := http2.NewTagController(mockAuthUsecase, mockTagUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(false, errors.New("error something")).Once()
-		err := middlewareToken(tagController.CreateTag, c)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "tag:manage").Return(false, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.TagManage, tagController.CreateTag), c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
-		assert.Equal(t, float64(401), responseBody["code"])
+		assert.Equal(t, float64(403), responseBody["code"])
 		mockTagUsecase.AssertExpectations(t)
+		mockAuthUsecase.AssertExpectations(t)
 	})
 	t.Run("error bind echo", func(t *testing.T) {
 		e := echo.New()
@@ -154,13 +166,14 @@ func TestTagController_CreateTag(t *testing.T) {
 		req, rec := makeRequestHttp(string(requestTag), echo.POST, "/tag", true, true)
 		c := e.NewContext(req, rec)
 		tagController := http2.NewTagController(mockAuthUsecase, mockTagUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "tag:manage").Return(true, nil).Once()
 		mockTagUsecase.On("CreateNewTag", mock.Anything).Return(domain.Tag{}, errors.New("error something")).Once()
-		err := middlewareToken(tagController.CreateTag, c)
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.TagManage, tagController.CreateTag), c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(400), responseBody["code"])
 		mockTagUsecase.AssertExpectations(t)
+		mockAuthUsecase.AssertExpectations(t)
 	})
 
 	t.Run("error nul value", func(t *testing.T) {
@@ -191,13 +204,14 @@ func TestTagController_DeleteTag(t *testing.T) {
 		c.SetParamNames("id")
 		c.SetParamValues(dummyTag[0].ID.String())
 		tagController := http2.NewTagController(mockAuthUsecase, mockTagUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockTagUsecase.On("DeleteTag", mock.Anything).Return(nil).Once()
-		err := middlewareToken(tagController.DeleteTag, c)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "tag:manage").Return(true, nil).Once()
+		mockTagUsecase.On("DeleteTag", mock.Anything, mock.Anything).Return(nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.TagManage, tagController.DeleteTag), c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(200), responseBody["code"])
 		mockTagUsecase.AssertExpectations(t)
+		mockAuthUsecase.AssertExpectations(t)
 	})
 	t.Run("error not admin", func(t *testing.T) {
 		e := echo.New()
@@ -207,12 +221,13 @@ func TestTagController_DeleteTag(t *testing.T) {
 		c.SetParamNames("id")
 		c.SetParamValues(dummyTag[0].ID.String())
 		tagController := http2.NewTagController(mockAuthUsecase, mockTagUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(false, errors.New("error something")).Once()
-		err := middlewareToken(tagController.DeleteTag, c)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "tag:manage").Return(false, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.TagManage, tagController.DeleteTag), c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
-		assert.Equal(t, float64(401), responseBody["code"])
+		assert.Equal(t, float64(403), responseBody["code"])
 		mockTagUsecase.AssertExpectations(t)
+		mockAuthUsecase.AssertExpectations(t)
 	})
 	t.Run("error delete tag", func(t *testing.T) {
 		e := echo.New()
@@ -222,13 +237,14 @@ func TestTagController_DeleteTag(t *testing.T) {
 		c.SetParamNames("id")
 		c.SetParamValues(dummyTag[0].ID.String())
 		tagController := http2.NewTagController(mockAuthUsecase, mockTagUsecase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockTagUsecase.On("DeleteTag", mock.Anything).Return(errors.New("error something")).Once()
-		err := middlewareToken(tagController.DeleteTag, c)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "tag:manage").Return(true, nil).Once()
+		mockTagUsecase.On("DeleteTag", mock.Anything, mock.Anything).Return(errors.New("error something")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.TagManage, tagController.DeleteTag), c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(400), responseBody["code"])
 		mockTagUsecase.AssertExpectations(t)
+		mockAuthUsecase.AssertExpectations(t)
 	})
 }
 
@@ -240,7 +256,7 @@ func TestTagController_GetTagsList(t *testing.T) {
 		req, rec := makeRequestHttp("", echo.GET, "/tags", true, true)
 		c := e.NewContext(req, rec)
 		tagController := http2.NewTagController(mockAuthUsecase, mockTagUsecase)
-		mockTagUsecase.On("GetAllTags", mock.Anything).Return(domain.Tags{dummyTag[0]}, nil).Once()
+		mockTagUsecase.On("GetAllTags", domain.PageRequest{Length: 20}).Return(domain.Tags{dummyTag[0]}, domain.PageInfo{}, nil).Once()
 		err := middlewareToken(tagController.GetTagsList, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
@@ -252,7 +268,7 @@ func TestTagController_GetTagsList(t *testing.T) {
 		req, rec := makeRequestHttp("", echo.GET, "/tags", true, true)
 		c := e.NewContext(req, rec)
 		tagController := http2.NewTagController(mockAuthUsecase, mockTagUsecase)
-		mockTagUsecase.On("GetAllTags", mock.Anything).Return(domain.Tags{}, errors.New("error something")).Once()
+		mockTagUsecase.On("GetAllTags", mock.Anything).Return(domain.Tags{}, domain.PageInfo{}, errors.New("error something")).Once()
 		err := middlewareToken(tagController.GetTagsList, c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
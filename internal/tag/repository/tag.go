@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/pagination"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -31,9 +32,17 @@ func (t tagRepository) FindByIDs(ids []string) (tags domain.Tags, err error) {
 	return tags, err
 }
 
-func (t tagRepository) FindAllTags() (tags domain.Tags, err error) {
-	err = t.DB.Find(&tags).Error
-	return tags, err
+// FindAllTags pages the tags by name.
+func (t tagRepository) FindAllTags(page domain.PageRequest) (tags domain.Tags, info domain.PageInfo, err error) {
+	err = pagination.Keyset{Column: "name"}.Apply(t.DB, page).Find(&tags).Error
+	if err != nil {
+		return tags, info, err
+	}
+	tags, info = pagination.Page(tags, page, func(tag domain.Tag) domain.Cursor {
+		return domain.Cursor{Key: tag.Name, ID: tag.ID.String()}
+	})
+	err = pagination.Count(t.DB.Model(&domain.Tag{}), page, &info)
+	return tags, info, err
 }
 
 func (t tagRepository) Delete(tag domain.Tag, id string) error {
@@ -95,7 +95,7 @@ func TestTagRepository_FindAllTags(t *testing.T) {
 			AddRow(tag[1].ID, tag[1].Name))
 
 	tagRepository := repository.NewTagRepository(db)
-	resTags, err := tagRepository.FindAllTags()
+	resTags, _, err := tagRepository.FindAllTags(domain.PageRequest{Length: 20})
 	if err != nil {
 		assert.Error(t, err)
 	}
@@ -5,35 +5,81 @@ import (
 	"github.com/nrmadi02/mini-project/domain"
 	"github.com/nrmadi02/mini-project/web/request"
 	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
 )
 
 type tagUsecase struct {
-	tagRepository domain.TagRepository
+	tagRepository        domain.TagRepository
+	enterpriseRepository domain.EnterpriseRepository
+	searcher             domain.Searcher
+	audit                domain.AuditUsecase
 }
 
-func NewTagUsecase(tg domain.TagRepository) domain.TagUsecase {
+func NewTagUsecase(tg domain.TagRepository, er domain.EnterpriseRepository, searcher domain.Searcher, audit domain.AuditUsecase) domain.TagUsecase {
 	return tagUsecase{
-		tagRepository: tg,
+		tagRepository:        tg,
+		enterpriseRepository: er,
+		searcher:             searcher,
+		audit:                audit,
 	}
 }
 
-func (t tagUsecase) GetAllTags() (domain.Tags, error) {
-	return t.tagRepository.FindAllTags()
+func (t tagUsecase) GetAllTags(page domain.PageRequest) (domain.Tags, domain.PageInfo, error) {
+	return t.tagRepository.FindAllTags(page)
 }
 
-func (t tagUsecase) DeleteTag(id string) error {
+func (t tagUsecase) DeleteTag(id string, actor domain.Actor) error {
 	tag, err := t.tagRepository.FindByID(id)
 	if err != nil {
 		return err
 	}
+	tagged, err := t.findTagged(id)
+	if err != nil {
+		return err
+	}
 	err = t.tagRepository.Delete(tag, id)
 	if err != nil {
 		return err
 	}
+	// the tag name no longer finds the enterprises that had it.
+	for _, enterprise := range tagged {
+		enterprise.Tags = withoutTag(enterprise.Tags, tag.ID)
+		if err := t.searcher.Index(enterprise); err != nil {
+			log.WithField("enterprise_id", enterprise.ID.String()).Warn("index enterprise: " + err.Error())
+		}
+	}
 
+	t.audit.Record(actor, domain.AuditTagDelete, domain.AuditTargetTag, id, map[string]interface{}{"name": tag.Name}, nil)
 	return nil
 }
 
+// findTagged reads every enterprise with the tag, a page at a time.
+func (t tagUsecase) findTagged(id string) (domain.Enterprises, error) {
+	var tagged domain.Enterprises
+	page := domain.PageRequest{Length: 100}
+	for {
+		enterprises, info, err := t.enterpriseRepository.FindAll(domain.EnterpriseFilter{TagIDs: []string{id}}, page)
+		if err != nil {
+			return nil, err
+		}
+		tagged = append(tagged, enterprises...)
+		if info.Next == nil {
+			return tagged, nil
+		}
+		page.Cursor = info.Next
+	}
+}
+
+func withoutTag(tags []domain.Tag, id uuid.UUID) []domain.Tag {
+	kept := make([]domain.Tag, 0, len(tags))
+	for _, tag := range tags {
+		if tag.ID != id {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
 func (t tagUsecase) CreateNewTag(request request.CreateTagRequest) (domain.Tag, error) {
 	var existingTag domain.Tag
 	existingTag, _ = t.tagRepository.FindByName(request.Name)
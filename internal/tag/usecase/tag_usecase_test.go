@@ -23,13 +23,34 @@ var dummyTag = domain.Tags{
 	},
 }
 
+// newAuditUsecase accepts every audit entry, tests of an entry assert it on their own mock.
+func newAuditUsecase() *mocks.AuditUsecase {
+	auditUsecase := new(mocks.AuditUsecase)
+	auditUsecase.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return auditUsecase
+}
+
+// newEnterpriseRepository finds no enterprise with the tag.
+func newEnterpriseRepository() *mocks.EnterpriseRepository {
+	enterpriseRepository := new(mocks.EnterpriseRepository)
+	enterpriseRepository.On("FindAll", mock.Anything, mock.Anything).Return(domain.Enterprises{}, domain.PageInfo{}, nil).Maybe()
+	return enterpriseRepository
+}
+
+// newSearcher accepts any index change.
+func newSearcher() *mocks.Searcher {
+	searcher := new(mocks.Searcher)
+	searcher.On("Index", mock.Anything).Return(nil).Maybe()
+	return searcher
+}
+
 func TestTagUsecase_GetAllTags(t *testing.T) {
 	mockTagRepository := new(mocks.TagRepository)
 
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewTagUsecase(mockTagRepository)
-		mockTagRepository.On("FindAllTags").Return(domain.Tags{dummyTag[0]}, nil).Once()
-		tags, err := uc.GetAllTags()
+		uc := usecase.NewTagUsecase(mockTagRepository, newEnterpriseRepository(), newSearcher(), newAuditUsecase())
+		mockTagRepository.On("FindAllTags", domain.PageRequest{Length: 20}).Return(domain.Tags{dummyTag[0]}, domain.PageInfo{}, nil).Once()
+		tags, _, err := uc.GetAllTags(domain.PageRequest{Length: 20})
 		assert.NoError(t, err)
 		assert.NotNil(t, tags)
 	})
@@ -42,7 +63,7 @@ func TestTagUsecase_CreateNewTag(t *testing.T) {
 		req := request.CreateTagRequest{
 			Name: "Tag Satu",
 		}
-		uc := usecase.NewTagUsecase(mockTagRepository)
+		uc := usecase.NewTagUsecase(mockTagRepository, newEnterpriseRepository(), newSearcher(), newAuditUsecase())
 		mockTagRepository.On("FindByName", mock.AnythingOfType("string")).Return(domain.Tag{}, nil).Once()
 		mockTagRepository.On("Save", mock.AnythingOfType("domain.Tag")).Return(dummyTag[0], nil).Once()
 		tags, err := uc.CreateNewTag(req)
@@ -53,7 +74,7 @@ func TestTagUsecase_CreateNewTag(t *testing.T) {
 		req := request.CreateTagRequest{
 			Name: "Tag Satu",
 		}
-		uc := usecase.NewTagUsecase(mockTagRepository)
+		uc := usecase.NewTagUsecase(mockTagRepository, newEnterpriseRepository(), newSearcher(), newAuditUsecase())
 		mockTagRepository.On("FindByName", mock.AnythingOfType("string")).Return(dummyTag[0], nil).Once()
 		_, err := uc.CreateNewTag(req)
 		assert.Error(t, err)
@@ -62,7 +83,7 @@ func TestTagUsecase_CreateNewTag(t *testing.T) {
 		req := request.CreateTagRequest{
 			Name: "Tag Satu",
 		}
-		uc := usecase.NewTagUsecase(mockTagRepository)
+		uc := usecase.NewTagUsecase(mockTagRepository, newEnterpriseRepository(), newSearcher(), newAuditUsecase())
 		mockTagRepository.On("FindByName", mock.AnythingOfType("string")).Return(domain.Tag{}, nil).Once()
 		mockTagRepository.On("Save", mock.AnythingOfType("domain.Tag")).Return(domain.Tag{}, errors.New("error something")).Once()
 		_, err := uc.CreateNewTag(req)
@@ -74,23 +95,47 @@ func TestTagUsecase_DeleteTag(t *testing.T) {
 	mockTagRepository := new(mocks.TagRepository)
 
 	t.Run("success", func(t *testing.T) {
-		uc := usecase.NewTagUsecase(mockTagRepository)
+		actor := domain.Actor{UserID: "35d6a9a1-aa5e-41f1-9991-08878dfdf891", IP: "127.0.0.1"}
+		mockAuditUsecase := new(mocks.AuditUsecase)
+		mockAuditUsecase.On("Record", actor, domain.AuditTagDelete, domain.AuditTargetTag, dummyTag[0].ID.String(),
+			map[string]interface{}{"name": dummyTag[0].Name}, map[string]interface{}(nil)).Once()
+		uc := usecase.NewTagUsecase(mockTagRepository, newEnterpriseRepository(), newSearcher(), mockAuditUsecase)
 		mockTagRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyTag[0], nil).Once()
 		mockTagRepository.On("Delete", mock.AnythingOfType("domain.Tag"), mock.AnythingOfType("string")).Return(nil).Once()
-		err := uc.DeleteTag(dummyTag[0].ID.String())
+		err := uc.DeleteTag(dummyTag[0].ID.String(), actor)
+		assert.NoError(t, err)
+		mockAuditUsecase.AssertExpectations(t)
+	})
+	t.Run("reindexes tagged enterprises", func(t *testing.T) {
+		enterprise := domain.Enterprise{ID: uuid.NewV4(), Name: "Warung Satu", Tags: []domain.Tag{dummyTag[0], dummyTag[1]}}
+		next := &domain.Cursor{Key: enterprise.ID.String(), ID: enterprise.ID.String()}
+		filter := domain.EnterpriseFilter{TagIDs: []string{dummyTag[0].ID.String()}}
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		searcher := new(mocks.Searcher)
+		uc := usecase.NewTagUsecase(mockTagRepository, mockEnterpriseRepository, searcher, newAuditUsecase())
+		mockTagRepository.On("FindByID", dummyTag[0].ID.String()).Return(dummyTag[0], nil).Once()
+		mockEnterpriseRepository.On("FindAll", filter, domain.PageRequest{Length: 100}).Return(domain.Enterprises{enterprise}, domain.PageInfo{Next: next}, nil).Once()
+		mockEnterpriseRepository.On("FindAll", filter, domain.PageRequest{Cursor: next, Length: 100}).Return(domain.Enterprises{}, domain.PageInfo{}, nil).Once()
+		mockTagRepository.On("Delete", dummyTag[0], dummyTag[0].ID.String()).Return(nil).Once()
+		searcher.On("Index", mock.MatchedBy(func(indexed domain.Enterprise) bool {
+			return indexed.ID == enterprise.ID && len(indexed.Tags) == 1 && indexed.Tags[0].ID == dummyTag[1].ID
+		})).Return(nil).Once()
+		err := uc.DeleteTag(dummyTag[0].ID.String(), domain.Actor{})
 		assert.NoError(t, err)
+		mockEnterpriseRepository.AssertExpectations(t)
+		searcher.AssertExpectations(t)
 	})
 	t.Run("error tag not found", func(t *testing.T) {
-		uc := usecase.NewTagUsecase(mockTagRepository)
+		uc := usecase.NewTagUsecase(mockTagRepository, newEnterpriseRepository(), newSearcher(), newAuditUsecase())
 		mockTagRepository.On("FindByID", mock.AnythingOfType("string")).Return(domain.Tag{}, errors.New("error something")).Once()
-		err := uc.DeleteTag(dummyTag[0].ID.String())
+		err := uc.DeleteTag(dummyTag[0].ID.String(), domain.Actor{})
 		assert.Error(t, err)
 	})
 	t.Run("error delete tag", func(t *testing.T) {
-		uc := usecase.NewTagUsecase(mockTagRepository)
+		uc := usecase.NewTagUsecase(mockTagRepository, newEnterpriseRepository(), newSearcher(), newAuditUsecase())
 		mockTagRepository.On("FindByID", mock.AnythingOfType("string")).Return(dummyTag[0], nil).Once()
 		mockTagRepository.On("Delete", mock.AnythingOfType("domain.Tag"), mock.AnythingOfType("string")).Return(errors.New("error something")).Once()
-		err := uc.DeleteTag(dummyTag[0].ID.String())
+		err := uc.DeleteTag(dummyTag[0].ID.String(), domain.Actor{})
 		assert.Error(t, err)
 	})
 }
@@ -1,15 +1,24 @@
 package http
 
 import (
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/web/request"
 	"github.com/nrmadi02/mini-project/web/response"
 	"net/http"
 )
 
 type AdminController interface {
 	GetUserList(c echo.Context) error
+	AssignRole(c echo.Context) error
+	RevokeRole(c echo.Context) error
+	SuspendUser(c echo.Context) error
+	ReactivateUser(c echo.Context) error
+	ForcePasswordReset(c echo.Context) error
+	DeleteUser(c echo.Context) error
+	GetLockedAccounts(c echo.Context) error
+	UnlockAccount(c echo.Context) error
 }
 
 type adminController struct {
@@ -26,26 +35,26 @@ func NewAdminController(au domain.AuthUsecase, uu domain.UserUsecase) AdminContr
 
 // GetUserList godoc
 // @Summary Get list users
-// @Description Get list users can access only admin
+// @Description Get list users, requires permission user:manage
 // @Tags User
 // @accept json
 // @Produce json
 // @Router /users [get]
-// @Success 200 {object} response.JSONSuccessResult{data=[]response.UsersListResponse}
+// @Param cursor query string false "next_cursor or prev_cursor of the previous response"
+// @Param length query int false "page length, default 20, at most 100"
+// @Param with_total query bool false "also count every row"
+// @Success 200 {object} response.JSONSuccessListResult{data=[]response.UsersListResponse,metadata=response.PageMetadata}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
 // @Security JWT
 func (a adminController) GetUserList(c echo.Context) error {
-
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
-
-	isAdmin, err := a.AuthUsecase.CheckIfUserIsAdmin(claims["UserID"].(string))
-	if err != nil || !isAdmin {
-		return response.FailResponse(c, http.StatusUnauthorized, false, "only access admin")
+	page, err := helper.GetPageRequest(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
 
-	foundUsers, err := a.UserUsecase.GetAllUsers()
+	foundUsers, info, err := a.UserUsecase.GetAllUsers(page)
 	if err != nil {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
@@ -62,5 +71,236 @@ func (a adminController) GetUserList(c echo.Context) error {
 		})
 	}
 
-	return response.SuccessResponse(c, http.StatusOK, true, "success get list users", res)
+	return response.SuccessListResponse(c, http.StatusOK, true, "success get list users", res, helper.PageMetadata(info, len(foundUsers)))
+}
+
+func userAdminResponse(user domain.User) response.UserAdminResponse {
+	roles := make([]string, 0, len(user.Roles))
+	for _, r := range user.Roles {
+		roles = append(roles, r.Name)
+	}
+	return response.UserAdminResponse{
+		ID:                    user.ID,
+		Email:                 user.Email,
+		EmailVerifiedAt:       user.EmailVerifiedAt,
+		Fullname:              user.Fullname,
+		Username:              user.Username,
+		Roles:                 roles,
+		SuspendedAt:           user.SuspendedAt,
+		SuspendedReason:       user.SuspendedReason,
+		PasswordResetRequired: user.PasswordResetRequired,
+		TwoFactorEnabled:      user.TwoFactorEnabled(),
+		CreatedAt:             user.CreatedAt,
+		UpdatedAt:             user.UpdatedAt,
+	}
+}
+
+// AssignRole godoc
+// @Summary Assign role to user
+// @Description Give a user one more role, requires permission user:manage
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /users/{id}/roles [post]
+// @Param id path string true "user id"
+// @Param data body request.AssignRoleRequest true "role: ROLE_ADMIN, ROLE_CLIENT, ROLE_OWNER, ROLE_MODERATOR"
+// @Success 200 {object} response.JSONSuccessResult{data=response.UserAdminResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a adminController) AssignRole(c echo.Context) error {
+	var req request.AssignRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	user, err := a.UserUsecase.AssignRole(c.Param("id"), helper.GetActor(c), req.Role)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success assign role", userAdminResponse(user))
+}
+
+// RevokeRole godoc
+// @Summary Revoke role from user
+// @Description Take a role away from a user, admins cannot revoke their own roles. Requires permission user:manage
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /users/{id}/roles/{role} [delete]
+// @Param id path string true "user id"
+// @Param role path string true "role name"
+// @Success 200 {object} response.JSONSuccessResult{data=response.UserAdminResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a adminController) RevokeRole(c echo.Context) error {
+	_, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	user, err := a.UserUsecase.RevokeRole(c.Param("id"), helper.GetActor(c), c.Param("role"))
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success revoke role", userAdminResponse(user))
+}
+
+// SuspendUser godoc
+// @Summary Suspend user
+// @Description Suspended users cannot login and their tokens stop working. Requires permission user:manage
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /users/{id}/suspend [put]
+// @Param id path string true "user id"
+// @Param data body request.SuspendUserRequest true "reason is required"
+// @Success 200 {object} response.JSONSuccessResult{data=response.UserAdminResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a adminController) SuspendUser(c echo.Context) error {
+	_, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	var req request.SuspendUserRequest
+	if err = c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	user, err := a.UserUsecase.SuspendUser(c.Param("id"), helper.GetActor(c), req)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success suspend user", userAdminResponse(user))
+}
+
+// ReactivateUser godoc
+// @Summary Reactivate user
+// @Description Lift a suspension, requires permission user:manage
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /users/{id}/reactivate [put]
+// @Param id path string true "user id"
+// @Success 200 {object} response.JSONSuccessResult{data=response.UserAdminResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a adminController) ReactivateUser(c echo.Context) error {
+	user, err := a.UserUsecase.ReactivateUser(c.Param("id"), helper.GetActor(c))
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success reactivate user", userAdminResponse(user))
+}
+
+// ForcePasswordReset godoc
+// @Summary Force password reset
+// @Description Logs the user out everywhere, login is refused until the password is changed. Requires permission user:manage
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /users/{id}/password-reset [put]
+// @Param id path string true "user id"
+// @Success 200 {object} response.JSONSuccessResult{data=response.UserAdminResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a adminController) ForcePasswordReset(c echo.Context) error {
+	user, err := a.UserUsecase.ForcePasswordReset(c.Param("id"), helper.GetActor(c))
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success force password reset", userAdminResponse(user))
+}
+
+// DeleteUser godoc
+// @Summary Delete user
+// @Description Delete a user with their enterprises, ratings, reviews and favorites. Requires permission user:manage
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /users/{id} [delete]
+// @Param id path string true "user id"
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a adminController) DeleteUser(c echo.Context) error {
+	_, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	err = a.UserUsecase.DeleteUser(c.Param("id"), helper.GetActor(c))
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success delete user", nil)
+}
+
+// GetLockedAccounts godoc
+// @Summary Get locked accounts
+// @Description List users locked out after too many failed logins, requires permission user:manage
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /users/locked [get]
+// @Success 200 {object} response.JSONSuccessResult{data=[]response.LockedAccountResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a adminController) GetLockedAccounts(c echo.Context) error {
+	accounts, err := a.AuthUsecase.GetLockedAccounts()
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	res := make([]response.LockedAccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		res = append(res, response.LockedAccountResponse{
+			User:        userAdminResponse(account.User),
+			Failures:    account.Failures,
+			LockedUntil: account.LockedUntil,
+		})
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success get locked accounts", res)
+}
+
+// UnlockAccount godoc
+// @Summary Unlock account
+// @Description Lift a lockout after failed logins before it expires, the user gets a mail. Requires permission user:manage
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /users/{id}/lockout [delete]
+// @Param id path string true "user id"
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 403 {object} response.JSONForbiddenResult{}
+// @Security JWT
+func (a adminController) UnlockAccount(c echo.Context) error {
+	if err := a.AuthUsecase.UnlockAccount(c.Param("id"), helper.GetActor(c)); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success unlock account", nil)
 }
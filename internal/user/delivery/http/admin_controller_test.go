@@ -7,11 +7,13 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/nrmadi02/mini-project/domain"
 	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
 	http2 "github.com/nrmadi02/mini-project/internal/user/delivery/http"
 	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	mid "github.com/nrmadi02/mini-project/internal/user/delivery/http/middleware"
+	"github.com/nrmadi02/mini-project/web/request"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
 	"net/http"
 	"net/http/httptest"
@@ -62,19 +64,27 @@ var dummyUser = domain.Users{
 	},
 }
 
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+
 func createToken() string {
-	jwtSetToken := helper.NewGoJWT()
+	jwtSetToken := helper.NewGoJWT(keySet, "test", "test")
 	token := jwtSetToken.CreateTokenJWT(&dummyUser[0])
 	return token
 }
 
 func middlewareToken(handlerFunc echo.HandlerFunc, c echo.Context) error {
 	err := middleware.JWTWithConfig(middleware.JWTConfig{
-		SigningKey: []byte("220220"),
+		KeyFunc: keySet.Keyfunc,
+		Claims:  &helper.JWTClaims{},
 	})(handlerFunc)(c)
 	return err
 }
 
+func requirePermission(authUsecase domain.AuthUsecase, permission role.Permission, handlerFunc echo.HandlerFunc) echo.HandlerFunc {
+	return mid.NewGoMiddleware(nil, nil, authUsecase, nil).RequirePermission(permission)(handlerFunc)
+}
+
 func parseResponse(rec *httptest.ResponseRecorder) map[string]interface{} {
 	var responseBody map[string]interface{}
 	resBody := rec.Body.String()
@@ -103,9 +113,9 @@ func TestAdminController_GetUserList(t *testing.T) {
 		req, rec := makeRequestHttp("", echo.GET, "/users", true, true)
 		c := e.NewContext(req, rec)
 		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockUsercase.On("GetAllUsers").Return(domain.Users{dummyUser[0]}, nil).Once()
-		err := middlewareToken(adminController.GetUserList, c)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("GetAllUsers", domain.PageRequest{Length: 20}).Return(domain.Users{dummyUser[0]}, domain.PageInfo{}, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.GetUserList), c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(200), responseBody["code"])
@@ -117,8 +127,21 @@ func TestAdminController_GetUserList(t *testing.T) {
 		req, rec := makeRequestHttp("", echo.GET, "/users", true, true)
 		c := e.NewContext(req, rec)
 		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(false, errors.New("error something")).Once()
-		err := middlewareToken(adminController.GetUserList, c)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(false, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.GetUserList), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(403), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error user not found", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/users", true, true)
+		c := e.NewContext(req, rec)
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(false, errors.New("record not found")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.GetUserList), c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(401), responseBody["code"])
@@ -130,9 +153,9 @@ func TestAdminController_GetUserList(t *testing.T) {
 		req, rec := makeRequestHttp("", echo.GET, "/users", true, true)
 		c := e.NewContext(req, rec)
 		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
-		mockAuthUsecase.On("CheckIfUserIsAdmin", mock.Anything).Return(true, nil).Once()
-		mockUsercase.On("GetAllUsers").Return(domain.Users{}, errors.New("error something")).Once()
-		err := middlewareToken(adminController.GetUserList, c)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("GetAllUsers", domain.PageRequest{Length: 20}).Return(domain.Users{}, domain.PageInfo{}, errors.New("error something")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.GetUserList), c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(400), responseBody["code"])
@@ -140,3 +163,290 @@ func TestAdminController_GetUserList(t *testing.T) {
 		mockAuthUsecase.AssertExpectations(t)
 	})
 }
+
+func TestAdminController_AssignRole(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockUsercase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"role": "ROLE_MODERATOR"}`, echo.POST, "/users/"+dummyUser[1].ID.String()+"/roles", true, true)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("AssignRole", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "ROLE_MODERATOR").Return(dummyUser[1], nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.AssignRole), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+		mockUsercase.AssertExpectations(t)
+	})
+	t.Run("error role not found", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"role": "ROLE_UNKNOWN"}`, echo.POST, "/users/"+dummyUser[1].ID.String()+"/roles", true, true)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("AssignRole", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "ROLE_UNKNOWN").Return(domain.User{}, errors.New("role not found - ROLE_UNKNOWN")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.AssignRole), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+}
+
+func TestAdminController_RevokeRole(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockUsercase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/users/"+dummyUser[1].ID.String()+"/roles/ROLE_CLIENT", true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "role")
+		c.SetParamValues(dummyUser[1].ID.String(), "ROLE_CLIENT")
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("RevokeRole", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "ROLE_CLIENT").Return(dummyUser[1], nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.RevokeRole), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+	t.Run("error own role", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/users/"+dummyUser[0].ID.String()+"/roles/ROLE_ADMIN", true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id", "role")
+		c.SetParamValues(dummyUser[0].ID.String(), "ROLE_ADMIN")
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("RevokeRole", dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, "ROLE_ADMIN").Return(domain.User{}, errors.New("cannot revoke your own role")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.RevokeRole), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+}
+
+func TestAdminController_SuspendUser(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockUsercase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"reason": "spam"}`, echo.PUT, "/users/"+dummyUser[1].ID.String()+"/suspend", true, true)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("SuspendUser", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, request.SuspendUserRequest{Reason: "spam"}).Return(dummyUser[1], nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.SuspendUser), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+	t.Run("error no reason", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{}`, echo.PUT, "/users/"+dummyUser[1].ID.String()+"/suspend", true, true)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("SuspendUser", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}, request.SuspendUserRequest{}).Return(domain.User{}, errors.New("reason is required")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.SuspendUser), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+}
+
+func TestAdminController_ReactivateUser(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockUsercase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.PUT, "/users/"+dummyUser[1].ID.String()+"/reactivate", true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("ReactivateUser", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}).Return(dummyUser[1], nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.ReactivateUser), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+	t.Run("error not suspended", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.PUT, "/users/"+dummyUser[1].ID.String()+"/reactivate", true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("ReactivateUser", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}).Return(domain.User{}, errors.New("user is not suspended")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.ReactivateUser), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+}
+
+func TestAdminController_ForcePasswordReset(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockUsercase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.PUT, "/users/"+dummyUser[1].ID.String()+"/password-reset", true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("ForcePasswordReset", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}).Return(dummyUser[1], nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.ForcePasswordReset), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+	t.Run("error not admin", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.PUT, "/users/"+dummyUser[1].ID.String()+"/password-reset", true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(false, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.ForcePasswordReset), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(403), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAdminController_DeleteUser(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockUsercase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/users/"+dummyUser[1].ID.String(), true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("DeleteUser", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}).Return(nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.DeleteUser), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+	t.Run("error delete self", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/users/"+dummyUser[0].ID.String(), true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[0].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockUsercase.On("DeleteUser", dummyUser[0].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}).Return(errors.New("cannot delete yourself")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.DeleteUser), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockUsercase.AssertExpectations(t)
+	})
+}
+
+func TestAdminController_GetLockedAccounts(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockUsercase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/users/locked", true, false)
+		c := e.NewContext(req, rec)
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockAuthUsecase.On("GetLockedAccounts").Return([]domain.LockedAccount{
+			{User: dummyUser[1], Failures: 5, LockedUntil: time.Now().Add(time.Minute)},
+		}, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.GetLockedAccounts), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		assert.Len(t, responseBody["data"], 1)
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error forbidden", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/users/locked", true, false)
+		c := e.NewContext(req, rec)
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(false, nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.GetLockedAccounts), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(403), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAdminController_UnlockAccount(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockUsercase := new(mocks.UserUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/users/"+dummyUser[1].ID.String()+"/lockout", true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockAuthUsecase.On("UnlockAccount", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}).Return(nil).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.UnlockAccount), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error not locked", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.DELETE, "/users/"+dummyUser[1].ID.String()+"/lockout", true, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(dummyUser[1].ID.String())
+		adminController := http2.NewAdminController(mockAuthUsecase, mockUsercase)
+		mockAuthUsecase.On("HasPermission", dummyUser[0].ID.String(), "user:manage").Return(true, nil).Once()
+		mockAuthUsecase.On("UnlockAccount", dummyUser[1].ID.String(), domain.Actor{UserID: dummyUser[0].ID.String()}).Return(errors.New("account is not locked")).Once()
+		err := middlewareToken(requirePermission(mockAuthUsecase, role.UserManage, adminController.UnlockAccount), c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		assert.Equal(t, "account is not locked", responseBody["message"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
@@ -1,16 +1,30 @@
 package http
 
 import (
+	"errors"
 	"github.com/labstack/echo/v4"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
 	"github.com/nrmadi02/mini-project/web/request"
 	"github.com/nrmadi02/mini-project/web/response"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type AuthController interface {
 	Register(c echo.Context) error
 	Login(c echo.Context) error
+	LoginTwoFactor(c echo.Context) error
+	OIDCAuthorize(c echo.Context) error
+	OIDCCallback(c echo.Context) error
+	RefreshToken(c echo.Context) error
+	Logout(c echo.Context) error
+	ForgotPassword(c echo.Context) error
+	ResetPassword(c echo.Context) error
+	VerifyEmail(c echo.Context) error
+	SendEmailVerification(c echo.Context) error
 }
 
 type authController struct {
@@ -64,7 +78,7 @@ func (a authController) Register(c echo.Context) error {
 
 // Login godoc
 // @Summary Login user
-// @Description Login for get JWT token
+// @Description Login for get JWT token. With two-factor authentication enabled there is no token yet, continue with /login/2fa
 // @Tags Auth
 // @param data body request.LoginRequest true "required"
 // @accept json
@@ -73,6 +87,7 @@ func (a authController) Register(c echo.Context) error {
 // @Success 200 {object} response.JSONSuccessResult{data=response.SuccessLogin}
 // @Failure 400 {object} response.JSONBadRequestResult{}
 // @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 429 {object} response.JSONTooManyRequestsResult{}
 func (a authController) Login(c echo.Context) error {
 	var req request.LoginRequest
 
@@ -80,11 +95,261 @@ func (a authController) Login(c echo.Context) error {
 		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
 	}
 
-	res, err := a.AuthUsecase.Login(req)
+	res, err := a.AuthUsecase.Login(req, helper.GetActor(c))
+	if err != nil {
+		return loginFailResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "login success", res)
+
+}
+
+// loginFailResponse answers 429 with Retry-After while the login is throttled, otherwise 401.
+func loginFailResponse(c echo.Context, err error) error {
+	var throttled *domain.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		return response.FailResponse(c, http.StatusTooManyRequests, false, err.Error())
+	}
+	return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+}
+
+// LoginTwoFactor godoc
+// @Summary Login second step
+// @Description When login answers two_factor_required, send the challenge token with a code from the authenticator app or a recovery code to get the JWT token
+// @Tags Auth
+// @param data body request.TwoFactorLoginRequest true "required"
+// @accept json
+// @Produce json
+// @Router /login/2fa [post]
+// @Success 200 {object} response.JSONSuccessResult{data=response.SuccessLogin}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Failure 429 {object} response.JSONTooManyRequestsResult{}
+func (a authController) LoginTwoFactor(c echo.Context) error {
+	var req request.TwoFactorLoginRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if val, err := request.ValidateTwoFactorLogin(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	res, err := a.AuthUsecase.VerifyTwoFactorLogin(req, helper.GetActor(c))
+	if err != nil {
+		return loginFailResponse(c, err)
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "login success", res)
+}
+
+// OIDCAuthorize godoc
+// @Summary Login with provider
+// @Description Start a login at an OpenID Connect provider like google or keycloak. Send the browser to authorization_url, the provider redirects back to the app with code and state
+// @Tags Auth
+// @Param provider path string true "provider name"
+// @accept json
+// @Produce json
+// @Router /login/oidc/{provider} [get]
+// @Success 200 {object} response.JSONSuccessResult{data=response.OIDCAuthorizationResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+func (a authController) OIDCAuthorize(c echo.Context) error {
+	authURL, err := a.AuthUsecase.OIDCAuthorizationURL(c.Param("provider"))
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success create authorization url", response.OIDCAuthorizationResponse{
+		AuthorizationURL: authURL,
+	})
+}
+
+// OIDCCallback godoc
+// @Summary Login with provider callback
+// @Description Send the code and state the provider redirected back with to get the JWT token. A new user is registered as client, an existing user is linked by verified email
+// @Tags Auth
+// @Param provider path string true "provider name"
+// @param data body request.OIDCCallbackRequest true "required"
+// @accept json
+// @Produce json
+// @Router /login/oidc/{provider}/callback [post]
+// @Success 200 {object} response.JSONSuccessResult{data=response.SuccessLogin}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+func (a authController) OIDCCallback(c echo.Context) error {
+	var req request.OIDCCallbackRequest
+	if err := c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if val, err := request.ValidateOIDCCallback(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	res, err := a.AuthUsecase.OIDCLogin(c.Param("provider"), req, helper.GetActor(c))
 	if err != nil {
 		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
 	}
 
 	return response.SuccessResponse(c, http.StatusOK, true, "login success", res)
+}
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access and refresh token, the old refresh token can not be used again
+// @Tags Auth
+// @param data body request.RefreshTokenRequest true "required"
+// @accept json
+// @Produce json
+// @Router /token/refresh [post]
+// @Success 200 {object} response.JSONSuccessResult{data=response.SuccessLogin}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+func (a authController) RefreshToken(c echo.Context) error {
+	var req request.RefreshTokenRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if req.RefreshToken == "" {
+		return response.FailResponse(c, http.StatusBadRequest, false, "refresh_token is required")
+	}
+
+	res, err := a.AuthUsecase.RefreshToken(req)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "refresh token success", res)
+}
+
+// Logout godoc
+// @Summary Logout user
+// @Description Revoke the access token in use and the refresh token if sent
+// @Tags Auth
+// @param data body request.RefreshTokenRequest false "optional"
+// @accept json
+// @Produce json
+// @Router /logout [post]
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (a authController) Logout(c echo.Context) error {
+	var req request.RefreshTokenRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	err = a.AuthUsecase.Logout(claims.UserID(), claims.Id, time.Unix(claims.ExpiresAt, 0), req)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "logout success", nil)
+}
+
+// ForgotPassword godoc
+// @Summary Forgot password
+// @Description Mail a password reset token, the answer is the same whether the email is registered or not
+// @Tags Auth
+// @param data body request.ForgotPasswordRequest true "required"
+// @accept json
+// @Produce json
+// @Router /password/forgot [post]
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+func (a authController) ForgotPassword(c echo.Context) error {
+	var req request.ForgotPasswordRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if req.Email == "" {
+		return response.FailResponse(c, http.StatusBadRequest, false, "email empty")
+	}
+
+	if err := a.AuthUsecase.ForgotPassword(req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "if the email is registered, a password reset token has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with the token from forgot password, the token works once
+// @Tags Auth
+// @param data body request.ResetPasswordRequest true "required"
+// @accept json
+// @Produce json
+// @Router /password/reset [post]
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+func (a authController) ResetPassword(c echo.Context) error {
+	var req request.ResetPasswordRequest
+
+	if err := c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if val, err := request.ValidateResetPassword(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	if err := a.AuthUsecase.ResetPassword(req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success reset password", nil)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email
+// @Description Confirm the email with the token from the verification mail
+// @Tags Auth
+// @Param token query string true "verification token"
+// @Produce json
+// @Router /verify-email [get]
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+func (a authController) VerifyEmail(c echo.Context) error {
+	token := c.QueryParam("token")
+	if token == "" {
+		return response.FailResponse(c, http.StatusBadRequest, false, "token empty")
+	}
+
+	if err := a.AuthUsecase.VerifyEmail(token); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	return response.SuccessResponse(c, http.StatusOK, true, "success verify email", nil)
+}
+
+// SendEmailVerification godoc
+// @Summary Send verification email again
+// @Description Mail a new verification token to the email of the logged in user
+// @Tags Auth
+// @Produce json
+// @Router /user/verify-email [post]
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (a authController) SendEmailVerification(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	if err = a.AuthUsecase.SendEmailVerification(claims.UserID()); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
 
+	return response.SuccessResponse(c, http.StatusOK, true, "verification email sent", nil)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestAuthController_Login(t *testing.T) {
@@ -26,7 +27,7 @@ func TestAuthController_Login(t *testing.T) {
 		req, rec := makeRequestHttp(string(requestLogin), echo.POST, "/login", true, true)
 		c := e.NewContext(req, rec)
 		authController := http.NewAuthController(mockAuthUsecase)
-		mockAuthUsecase.On("Login", mock.Anything).Return(response.SuccessLogin{
+		mockAuthUsecase.On("Login", mock.Anything, mock.Anything).Return(response.SuccessLogin{
 			ID:       dummyUser[0].ID,
 			Email:    "satu@gmail.com",
 			Fullname: "user1",
@@ -55,13 +56,170 @@ func TestAuthController_Login(t *testing.T) {
 		req, rec := makeRequestHttp(string(requestLogin), echo.POST, "/login", true, true)
 		c := e.NewContext(req, rec)
 		authController := http.NewAuthController(mockAuthUsecase)
-		mockAuthUsecase.On("Login", mock.Anything).Return(response.SuccessLogin{}, errors.New("error something")).Once()
+		mockAuthUsecase.On("Login", mock.Anything, mock.Anything).Return(response.SuccessLogin{}, errors.New("error something")).Once()
 		err := authController.Login(c)
 		responseBody := parseResponse(rec)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(401), responseBody["code"])
 		mockAuthUsecase.AssertExpectations(t)
 	})
+	t.Run("error throttled", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestLogin), echo.POST, "/login", true, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("Login", mock.Anything, mock.Anything).Return(response.SuccessLogin{}, &domain.LoginThrottledError{RetryAfter: 1500 * time.Millisecond}).Once()
+		err := authController.Login(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(429), responseBody["code"])
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAuthController_LoginTwoFactor(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	reqBody := request.TwoFactorLoginRequest{
+		ChallengeToken: "challenge",
+		Code:           "123456",
+	}
+	requestLogin, _ := json.Marshal(reqBody)
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestLogin), echo.POST, "/login/2fa", true, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("VerifyTwoFactorLogin", reqBody, mock.Anything).Return(response.SuccessLogin{
+			ID:    dummyUser[0].ID,
+			Token: createToken(),
+		}, nil).Once()
+		err := authController.LoginTwoFactor(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error code empty", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"challenge_token": "challenge"}`, echo.POST, "/login/2fa", true, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		err := authController.LoginTwoFactor(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+	t.Run("error invalid code", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestLogin), echo.POST, "/login/2fa", true, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("VerifyTwoFactorLogin", reqBody, mock.Anything).Return(response.SuccessLogin{}, errors.New("invalid code")).Once()
+		err := authController.LoginTwoFactor(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(401), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error throttled", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestLogin), echo.POST, "/login/2fa", true, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("VerifyTwoFactorLogin", reqBody, mock.Anything).Return(response.SuccessLogin{}, &domain.LoginThrottledError{RetryAfter: 15 * time.Minute}).Once()
+		err := authController.LoginTwoFactor(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(429), responseBody["code"])
+		assert.Equal(t, "900", rec.Header().Get("Retry-After"))
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAuthController_OIDCAuthorize(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/login/oidc/google", false, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("provider")
+		c.SetParamValues("google")
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("OIDCAuthorizationURL", "google").Return("https://accounts.google.com/o/oauth2/v2/auth?state=abc", nil).Once()
+		err := authController.OIDCAuthorize(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		assert.Equal(t, "https://accounts.google.com/o/oauth2/v2/auth?state=abc", responseBody["data"].(map[string]interface{})["authorization_url"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error unknown provider", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/login/oidc/github", false, false)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("provider")
+		c.SetParamValues("github")
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("OIDCAuthorizationURL", "github").Return("", errors.New("unknown login provider github")).Once()
+		err := authController.OIDCAuthorize(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAuthController_OIDCCallback(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	reqBody := request.OIDCCallbackRequest{
+		Code:  "code",
+		State: "state",
+	}
+	requestCallback, _ := json.Marshal(reqBody)
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestCallback), echo.POST, "/login/oidc/google/callback", false, true)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("provider")
+		c.SetParamValues("google")
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("OIDCLogin", "google", reqBody, mock.Anything).Return(response.SuccessLogin{
+			ID:    dummyUser[0].ID,
+			Token: createToken(),
+		}, nil).Once()
+		err := authController.OIDCCallback(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error state empty", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"code": "code"}`, echo.POST, "/login/oidc/google/callback", false, true)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("provider")
+		c.SetParamValues("google")
+		authController := http.NewAuthController(mockAuthUsecase)
+		err := authController.OIDCCallback(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+	t.Run("error login failed", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestCallback), echo.POST, "/login/oidc/google/callback", false, true)
+		c := e.NewContext(req, rec)
+		c.SetParamNames("provider")
+		c.SetParamValues("google")
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("OIDCLogin", "google", reqBody, mock.Anything).Return(response.SuccessLogin{}, errors.New("login state expired")).Once()
+		err := authController.OIDCCallback(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(401), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
 }
 
 func TestAuthController_Register(t *testing.T) {
@@ -104,6 +262,20 @@ func TestAuthController_Register(t *testing.T) {
 		assert.Equal(t, float64(400), responseBody["code"])
 		mockAuthUsecase.AssertExpectations(t)
 	})
+	t.Run("error email invalid", func(t *testing.T) {
+		reqBody3 := reqBody
+		reqBody3.Email = "not-an-email"
+		requestRegister3, _ := json.Marshal(reqBody3)
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestRegister3), echo.POST, "/register ", true, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		err := authController.Register(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		assert.Equal(t, "email invalid", responseBody["message"])
+	})
 	t.Run("error register", func(t *testing.T) {
 		e := echo.New()
 		req, rec := makeRequestHttp(string(requestRegister), echo.POST, "/register ", true, true)
@@ -128,3 +300,232 @@ func TestAuthController_Register(t *testing.T) {
 		mockAuthUsecase.AssertExpectations(t)
 	})
 }
+
+func TestAuthController_RefreshToken(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	reqBody := request.RefreshTokenRequest{
+		RefreshToken: "dGhpcy1pcy1ub3QtYS1yZWFsLXJlZnJlc2gtdG9rZW4",
+	}
+	requestRefresh, _ := json.Marshal(reqBody)
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestRefresh), echo.POST, "/token/refresh", false, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("RefreshToken", reqBody).Return(response.SuccessLogin{
+			ID:           dummyUser[0].ID,
+			Email:        "satu@gmail.com",
+			Fullname:     "user1",
+			Username:     "usr1",
+			Token:        createToken(),
+			RefreshToken: "bmV4dC1yZWZyZXNoLXRva2Vu",
+			ExpiresIn:    900,
+		}, nil).Once()
+		err := authController.RefreshToken(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error empty refresh token", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("{}", echo.POST, "/token/refresh", false, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		err := authController.RefreshToken(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error refresh token", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestRefresh), echo.POST, "/token/refresh", false, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("RefreshToken", reqBody).Return(response.SuccessLogin{}, errors.New("refresh token reused, please login again")).Once()
+		err := authController.RefreshToken(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(401), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAuthController_Logout(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	reqBody := request.RefreshTokenRequest{
+		RefreshToken: "dGhpcy1pcy1ub3QtYS1yZWFsLXJlZnJlc2gtdG9rZW4",
+	}
+	requestLogout, _ := json.Marshal(reqBody)
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestLogout), echo.POST, "/logout", true, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("Logout", dummyUser[0].ID.String(), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), reqBody).Return(nil).Once()
+		err := middlewareToken(authController.Logout, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error logout", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestLogout), echo.POST, "/logout", true, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("Logout", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("invalid refresh token")).Once()
+		err := middlewareToken(authController.Logout, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAuthController_ForgotPassword(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	reqBody := request.ForgotPasswordRequest{Email: "satu@email.com"}
+	requestForgot, _ := json.Marshal(reqBody)
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestForgot), echo.POST, "/password/forgot", false, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("ForgotPassword", reqBody).Return(nil).Once()
+		err := authController.ForgotPassword(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error email empty", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"email": ""}`, echo.POST, "/password/forgot", false, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		err := authController.ForgotPassword(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+	t.Run("error send mail", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestForgot), echo.POST, "/password/forgot", false, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("ForgotPassword", reqBody).Return(errors.New("dial tcp: connection refused")).Once()
+		err := authController.ForgotPassword(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAuthController_ResetPassword(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	reqBody := request.ResetPasswordRequest{Token: "reset-token", NewPassword: "87654321"}
+	requestReset, _ := json.Marshal(reqBody)
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestReset), echo.POST, "/password/reset", false, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("ResetPassword", reqBody).Return(nil).Once()
+		err := authController.ResetPassword(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error password too short", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"token": "reset-token", "new_password": "123"}`, echo.POST, "/password/reset", false, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		err := authController.ResetPassword(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+	t.Run("error token used", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(string(requestReset), echo.POST, "/password/reset", false, true)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("ResetPassword", reqBody).Return(errors.New("password reset token already used")).Once()
+		err := authController.ResetPassword(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAuthController_VerifyEmail(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/verify-email?token=verify-token", false, false)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("VerifyEmail", "verify-token").Return(nil).Once()
+		err := authController.VerifyEmail(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error token empty", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/verify-email", false, false)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		err := authController.VerifyEmail(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+	t.Run("error token expired", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/verify-email?token=verify-token", false, false)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("VerifyEmail", "verify-token").Return(errors.New("email verification token expired")).Once()
+		err := authController.VerifyEmail(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
+
+func TestAuthController_SendEmailVerification(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.POST, "/user/verify-email", true, false)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("SendEmailVerification", dummyUser[0].ID.String()).Return(nil).Once()
+		err := middlewareToken(authController.SendEmailVerification, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+	t.Run("error already verified", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.POST, "/user/verify-email", true, false)
+		c := e.NewContext(req, rec)
+		authController := http.NewAuthController(mockAuthUsecase)
+		mockAuthUsecase.On("SendEmailVerification", dummyUser[0].ID.String()).Return(errors.New("email already verified")).Once()
+		err := middlewareToken(authController.SendEmailVerification, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthUsecase.AssertExpectations(t)
+	})
+}
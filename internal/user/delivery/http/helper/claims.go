@@ -0,0 +1,65 @@
+package helper
+
+import (
+	"errors"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/domain"
+	uuid "github.com/satori/go.uuid"
+)
+
+const apiKeyContextKey = "api_key"
+
+// JWTClaims are the claims of an access token. The user id is the subject.
+type JWTClaims struct {
+	Roles []string `json:"roles"`
+	jwt.StandardClaims
+}
+
+// UserID returns the subject, ParseToken has already checked it is a uuid.
+func (c *JWTClaims) UserID() string {
+	return c.Subject
+}
+
+// GetAuthClaims returns the claims AuthMiddleware stored in the context.
+// Handlers get an error instead of a panic when the route is not behind the middleware.
+func GetAuthClaims(c echo.Context) (*JWTClaims, error) {
+	token, ok := c.Get("user").(*jwt.Token)
+	if !ok || token == nil || !token.Valid {
+		return nil, errors.New("missing or invalid token")
+	}
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || uuid.FromStringOrNil(claims.Subject) == uuid.Nil {
+		return nil, errors.New("missing or invalid token")
+	}
+	return claims, nil
+}
+
+// SetAPIKey marks the request as authenticated by an API key. The key's user becomes the subject
+// of the claims, so handlers work the same for JWT and API key requests.
+func SetAPIKey(c echo.Context, key domain.APIKey) {
+	c.Set(apiKeyContextKey, key)
+	c.Set("user", &jwt.Token{
+		Valid:  true,
+		Claims: &JWTClaims{StandardClaims: jwt.StandardClaims{Subject: key.UserID.String()}},
+	})
+}
+
+// GetAPIKey returns the API key of the request, ok is false for JWT requests.
+func GetAPIKey(c echo.Context) (domain.APIKey, bool) {
+	key, ok := c.Get(apiKeyContextKey).(domain.APIKey)
+	return key, ok
+}
+
+// GetActor returns who makes the request for the audit log. The user id is empty on routes
+// without AuthMiddleware. The IP is what the IPExtractor of the server trusts, see config.InitIPExtractor.
+func GetActor(c echo.Context) domain.Actor {
+	actor := domain.Actor{
+		IP:        c.RealIP(),
+		UserAgent: c.Request().UserAgent(),
+	}
+	if claims, err := GetAuthClaims(c); err == nil {
+		actor.UserID = claims.UserID()
+	}
+	return actor
+}
@@ -0,0 +1,16 @@
+package helper
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"net/http"
+)
+
+// StatusCode returns 403 for a domain.ForbiddenError and fallback for every other error.
+func StatusCode(err error, fallback int) int {
+	var forbidden *domain.ForbiddenError
+	if errors.As(err, &forbidden) {
+		return http.StatusForbidden
+	}
+	return fallback
+}
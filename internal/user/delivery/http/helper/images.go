@@ -0,0 +1,23 @@
+package helper
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/web/response"
+)
+
+// EnterpriseImages groups the images of an enterprise by kind, the gallery in upload order.
+func EnterpriseImages(images []domain.EnterpriseImage) response.EnterpriseImagesResponse {
+	res := response.EnterpriseImagesResponse{Gallery: []response.ImageResponse{}}
+	for _, image := range images {
+		item := response.ImageResponse{ID: image.ID, URL: image.URL}
+		switch image.Kind {
+		case domain.ImageLogo:
+			res.Logo = &item
+		case domain.ImageCover:
+			res.Cover = &item
+		case domain.ImageGallery:
+			res.Gallery = append(res.Gallery, item)
+		}
+	}
+	return res
+}
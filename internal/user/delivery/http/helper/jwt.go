@@ -1,32 +1,87 @@
 package helper
 
 import (
+	"errors"
 	"github.com/golang-jwt/jwt"
 	"github.com/nrmadi02/mini-project/domain"
+	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 	"time"
 )
 
+// AccessTokenTTL is kept short because an access token is only checked against the revocation list, never refreshed.
+const AccessTokenTTL = 15 * time.Minute
+
 type GoJWT struct {
+	keys     *KeySet
+	issuer   string
+	audience string
+}
+
+func NewGoJWT(keys *KeySet, issuer string, audience string) *GoJWT {
+	return &GoJWT{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+	}
 }
 
-func NewGoJWT() *GoJWT {
-	return &GoJWT{}
+func (j *GoJWT) Keys() *KeySet {
+	return j.keys
 }
 
 func (j *GoJWT) CreateTokenJWT(user *domain.User) string {
+	roles := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, role.Name)
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"UserID":    user.ID,
-		"Roles":     user.Roles,
-		"ExpiresAt": time.Now().Add(time.Hour * 48).Unix(),
+	now := time.Now()
+	fixToken, err := j.keys.Sign(&JWTClaims{
+		Roles: roles,
+		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.NewV4().String(),
+			Subject:   user.ID.String(),
+			Issuer:    j.issuer,
+			Audience:  j.audience,
+			IssuedAt:  now.Unix(),
+			NotBefore: now.Unix(),
+			ExpiresAt: now.Add(AccessTokenTTL).Unix(),
+		},
 	})
-
-	fixToken, err := token.SignedString([]byte("220220"))
 	if err != nil {
-		panic(err.Error())
 		log.Info("error create token jwt")
+		panic(err.Error())
 	}
 
 	return fixToken
 }
+
+// ParseToken verifies the signature and every registered claim. Unlike jwt.StandardClaims.Valid,
+// exp, iat and jti are required, and iss and aud must match this API.
+func (j *GoJWT) ParseToken(auth string) (*jwt.Token, error) {
+	token, err := jwt.ParseWithClaims(auth, &JWTClaims{}, j.keys.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	switch {
+	case claims.ExpiresAt == 0:
+		return nil, errors.New("token has no expiry")
+	case claims.IssuedAt == 0:
+		return nil, errors.New("token has no issued at")
+	case claims.Id == "":
+		return nil, errors.New("token has no id")
+	case uuid.FromStringOrNil(claims.Subject) == uuid.Nil:
+		return nil, errors.New("token has no valid subject")
+	case !claims.VerifyIssuer(j.issuer, true):
+		return nil, errors.New("token issuer not accepted")
+	case !claims.VerifyAudience(j.audience, true):
+		return nil, errors.New("token audience not accepted")
+	}
+	return token, nil
+}
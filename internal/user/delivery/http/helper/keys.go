@@ -0,0 +1,270 @@
+package helper
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"math/big"
+)
+
+// minHMACSecretLength follows RFC 7518: an HS256 secret must be at least as long as the hash output.
+const minHMACSecretLength = 32
+
+// SigningKey is one key identified by its kid. Keys parsed from a public key can only verify.
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	sign   interface{}
+	verify interface{}
+}
+
+// CanSign reports whether the key holds a secret or private key.
+func (k *SigningKey) CanSign() bool {
+	return k.sign != nil
+}
+
+// NewHMACKey creates an HS256 key from a shared secret.
+func NewHMACKey(id string, secret []byte) (*SigningKey, error) {
+	if len(secret) < minHMACSecretLength {
+		return nil, fmt.Errorf("jwt key %s: HS256 secret must be at least %d bytes", id, minHMACSecretLength)
+	}
+	return &SigningKey{ID: id, Method: jwt.SigningMethodHS256, sign: secret, verify: secret}, nil
+}
+
+// ParsePrivateKeyPEM reads an RSA or P-256 private key, the algorithm is RS256 or ES256 accordingly.
+func ParsePrivateKeyPEM(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt key %s: no PEM data found", id)
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt key %s: %v", id, err)
+	}
+
+	switch private := key.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, sign: private, verify: &private.PublicKey}, nil
+	case *ecdsa.PrivateKey:
+		if private.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("jwt key %s: only P-256 is supported for ES256", id)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodES256, sign: private, verify: &private.PublicKey}, nil
+	}
+	return nil, fmt.Errorf("jwt key %s: unsupported private key type %T", id, key)
+}
+
+// ParsePublicKeyPEM reads an RSA or P-256 public key, used to keep verifying tokens after a key is rotated out.
+func ParsePublicKeyPEM(id string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwt key %s: no PEM data found", id)
+	}
+
+	var key interface{}
+	var err error
+	if block.Type == "RSA PUBLIC KEY" {
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt key %s: %v", id, err)
+	}
+
+	switch public := key.(type) {
+	case *rsa.PublicKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, verify: public}, nil
+	case *ecdsa.PublicKey:
+		if public.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("jwt key %s: only P-256 is supported for ES256", id)
+		}
+		return &SigningKey{ID: id, Method: jwt.SigningMethodES256, verify: public}, nil
+	}
+	return nil, fmt.Errorf("jwt key %s: unsupported public key type %T", id, key)
+}
+
+// KeySet signs with one active key and verifies with every key it knows, so tokens signed
+// with a previous key stay valid until they expire.
+type KeySet struct {
+	signing *SigningKey
+	keys    map[string]*SigningKey
+	order   []string
+}
+
+func NewKeySet(signing *SigningKey, verification ...*SigningKey) (*KeySet, error) {
+	if signing == nil || !signing.CanSign() {
+		return nil, errors.New("jwt signing key must hold a secret or private key")
+	}
+
+	keySet := &KeySet{signing: signing, keys: map[string]*SigningKey{}}
+	for _, key := range append([]*SigningKey{signing}, verification...) {
+		if key.ID == "" {
+			return nil, errors.New("jwt key id must not be empty")
+		}
+		if _, ok := keySet.keys[key.ID]; ok {
+			return nil, fmt.Errorf("jwt key id %s is used twice", key.ID)
+		}
+		keySet.keys[key.ID] = key
+		keySet.order = append(keySet.order, key.ID)
+	}
+	return keySet, nil
+}
+
+// Sign signs the claims with the active key and puts its kid in the header.
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.Method, claims)
+	token.Header["kid"] = k.signing.ID
+	return token.SignedString(k.signing.sign)
+}
+
+// Keyfunc picks the verification key by kid. The algorithm must be the one of that key,
+// otherwise a public RSA key could be abused as an HMAC secret.
+func (k *KeySet) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown jwt key id=%v", t.Header["kid"])
+	}
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected jwt signing method=%v", t.Header["alg"])
+	}
+	return key.verify, nil
+}
+
+// JSONWebKey is the public part of an RS256 or ES256 key as described in RFC 7517.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS lists the public keys. HS256 secrets are never published, so services that verify
+// HS256 tokens have to share the secret out of band.
+func (k *KeySet) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, id := range k.order {
+		key := k.keys[id]
+		switch public := key.verify.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Algorithm: key.Method.Alg(),
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			size := (public.Curve.Params().BitSize + 7) / 8
+			set.Keys = append(set.Keys, JSONWebKey{
+				KeyType:   "EC",
+				KeyID:     key.ID,
+				Algorithm: key.Method.Alg(),
+				Use:       "sig",
+				Curve:     public.Curve.Params().Name,
+				X:         base64.RawURLEncoding.EncodeToString(public.X.FillBytes(make([]byte, size))),
+				Y:         base64.RawURLEncoding.EncodeToString(public.Y.FillBytes(make([]byte, size))),
+			})
+		}
+	}
+	return set
+}
+
+// Keyfunc verifies tokens of another issuer, like an OpenID Connect provider, with the keys it
+// publishes. The key is picked by kid and the algorithm must match the key, as in KeySet.Keyfunc.
+func (s JSONWebKeySet) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	for _, webKey := range s.Keys {
+		if webKey.KeyID != kid || (webKey.Use != "" && webKey.Use != "sig") {
+			continue
+		}
+		key, err := webKey.verificationKey()
+		if err != nil {
+			return nil, err
+		}
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected jwt signing method=%v", t.Header["alg"])
+		}
+		return key.verify, nil
+	}
+	return nil, fmt.Errorf("unknown jwt key id=%v", t.Header["kid"])
+}
+
+// HasKey reports whether a key with the kid is in the set.
+func (s JSONWebKeySet) HasKey(kid string) bool {
+	for _, webKey := range s.Keys {
+		if webKey.KeyID == kid {
+			return true
+		}
+	}
+	return false
+}
+
+// verificationKey reads an RS256 or ES256 public key, the same kinds JWKS publishes.
+func (k JSONWebKey) verificationKey() (*SigningKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		if k.Algorithm != "" && k.Algorithm != jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("jwt key %s: unsupported algorithm %s", k.KeyID, k.Algorithm)
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %v", k.KeyID, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %v", k.KeyID, err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("jwt key %s: invalid RSA key", k.KeyID)
+		}
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+		return &SigningKey{ID: k.KeyID, Method: jwt.SigningMethodRS256, verify: public}, nil
+	case "EC":
+		if k.Curve != elliptic.P256().Params().Name || (k.Algorithm != "" && k.Algorithm != jwt.SigningMethodES256.Alg()) {
+			return nil, fmt.Errorf("jwt key %s: only P-256 is supported for ES256", k.KeyID)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %v", k.KeyID, err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %v", k.KeyID, err)
+		}
+		public := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !public.Curve.IsOnCurve(public.X, public.Y) {
+			return nil, fmt.Errorf("jwt key %s: point is not on the curve", k.KeyID)
+		}
+		return &SigningKey{ID: k.KeyID, Method: jwt.SigningMethodES256, verify: public}, nil
+	}
+	return nil, fmt.Errorf("jwt key %s: unsupported key type %s", k.KeyID, k.KeyType)
+}
@@ -0,0 +1,22 @@
+package helper
+
+import "time"
+
+// OIDCStateTTL is how long a user may take to sign in at the provider.
+const OIDCStateTTL = 10 * time.Minute
+
+// NewOIDCState works like NewRefreshToken, the state travels through the provider and back.
+func NewOIDCState() (state string, hash string, err error) {
+	return newOpaqueToken()
+}
+
+func HashOIDCState(state string) string {
+	return hashToken(state)
+}
+
+// NewOIDCSecret returns a random value for the nonce and the PKCE code verifier, both are
+// only compared and so are stored as they are.
+func NewOIDCSecret() (string, error) {
+	secret, _, err := newOpaqueToken()
+	return secret, err
+}
@@ -0,0 +1,55 @@
+package helper
+
+import (
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/web/response"
+	"strconv"
+)
+
+const (
+	DefaultPageLength = 20
+	MaxPageLength     = 100
+)
+
+// GetPageRequest reads the cursor, length and with_total query parameters of the paginated listings.
+func GetPageRequest(c echo.Context) (domain.PageRequest, error) {
+	page := domain.PageRequest{Length: DefaultPageLength}
+	if value := c.QueryParam("length"); value != "" {
+		length, err := strconv.Atoi(value)
+		if err != nil || length < 1 || length > MaxPageLength {
+			return page, errors.New("length must be between 1 and " + strconv.Itoa(MaxPageLength))
+		}
+		page.Length = length
+	}
+	if value := c.QueryParam("cursor"); value != "" {
+		cursor, err := domain.ParseCursor(value)
+		if err != nil {
+			return page, err
+		}
+		page.Cursor = &cursor
+	}
+	if value := c.QueryParam("with_total"); value != "" {
+		withTotal, err := strconv.ParseBool(value)
+		if err != nil {
+			return page, errors.New("with_total must be true or false")
+		}
+		page.WithTotal = withTotal
+	}
+	return page, nil
+}
+
+// PageMetadata is the response metadata of a page holding length rows.
+func PageMetadata(info domain.PageInfo, length int) response.PageMetadata {
+	metadata := response.PageMetadata{Length: length, TotalData: info.Total}
+	if info.Next != nil {
+		next := info.Next.Encode()
+		metadata.NextCursor = &next
+	}
+	if info.Prev != nil {
+		prev := info.Prev.Encode()
+		metadata.PrevCursor = &prev
+	}
+	return metadata
+}
@@ -0,0 +1,75 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+// PasswordResetTokenTTL is how long the link in a password reset mail stays valid.
+const PasswordResetTokenTTL = time.Hour
+
+// EmailVerificationTokenTTL is how long the link in a verification mail stays valid.
+const EmailVerificationTokenTTL = 48 * time.Hour
+
+// NewRefreshToken returns an opaque token for the client and the hash to store in the database.
+func NewRefreshToken() (token string, hash string, err error) {
+	return newOpaqueToken()
+}
+
+func HashRefreshToken(token string) string {
+	return hashToken(token)
+}
+
+// NewPasswordResetToken works like NewRefreshToken, the token is only sent by mail.
+func NewPasswordResetToken() (token string, hash string, err error) {
+	return newOpaqueToken()
+}
+
+func HashPasswordResetToken(token string) string {
+	return hashToken(token)
+}
+
+// NewEmailVerificationToken works like NewRefreshToken, the token is only sent by mail.
+func NewEmailVerificationToken() (token string, hash string, err error) {
+	return newOpaqueToken()
+}
+
+func HashEmailVerificationToken(token string) string {
+	return hashToken(token)
+}
+
+// APIKeyPrefix marks API keys, so a leaked key is easy to recognise in logs and code.
+const APIKeyPrefix = "umkm_"
+
+// NewAPIKey works like NewRefreshToken, the key is shown to the user once.
+func NewAPIKey() (key string, hash string, err error) {
+	token, _, err := newOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	key = APIKeyPrefix + token
+	return key, hashToken(key), nil
+}
+
+func HashAPIKey(key string) string {
+	return hashToken(key)
+}
+
+func newOpaqueToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
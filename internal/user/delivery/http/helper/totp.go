@@ -0,0 +1,128 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP follows RFC 6238 with the defaults every authenticator app understands:
+// SHA1, 6 digits and a 30 second period.
+const (
+	TOTPIssuer = "Mini Project UMKM"
+	TOTPPeriod = 30 * time.Second
+	totpDigits = 6
+	totpModulo = 1000000
+	// totpSkew accepts the code of the previous and next period against clock drift.
+	totpSkew = 1
+)
+
+// TwoFactorChallengeTTL is how long the second login step may take after the password was accepted.
+const TwoFactorChallengeTTL = 5 * time.Minute
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret returns a random 160 bit secret, base32 encoded for authenticator apps.
+func NewTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPProvisioningURI is the otpauth URI authenticator apps read from a QR code.
+func TOTPProvisioningURI(account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", TOTPIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(TOTPPeriod.Seconds())))
+	return "otpauth://totp/" + url.PathEscape(TOTPIssuer+":"+account) + "?" + query.Encode()
+}
+
+func TOTPStep(now time.Time) int64 {
+	return now.Unix() / int64(TOTPPeriod.Seconds())
+}
+
+// GenerateTOTP returns the code of the period now falls in.
+func GenerateTOTP(secret string, now time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+	return totpCode(key, TOTPStep(now)), nil
+}
+
+// ValidateTOTP returns the period the code belongs to, callers keep the last accepted period
+// so a code cannot be used twice.
+func ValidateTOTP(secret string, code string, now time.Time) (int64, error) {
+	// every code of an empty key is known
+	if secret == "" {
+		return 0, errors.New("no totp secret")
+	}
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, err
+	}
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, errors.New("invalid code")
+	}
+
+	step := TOTPStep(now)
+	for i := int64(-totpSkew); i <= totpSkew; i++ {
+		if hmac.Equal([]byte(totpCode(key, step+i)), []byte(code)) {
+			return step + i, nil
+		}
+	}
+	return 0, errors.New("invalid code")
+}
+
+// totpCode is the HOTP value of RFC 4226 for the counter step.
+func totpCode(key []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%totpModulo)
+}
+
+// NewRecoveryCode returns a code to show the user once, like "k3j9d-x7q2m", and the hash to store.
+func NewRecoveryCode() (code string, hash string, err error) {
+	b := make([]byte, 7)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	raw := strings.ToLower(totpEncoding.EncodeToString(b))[:10]
+	code = raw[:5] + "-" + raw[5:]
+	return code, HashRecoveryCode(code), nil
+}
+
+// HashRecoveryCode ignores case, spaces and dashes, users type recovery codes by hand.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return hashToken(code)
+}
+
+// NewTwoFactorChallenge works like NewRefreshToken, the token only carries a login to the second step.
+func NewTwoFactorChallenge() (token string, hash string, err error) {
+	return newOpaqueToken()
+}
+
+func HashTwoFactorChallenge(token string) string {
+	return hashToken(token)
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"net/http"
+)
+
+type KeyController interface {
+	JWKS(c echo.Context) error
+}
+
+type keyController struct {
+	Keys *helper.KeySet
+}
+
+func NewKeyController(keys *helper.KeySet) KeyController {
+	return keyController{
+		Keys: keys,
+	}
+}
+
+// JWKS serves the public verification keys at /.well-known/jwks.json for other services.
+// It is answered as a plain JWK Set (RFC 7517) instead of the usual response envelope,
+// because JWT libraries read this document directly.
+func (k keyController) JWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, k.Keys.JWKS())
+}
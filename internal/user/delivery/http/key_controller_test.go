@@ -0,0 +1,64 @@
+package http_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/labstack/echo/v4"
+	http2 "github.com/nrmadi02/mini-project/internal/user/delivery/http"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestKeyController_JWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rsaDER := x509.MarshalPKCS1PrivateKey(rsaKey)
+	rsaSigningKey, err := helper.ParsePrivateKeyPEM("rsa-2022", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: rsaDER}))
+	assert.NoError(t, err)
+
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	ecVerificationKey, err := helper.ParsePublicKeyPEM("ec-2021", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecDER}))
+	assert.NoError(t, err)
+
+	hmacKey, _ := helper.NewHMACKey("hs-2020", []byte("secret-for-signing-test-tokens-only"))
+	keys, err := helper.NewKeySet(rsaSigningKey, ecVerificationKey, hmacKey)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	keyController := http2.NewKeyController(keys)
+	err = keyController.JWKS(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var jwks helper.JSONWebKeySet
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jwks))
+	assert.Len(t, jwks.Keys, 2)
+
+	assert.Equal(t, "rsa-2022", jwks.Keys[0].KeyID)
+	assert.Equal(t, "RSA", jwks.Keys[0].KeyType)
+	assert.Equal(t, "RS256", jwks.Keys[0].Algorithm)
+	n, _ := base64.RawURLEncoding.DecodeString(jwks.Keys[0].N)
+	assert.Equal(t, rsaKey.N, new(big.Int).SetBytes(n))
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+
+	assert.Equal(t, "ec-2021", jwks.Keys[1].KeyID)
+	assert.Equal(t, "EC", jwks.Keys[1].KeyType)
+	assert.Equal(t, "ES256", jwks.Keys[1].Algorithm)
+	assert.Equal(t, "P-256", jwks.Keys[1].Curve)
+	x, _ := base64.RawURLEncoding.DecodeString(jwks.Keys[1].X)
+	assert.Len(t, x, 32)
+	assert.Equal(t, ecKey.X, new(big.Int).SetBytes(x))
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/web/response"
+	"net/http"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyMiddleware authenticates the request with the X-API-Key header. GET and HEAD need
+// the read scope, every other method the write scope.
+func (m *GoMiddleware) apiKeyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key, err := m.apiKeyUsecase.Authenticate(c.Request().Header.Get(APIKeyHeader))
+		if err != nil {
+			return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+		}
+
+		scope := domain.APIKeyScopeWrite
+		if method := c.Request().Method; method == http.MethodGet || method == http.MethodHead {
+			scope = domain.APIKeyScopeRead
+		}
+		if !key.HasScope(scope) {
+			return response.FailResponse(c, http.StatusForbidden, false, "api key scope "+scope+" required")
+		}
+
+		helper.SetAPIKey(c, key)
+		return next(c)
+	}
+}
+
+// RequireSession must run after AuthMiddleware. It keeps API keys away from routes that manage
+// the account itself, like passwords, two-factor authentication and the API keys.
+func (m *GoMiddleware) RequireSession() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := helper.GetAPIKey(c); ok {
+				return response.FailResponse(c, http.StatusForbidden, false, "not allowed with an api key")
+			}
+			return next(c)
+		}
+	}
+}
@@ -2,19 +2,31 @@ package middleware
 
 import (
 	"errors"
-	"fmt"
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	mid "github.com/labstack/echo/v4/middleware"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/web/response"
 	"log"
+	"net/http"
 	"os"
 )
 
 type GoMiddleware struct {
+	tokenRepository domain.TokenRepository
+	jwt             *helper.GoJWT
+	authUsecase     domain.AuthUsecase
+	apiKeyUsecase   domain.APIKeyUsecase
 }
 
-func NewGoMiddleware() *GoMiddleware {
-	return &GoMiddleware{}
+func NewGoMiddleware(tr domain.TokenRepository, jwt *helper.GoJWT, au domain.AuthUsecase, aku domain.APIKeyUsecase) *GoMiddleware {
+	return &GoMiddleware{
+		tokenRepository: tr,
+		jwt:             jwt,
+		authUsecase:     au,
+		apiKeyUsecase:   aku,
+	}
 }
 
 func (m *GoMiddleware) LogMiddleware(e *echo.Echo) {
@@ -30,24 +42,38 @@ func (m *GoMiddleware) LogMiddleware(e *echo.Echo) {
 	}))
 }
 
+// AuthMiddleware accepts a JWT access token, or an API key when the X-API-Key header is set.
 func (m *GoMiddleware) AuthMiddleware() echo.MiddlewareFunc {
-	signingKey := []byte("220220")
+	jwtAuth := m.jwtMiddleware()
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		jwtNext := jwtAuth(next)
+		apiKeyNext := m.apiKeyMiddleware(next)
+		return func(c echo.Context) error {
+			if c.Request().Header.Get(APIKeyHeader) != "" {
+				return apiKeyNext(c)
+			}
+			return jwtNext(c)
+		}
+	}
+}
 
+func (m *GoMiddleware) jwtMiddleware() echo.MiddlewareFunc {
 	config := mid.JWTConfig{
 		ParseTokenFunc: func(auth string, c echo.Context) (interface{}, error) {
-			keyFunc := func(t *jwt.Token) (interface{}, error) {
-				if t.Method.Alg() != "HS256" {
-					return nil, fmt.Errorf("unexpected jwt signing method=%v", t.Header["alg"])
-				}
-				return signingKey, nil
+			token, err := m.jwt.ParseToken(auth)
+			if err != nil {
+				return nil, err
 			}
 
-			token, err := jwt.Parse(auth, keyFunc)
+			revoked, err := m.tokenRepository.IsAccessTokenRevoked(token.Claims.(*helper.JWTClaims).Id)
 			if err != nil {
 				return nil, err
 			}
-			if !token.Valid {
-				return nil, errors.New("invalid token")
+			if revoked {
+				return nil, errors.New("token has been revoked")
+			}
+			if err = m.authUsecase.CheckUserActive(token.Claims.(*helper.JWTClaims).UserID()); err != nil {
+				return nil, err
 			}
 			return token, nil
 		},
@@ -55,3 +81,65 @@ func (m *GoMiddleware) AuthMiddleware() echo.MiddlewareFunc {
 
 	return mid.JWTWithConfig(config)
 }
+
+// RequirePermission must run after AuthMiddleware. Permissions are read from the database on
+// each request, so a role change applies without waiting for the access token to expire.
+// An API key also needs the permission as scope.
+func (m *GoMiddleware) RequirePermission(permission role.Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := helper.GetAuthClaims(c)
+			if err != nil {
+				return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+			}
+
+			allowed, err := m.authUsecase.HasPermission(claims.UserID(), permission.String())
+			if err != nil {
+				return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+			}
+			if !allowed {
+				return response.FailResponse(c, http.StatusForbidden, false, "permission "+permission.String()+" required")
+			}
+			if key, ok := helper.GetAPIKey(c); ok && !key.HasScope(permission.String()) {
+				return response.FailResponse(c, http.StatusForbidden, false, "api key scope "+permission.String()+" required")
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireVerifiedEmail must run after AuthMiddleware. It blocks users who have not yet
+// confirmed their email with the link from the verification mail.
+func (m *GoMiddleware) RequireVerifiedEmail() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := helper.GetAuthClaims(c)
+			if err != nil {
+				return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+			}
+
+			if err = m.authUsecase.CheckEmailVerified(claims.UserID()); err != nil {
+				return response.FailResponse(c, http.StatusForbidden, false, err.Error())
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireTwoFactor must run after AuthMiddleware. It blocks users whose role requires two-factor
+// authentication until they have enabled it.
+func (m *GoMiddleware) RequireTwoFactor() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := helper.GetAuthClaims(c)
+			if err != nil {
+				return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+			}
+
+			if err = m.authUsecase.CheckTwoFactor(claims.UserID()); err != nil {
+				return response.FailResponse(c, http.StatusForbidden, false, err.Error())
+			}
+			return next(c)
+		}
+	}
+}
@@ -1,9 +1,10 @@
 package http
 
 import (
-	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/web/request"
 	"github.com/nrmadi02/mini-project/web/response"
 	uuid "github.com/satori/go.uuid"
 	"math"
@@ -13,16 +14,26 @@ import (
 
 type UserController interface {
 	User(c echo.Context) error
+	UpdateProfile(c echo.Context) error
+	ChangePassword(c echo.Context) error
+	ExportData(c echo.Context) error
+	DeleteAccount(c echo.Context) error
+	SetupTwoFactor(c echo.Context) error
+	EnableTwoFactor(c echo.Context) error
+	DisableTwoFactor(c echo.Context) error
+	RegenerateRecoveryCodes(c echo.Context) error
 }
 
 type userController struct {
 	AuthUsecase   domain.AuthUsecase
+	UserUsecase   domain.UserUsecase
 	RatingUsecase domain.RatingUsecase
 }
 
-func NewUserController(au domain.AuthUsecase, ru domain.RatingUsecase) UserController {
+func NewUserController(au domain.AuthUsecase, uu domain.UserUsecase, ru domain.RatingUsecase) UserController {
 	return userController{
 		AuthUsecase:   au,
+		UserUsecase:   uu,
 		RatingUsecase: ru,
 	}
 }
@@ -38,10 +49,12 @@ func NewUserController(au domain.AuthUsecase, ru domain.RatingUsecase) UserContr
 // @Failure 401 {object} response.JSONUnauthorizedResult{}
 // @Security JWT
 func (u userController) User(c echo.Context) error {
-	jwtBearer := c.Get("user").(*jwt.Token)
-	claims := jwtBearer.Claims.(jwt.MapClaims)
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
 
-	user, favorite, enterprises, err := u.AuthUsecase.GetUserDetails(claims["UserID"].(string))
+	user, favorite, enterprises, err := u.AuthUsecase.GetUserDetails(claims.UserID())
 	if err != nil {
 		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
 	}
@@ -66,8 +79,8 @@ func (u userController) User(c echo.Context) error {
 			Address     string      `json:"address"`
 			Postcode    int         `json:"postcode"`
 			Description string      `json:"description"`
-			Latitude    string      `json:"latitude"`
-			Longitude   string      `json:"longitude"`
+			Latitude    *float64    `json:"latitude"`
+			Longitude   *float64    `json:"longitude"`
 			Status      int         `json:"status"`
 			Tags        interface{} `json:"tags"`
 			CreatedAt   time.Time   `json:"created_at"`
@@ -81,7 +94,7 @@ func (u userController) User(c echo.Context) error {
 			Address:     enterprise.Address,
 			Postcode:    enterprise.Postcode,
 			Description: enterprise.Description,
-			Status:      enterprise.Status,
+			Status:      int(enterprise.Status),
 			Tags:        enterprise.Tags,
 			UpdatedAt:   enterprise.UpdatedAt,
 			CreatedAt:   enterprise.CreatedAt,
@@ -92,15 +105,311 @@ func (u userController) User(c echo.Context) error {
 	}
 
 	res := response.UserDetailResponse{
-		Fullname:    user.Fullname,
-		Username:    user.Username,
-		Email:       user.Email,
-		ID:          user.ID,
-		Enterprises: resEnterprises,
-		Favorite:    resFavorite,
-		CreatedAt:   user.CreatedAt,
-		UpdatedAt:   user.UpdatedAt,
+		Fullname:        user.Fullname,
+		Username:        user.Username,
+		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		ID:              user.ID,
+		Enterprises:     resEnterprises,
+		Favorite:        resFavorite,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
 	}
 	return response.SuccessResponse(c, http.StatusOK, true, "success get detail user", res)
 
 }
+
+// UpdateProfile godoc
+// @Summary Update profile user by JWT Token
+// @Description Change fullname, username and email of the logged in user
+// @Tags User
+// @param data body request.UserUpdateRequest true "required"
+// @accept json
+// @Produce json
+// @Router /user [put]
+// @Success 200 {object} response.JSONSuccessResult{data=response.UserDetailResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (u userController) UpdateProfile(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	var req request.UserUpdateRequest
+	if err = c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if val, err := request.ValidateUpdate(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	user, err := u.UserUsecase.UpdateProfile(claims.UserID(), req)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	res := response.UserDetailResponse{
+		ID:              user.ID,
+		Fullname:        user.Fullname,
+		Username:        user.Username,
+		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+	return response.SuccessResponse(c, http.StatusOK, true, "success update user", res)
+}
+
+// ChangePassword godoc
+// @Summary Change password user by JWT Token
+// @Description Needs the current password, every other session has to login again
+// @Tags User
+// @param data body request.ChangePasswordRequest true "required"
+// @accept json
+// @Produce json
+// @Router /user/password [put]
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (u userController) ChangePassword(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	var req request.ChangePasswordRequest
+	if err = c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if val, err := request.ValidateChangePassword(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	if err = u.UserUsecase.ChangePassword(claims.UserID(), req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	return response.SuccessResponse(c, http.StatusOK, true, "success change password", nil)
+}
+
+// ExportData godoc
+// @Summary Export personal data by JWT Token
+// @Description Download everything stored about the logged in user: profile, enterprises, favorites, ratings and reviews
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /user/export [get]
+// @Success 200 {object} response.JSONSuccessResult{data=response.UserExportResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (u userController) ExportData(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	export, err := u.UserUsecase.ExportUserData(claims.UserID())
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	res := response.UserExportResponse{
+		ExportedAt:  export.ExportedAt,
+		Profile:     userAdminResponse(export.User),
+		Enterprises: make([]response.UserExportEnterpriseResponse, 0, len(export.Enterprises)),
+		Favorites:   make([]response.UserExportFavoriteResponse, 0, len(export.Favorite.Enterprises)),
+		Ratings:     make([]response.UserExportRatingResponse, 0, len(export.Ratings)),
+		Reviews:     make([]response.UserExportReviewResponse, 0, len(export.Reviews)),
+	}
+	for _, enterprise := range export.Enterprises {
+		tags := make([]string, 0, len(enterprise.Tags))
+		for _, tag := range enterprise.Tags {
+			tags = append(tags, tag.Name)
+		}
+		res.Enterprises = append(res.Enterprises, response.UserExportEnterpriseResponse{
+			ID:           enterprise.ID,
+			Name:         enterprise.Name,
+			NumberPhone:  enterprise.NumberPhone,
+			Address:      enterprise.Address,
+			Postcode:     enterprise.Postcode,
+			Description:  enterprise.Description,
+			Latitude:     enterprise.Latitude,
+			Longitude:    enterprise.Longitude,
+			Status:       enterprise.Status.String(),
+			StatusReason: enterprise.StatusReason,
+			Tags:         tags,
+			CreatedAt:    enterprise.CreatedAt,
+			UpdatedAt:    enterprise.UpdatedAt,
+		})
+	}
+	for _, enterprise := range export.Favorite.Enterprises {
+		res.Favorites = append(res.Favorites, response.UserExportFavoriteResponse{
+			EnterpriseID: enterprise.ID,
+			Name:         enterprise.Name,
+		})
+	}
+	for _, rating := range export.Ratings {
+		res.Ratings = append(res.Ratings, response.UserExportRatingResponse{
+			ID:           rating.ID,
+			EnterpriseID: rating.EnterpriseID,
+			Rating:       rating.Rating,
+		})
+	}
+	for _, review := range export.Reviews {
+		res.Reviews = append(res.Reviews, response.UserExportReviewResponse{
+			ID:           review.ID,
+			EnterpriseID: review.EnterpriseID,
+			Review:       review.Review,
+			CreatedAt:    review.CreatedAt,
+			UpdatedAt:    review.UpdatedAt,
+		})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="user-data-`+claims.UserID()+`.json"`)
+	return response.SuccessResponse(c, http.StatusOK, true, "success export user data", res)
+}
+
+// DeleteAccount godoc
+// @Summary Delete account by JWT Token
+// @Description Deletes the logged in user's account, enterprises, favorites and sign-in data. Ratings and reviews are kept without the user's name. Users without a password set one with /password/forgot first
+// @Tags User
+// @param data body request.DeleteAccountRequest true "current password"
+// @accept json
+// @Produce json
+// @Router /user [delete]
+// @Success 200 {object} response.JSONSuccessDeleteResult{}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (u userController) DeleteAccount(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	var req request.DeleteAccountRequest
+	if err = c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	if val, err := request.ValidateDeleteAccount(req); val == false {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	if err = u.UserUsecase.EraseAccount(claims.UserID(), helper.GetActor(c), req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	return response.SuccessDeleteResponse(c, http.StatusOK, true, "success delete account")
+}
+
+// SetupTwoFactor godoc
+// @Summary Start two-factor authentication setup
+// @Description Creates a TOTP secret, show provisioning_uri as QR code in the authenticator app and confirm a code with /user/2fa/enable
+// @Tags User
+// @accept json
+// @Produce json
+// @Router /user/2fa/setup [post]
+// @Success 200 {object} response.JSONSuccessResult{data=response.TwoFactorSetupResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (u userController) SetupTwoFactor(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+
+	res, err := u.AuthUsecase.SetupTwoFactor(claims.UserID())
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	return response.SuccessResponse(c, http.StatusOK, true, "success setup two-factor authentication", res)
+}
+
+// EnableTwoFactor godoc
+// @Summary Enable two-factor authentication
+// @Description Confirms the secret from setup with a code, the recovery codes in the response are shown only once
+// @Tags User
+// @param data body request.TwoFactorCodeRequest true "required"
+// @accept json
+// @Produce json
+// @Router /user/2fa/enable [post]
+// @Success 200 {object} response.JSONSuccessResult{data=response.RecoveryCodesResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (u userController) EnableTwoFactor(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	var req request.TwoFactorCodeRequest
+	if err = c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	codes, err := u.AuthUsecase.EnableTwoFactor(claims.UserID(), req)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	return response.SuccessResponse(c, http.StatusOK, true, "success enable two-factor authentication", response.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Needs the password and a code or recovery code, not possible when the role requires two-factor authentication
+// @Tags User
+// @param data body request.DisableTwoFactorRequest true "required"
+// @accept json
+// @Produce json
+// @Router /user/2fa/disable [post]
+// @Success 200 {object} response.JSONSuccessResult{data=interface{}}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (u userController) DisableTwoFactor(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	var req request.DisableTwoFactorRequest
+	if err = c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	if err = u.AuthUsecase.DisableTwoFactor(claims.UserID(), req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	return response.SuccessResponse(c, http.StatusOK, true, "success disable two-factor authentication", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replaces all recovery codes, needs a code from the authenticator app
+// @Tags User
+// @param data body request.TwoFactorCodeRequest true "required"
+// @accept json
+// @Produce json
+// @Router /user/2fa/recovery-codes [post]
+// @Success 200 {object} response.JSONSuccessResult{data=response.RecoveryCodesResponse}
+// @Failure 400 {object} response.JSONBadRequestResult{}
+// @Failure 401 {object} response.JSONUnauthorizedResult{}
+// @Security JWT
+func (u userController) RegenerateRecoveryCodes(c echo.Context) error {
+	claims, err := helper.GetAuthClaims(c)
+	if err != nil {
+		return response.FailResponse(c, http.StatusUnauthorized, false, err.Error())
+	}
+	var req request.TwoFactorCodeRequest
+	if err = c.Bind(&req); err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+
+	codes, err := u.AuthUsecase.RegenerateRecoveryCodes(claims.UserID(), req)
+	if err != nil {
+		return response.FailResponse(c, http.StatusBadRequest, false, err.Error())
+	}
+	return response.SuccessResponse(c, http.StatusOK, true, "success regenerate recovery codes", response.RecoveryCodesResponse{RecoveryCodes: codes})
+}
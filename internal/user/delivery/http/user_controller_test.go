@@ -6,6 +6,8 @@ import (
 	"github.com/nrmadi02/mini-project/domain"
 	"github.com/nrmadi02/mini-project/domain/mocks"
 	"github.com/nrmadi02/mini-project/internal/user/delivery/http"
+	"github.com/nrmadi02/mini-project/web/request"
+	"github.com/nrmadi02/mini-project/web/response"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -13,6 +15,8 @@ import (
 	"time"
 )
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
 var dummyEnterprise = domain.Enterprises{
 	domain.Enterprise{
 		ID:          uuid.FromStringOrNil("35d6a9a1-aa5e-41f1-9991-08878dfdf89a"),
@@ -21,8 +25,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone: "0012798232",
 		Address:     "bjb",
 		Postcode:    707722,
-		Latitude:    "4235,23",
-		Longitude:   "4225,233231",
+		Latitude:    &dummyLatitude,
+		Longitude:   &dummyLongitude,
 		Description: "testing1",
 		Status:      0,
 		Tags: domain.Tags{
@@ -43,8 +47,8 @@ var dummyEnterprise = domain.Enterprises{
 		NumberPhone:      "0012798232",
 		Address:          "bjm",
 		Postcode:         707722,
-		Latitude:         "4235,23",
-		Longitude:        "4225,233231",
+		Latitude:         &dummyLatitude,
+		Longitude:        &dummyLongitude,
 		Description:      "testing1",
 		Status:           0,
 		Tags:             nil,
@@ -93,14 +97,15 @@ var dummyFavorite = domain.Favorites{
 
 func TestUserController_User(t *testing.T) {
 	mockAuthusecase := new(mocks.AuthUsecase)
+	mockUserUsecase := new(mocks.UserUsecase)
 	mockRatingUsecase := new(mocks.RatingUsecase)
 
 	t.Run("success", func(t *testing.T) {
 		e := echo.New()
 		req, rec := makeRequestHttp("", echo.GET, "/user", true, true)
 		c := e.NewContext(req, rec)
-		userController := http.NewUserController(mockAuthusecase, mockRatingUsecase)
-		mockAuthusecase.On("GetUserDetails", mock.Anything).Return(dummyUser[0], dummyFavorite[1], domain.Enterprises{dummyEnterprise[0]}, nil).Once()
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockAuthusecase.On("GetUserDetails", dummyUser[0].ID.String()).Return(dummyUser[0], dummyFavorite[1], domain.Enterprises{dummyEnterprise[0]}, nil).Once()
 		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
 		err := middlewareToken(userController.User, c)
 		responseBody := parseResponse(rec)
@@ -113,7 +118,7 @@ func TestUserController_User(t *testing.T) {
 		e := echo.New()
 		req, rec := makeRequestHttp("", echo.GET, "/user", true, true)
 		c := e.NewContext(req, rec)
-		userController := http.NewUserController(mockAuthusecase, mockRatingUsecase)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
 		mockAuthusecase.On("GetUserDetails", mock.Anything).Return(domain.User{}, domain.Favorite{}, domain.Enterprises{}, errors.New("error something")).Once()
 		err := middlewareToken(userController.User, c)
 		responseBody := parseResponse(rec)
@@ -122,11 +127,22 @@ func TestUserController_User(t *testing.T) {
 		mockAuthusecase.AssertExpectations(t)
 		mockRatingUsecase.AssertExpectations(t)
 	})
+	t.Run("error without token", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/user", false, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		err := userController.User(c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(401), responseBody["code"])
+		mockAuthusecase.AssertExpectations(t)
+	})
 	t.Run("get without favorite", func(t *testing.T) {
 		e := echo.New()
 		req, rec := makeRequestHttp("", echo.GET, "/user", true, true)
 		c := e.NewContext(req, rec)
-		userController := http.NewUserController(mockAuthusecase, mockRatingUsecase)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
 		mockAuthusecase.On("GetUserDetails", mock.Anything).Return(dummyUser[0], domain.Favorite{}, domain.Enterprises{dummyEnterprise[0]}, nil).Once()
 		mockRatingUsecase.On("GetAverageRatingEnterprise", mock.Anything).Return(float64(3), nil).Once()
 		err := middlewareToken(userController.User, c)
@@ -137,3 +153,301 @@ func TestUserController_User(t *testing.T) {
 		mockRatingUsecase.AssertExpectations(t)
 	})
 }
+
+func TestUserController_UpdateProfile(t *testing.T) {
+	mockAuthusecase := new(mocks.AuthUsecase)
+	mockUserUsecase := new(mocks.UserUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+	updateRequest := request.UserUpdateRequest{
+		Fullname: "user satu",
+		Username: "usr1",
+		Email:    "satu@email.com",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"fullname": "user satu", "username": "usr1", "email": "satu@email.com"}`, echo.PUT, "/user", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockUserUsecase.On("UpdateProfile", dummyUser[0].ID.String(), updateRequest).Return(dummyUser[0], nil).Once()
+		err := middlewareToken(userController.UpdateProfile, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockUserUsecase.AssertExpectations(t)
+	})
+	t.Run("error validation", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"fullname": "user satu", "username": "", "email": "satu@email.com"}`, echo.PUT, "/user", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		err := middlewareToken(userController.UpdateProfile, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+	t.Run("error username used", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"fullname": "user satu", "username": "usr1", "email": "satu@email.com"}`, echo.PUT, "/user", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockUserUsecase.On("UpdateProfile", dummyUser[0].ID.String(), updateRequest).Return(domain.User{}, errors.New("username already used")).Once()
+		err := middlewareToken(userController.UpdateProfile, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+func TestUserController_ChangePassword(t *testing.T) {
+	mockAuthusecase := new(mocks.AuthUsecase)
+	mockUserUsecase := new(mocks.UserUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+	passwordRequest := request.ChangePasswordRequest{
+		CurrentPassword: "12345678",
+		NewPassword:     "87654321",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"current_password": "12345678", "new_password": "87654321"}`, echo.PUT, "/user/password", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockUserUsecase.On("ChangePassword", dummyUser[0].ID.String(), passwordRequest).Return(nil).Once()
+		err := middlewareToken(userController.ChangePassword, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockUserUsecase.AssertExpectations(t)
+	})
+	t.Run("error password too short", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"current_password": "12345678", "new_password": "123"}`, echo.PUT, "/user/password", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		err := middlewareToken(userController.ChangePassword, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+	t.Run("error current password wrong", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"current_password": "12345678", "new_password": "87654321"}`, echo.PUT, "/user/password", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockUserUsecase.On("ChangePassword", dummyUser[0].ID.String(), passwordRequest).Return(errors.New("current password wrong")).Once()
+		err := middlewareToken(userController.ChangePassword, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+func TestUserController_SetupTwoFactor(t *testing.T) {
+	mockAuthusecase := new(mocks.AuthUsecase)
+	mockUserUsecase := new(mocks.UserUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.POST, "/user/2fa/setup", true, false)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockAuthusecase.On("SetupTwoFactor", dummyUser[0].ID.String()).Return(response.TwoFactorSetupResponse{
+			Secret:          "JBSWY3DPEHPK3PXP",
+			ProvisioningURI: "otpauth://totp/Mini%20Project%20UMKM:satu@email.com?secret=JBSWY3DPEHPK3PXP",
+		}, nil).Once()
+		err := middlewareToken(userController.SetupTwoFactor, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthusecase.AssertExpectations(t)
+	})
+	t.Run("error already enabled", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.POST, "/user/2fa/setup", true, false)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockAuthusecase.On("SetupTwoFactor", dummyUser[0].ID.String()).Return(response.TwoFactorSetupResponse{}, errors.New("two-factor authentication already enabled")).Once()
+		err := middlewareToken(userController.SetupTwoFactor, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthusecase.AssertExpectations(t)
+	})
+}
+
+func TestUserController_EnableTwoFactor(t *testing.T) {
+	mockAuthusecase := new(mocks.AuthUsecase)
+	mockUserUsecase := new(mocks.UserUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"code": "123456"}`, echo.POST, "/user/2fa/enable", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockAuthusecase.On("EnableTwoFactor", dummyUser[0].ID.String(), request.TwoFactorCodeRequest{Code: "123456"}).Return([]string{"abcde-fghij"}, nil).Once()
+		err := middlewareToken(userController.EnableTwoFactor, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		assert.Equal(t, []interface{}{"abcde-fghij"}, responseBody["data"].(map[string]interface{})["recovery_codes"])
+		mockAuthusecase.AssertExpectations(t)
+	})
+	t.Run("error invalid code", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"code": "000000"}`, echo.POST, "/user/2fa/enable", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockAuthusecase.On("EnableTwoFactor", dummyUser[0].ID.String(), request.TwoFactorCodeRequest{Code: "000000"}).Return(nil, errors.New("invalid code")).Once()
+		err := middlewareToken(userController.EnableTwoFactor, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthusecase.AssertExpectations(t)
+	})
+}
+
+func TestUserController_DisableTwoFactor(t *testing.T) {
+	mockAuthusecase := new(mocks.AuthUsecase)
+	mockUserUsecase := new(mocks.UserUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+	disableRequest := request.DisableTwoFactorRequest{Password: "12345678", Code: "123456"}
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"password": "12345678", "code": "123456"}`, echo.POST, "/user/2fa/disable", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockAuthusecase.On("DisableTwoFactor", dummyUser[0].ID.String(), disableRequest).Return(nil).Once()
+		err := middlewareToken(userController.DisableTwoFactor, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthusecase.AssertExpectations(t)
+	})
+	t.Run("error required for role", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"password": "12345678", "code": "123456"}`, echo.POST, "/user/2fa/disable", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockAuthusecase.On("DisableTwoFactor", dummyUser[0].ID.String(), disableRequest).Return(errors.New("two-factor authentication is required for your role")).Once()
+		err := middlewareToken(userController.DisableTwoFactor, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthusecase.AssertExpectations(t)
+	})
+}
+
+func TestUserController_RegenerateRecoveryCodes(t *testing.T) {
+	mockAuthusecase := new(mocks.AuthUsecase)
+	mockUserUsecase := new(mocks.UserUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"code": "123456"}`, echo.POST, "/user/2fa/recovery-codes", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockAuthusecase.On("RegenerateRecoveryCodes", dummyUser[0].ID.String(), request.TwoFactorCodeRequest{Code: "123456"}).Return([]string{"abcde-fghij"}, nil).Once()
+		err := middlewareToken(userController.RegenerateRecoveryCodes, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockAuthusecase.AssertExpectations(t)
+	})
+	t.Run("error not enabled", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"code": "123456"}`, echo.POST, "/user/2fa/recovery-codes", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockAuthusecase.On("RegenerateRecoveryCodes", dummyUser[0].ID.String(), request.TwoFactorCodeRequest{Code: "123456"}).Return(nil, errors.New("two-factor authentication not enabled")).Once()
+		err := middlewareToken(userController.RegenerateRecoveryCodes, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockAuthusecase.AssertExpectations(t)
+	})
+}
+
+func TestUserController_ExportData(t *testing.T) {
+	mockAuthusecase := new(mocks.AuthUsecase)
+	mockUserUsecase := new(mocks.UserUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/user/export", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockUserUsecase.On("ExportUserData", dummyUser[0].ID.String()).Return(domain.UserExport{
+			User:        dummyUser[0],
+			Enterprises: dummyEnterprise,
+			Ratings:     dummyRating,
+		}, nil).Once()
+		err := middlewareToken(userController.ExportData, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "attachment")
+		mockUserUsecase.AssertExpectations(t)
+	})
+	t.Run("error", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp("", echo.GET, "/user/export", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockUserUsecase.On("ExportUserData", dummyUser[0].ID.String()).Return(domain.UserExport{}, errors.New("user not found")).Once()
+		err := middlewareToken(userController.ExportData, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+func TestUserController_DeleteAccount(t *testing.T) {
+	mockAuthusecase := new(mocks.AuthUsecase)
+	mockUserUsecase := new(mocks.UserUsecase)
+	mockRatingUsecase := new(mocks.RatingUsecase)
+	actor := domain.Actor{UserID: dummyUser[0].ID.String()}
+
+	t.Run("success", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"password": "12345678"}`, echo.DELETE, "/user", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockUserUsecase.On("EraseAccount", dummyUser[0].ID.String(), actor, request.DeleteAccountRequest{Password: "12345678"}).Return(nil).Once()
+		err := middlewareToken(userController.DeleteAccount, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(200), responseBody["code"])
+		mockUserUsecase.AssertExpectations(t)
+	})
+	t.Run("error password empty", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{}`, echo.DELETE, "/user", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		err := middlewareToken(userController.DeleteAccount, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+	})
+	t.Run("error password wrong", func(t *testing.T) {
+		e := echo.New()
+		req, rec := makeRequestHttp(`{"password": "wrong"}`, echo.DELETE, "/user", true, true)
+		c := e.NewContext(req, rec)
+		userController := http.NewUserController(mockAuthusecase, mockUserUsecase, mockRatingUsecase)
+		mockUserUsecase.On("EraseAccount", dummyUser[0].ID.String(), actor, request.DeleteAccountRequest{Password: "wrong"}).Return(errors.New("password wrong")).Once()
+		err := middlewareToken(userController.DeleteAccount, c)
+		responseBody := parseResponse(rec)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(400), responseBody["code"])
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"gorm.io/gorm"
+	"time"
+)
+
+// loginAttemptRepository builds its conditions from the struct, key is a reserved word
+// and gorm quotes it for the dialect in use.
+type loginAttemptRepository struct {
+	Conn *gorm.DB
+}
+
+func NewLoginAttemptRepository(Conn *gorm.DB) domain.LoginAttemptRepository {
+	return &loginAttemptRepository{Conn: Conn}
+}
+
+// Get returns an empty attempt for a key without failures.
+func (l loginAttemptRepository) Get(key string) (domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+	err := l.Conn.Where(&domain.LoginAttempt{Key: key}).First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.LoginAttempt{Key: key}, nil
+	}
+	return attempt, err
+}
+
+func (l loginAttemptRepository) RecordFailure(key string, now time.Time, window time.Duration) (domain.LoginAttempt, error) {
+	attempt, inserted, err := l.recordFailure(key, now, window)
+	if err != nil && inserted {
+		// a concurrent first failure of the key inserted the row first, count on that row.
+		attempt, _, err = l.recordFailure(key, now, window)
+	}
+	return attempt, err
+}
+
+// recordFailure counts in a single UPDATE, concurrent failures of a key cannot read the same
+// count and write back the same sum. inserted reports that the key had no row yet.
+func (l loginAttemptRepository) recordFailure(key string, now time.Time, window time.Duration) (attempt domain.LoginAttempt, inserted bool, err error) {
+	err = l.Conn.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.LoginAttempt{}).Where(&domain.LoginAttempt{Key: key}).Updates(map[string]interface{}{
+			"failures": gorm.Expr("CASE WHEN last_failure_at < ? AND (locked_until IS NULL OR locked_until <= ?) THEN 1 ELSE failures + 1 END",
+				now.Add(-window), now),
+			"last_failure_at": now,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			inserted = true
+			attempt = domain.LoginAttempt{Key: key, Failures: 1, LastFailureAt: now}
+			return tx.Create(&attempt).Error
+		}
+		return tx.Where(&domain.LoginAttempt{Key: key}).First(&attempt).Error
+	})
+	return attempt, inserted, err
+}
+
+func (l loginAttemptRepository) Lock(key string, until time.Time) error {
+	return l.Conn.Model(&domain.LoginAttempt{Key: key}).Update("locked_until", until).Error
+}
+
+func (l loginAttemptRepository) Reset(key string) error {
+	return l.Conn.Delete(&domain.LoginAttempt{Key: key}).Error
+}
+
+func (l loginAttemptRepository) FindLocked(now time.Time) (attempts []domain.LoginAttempt, err error) {
+	err = l.Conn.Where("locked_until > ?", now).Order("locked_until").Find(&attempts).Error
+	return attempts, err
+}
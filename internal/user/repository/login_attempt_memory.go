@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempt
+}
+
+// NewMemoryLoginAttemptRepository keeps the counters in this process only. It is meant for
+// tests and a single instance setup, several instances need the database store.
+func NewMemoryLoginAttemptRepository() domain.LoginAttemptRepository {
+	return &memoryLoginAttemptRepository{attempts: map[string]domain.LoginAttempt{}}
+}
+
+func (m *memoryLoginAttemptRepository) Get(key string) (domain.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if attempt, ok := m.attempts[key]; ok {
+		return attempt, nil
+	}
+	return domain.LoginAttempt{Key: key}, nil
+}
+
+func (m *memoryLoginAttemptRepository) RecordFailure(key string, now time.Time, window time.Duration) (domain.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attempt, ok := m.attempts[key]
+	if !ok {
+		attempt = domain.LoginAttempt{Key: key}
+	}
+	if now.Sub(attempt.LastFailureAt) > window && !attempt.IsLocked(now) {
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	m.attempts[key] = attempt
+	return attempt, nil
+}
+
+func (m *memoryLoginAttemptRepository) Lock(key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attempt, ok := m.attempts[key]
+	if !ok {
+		attempt = domain.LoginAttempt{Key: key}
+	}
+	attempt.LockedUntil = &until
+	m.attempts[key] = attempt
+	return nil
+}
+
+func (m *memoryLoginAttemptRepository) Reset(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts, key)
+	return nil
+}
+
+func (m *memoryLoginAttemptRepository) FindLocked(now time.Time) ([]domain.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var attempts []domain.LoginAttempt
+	for _, attempt := range m.attempts {
+		if attempt.IsLocked(now) {
+			attempts = append(attempts, attempt)
+		}
+	}
+	sort.Slice(attempts, func(i, j int) bool {
+		return attempts[i].LockedUntil.Before(*attempts[j].LockedUntil)
+	})
+	return attempts, nil
+}
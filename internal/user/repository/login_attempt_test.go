@@ -0,0 +1,202 @@
+package repository_test
+
+import (
+	"errors"
+	sqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/nrmadi02/mini-project/internal/user/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLoginAttemptRepository_Get(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	lastFailure := time.Now()
+
+	mock.ExpectQuery("SELECT * FROM `login_attempts` WHERE `login_attempts`.`key` = ? ORDER BY `login_attempts`.`key` LIMIT 1").
+		WithArgs("user:1").
+		WillReturnRows(sqlMock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}).
+			AddRow("user:1", 3, lastFailure, nil))
+	mock.ExpectQuery("SELECT * FROM `login_attempts` WHERE `login_attempts`.`key` = ? ORDER BY `login_attempts`.`key` LIMIT 1").
+		WithArgs("user:2").
+		WillReturnRows(sqlMock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}))
+
+	loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+	attempt, err := loginAttemptRepository.Get("user:1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempt.Failures)
+	attempt, err = loginAttemptRepository.Get("user:2")
+	assert.NoError(t, err)
+	assert.Equal(t, "user:2", attempt.Key)
+	assert.Equal(t, 0, attempt.Failures)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptRepository_RecordFailure(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	now := time.Now()
+	window := 15 * time.Minute
+	update := "UPDATE `login_attempts` SET `failures`=CASE WHEN last_failure_at < ? AND (locked_until IS NULL OR locked_until <= ?) THEN 1 ELSE failures + 1 END,`last_failure_at`=? WHERE `login_attempts`.`key` = ?"
+	insert := "INSERT INTO `login_attempts` (`key`,`failures`,`last_failure_at`,`locked_until`) VALUES (?,?,?,?)"
+
+	t.Run("first failure", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).
+			WithArgs(now.Add(-window), now, now, "ip:127.0.0.1").
+			WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectExec(insert).
+			WithArgs("ip:127.0.0.1", 1, now, nil).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+		attempt, err := loginAttemptRepository.RecordFailure("ip:127.0.0.1", now, window)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("counted in the update", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).
+			WithArgs(now.Add(-window), now, now, "ip:127.0.0.1").
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT * FROM `login_attempts` WHERE `login_attempts`.`key` = ? ORDER BY `login_attempts`.`key` LIMIT 1").
+			WithArgs("ip:127.0.0.1").
+			WillReturnRows(sqlMock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}).
+				AddRow("ip:127.0.0.1", 5, now, nil))
+		mock.ExpectCommit()
+
+		loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+		attempt, err := loginAttemptRepository.RecordFailure("ip:127.0.0.1", now, window)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, attempt.Failures)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("concurrent first failure", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(update).
+			WithArgs(now.Add(-window), now, now, "ip:127.0.0.1").
+			WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectExec(insert).
+			WithArgs("ip:127.0.0.1", 1, now, nil).
+			WillReturnError(errors.New("duplicate key"))
+		mock.ExpectRollback()
+		mock.ExpectBegin()
+		mock.ExpectExec(update).
+			WithArgs(now.Add(-window), now, now, "ip:127.0.0.1").
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT * FROM `login_attempts` WHERE `login_attempts`.`key` = ? ORDER BY `login_attempts`.`key` LIMIT 1").
+			WithArgs("ip:127.0.0.1").
+			WillReturnRows(sqlMock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}).
+				AddRow("ip:127.0.0.1", 2, now, nil))
+		mock.ExpectCommit()
+
+		loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+		attempt, err := loginAttemptRepository.RecordFailure("ip:127.0.0.1", now, window)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempt.Failures)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestLoginAttemptRepository_Lock(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	until := time.Now().Add(15 * time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `login_attempts` SET `locked_until`=? WHERE `key` = ?").
+		WithArgs(until, "user:1").
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+	err = loginAttemptRepository.Lock("user:1", until)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptRepository_Reset(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM `login_attempts` WHERE `login_attempts`.`key` = ?").
+		WithArgs("user:1").
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+	err = loginAttemptRepository.Reset("user:1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginAttemptRepository_FindLocked(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	now := time.Now()
+
+	mock.ExpectQuery("SELECT * FROM `login_attempts` WHERE locked_until > ? ORDER BY locked_until").
+		WithArgs(now).
+		WillReturnRows(sqlMock.NewRows([]string{"key", "failures", "last_failure_at", "locked_until"}).
+			AddRow("user:1", 5, now, now.Add(time.Minute)))
+
+	loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+	attempts, err := loginAttemptRepository.FindLocked(now)
+	assert.NoError(t, err)
+	assert.Len(t, attempts, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMemoryLoginAttemptRepository(t *testing.T) {
+	now := time.Now()
+
+	t.Run("record and reset", func(t *testing.T) {
+		loginAttemptRepository := repository.NewMemoryLoginAttemptRepository()
+		attempt, err := loginAttemptRepository.RecordFailure("user:1", now.Add(-time.Hour), 15*time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, attempt.Failures)
+		attempt, _ = loginAttemptRepository.RecordFailure("user:1", now.Add(-time.Minute), 15*time.Minute)
+		assert.Equal(t, 1, attempt.Failures, "failure outside the window is forgotten")
+		attempt, _ = loginAttemptRepository.RecordFailure("user:1", now, 15*time.Minute)
+		assert.Equal(t, 2, attempt.Failures)
+
+		assert.NoError(t, loginAttemptRepository.Reset("user:1"))
+		attempt, err = loginAttemptRepository.Get("user:1")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, attempt.Failures)
+	})
+
+	t.Run("find locked", func(t *testing.T) {
+		loginAttemptRepository := repository.NewMemoryLoginAttemptRepository()
+		_ = loginAttemptRepository.Lock("user:1", now.Add(2*time.Minute))
+		_ = loginAttemptRepository.Lock("user:2", now.Add(time.Minute))
+		_ = loginAttemptRepository.Lock("user:3", now.Add(-time.Minute))
+		attempts, err := loginAttemptRepository.FindLocked(now)
+		assert.NoError(t, err)
+		if assert.Len(t, attempts, 2) {
+			assert.Equal(t, "user:2", attempts[0].Key)
+			assert.Equal(t, "user:1", attempts[1].Key)
+		}
+	})
+}
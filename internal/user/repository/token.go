@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type tokenRepository struct {
+	Conn *gorm.DB
+}
+
+func NewTokenRepository(Conn *gorm.DB) domain.TokenRepository {
+	return &tokenRepository{Conn: Conn}
+}
+
+func (t tokenRepository) SaveRefreshToken(token domain.RefreshToken) (domain.RefreshToken, error) {
+	err := t.Conn.Create(&token).Error
+	return token, err
+}
+
+func (t tokenRepository) FindRefreshTokenByHash(hash string) (token domain.RefreshToken, err error) {
+	err = t.Conn.Where("token_hash = ?", hash).First(&token).Error
+	return token, err
+}
+
+// RotateRefreshToken revokes current and stores next in one transaction. Only one of two
+// concurrent refreshes with the same token can win, the other gets an error.
+func (t tokenRepository) RotateRefreshToken(current domain.RefreshToken, next domain.RefreshToken) (domain.RefreshToken, error) {
+	err := t.Conn.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.RefreshToken{}).Where("id = ? AND revoked_at IS NULL", current.ID).
+			Updates(map[string]interface{}{"revoked_at": time.Now(), "replaced_by_id": next.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("refresh token already used")
+		}
+		return tx.Create(&next).Error
+	})
+	return next, err
+}
+
+func (t tokenRepository) RevokeRefreshTokenFamily(familyID string) error {
+	return t.Conn.Model(&domain.RefreshToken{}).Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserRefreshTokens ends every login of the user, access tokens still run until they expire.
+func (t tokenRepository) RevokeUserRefreshTokens(userID string) error {
+	return t.Conn.Model(&domain.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (t tokenRepository) RevokeAccessToken(token domain.RevokedToken) error {
+	return t.Conn.Clauses(clause.OnConflict{DoNothing: true}).Create(&token).Error
+}
+
+func (t tokenRepository) IsAccessTokenRevoked(jti string) (bool, error) {
+	var count int64
+	err := t.Conn.Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+func (t tokenRepository) SavePasswordResetToken(token domain.PasswordResetToken) (domain.PasswordResetToken, error) {
+	err := t.Conn.Create(&token).Error
+	return token, err
+}
+
+func (t tokenRepository) FindPasswordResetTokenByHash(hash string) (token domain.PasswordResetToken, err error) {
+	err = t.Conn.Where("token_hash = ?", hash).First(&token).Error
+	return token, err
+}
+
+// UsePasswordResetToken marks the token as used together with every other open reset token
+// of the same user. Of two concurrent resets with the same token only one succeeds.
+func (t tokenRepository) UsePasswordResetToken(token domain.PasswordResetToken) error {
+	return t.Conn.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&domain.PasswordResetToken{}).Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("password reset token already used")
+		}
+		return tx.Model(&domain.PasswordResetToken{}).Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error
+	})
+}
+
+func (t tokenRepository) SaveEmailVerificationToken(token domain.EmailVerificationToken) (domain.EmailVerificationToken, error) {
+	err := t.Conn.Create(&token).Error
+	return token, err
+}
+
+func (t tokenRepository) FindEmailVerificationTokenByHash(hash string) (token domain.EmailVerificationToken, err error) {
+	err = t.Conn.Where("token_hash = ?", hash).First(&token).Error
+	return token, err
+}
+
+// UseEmailVerificationToken marks every open verification token of the user as used,
+// the token must still be open.
+func (t tokenRepository) UseEmailVerificationToken(token domain.EmailVerificationToken) error {
+	return t.Conn.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&domain.EmailVerificationToken{}).Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("email verification token already used")
+		}
+		return tx.Model(&domain.EmailVerificationToken{}).Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error
+	})
+}
+
+func (t tokenRepository) SaveTwoFactorChallenge(token domain.TwoFactorChallenge) (domain.TwoFactorChallenge, error) {
+	err := t.Conn.Create(&token).Error
+	return token, err
+}
+
+func (t tokenRepository) FindTwoFactorChallengeByHash(hash string) (token domain.TwoFactorChallenge, err error) {
+	err = t.Conn.Where("token_hash = ?", hash).First(&token).Error
+	return token, err
+}
+
+// UseTwoFactorChallenge marks the challenge as used, the challenge must still be open.
+func (t tokenRepository) UseTwoFactorChallenge(token domain.TwoFactorChallenge) error {
+	result := t.Conn.Model(&domain.TwoFactorChallenge{}).Where("id = ? AND used_at IS NULL", token.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("challenge token already used")
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes removes every recovery code of the user and stores codes instead.
+func (t tokenRepository) ReplaceRecoveryCodes(userID string, codes []domain.RecoveryCode) error {
+	return t.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode marks an open recovery code of the user with the hash as used.
+func (t tokenRepository) UseRecoveryCode(userID string, hash string) error {
+	result := t.Conn.Model(&domain.RecoveryCode{}).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("invalid recovery code")
+	}
+	return nil
+}
+
+func (t tokenRepository) SaveOIDCState(state domain.OIDCState) (domain.OIDCState, error) {
+	err := t.Conn.Create(&state).Error
+	return state, err
+}
+
+func (t tokenRepository) FindOIDCStateByHash(hash string) (state domain.OIDCState, err error) {
+	err = t.Conn.Where("state_hash = ?", hash).First(&state).Error
+	return state, err
+}
+
+// UseOIDCState marks the state as used, so the redirect from the provider can only be replayed once.
+func (t tokenRepository) UseOIDCState(state domain.OIDCState) error {
+	result := t.Conn.Model(&domain.OIDCState{}).Where("id = ? AND used_at IS NULL", state.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("login state already used")
+	}
+	return nil
+}
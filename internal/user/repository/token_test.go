@@ -0,0 +1,565 @@
+package repository_test
+
+import (
+	sqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/repository"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var dummyRefreshToken = domain.RefreshToken{
+	ID:        uuid.NewV4(),
+	UserID:    uuid.NewV4(),
+	FamilyID:  uuid.NewV4(),
+	TokenHash: "7509e5bda0c762d2bac7f90d758b5b2263fa01ccbc542ab5e3df163be08e6ca9",
+	ExpiresAt: time.Now().Add(time.Hour),
+}
+
+func TestTokenRepository_SaveRefreshToken(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `refresh_tokens` (`id`,`user_id`,`family_id`,`token_hash`,`expires_at`,`revoked_at`,`replaced_by_id`,`created_at`) VALUES (?,?,?,?,?,?,?,?)").
+		WithArgs(dummyRefreshToken.ID, dummyRefreshToken.UserID, dummyRefreshToken.FamilyID, dummyRefreshToken.TokenHash, AnyTime{}, nil, nil, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tokenRepository := repository.NewTokenRepository(db)
+	_, err = tokenRepository.SaveRefreshToken(dummyRefreshToken)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepository_FindRefreshTokenByHash(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `refresh_tokens` WHERE token_hash = ? ORDER BY `refresh_tokens`.`id` LIMIT 1").
+		WithArgs(dummyRefreshToken.TokenHash).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "user_id", "family_id", "token_hash", "expires_at"}).
+			AddRow(dummyRefreshToken.ID, dummyRefreshToken.UserID, dummyRefreshToken.FamilyID, dummyRefreshToken.TokenHash, dummyRefreshToken.ExpiresAt))
+
+	tokenRepository := repository.NewTokenRepository(db)
+	token, err := tokenRepository.FindRefreshTokenByHash(dummyRefreshToken.TokenHash)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyRefreshToken.FamilyID, token.FamilyID)
+	assert.Nil(t, token.RevokedAt)
+}
+
+func TestTokenRepository_RotateRefreshToken(t *testing.T) {
+	next := dummyRefreshToken
+	next.ID = uuid.NewV4()
+	next.TokenHash = "a8c1e9f4f3c36ecf1b4a0bcbc2a6cfc4d5d5a1f6f1f2a9f77f47ab4df7d3b0c1"
+
+	t.Run("success", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `refresh_tokens` SET `replaced_by_id`=?,`revoked_at`=? WHERE id = ? AND revoked_at IS NULL").
+			WithArgs(next.ID, AnyTime{}, dummyRefreshToken.ID).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `refresh_tokens` (`id`,`user_id`,`family_id`,`token_hash`,`expires_at`,`revoked_at`,`replaced_by_id`,`created_at`) VALUES (?,?,?,?,?,?,?,?)").
+			WithArgs(next.ID, next.UserID, next.FamilyID, next.TokenHash, AnyTime{}, nil, nil, AnyTime{}).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		_, err = tokenRepository.RotateRefreshToken(dummyRefreshToken, next)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already rotated", func(t *testing.T) {
+		dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		db := SetupDBMock(dbMock)
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `refresh_tokens` SET `replaced_by_id`=?,`revoked_at`=? WHERE id = ? AND revoked_at IS NULL").
+			WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		_, err = tokenRepository.RotateRefreshToken(dummyRefreshToken, next)
+		assert.EqualError(t, err, "refresh token already used")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTokenRepository_RevokeRefreshTokenFamily(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `refresh_tokens` SET `revoked_at`=? WHERE family_id = ? AND revoked_at IS NULL").
+		WithArgs(AnyTime{}, dummyRefreshToken.FamilyID.String()).
+		WillReturnResult(sqlMock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	tokenRepository := repository.NewTokenRepository(db)
+	err = tokenRepository.RevokeRefreshTokenFamily(dummyRefreshToken.FamilyID.String())
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepository_RevokeUserRefreshTokens(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `refresh_tokens` SET `revoked_at`=? WHERE user_id = ? AND revoked_at IS NULL").
+		WithArgs(AnyTime{}, dummyRefreshToken.UserID.String()).
+		WillReturnResult(sqlMock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	tokenRepository := repository.NewTokenRepository(db)
+	err = tokenRepository.RevokeUserRefreshTokens(dummyRefreshToken.UserID.String())
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepository_RevokeAccessToken(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	revoked := domain.RevokedToken{JTI: "jti-1", UserID: dummyRefreshToken.UserID, ExpiresAt: time.Now().Add(time.Minute)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `revoked_tokens` (`jti`,`user_id`,`expires_at`,`created_at`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `jti`=`jti`").
+		WithArgs("jti-1", revoked.UserID, AnyTime{}, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tokenRepository := repository.NewTokenRepository(db)
+	err = tokenRepository.RevokeAccessToken(revoked)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepository_IsAccessTokenRevoked(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT count(*) FROM `revoked_tokens` WHERE jti = ?").
+		WithArgs("jti-1").
+		WillReturnRows(sqlMock.NewRows([]string{"count(*)"}).AddRow(1))
+	mock.ExpectQuery("SELECT count(*) FROM `revoked_tokens` WHERE jti = ?").
+		WithArgs("jti-2").
+		WillReturnRows(sqlMock.NewRows([]string{"count(*)"}).AddRow(0))
+
+	tokenRepository := repository.NewTokenRepository(db)
+	revoked, err := tokenRepository.IsAccessTokenRevoked("jti-1")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = tokenRepository.IsAccessTokenRevoked("jti-2")
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+var dummyPasswordResetToken = domain.PasswordResetToken{
+	ID:        uuid.NewV4(),
+	UserID:    uuid.NewV4(),
+	TokenHash: "2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b",
+	ExpiresAt: time.Now().Add(time.Hour),
+}
+
+func TestTokenRepository_SavePasswordResetToken(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `password_reset_tokens` (`id`,`user_id`,`token_hash`,`expires_at`,`used_at`,`created_at`) VALUES (?,?,?,?,?,?)").
+		WithArgs(dummyPasswordResetToken.ID, dummyPasswordResetToken.UserID, dummyPasswordResetToken.TokenHash, AnyTime{}, nil, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tokenRepository := repository.NewTokenRepository(db)
+	_, err = tokenRepository.SavePasswordResetToken(dummyPasswordResetToken)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepository_FindPasswordResetTokenByHash(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `password_reset_tokens` WHERE token_hash = ? ORDER BY `password_reset_tokens`.`id` LIMIT 1").
+		WithArgs(dummyPasswordResetToken.TokenHash).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "user_id", "token_hash", "expires_at"}).
+			AddRow(dummyPasswordResetToken.ID, dummyPasswordResetToken.UserID, dummyPasswordResetToken.TokenHash, dummyPasswordResetToken.ExpiresAt))
+
+	tokenRepository := repository.NewTokenRepository(db)
+	token, err := tokenRepository.FindPasswordResetTokenByHash(dummyPasswordResetToken.TokenHash)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyPasswordResetToken.UserID, token.UserID)
+	assert.Nil(t, token.UsedAt)
+}
+
+func TestTokenRepository_UsePasswordResetToken(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	tokenRepository := repository.NewTokenRepository(db)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `password_reset_tokens` SET `used_at`=? WHERE id = ? AND used_at IS NULL").
+			WithArgs(AnyTime{}, dummyPasswordResetToken.ID).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE `password_reset_tokens` SET `used_at`=? WHERE user_id = ? AND used_at IS NULL").
+			WithArgs(AnyTime{}, dummyPasswordResetToken.UserID).
+			WillReturnResult(sqlMock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		err := tokenRepository.UsePasswordResetToken(dummyPasswordResetToken)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already used", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `password_reset_tokens` SET `used_at`=? WHERE id = ? AND used_at IS NULL").
+			WithArgs(AnyTime{}, dummyPasswordResetToken.ID).
+			WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := tokenRepository.UsePasswordResetToken(dummyPasswordResetToken)
+		assert.EqualError(t, err, "password reset token already used")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+var dummyEmailVerificationToken = domain.EmailVerificationToken{
+	ID:        uuid.NewV4(),
+	UserID:    uuid.NewV4(),
+	Email:     "satu@email.com",
+	TokenHash: "5d41402abc4b2a76b9719d911017c592ae2c1e1b8f8a4c1d2f3e4a5b6c7d8e9f",
+	ExpiresAt: time.Now().Add(time.Hour),
+}
+
+func TestTokenRepository_SaveEmailVerificationToken(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `email_verification_tokens` (`id`,`user_id`,`email`,`token_hash`,`expires_at`,`used_at`,`created_at`) VALUES (?,?,?,?,?,?,?)").
+		WithArgs(dummyEmailVerificationToken.ID, dummyEmailVerificationToken.UserID, dummyEmailVerificationToken.Email, dummyEmailVerificationToken.TokenHash, AnyTime{}, nil, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tokenRepository := repository.NewTokenRepository(db)
+	_, err = tokenRepository.SaveEmailVerificationToken(dummyEmailVerificationToken)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepository_FindEmailVerificationTokenByHash(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `email_verification_tokens` WHERE token_hash = ? ORDER BY `email_verification_tokens`.`id` LIMIT 1").
+		WithArgs(dummyEmailVerificationToken.TokenHash).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "user_id", "email", "token_hash", "expires_at"}).
+			AddRow(dummyEmailVerificationToken.ID, dummyEmailVerificationToken.UserID, dummyEmailVerificationToken.Email, dummyEmailVerificationToken.TokenHash, dummyEmailVerificationToken.ExpiresAt))
+
+	tokenRepository := repository.NewTokenRepository(db)
+	token, err := tokenRepository.FindEmailVerificationTokenByHash(dummyEmailVerificationToken.TokenHash)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyEmailVerificationToken.Email, token.Email)
+}
+
+func TestTokenRepository_UseEmailVerificationToken(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `email_verification_tokens` SET `used_at`=? WHERE id = ? AND used_at IS NULL").
+		WithArgs(AnyTime{}, dummyEmailVerificationToken.ID).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `email_verification_tokens` SET `used_at`=? WHERE user_id = ? AND used_at IS NULL").
+		WithArgs(AnyTime{}, dummyEmailVerificationToken.UserID).
+		WillReturnResult(sqlMock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tokenRepository := repository.NewTokenRepository(db)
+	err = tokenRepository.UseEmailVerificationToken(dummyEmailVerificationToken)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var dummyTwoFactorChallenge = domain.TwoFactorChallenge{
+	ID:        uuid.NewV4(),
+	UserID:    uuid.NewV4(),
+	TokenHash: "9a3b0c1e4f1d2a7c6b5e8d9f0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d",
+	ExpiresAt: time.Now().Add(5 * time.Minute),
+}
+
+func TestTokenRepository_SaveTwoFactorChallenge(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `two_factor_challenges` (`id`,`user_id`,`token_hash`,`expires_at`,`used_at`,`created_at`) VALUES (?,?,?,?,?,?)").
+		WithArgs(dummyTwoFactorChallenge.ID, dummyTwoFactorChallenge.UserID, dummyTwoFactorChallenge.TokenHash, AnyTime{}, nil, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tokenRepository := repository.NewTokenRepository(db)
+	_, err = tokenRepository.SaveTwoFactorChallenge(dummyTwoFactorChallenge)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepository_FindTwoFactorChallengeByHash(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `two_factor_challenges` WHERE token_hash = ? ORDER BY `two_factor_challenges`.`id` LIMIT 1").
+		WithArgs(dummyTwoFactorChallenge.TokenHash).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "user_id", "token_hash", "expires_at"}).
+			AddRow(dummyTwoFactorChallenge.ID, dummyTwoFactorChallenge.UserID, dummyTwoFactorChallenge.TokenHash, dummyTwoFactorChallenge.ExpiresAt))
+
+	tokenRepository := repository.NewTokenRepository(db)
+	token, err := tokenRepository.FindTwoFactorChallengeByHash(dummyTwoFactorChallenge.TokenHash)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyTwoFactorChallenge.UserID, token.UserID)
+}
+
+func TestTokenRepository_UseTwoFactorChallenge(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `two_factor_challenges` SET `used_at`=? WHERE id = ? AND used_at IS NULL").
+			WithArgs(AnyTime{}, dummyTwoFactorChallenge.ID).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		err = tokenRepository.UseTwoFactorChallenge(dummyTwoFactorChallenge)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error already used", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `two_factor_challenges` SET `used_at`=? WHERE id = ? AND used_at IS NULL").
+			WithArgs(AnyTime{}, dummyTwoFactorChallenge.ID).
+			WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		err = tokenRepository.UseTwoFactorChallenge(dummyTwoFactorChallenge)
+		assert.EqualError(t, err, "challenge token already used")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTokenRepository_ReplaceRecoveryCodes(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	userID := uuid.NewV4()
+	code := domain.RecoveryCode{ID: uuid.NewV4(), UserID: userID, CodeHash: dummyTwoFactorChallenge.TokenHash}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM `recovery_codes` WHERE user_id = ?").
+			WithArgs(userID.String()).
+			WillReturnResult(sqlMock.NewResult(0, 10))
+		mock.ExpectExec("INSERT INTO `recovery_codes` (`id`,`user_id`,`code_hash`,`used_at`,`created_at`) VALUES (?,?,?,?,?)").
+			WithArgs(code.ID, code.UserID, code.CodeHash, nil, AnyTime{}).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		err = tokenRepository.ReplaceRecoveryCodes(userID.String(), []domain.RecoveryCode{code})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success remove all", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("DELETE FROM `recovery_codes` WHERE user_id = ?").
+			WithArgs(userID.String()).
+			WillReturnResult(sqlMock.NewResult(0, 10))
+		mock.ExpectCommit()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		err = tokenRepository.ReplaceRecoveryCodes(userID.String(), nil)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTokenRepository_UseRecoveryCode(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	userID := uuid.NewV4().String()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `recovery_codes` SET `used_at`=? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL").
+			WithArgs(AnyTime{}, userID, dummyTwoFactorChallenge.TokenHash).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		err = tokenRepository.UseRecoveryCode(userID, dummyTwoFactorChallenge.TokenHash)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error used or unknown", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `recovery_codes` SET `used_at`=? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL").
+			WithArgs(AnyTime{}, userID, dummyTwoFactorChallenge.TokenHash).
+			WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		err = tokenRepository.UseRecoveryCode(userID, dummyTwoFactorChallenge.TokenHash)
+		assert.EqualError(t, err, "invalid recovery code")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+var dummyOIDCState = domain.OIDCState{
+	ID:           uuid.FromStringOrNil("7d3e1b2a-4c5f-4a6b-8d9e-0f1a2b3c4d5e"),
+	Provider:     "google",
+	StateHash:    "8c6976e5b5410415bde908bd4dee15dfb167a9c873fc4bb8a81f6f2ab448a918",
+	Nonce:        "nonce",
+	CodeVerifier: "verifier",
+	ExpiresAt:    time.Now().Add(10 * time.Minute),
+}
+
+func TestTokenRepository_SaveOIDCState(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `oidc_states` (`id`,`provider`,`state_hash`,`nonce`,`code_verifier`,`expires_at`,`used_at`,`created_at`) VALUES (?,?,?,?,?,?,?,?)").
+		WithArgs(dummyOIDCState.ID, dummyOIDCState.Provider, dummyOIDCState.StateHash, dummyOIDCState.Nonce, dummyOIDCState.CodeVerifier, AnyTime{}, nil, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tokenRepository := repository.NewTokenRepository(db)
+	_, err = tokenRepository.SaveOIDCState(dummyOIDCState)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepository_FindOIDCStateByHash(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `oidc_states` WHERE state_hash = ? ORDER BY `oidc_states`.`id` LIMIT 1").
+		WithArgs(dummyOIDCState.StateHash).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "provider", "state_hash", "nonce", "code_verifier", "expires_at"}).
+			AddRow(dummyOIDCState.ID, dummyOIDCState.Provider, dummyOIDCState.StateHash, dummyOIDCState.Nonce, dummyOIDCState.CodeVerifier, dummyOIDCState.ExpiresAt))
+
+	tokenRepository := repository.NewTokenRepository(db)
+	state, err := tokenRepository.FindOIDCStateByHash(dummyOIDCState.StateHash)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyOIDCState.CodeVerifier, state.CodeVerifier)
+}
+
+func TestTokenRepository_UseOIDCState(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `oidc_states` SET `used_at`=? WHERE id = ? AND used_at IS NULL").
+			WithArgs(AnyTime{}, dummyOIDCState.ID).
+			WillReturnResult(sqlMock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		err = tokenRepository.UseOIDCState(dummyOIDCState)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error already used", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `oidc_states` SET `used_at`=? WHERE id = ? AND used_at IS NULL").
+			WithArgs(AnyTime{}, dummyOIDCState.ID).
+			WillReturnResult(sqlMock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		tokenRepository := repository.NewTokenRepository(db)
+		err = tokenRepository.UseOIDCState(dummyOIDCState)
+		assert.EqualError(t, err, "login state already used")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
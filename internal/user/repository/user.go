@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/pagination"
 	"gorm.io/gorm"
 )
 
@@ -19,7 +20,12 @@ func (u userRepository) FindUserByEmail(email string) (user domain.User, err err
 }
 
 func (u userRepository) FindUserById(id string) (user domain.User, err error) {
-	err = u.Conn.Preload("Roles").Where("id = ?", id).First(&user).Error
+	err = u.Conn.Preload("Roles.Permissions").Where("id = ?", id).First(&user).Error
+	return user, err
+}
+
+func (u userRepository) FindUserByUsername(username string) (user domain.User, err error) {
+	err = u.Conn.Where("username = ?", username).First(&user).Error
 	return user, err
 }
 
@@ -28,7 +34,141 @@ func (u userRepository) Save(user domain.User) (domain.User, error) {
 	return user, err
 }
 
-func (u userRepository) FindAllUsers() (users domain.Users, err error) {
-	err = u.Conn.Preload("Roles").Find(&users).Error
-	return users, err
+// FindAllUsers pages the users from the oldest account.
+func (u userRepository) FindAllUsers(page domain.PageRequest) (users domain.Users, info domain.PageInfo, err error) {
+	err = pagination.Keyset{Column: "created_at"}.Apply(u.Conn.Preload("Roles"), page).Find(&users).Error
+	if err != nil {
+		return users, info, err
+	}
+	users, info = pagination.Page(users, page, func(user domain.User) domain.Cursor {
+		return domain.Cursor{Key: user.CreatedAt, ID: user.ID.String()}
+	})
+	err = pagination.Count(u.Conn.Model(&domain.User{}), page, &info)
+	return users, info, err
+}
+
+// Update writes the account fields, roles are changed with AddRole and RemoveRole.
+func (u userRepository) Update(user domain.User) (domain.User, error) {
+	err := u.Conn.Model(&user).
+		Select("fullname", "email", "username", "password", "email_verified_at", "suspended_at", "suspended_reason", "password_reset_required", "totp_secret", "totp_enabled_at", "totp_last_step", "updated_at").
+		Updates(&user).Error
+	return user, err
+}
+
+func (u userRepository) AddRole(user domain.User, role domain.Role) error {
+	return u.Conn.Model(&user).Association("Roles").Append(&role)
+}
+
+func (u userRepository) RemoveRole(user domain.User, role domain.Role) error {
+	return u.Conn.Model(&user).Association("Roles").Delete(&role)
+}
+
+// Delete removes the user with everything that only exists for them: their enterprises
+// (with the ratings, reviews, tags, favorites and status history of those enterprises),
+// their own ratings and reviews, and the data of deleteUserData.
+// Status history written by the user on other enterprises is kept as the moderation record.
+func (u userRepository) Delete(user domain.User) error {
+	return u.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := deleteEnterprises(tx, user); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&domain.RatingEnterprise{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&domain.Review{}).Error; err != nil {
+			return err
+		}
+		if err := deleteUserData(tx, user); err != nil {
+			return err
+		}
+		return tx.Delete(&user).Error
+	})
+}
+
+// Erase keeps the user's ratings and reviews on other enterprises, everything else tied to the
+// user is deleted and the row is overwritten with the anonymized user.
+func (u userRepository) Erase(user domain.User) error {
+	return u.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := deleteEnterprises(tx, user); err != nil {
+			return err
+		}
+		if err := deleteUserData(tx, user); err != nil {
+			return err
+		}
+
+		return tx.Model(&user).
+			Select("fullname", "email", "username", "password", "email_verified_at", "suspended_at", "suspended_reason", "password_reset_required", "totp_secret", "totp_enabled_at", "totp_last_step", "erased_at", "updated_at").
+			Updates(&user).Error
+	})
+}
+
+// deleteUserData removes what belongs to the user's account whether the row is deleted or
+// erased: the favorite list, roles, tokens, two-factor data, API keys, provider identities and
+// failed login counter. A new table keyed by user_id belongs in this list.
+func deleteUserData(tx *gorm.DB, user domain.User) error {
+	if err := tx.Exec("DELETE FROM enterprise_favorites WHERE favorite_id IN (SELECT id FROM favorites WHERE user_id = ?)", user.ID).Error; err != nil {
+		return err
+	}
+	for _, model := range []interface{}{
+		&domain.Favorite{},
+		&domain.RefreshToken{},
+		&domain.PasswordResetToken{},
+		&domain.EmailVerificationToken{},
+		&domain.TwoFactorChallenge{},
+		&domain.RecoveryCode{},
+		&domain.APIKey{},
+		&domain.UserIdentity{},
+	} {
+		if err := tx.Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Exec("DELETE FROM user_roles WHERE user_id = ?", user.ID).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&domain.LoginAttempt{Key: "user:" + user.ID.String()}).Error
+}
+
+// deleteEnterprises removes the user's enterprises with the ratings, reviews, tags, favorites,
+// status history and image rows of those enterprises. The image files are left to the caller.
+func deleteEnterprises(tx *gorm.DB, user domain.User) error {
+	var enterpriseIDs []string
+	if err := tx.Model(&domain.Enterprise{}).Where("user_id = ?", user.ID).Pluck("id", &enterpriseIDs).Error; err != nil {
+		return err
+	}
+	if len(enterpriseIDs) == 0 {
+		return nil
+	}
+	if err := tx.Exec("DELETE FROM enterprise_favorites WHERE enterprise_id IN ?", enterpriseIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM enterprise_tags WHERE enterprise_id IN ?", enterpriseIDs).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("enterprise_id IN ?", enterpriseIDs).Delete(&domain.RatingEnterprise{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("enterprise_id IN ?", enterpriseIDs).Delete(&domain.Review{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("enterprise_id IN ?", enterpriseIDs).Delete(&domain.EnterpriseStatusHistory{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("enterprise_id IN ?", enterpriseIDs).Delete(&domain.EnterpriseImage{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", enterpriseIDs).Delete(&domain.Enterprise{}).Error
+}
+
+// FindUserByIdentity finds the user linked to the subject at an OpenID Connect provider.
+func (u userRepository) FindUserByIdentity(provider string, subject string) (user domain.User, err error) {
+	identity := u.Conn.Model(&domain.UserIdentity{}).Select("user_id").Where("provider = ? AND subject = ?", provider, subject)
+	err = u.Conn.Preload("Roles.Permissions").Where("id = (?)", identity).First(&user).Error
+	return user, err
+}
+
+func (u userRepository) SaveIdentity(identity domain.UserIdentity) (domain.UserIdentity, error) {
+	err := u.Conn.Create(&identity).Error
+	return identity, err
 }
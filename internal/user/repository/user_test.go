@@ -71,18 +71,49 @@ func TestUserRepository_FindAllUsers(t *testing.T) {
 	}
 	db := SetupDBMock(dbMock)
 
-	mock.ExpectQuery("SELECT * FROM `users`").
+	mock.ExpectQuery("SELECT * FROM `users` ORDER BY created_at ASC,id ASC LIMIT 2").
 		WillReturnRows(sqlMock.NewRows([]string{"id", "fullname", "email", "username", "password", "created_at", "updated_at"}).
 			AddRow(dummyUser[0].ID, dummyUser[0].Fullname, dummyUser[0].Email, dummyUser[0].Username, dummyUser[0].Password, dummyUser[0].CreatedAt, dummyUser[0].UpdatedAt).
 			AddRow(dummyUser[1].ID, dummyUser[1].Fullname, dummyUser[1].Email, dummyUser[1].Username, dummyUser[1].Password, dummyUser[1].CreatedAt, dummyUser[1].UpdatedAt))
 
 	userRepository := repository.NewUserRepository(db)
-	users, err := userRepository.FindAllUsers()
+	users, info, err := userRepository.FindAllUsers(domain.PageRequest{Length: 1})
 	if err != nil {
 		assert.Error(t, err)
 	}
 	assert.NoError(t, err)
-	assert.NotNil(t, users)
+	assert.Len(t, users, 1)
+	assert.NotNil(t, info.Next)
+	assert.Nil(t, info.Prev)
+}
+
+func TestUserRepository_FindAllUsersCursor(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	createdAt := time.Date(2022, 5, 7, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT * FROM `users` WHERE (created_at > ? OR (created_at = ? AND id > ?)) ORDER BY created_at ASC,id ASC LIMIT 3").
+		WithArgs(createdAt, createdAt, dummyUser[0].ID.String()).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "fullname", "created_at"}).
+			AddRow(dummyUser[1].ID, dummyUser[1].Fullname, createdAt))
+	mock.ExpectQuery("SELECT count(*) FROM `users`").
+		WillReturnRows(sqlMock.NewRows([]string{"count"}).AddRow(2))
+
+	userRepository := repository.NewUserRepository(db)
+	users, info, err := userRepository.FindAllUsers(domain.PageRequest{
+		Cursor:    &domain.Cursor{Key: createdAt, ID: dummyUser[0].ID.String()},
+		Length:    2,
+		WithTotal: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Nil(t, info.Next)
+	assert.NotNil(t, info.Prev)
+	assert.Equal(t, 2, *info.Total)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestUserRepository_FindUserById(t *testing.T) {
@@ -135,8 +166,8 @@ func TestUserRepository_Save(t *testing.T) {
 	db := SetupDBMock(dbMock)
 
 	mock.ExpectBegin()
-	mock.ExpectExec("INSERT INTO `users` (`id`,`fullname`,`email`,`username`,`password`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?)").
-		WithArgs(dummyUser[0].ID, dummyUser[0].Fullname, dummyUser[0].Email, dummyUser[0].Username, dummyUser[0].Password, AnyTime{}, AnyTime{}).WillReturnResult(sqlMock.NewErrorResult(nil))
+	mock.ExpectExec("INSERT INTO `users` (`id`,`fullname`,`email`,`username`,`password`,`email_verified_at`,`suspended_at`,`suspended_reason`,`password_reset_required`,`totp_secret`,`totp_enabled_at`,`totp_last_step`,`erased_at`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)").
+		WithArgs(dummyUser[0].ID, dummyUser[0].Fullname, dummyUser[0].Email, dummyUser[0].Username, dummyUser[0].Password, nil, nil, "", false, "", nil, 0, nil, AnyTime{}, AnyTime{}).WillReturnResult(sqlMock.NewErrorResult(nil))
 	mock.ExpectCommit()
 
 	userRepository := repository.NewUserRepository(db)
@@ -147,3 +178,176 @@ func TestUserRepository_Save(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
 }
+
+func TestUserRepository_FindUserByUsername(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+
+	mock.ExpectQuery("SELECT * FROM `users` WHERE username = ? ORDER BY `users`.`id` LIMIT 1").
+		WithArgs(dummyUser[0].Username).
+		WillReturnRows(sqlMock.NewRows([]string{"id", "fullname", "email", "username", "password"}).
+			AddRow(dummyUser[0].ID, dummyUser[0].Fullname, dummyUser[0].Email, dummyUser[0].Username, dummyUser[0].Password))
+
+	userRepository := repository.NewUserRepository(db)
+	user, err := userRepository.FindUserByUsername(dummyUser[0].Username)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyUser[0].Username, user.Username)
+}
+
+func TestUserRepository_Update(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	user := dummyUser[0]
+	user.ID = uuid.NewV4()
+	user.PasswordResetRequired = true
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `users` SET `fullname`=?,`email`=?,`username`=?,`password`=?,`email_verified_at`=?,`suspended_at`=?,`suspended_reason`=?,`password_reset_required`=?,`totp_secret`=?,`totp_enabled_at`=?,`totp_last_step`=?,`updated_at`=? WHERE `id` = ?").
+		WithArgs(dummyUser[0].Fullname, dummyUser[0].Email, dummyUser[0].Username, dummyUser[0].Password, nil, nil, "", true, "", nil, 0, AnyTime{}, user.ID).
+		WillReturnResult(sqlMock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	userRepository := repository.NewUserRepository(db)
+	res, err := userRepository.Update(user)
+	assert.NoError(t, err)
+	assert.True(t, res.PasswordResetRequired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// expectDeleteUserData expects the account data that Delete and Erase both remove.
+func expectDeleteUserData(mock sqlMock.Sqlmock, user domain.User) {
+	mock.ExpectExec("DELETE FROM enterprise_favorites WHERE favorite_id IN (SELECT id FROM favorites WHERE user_id = ?)").
+		WithArgs(user.ID).WillReturnResult(sqlMock.NewResult(0, 1))
+	for _, table := range []string{"favorites", "refresh_tokens", "password_reset_tokens", "email_verification_tokens", "two_factor_challenges", "recovery_codes", "api_keys", "user_identities"} {
+		mock.ExpectExec("DELETE FROM `" + table + "` WHERE user_id = ?").
+			WithArgs(user.ID).WillReturnResult(sqlMock.NewResult(0, 1))
+	}
+	mock.ExpectExec("DELETE FROM user_roles WHERE user_id = ?").
+		WithArgs(user.ID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `login_attempts` WHERE `login_attempts`.`key` = ?").
+		WithArgs("user:" + user.ID.String()).WillReturnResult(sqlMock.NewResult(0, 0))
+}
+
+func TestUserRepository_Delete(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	user := dummyUser[0]
+	user.ID = uuid.NewV4()
+	enterpriseID := uuid.NewV4().String()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id` FROM `enterprises` WHERE user_id = ?").
+		WithArgs(user.ID).
+		WillReturnRows(sqlMock.NewRows([]string{"id"}).AddRow(enterpriseID))
+	mock.ExpectExec("DELETE FROM enterprise_favorites WHERE enterprise_id IN (?)").
+		WithArgs(enterpriseID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM enterprise_tags WHERE enterprise_id IN (?)").
+		WithArgs(enterpriseID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `rating_enterprises` WHERE enterprise_id IN (?)").
+		WithArgs(enterpriseID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `reviews` WHERE enterprise_id IN (?)").
+		WithArgs(enterpriseID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `enterprise_status_histories` WHERE enterprise_id IN (?)").
+		WithArgs(enterpriseID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `enterprise_images` WHERE enterprise_id IN (?)").
+		WithArgs(enterpriseID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `enterprises` WHERE id IN (?)").
+		WithArgs(enterpriseID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `rating_enterprises` WHERE user_id = ?").
+		WithArgs(user.ID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM `reviews` WHERE user_id = ?").
+		WithArgs(user.ID).WillReturnResult(sqlMock.NewResult(0, 1))
+	expectDeleteUserData(mock, user)
+	mock.ExpectExec("DELETE FROM `users` WHERE `users`.`id` = ?").
+		WithArgs(user.ID).WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	userRepository := repository.NewUserRepository(db)
+	err = userRepository.Delete(user)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Erase(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	user := dummyUser[0]
+	user.ID = uuid.NewV4()
+	user = user.Anonymize(time.Now())
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id` FROM `enterprises` WHERE user_id = ?").
+		WithArgs(user.ID).
+		WillReturnRows(sqlMock.NewRows([]string{"id"}))
+	expectDeleteUserData(mock, user)
+	mock.ExpectExec("UPDATE `users` SET `fullname`=?,`email`=?,`username`=?,`password`=?,`email_verified_at`=?,`suspended_at`=?,`suspended_reason`=?,`password_reset_required`=?,`totp_secret`=?,`totp_enabled_at`=?,`totp_last_step`=?,`erased_at`=?,`updated_at`=? WHERE `id` = ?").
+		WithArgs("Deleted user", "", "deleted-"+user.ID.String(), "", nil, nil, "", false, "", nil, 0, AnyTime{}, AnyTime{}, user.ID).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	userRepository := repository.NewUserRepository(db)
+	err = userRepository.Erase(user)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_FindUserByIdentity(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	user := dummyUser[0]
+	user.ID = uuid.NewV4()
+
+	mock.ExpectQuery("SELECT * FROM `users` WHERE id = (SELECT `user_id` FROM `user_identities` WHERE provider = ? AND subject = ?) ORDER BY `users`.`id` LIMIT 1").
+		WithArgs("google", "248289761001").
+		WillReturnRows(sqlMock.NewRows([]string{"id", "email"}).AddRow(user.ID, user.Email))
+	mock.ExpectQuery("SELECT * FROM `user_roles` WHERE `user_roles`.`user_id` = ?").
+		WithArgs(user.ID).
+		WillReturnRows(sqlMock.NewRows([]string{"user_id", "role_id"}))
+
+	userRepository := repository.NewUserRepository(db)
+	res, err := userRepository.FindUserByIdentity("google", "248289761001")
+	assert.NoError(t, err)
+	assert.Equal(t, user.Email, res.Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_SaveIdentity(t *testing.T) {
+	dbMock, mock, err := sqlMock.New(sqlMock.QueryMatcherOption(sqlMock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	db := SetupDBMock(dbMock)
+	identity := domain.UserIdentity{
+		ID:       uuid.NewV4(),
+		UserID:   dummyUser[0].ID,
+		Provider: "google",
+		Subject:  "248289761001",
+		Email:    dummyUser[0].Email,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `user_identities` (`id`,`user_id`,`provider`,`subject`,`email`,`created_at`) VALUES (?,?,?,?,?,?)").
+		WithArgs(identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email, AnyTime{}).
+		WillReturnResult(sqlMock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	userRepository := repository.NewUserRepository(db)
+	_, err = userRepository.SaveIdentity(identity)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
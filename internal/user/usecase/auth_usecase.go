@@ -8,49 +8,227 @@ import (
 	request2 "github.com/nrmadi02/mini-project/web/request"
 	"github.com/nrmadi02/mini-project/web/response"
 	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
 )
 
 type authUsecase struct {
-	userRepository       domain.UserRepository
-	roleRepository       domain.RoleRepository
-	favoriteRepository   domain.FavoriteRepository
-	enterpriseRepository domain.EnterpriseRepository
+	userRepository         domain.UserRepository
+	roleRepository         domain.RoleRepository
+	favoriteRepository     domain.FavoriteRepository
+	enterpriseRepository   domain.EnterpriseRepository
+	tokenRepository        domain.TokenRepository
+	jwt                    *helper.GoJWT
+	mailer                 domain.Mailer
+	loginAttemptRepository domain.LoginAttemptRepository
+	twoFactorRoles         []string
+	oidcProviders          []domain.OIDCProvider
+	audit                  domain.AuditUsecase
 }
 
-func NewAuthUsecase(ur domain.UserRepository, rr domain.RoleRepository, fr domain.FavoriteRepository, er domain.EnterpriseRepository) domain.AuthUsecase {
+func NewAuthUsecase(ur domain.UserRepository, rr domain.RoleRepository, fr domain.FavoriteRepository, er domain.EnterpriseRepository, tr domain.TokenRepository, jwt *helper.GoJWT, mailer domain.Mailer, lr domain.LoginAttemptRepository, twoFactorRoles []string, oidcProviders []domain.OIDCProvider, audit domain.AuditUsecase) domain.AuthUsecase {
 	return authUsecase{
-		userRepository:       ur,
-		roleRepository:       rr,
-		favoriteRepository:   fr,
-		enterpriseRepository: er,
+		userRepository:         ur,
+		roleRepository:         rr,
+		favoriteRepository:     fr,
+		enterpriseRepository:   er,
+		tokenRepository:        tr,
+		jwt:                    jwt,
+		mailer:                 mailer,
+		loginAttemptRepository: lr,
+		twoFactorRoles:         twoFactorRoles,
+		oidcProviders:          oidcProviders,
+		audit:                  audit,
 	}
 }
 
-func (a authUsecase) Login(request request2.LoginRequest) (response.SuccessLogin, error) {
+// errLoginFailed answers an unknown email and a wrong password alike, so login cannot be used
+// to find out which emails are registered.
+var errLoginFailed = errors.New("email or password wrong")
+
+// dummyPasswordHash is compared with the password of an unknown email, so that answer takes as
+// long as a wrong password.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// Login counts failed logins per account and per client IP. Past a few failures the next try has
+// to wait, too many failures lock the account or IP for a while, see login_throttle.go.
+func (a authUsecase) Login(request request2.LoginRequest, actor domain.Actor) (response.SuccessLogin, error) {
+	now := time.Now()
+	ipKey := loginIPKey(actor.IP)
+	if err := a.checkLoginAttempt(ipKey, now); err != nil {
+		return response.SuccessLogin{}, err
+	}
+
 	user, err := a.userRepository.FindUserByEmail(request.Email)
 	if err != nil {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(request.Password))
+		a.recordLoginFailed(actor, "", map[string]interface{}{"email": request.Email, "reason": "unknown email"})
+		if _, err := a.recordLoginFailure(ipKey, loginMaxIPFailures, now); err != nil {
+			return response.SuccessLogin{}, err
+		}
+		return response.SuccessLogin{}, errLoginFailed
+	}
+	userKey := loginUserKey(user.ID.String())
+	if err = a.checkLoginAttempt(userKey, now); err != nil {
 		return response.SuccessLogin{}, err
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password))
 	if err != nil {
-		return response.SuccessLogin{}, errors.New("password wrong")
+		a.recordLoginFailed(actor, user.ID.String(), map[string]interface{}{"reason": "wrong password"})
+		if _, err := a.recordLoginFailure(ipKey, loginMaxIPFailures, now); err != nil {
+			return response.SuccessLogin{}, err
+		}
+		locked, err := a.recordLoginFailure(userKey, loginMaxUserFailures, now)
+		if err != nil {
+			return response.SuccessLogin{}, err
+		}
+		if locked {
+			return response.SuccessLogin{}, &domain.LoginThrottledError{RetryAfter: loginLockDuration}
+		}
+		return response.SuccessLogin{}, errLoginFailed
+	}
+	if err = user.CheckActive(); err != nil {
+		return response.SuccessLogin{}, err
+	}
+	if user.PasswordResetRequired {
+		return response.SuccessLogin{}, errors.New("password reset required")
+	}
+	// the failures are only forgiven after the second factor, see VerifyTwoFactorLogin
+	if user.TwoFactorEnabled() {
+		return a.twoFactorChallenge(user)
+	}
+	if err = a.loginAttemptRepository.Reset(userKey); err != nil {
+		return response.SuccessLogin{}, err
+	}
+
+	a.recordLogin(actor, user, "password")
+	return a.startSession(user)
+}
+
+// recordLogin writes the audit entry of a successful login, the user is the actor.
+func (a authUsecase) recordLogin(actor domain.Actor, user domain.User, method string) {
+	actor.UserID = user.ID.String()
+	a.audit.Record(actor, domain.AuditLogin, domain.AuditTargetUser, user.ID.String(), nil, map[string]interface{}{"method": method})
+}
+
+// recordLoginFailed writes the audit entry of a failed login, userID is empty for an unknown email.
+func (a authUsecase) recordLoginFailed(actor domain.Actor, userID string, details map[string]interface{}) {
+	a.audit.Record(actor, domain.AuditLoginFailed, domain.AuditTargetUser, userID, nil, details)
+}
+
+// startSession issues the tokens of a new login, with a new refresh token family.
+func (a authUsecase) startSession(user domain.User) (response.SuccessLogin, error) {
+	refreshToken, err := a.newRefreshToken(user, uuid.NewV4())
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+	_, err = a.tokenRepository.SaveRefreshToken(refreshToken.RefreshToken)
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+
+	return a.successLogin(user, refreshToken.token), nil
+}
+
+// RefreshToken trades a refresh token for a new token pair. The old refresh token stops working;
+// presenting it again is treated as theft and ends every session of that login.
+func (a authUsecase) RefreshToken(request request2.RefreshTokenRequest) (response.SuccessLogin, error) {
+	current, err := a.tokenRepository.FindRefreshTokenByHash(helper.HashRefreshToken(request.RefreshToken))
+	if err != nil {
+		return response.SuccessLogin{}, errors.New("invalid refresh token")
+	}
+	if current.RevokedAt != nil {
+		_ = a.tokenRepository.RevokeRefreshTokenFamily(current.FamilyID.String())
+		return response.SuccessLogin{}, errors.New("refresh token reused, please login again")
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return response.SuccessLogin{}, errors.New("refresh token expired")
+	}
+
+	user, err := a.userRepository.FindUserById(current.UserID.String())
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+	if err = user.CheckActive(); err != nil {
+		return response.SuccessLogin{}, err
 	}
 
-	jwt := helper.NewGoJWT()
-	token := jwt.CreateTokenJWT(&user)
+	next, err := a.newRefreshToken(user, current.FamilyID)
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+	_, err = a.tokenRepository.RotateRefreshToken(current, next.RefreshToken)
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+
+	return a.successLogin(user, next.token), nil
+}
+
+// Logout revokes the access token in use and, when given, the refresh token chain of the same user.
+func (a authUsecase) Logout(userID string, jti string, expiresAt time.Time, request request2.RefreshTokenRequest) error {
+	user, err := uuid.FromString(userID)
+	if err != nil {
+		return err
+	}
+	if jti == "" {
+		return errors.New("token has no id")
+	}
+
+	err = a.tokenRepository.RevokeAccessToken(domain.RevokedToken{
+		JTI:       jti,
+		UserID:    user,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
 
-	responseLogin := response.SuccessLogin{
-		ID:       user.ID,
-		Username: user.Username,
-		Fullname: user.Fullname,
-		Email:    user.Email,
-		Token:    token,
+	if request.RefreshToken == "" {
+		return nil
 	}
+	refreshToken, err := a.tokenRepository.FindRefreshTokenByHash(helper.HashRefreshToken(request.RefreshToken))
+	if err != nil || refreshToken.UserID != user {
+		return errors.New("invalid refresh token")
+	}
+	return a.tokenRepository.RevokeRefreshTokenFamily(refreshToken.FamilyID.String())
+}
 
-	return responseLogin, nil
+type issuedRefreshToken struct {
+	domain.RefreshToken
+	token string
+}
+
+func (a authUsecase) newRefreshToken(user domain.User, familyID uuid.UUID) (issuedRefreshToken, error) {
+	token, hash, err := helper.NewRefreshToken()
+	if err != nil {
+		return issuedRefreshToken{}, err
+	}
+	return issuedRefreshToken{
+		RefreshToken: domain.RefreshToken{
+			ID:        uuid.NewV4(),
+			UserID:    user.ID,
+			FamilyID:  familyID,
+			TokenHash: hash,
+			ExpiresAt: time.Now().Add(helper.RefreshTokenTTL),
+		},
+		token: token,
+	}, nil
+}
 
+func (a authUsecase) successLogin(user domain.User, refreshToken string) response.SuccessLogin {
+	return response.SuccessLogin{
+		ID:           user.ID,
+		Username:     user.Username,
+		Fullname:     user.Fullname,
+		Email:        user.Email,
+		Token:        a.jwt.CreateTokenJWT(&user),
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(helper.AccessTokenTTL.Seconds()),
+	}
 }
 
 func (a authUsecase) Register(request request2.UserCreateRequest) (domain.User, error) {
@@ -86,6 +264,11 @@ func (a authUsecase) Register(request request2.UserCreateRequest) (domain.User,
 	}
 	_, _ = a.favoriteRepository.Add(favorite)
 
+	// the account exists either way, a lost mail can be sent again with SendEmailVerification
+	if err = a.sendEmailVerification(user); err != nil {
+		log.WithField("user_id", user.ID.String()).Warn("send email verification: " + err.Error())
+	}
+
 	return user, nil
 
 }
@@ -117,3 +300,212 @@ func (a authUsecase) CheckIfUserIsAdmin(id string) (bool, error) {
 	return false, nil
 
 }
+
+func (a authUsecase) HasPermission(id string, permission string) (bool, error) {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return false, err
+	}
+	return user.HasPermission(permission), nil
+}
+
+// CheckUserActive is called on every authenticated request, so a suspension takes effect
+// before the access token expires.
+func (a authUsecase) CheckUserActive(id string) error {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return err
+	}
+	return user.CheckActive()
+}
+
+// ForgotPassword mails a reset token when the email belongs to a user. An unknown email is not
+// an error, so the endpoint cannot be used to find out which emails are registered.
+func (a authUsecase) ForgotPassword(request request2.ForgotPasswordRequest) error {
+	user, err := a.userRepository.FindUserByEmail(request.Email)
+	if err != nil {
+		return nil
+	}
+
+	token, hash, err := helper.NewPasswordResetToken()
+	if err != nil {
+		return err
+	}
+	_, err = a.tokenRepository.SavePasswordResetToken(domain.PasswordResetToken{
+		ID:        uuid.NewV4(),
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(helper.PasswordResetTokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	body := "Hi " + user.Fullname + ",\n\n" +
+		"use this token to reset your password, it is valid for " + helper.PasswordResetTokenTTL.String() + ":\n\n" +
+		token + "\n\n" +
+		"If you did not ask for a new password you can ignore this mail."
+	return a.mailer.Send(user.Email, "Reset your password", body)
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword. Every session of the
+// user ends, and a reset required by an admin is done.
+func (a authUsecase) ResetPassword(request request2.ResetPasswordRequest) error {
+	token, err := a.tokenRepository.FindPasswordResetTokenByHash(helper.HashPasswordResetToken(request.Token))
+	if err != nil {
+		return errors.New("invalid password reset token")
+	}
+	if token.UsedAt != nil {
+		return errors.New("password reset token already used")
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return errors.New("password reset token expired")
+	}
+
+	user, err := a.userRepository.FindUserById(token.UserID.String())
+	if err != nil {
+		return err
+	}
+	password, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	if err = a.tokenRepository.UsePasswordResetToken(token); err != nil {
+		return err
+	}
+	user.Password = string(password)
+	user.PasswordResetRequired = false
+	if _, err = a.userRepository.Update(user); err != nil {
+		return err
+	}
+	if err = a.tokenRepository.RevokeUserRefreshTokens(user.ID.String()); err != nil {
+		return err
+	}
+	// whoever resets the password owns the mailbox, a lockout is not needed anymore
+	if err = a.clearLoginLock(user); err != nil {
+		log.WithField("user_id", user.ID.String()).Warn("clear login lock: " + err.Error())
+	}
+	return nil
+}
+
+// SendEmailVerification mails a new verification token to the current email of the user.
+func (a authUsecase) SendEmailVerification(id string) error {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return errors.New("email already verified")
+	}
+	return a.sendEmailVerification(user)
+}
+
+func (a authUsecase) sendEmailVerification(user domain.User) error {
+	token, hash, err := helper.NewEmailVerificationToken()
+	if err != nil {
+		return err
+	}
+	_, err = a.tokenRepository.SaveEmailVerificationToken(domain.EmailVerificationToken{
+		ID:        uuid.NewV4(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(helper.EmailVerificationTokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	body := "Hi " + user.Fullname + ",\n\n" +
+		"confirm your email with this token, it is valid for " + helper.EmailVerificationTokenTTL.String() + ":\n\n" +
+		token + "\n\n" +
+		"Open GET /api/v1/verify-email?token=<token> or paste it into the app."
+	return a.mailer.Send(user.Email, "Verify your email", body)
+}
+
+// VerifyEmail marks the email of the token as verified. A token for an email the user has
+// changed since is rejected.
+func (a authUsecase) VerifyEmail(token string) error {
+	verification, err := a.tokenRepository.FindEmailVerificationTokenByHash(helper.HashEmailVerificationToken(token))
+	if err != nil {
+		return errors.New("invalid email verification token")
+	}
+	if verification.UsedAt != nil {
+		return errors.New("email verification token already used")
+	}
+	if time.Now().After(verification.ExpiresAt) {
+		return errors.New("email verification token expired")
+	}
+
+	user, err := a.userRepository.FindUserById(verification.UserID.String())
+	if err != nil {
+		return err
+	}
+	if user.Email != verification.Email {
+		return errors.New("email has changed, request a new verification mail")
+	}
+
+	if err = a.tokenRepository.UseEmailVerificationToken(verification); err != nil {
+		return err
+	}
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	_, err = a.userRepository.Update(user)
+	return err
+}
+
+func (a authUsecase) CheckEmailVerified(id string) error {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return err
+	}
+	return user.CheckEmailVerified()
+}
+
+// GetLockedAccounts lists the users that are locked out after too many failed logins.
+func (a authUsecase) GetLockedAccounts() ([]domain.LockedAccount, error) {
+	now := time.Now()
+	attempts, err := a.loginAttemptRepository.FindLocked(now)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := []domain.LockedAccount{}
+	for _, attempt := range attempts {
+		if !strings.HasPrefix(attempt.Key, loginUserKeyPrefix) {
+			continue
+		}
+		user, err := a.userRepository.FindUserById(strings.TrimPrefix(attempt.Key, loginUserKeyPrefix))
+		if err != nil {
+			continue
+		}
+		accounts = append(accounts, domain.LockedAccount{
+			User:        user,
+			Failures:    attempt.Failures,
+			LockedUntil: *attempt.LockedUntil,
+		})
+	}
+	return accounts, nil
+}
+
+// UnlockAccount lifts the lockout of the user before it expires and lets the user know.
+func (a authUsecase) UnlockAccount(id string, actor domain.Actor) error {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return err
+	}
+	attempt, err := a.loginAttemptRepository.Get(loginUserKey(user.ID.String()))
+	if err != nil {
+		return err
+	}
+	if !attempt.IsLocked(time.Now()) {
+		return errors.New("account is not locked")
+	}
+	if err = a.clearLoginLock(user); err != nil {
+		return err
+	}
+	a.audit.Record(actor, domain.AuditAccountUnlock, domain.AuditTargetUser, id,
+		map[string]interface{}{"locked_until": attempt.LockedUntil}, map[string]interface{}{"locked_until": nil})
+	return nil
+}
@@ -2,66 +2,413 @@ package usecase_test
 
 import (
 	"errors"
+	"fmt"
 	"github.com/nrmadi02/mini-project/domain"
 	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/mail"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/internal/user/repository"
 	"github.com/nrmadi02/mini-project/internal/user/usecase"
 	"github.com/nrmadi02/mini-project/web/request"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
 
+var dummyLatitude, dummyLongitude = -3.442821, 114.740106
+
+var signingKey, _ = helper.NewHMACKey("test", []byte("secret-for-signing-test-tokens-only"))
+var keySet, _ = helper.NewKeySet(signingKey)
+var goJWT = helper.NewGoJWT(keySet, "test", "test")
+
+// newAuditUsecase accepts every audit entry, tests of an entry assert it on their own mock.
+func newAuditUsecase() *mocks.AuditUsecase {
+	auditUsecase := new(mocks.AuditUsecase)
+	auditUsecase.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+	return auditUsecase
+}
+
+// burstLoginAttempts holds back the first failure of every login until all logins of the
+// burst have arrived.
+type burstLoginAttempts struct {
+	domain.LoginAttemptRepository
+	arrived sync.WaitGroup
+}
+
+func (b *burstLoginAttempts) RecordFailure(key string, now time.Time, window time.Duration) (domain.LoginAttempt, error) {
+	if strings.HasPrefix(key, "ip:") {
+		b.arrived.Done()
+		b.arrived.Wait()
+	}
+	return b.LoginAttemptRepository.RecordFailure(key, now, window)
+}
+
 func TestAuthUsecase_Login(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 	mockRoleRepository := new(mocks.RoleRepository)
 	mockFavoriteRepository := new(mocks.FavoriteRepository)
 	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
 
 	t.Run("success", func(t *testing.T) {
 		req := request.LoginRequest{
 			Email:    "satu@email.com",
 			Password: "12345678",
 		}
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
-		res, err := uc.Login(req)
+		mockTokenRepository.On("SaveRefreshToken", mock.MatchedBy(func(token domain.RefreshToken) bool {
+			return token.UserID == dummyUser[0].ID && token.FamilyID != uuid.Nil && len(token.TokenHash) == 64
+		})).Return(domain.RefreshToken{}, nil).Once()
+		res, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
 		assert.NoError(t, err)
-		assert.NotNil(t, res)
+		assert.NotEmpty(t, res.Token)
+		assert.NotEmpty(t, res.RefreshToken)
+		assert.Equal(t, int64(900), res.ExpiresIn)
 		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
 	})
 
-	t.Run("error wrong password", func(t *testing.T) {
+	t.Run("success audited", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "satu@email.com",
+			Password: "12345678",
+		}
+		actor := domain.Actor{IP: "127.0.0.1", UserAgent: "curl/7.79.1"}
+		mockAuditUsecase := new(mocks.AuditUsecase)
+		mockAuditUsecase.On("Record", domain.Actor{UserID: dummyUser[0].ID.String(), IP: actor.IP, UserAgent: actor.UserAgent},
+			domain.AuditLogin, domain.AuditTargetUser, dummyUser[0].ID.String(), map[string]interface{}(nil), map[string]interface{}{"method": "password"}).Once()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, mockAuditUsecase)
+		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
+		mockTokenRepository.On("SaveRefreshToken", mock.Anything).Return(domain.RefreshToken{}, nil).Once()
+		_, err := uc.Login(req, actor)
+		assert.NoError(t, err)
+		mockAuditUsecase.AssertExpectations(t)
+	})
+
+	t.Run("error wrong password audited", func(t *testing.T) {
 		req := request.LoginRequest{
 			Email:    "satu@email.com",
 			Password: "1234",
 		}
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		actor := domain.Actor{IP: "127.0.0.1"}
+		mockAuditUsecase := new(mocks.AuditUsecase)
+		mockAuditUsecase.On("Record", actor, domain.AuditLoginFailed, domain.AuditTargetUser, dummyUser[0].ID.String(),
+			map[string]interface{}(nil), map[string]interface{}{"reason": "wrong password"}).Once()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, mockAuditUsecase)
 		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
-		_, err := uc.Login(req)
+		_, err := uc.Login(req, actor)
 		assert.Error(t, err)
+		mockAuditUsecase.AssertExpectations(t)
+	})
+
+	t.Run("error wrong password", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "satu@email.com",
+			Password: "1234",
+		}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
+		_, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
+		assert.EqualError(t, err, "email or password wrong")
 		mockUserRepository.AssertExpectations(t)
 	})
 
+	t.Run("error unknown email", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "unknown@email.com",
+			Password: "12345678",
+		}
+		actor := domain.Actor{IP: "127.0.0.1"}
+		mockAuditUsecase := new(mocks.AuditUsecase)
+		mockAuditUsecase.On("Record", actor, domain.AuditLoginFailed, domain.AuditTargetUser, "",
+			map[string]interface{}(nil), map[string]interface{}{"email": req.Email, "reason": "unknown email"}).Once()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, mockAuditUsecase)
+		mockUserRepository.On("FindUserByEmail", req.Email).Return(domain.User{}, errors.New("record not found")).Once()
+		_, err := uc.Login(req, actor)
+		assert.EqualError(t, err, "email or password wrong")
+		mockUserRepository.AssertExpectations(t)
+		mockAuditUsecase.AssertExpectations(t)
+	})
+
+	t.Run("error suspended", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "satu@email.com",
+			Password: "12345678",
+		}
+		suspended := dummyUser[0]
+		now := time.Now()
+		suspended.SuspendedAt = &now
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(suspended, nil).Once()
+		_, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
+		assert.EqualError(t, err, "account suspended")
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error password reset required", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "satu@email.com",
+			Password: "12345678",
+		}
+		resetRequired := dummyUser[0]
+		resetRequired.PasswordResetRequired = true
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(resetRequired, nil).Once()
+		_, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
+		assert.EqualError(t, err, "password reset required")
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error delayed after failures", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "dua@email.com",
+			Password: "1234",
+		}
+		attempts := repository.NewMemoryLoginAttemptRepository()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), attempts, nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", req.Email).Return(dummyUser[1], nil).Times(3)
+		for i := 0; i < 3; i++ {
+			_, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
+			assert.EqualError(t, err, "email or password wrong")
+		}
+		// the ip has failed as often as the account, the delay applies before the user is looked up
+		_, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
+		var throttled *domain.LoginThrottledError
+		assert.ErrorAs(t, err, &throttled)
+		assert.True(t, throttled.RetryAfter > 0 && throttled.RetryAfter <= time.Second)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error account locked", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "dua@email.com",
+			Password: "1234",
+		}
+		attempts := repository.NewMemoryLoginAttemptRepository()
+		for i := 0; i < 4; i++ {
+			_, _ = attempts.RecordFailure("user:"+dummyUser[1].ID.String(), time.Now().Add(-time.Minute), 15*time.Minute)
+		}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), attempts, nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", req.Email).Return(dummyUser[1], nil).Twice()
+		_, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
+		var throttled *domain.LoginThrottledError
+		assert.ErrorAs(t, err, &throttled)
+		assert.Equal(t, 15*time.Minute, throttled.RetryAfter)
+
+		// the right password does not help while the account is locked
+		req.Password = "12345678"
+		_, err = uc.Login(req, domain.Actor{IP: "127.0.0.2"})
+		assert.ErrorAs(t, err, &throttled)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error concurrent failures lock account", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "dua@email.com",
+			Password: "1234",
+		}
+		// every login has passed its checks before the first failure is counted, as in a burst.
+		attempts := &burstLoginAttempts{LoginAttemptRepository: repository.NewMemoryLoginAttemptRepository()}
+		attempts.arrived.Add(5)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), attempts, nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", req.Email).Return(dummyUser[1], nil).Times(5)
+
+		errs := make(chan error, 5)
+		for i := 0; i < 5; i++ {
+			go func(i int) {
+				_, err := uc.Login(req, domain.Actor{IP: fmt.Sprintf("127.0.0.%d", i+1)})
+				errs <- err
+			}(i)
+		}
+		throttled := 0
+		for i := 0; i < 5; i++ {
+			var lockErr *domain.LoginThrottledError
+			if err := <-errs; errors.As(err, &lockErr) {
+				throttled++
+			} else {
+				assert.EqualError(t, err, "email or password wrong")
+			}
+		}
+		assert.Equal(t, 1, throttled)
+		attempt, err := attempts.Get("user:" + dummyUser[1].ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, 5, attempt.Failures)
+		assert.True(t, attempt.IsLocked(time.Now()))
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error ip locked", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "dua@email.com",
+			Password: "12345678",
+		}
+		attempts := repository.NewMemoryLoginAttemptRepository()
+		_ = attempts.Lock("ip:127.0.0.1", time.Now().Add(time.Minute))
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), attempts, nil, nil, newAuditUsecase())
+		_, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
+		var throttled *domain.LoginThrottledError
+		assert.ErrorAs(t, err, &throttled)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("success resets failures", func(t *testing.T) {
+		req := request.LoginRequest{
+			Email:    "dua@email.com",
+			Password: "12345678",
+		}
+		attempts := repository.NewMemoryLoginAttemptRepository()
+		key := "user:" + dummyUser[1].ID.String()
+		_, _ = attempts.RecordFailure(key, time.Now().Add(-time.Minute), 15*time.Minute)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), attempts, nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", req.Email).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("SaveRefreshToken", mock.Anything).Return(domain.RefreshToken{}, nil).Once()
+		_, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
+		assert.NoError(t, err)
+		attempt, _ := attempts.Get(key)
+		assert.Equal(t, 0, attempt.Failures)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
 	t.Run("error token jwt", func(t *testing.T) {
 		req := request.LoginRequest{
 			Email:    "sasstu@email.com",
 			Password: "12345678",
 		}
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(domain.User{Username: ""}, errors.New("")).Once()
-		_, err := uc.Login(req)
+		_, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
 		assert.Error(t, err)
 		mockUserRepository.AssertExpectations(t)
 	})
 }
 
+func TestAuthUsecase_RefreshToken(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	revokedAt := time.Now().Add(-time.Minute)
+	current := domain.RefreshToken{
+		ID:        uuid.NewV4(),
+		UserID:    dummyUser[0].ID,
+		FamilyID:  uuid.NewV4(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("success rotate", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindRefreshTokenByHash", helper.HashRefreshToken("refresh-token")).Return(current, nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser[0].ID.String()).Return(dummyUser[0], nil).Once()
+		mockTokenRepository.On("RotateRefreshToken", current, mock.MatchedBy(func(next domain.RefreshToken) bool {
+			return next.FamilyID == current.FamilyID && next.ID != current.ID
+		})).Return(domain.RefreshToken{}, nil).Once()
+		res, err := uc.RefreshToken(request.RefreshTokenRequest{RefreshToken: "refresh-token"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Token)
+		assert.NotEqual(t, "refresh-token", res.RefreshToken)
+		mockTokenRepository.AssertExpectations(t)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindRefreshTokenByHash", mock.AnythingOfType("string")).Return(domain.RefreshToken{}, errors.New("record not found")).Once()
+		_, err := uc.RefreshToken(request.RefreshTokenRequest{RefreshToken: "unknown"})
+		assert.EqualError(t, err, "invalid refresh token")
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("reused token revokes family", func(t *testing.T) {
+		used := current
+		used.RevokedAt = &revokedAt
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindRefreshTokenByHash", mock.AnythingOfType("string")).Return(used, nil).Once()
+		mockTokenRepository.On("RevokeRefreshTokenFamily", used.FamilyID.String()).Return(nil).Once()
+		_, err := uc.RefreshToken(request.RefreshTokenRequest{RefreshToken: "refresh-token"})
+		assert.Error(t, err)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		expired := current
+		expired.ExpiresAt = time.Now().Add(-time.Hour)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindRefreshTokenByHash", mock.AnythingOfType("string")).Return(expired, nil).Once()
+		_, err := uc.RefreshToken(request.RefreshTokenRequest{RefreshToken: "refresh-token"})
+		assert.EqualError(t, err, "refresh token expired")
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("failed rotate", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindRefreshTokenByHash", mock.AnythingOfType("string")).Return(current, nil).Once()
+		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
+		mockTokenRepository.On("RotateRefreshToken", current, mock.AnythingOfType("domain.RefreshToken")).Return(domain.RefreshToken{}, errors.New("refresh token already used")).Once()
+		_, err := uc.RefreshToken(request.RefreshTokenRequest{RefreshToken: "refresh-token"})
+		assert.Error(t, err)
+		mockTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_Logout(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	userID := uuid.NewV4()
+	expiresAt := time.Now().Add(time.Minute)
+
+	t.Run("success with refresh token", func(t *testing.T) {
+		familyID := uuid.NewV4()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("RevokeAccessToken", domain.RevokedToken{JTI: "jti-1", UserID: userID, ExpiresAt: expiresAt}).Return(nil).Once()
+		mockTokenRepository.On("FindRefreshTokenByHash", helper.HashRefreshToken("refresh-token")).Return(domain.RefreshToken{UserID: userID, FamilyID: familyID}, nil).Once()
+		mockTokenRepository.On("RevokeRefreshTokenFamily", familyID.String()).Return(nil).Once()
+		err := uc.Logout(userID.String(), "jti-1", expiresAt, request.RefreshTokenRequest{RefreshToken: "refresh-token"})
+		assert.NoError(t, err)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("success without refresh token", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("RevokeAccessToken", mock.AnythingOfType("domain.RevokedToken")).Return(nil).Once()
+		err := uc.Logout(userID.String(), "jti-2", expiresAt, request.RefreshTokenRequest{})
+		assert.NoError(t, err)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("refresh token of other user", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("RevokeAccessToken", mock.AnythingOfType("domain.RevokedToken")).Return(nil).Once()
+		mockTokenRepository.On("FindRefreshTokenByHash", mock.AnythingOfType("string")).Return(domain.RefreshToken{UserID: uuid.NewV4()}, nil).Once()
+		err := uc.Logout(userID.String(), "jti-3", expiresAt, request.RefreshTokenRequest{RefreshToken: "refresh-token"})
+		assert.EqualError(t, err, "invalid refresh token")
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("token without id", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		err := uc.Logout(userID.String(), "", expiresAt, request.RefreshTokenRequest{})
+		assert.Error(t, err)
+		mockTokenRepository.AssertExpectations(t)
+	})
+}
+
 func TestAuthUsecase_Register(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 	mockRoleRepository := new(mocks.RoleRepository)
 	mockFavoriteRepository := new(mocks.FavoriteRepository)
 	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
 
 	t.Run("success", func(t *testing.T) {
 		req := request.UserCreateRequest{
@@ -70,18 +417,28 @@ func TestAuthUsecase_Register(t *testing.T) {
 			Email:    "satu@email.com",
 			Password: "12345678",
 		}
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		mailer := mail.NewMemoryMailer()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mailer, repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(domain.User{}, nil).Once()
 		mockRoleRepository.On("FindByName", mock.AnythingOfType("string")).Return(domain.Role{Name: "admin", ID: 1}, nil).Once()
-		mockUserRepository.On("Save", mock.AnythingOfType("domain.User")).Return(dummyUser[0], nil).Once()
+		mockUserRepository.On("Save", mock.MatchedBy(func(user domain.User) bool {
+			return user.EmailVerifiedAt == nil
+		})).Return(dummyUser[0], nil).Once()
 		mockFavoriteRepository.On("Add", mock.AnythingOfType("domain.Favorite")).Return(domain.Favorite{
 			ID:     uuid.NewV4(),
 			UserID: dummyUser[0].ID,
 		}, nil).Once()
+		mockTokenRepository.On("SaveEmailVerificationToken", mock.MatchedBy(func(token domain.EmailVerificationToken) bool {
+			return token.UserID == dummyUser[0].ID && token.Email == dummyUser[0].Email && len(token.TokenHash) == 64
+		})).Return(domain.EmailVerificationToken{}, nil).Once()
 		res, err := uc.Register(req)
 		assert.NoError(t, err)
 		assert.NotNil(t, res)
+		if assert.Len(t, mailer.Messages(), 1) {
+			assert.Equal(t, dummyUser[0].Email, mailer.Messages()[0].To)
+		}
 		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
 	})
 	t.Run("user already register", func(t *testing.T) {
 		req := request.UserCreateRequest{
@@ -90,7 +447,7 @@ func TestAuthUsecase_Register(t *testing.T) {
 			Email:    "satu@email.com",
 			Password: "12345678",
 		}
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(dummyUser[1], nil).Once()
 		_, err := uc.Register(req)
 		assert.Error(t, err)
@@ -104,7 +461,7 @@ func TestAuthUsecase_Register(t *testing.T) {
 			Email:    "satu@email.com",
 			Password: "12345678",
 		}
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(domain.User{}, nil).Once()
 		mockRoleRepository.On("FindByName", mock.AnythingOfType("string")).Return(domain.Role{}, errors.New("role not found")).Once()
 		_, err := uc.Register(req)
@@ -119,7 +476,7 @@ func TestAuthUsecase_Register(t *testing.T) {
 			Email:    "satu@email.com",
 			Password: "12345678",
 		}
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserByEmail", mock.AnythingOfType("string")).Return(domain.User{}, nil).Once()
 		mockRoleRepository.On("FindByName", mock.AnythingOfType("string")).Return(domain.Role{Name: "admin", ID: 1}, nil).Once()
 		mockUserRepository.On("Save", mock.AnythingOfType("domain.User")).Return(domain.User{}, errors.New("error save")).Once()
@@ -134,10 +491,11 @@ func TestAuthUsecase_GetUserDetails(t *testing.T) {
 	mockRoleRepository := new(mocks.RoleRepository)
 	mockFavoriteRepository := new(mocks.FavoriteRepository)
 	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
 
 	t.Run("success", func(t *testing.T) {
 		id := uuid.FromStringOrNil("0cf712fc-e631-40c7-8572-54772e698edg")
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		mockFavoriteRepository.On("FindByUserID", mock.AnythingOfType("string")).Return(domain.Favorite{
 			ID:     uuid.NewV4(),
@@ -151,8 +509,8 @@ func TestAuthUsecase_GetUserDetails(t *testing.T) {
 				NumberPhone:      "0012798232",
 				Address:          "bjb",
 				Postcode:         707722,
-				Latitude:         "4235,23",
-				Longitude:        "4225,233231",
+				Latitude:         &dummyLatitude,
+				Longitude:        &dummyLongitude,
 				Description:      "testing1",
 				Status:           0,
 				Tags:             nil,
@@ -172,7 +530,7 @@ func TestAuthUsecase_GetUserDetails(t *testing.T) {
 
 	t.Run("user null", func(t *testing.T) {
 		id := uuid.FromStringOrNil("0cf712fc-e631-40c7-8572-54772e698edg")
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(domain.User{}, errors.New("user not found")).Once()
 		_, _, _, err := uc.GetUserDetails(id.String())
 		assert.Error(t, err)
@@ -185,10 +543,11 @@ func TestAuthUsecase_CheckIfUserIsAdmin(t *testing.T) {
 	mockRoleRepository := new(mocks.RoleRepository)
 	mockFavoriteRepository := new(mocks.FavoriteRepository)
 	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
 
 	t.Run("success", func(t *testing.T) {
 		id := uuid.FromStringOrNil("0cf712fc-e631-40c7-8572-54772e698edg")
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[0], nil).Once()
 		res, err := uc.CheckIfUserIsAdmin(id.String())
 		assert.NoError(t, err)
@@ -198,7 +557,7 @@ func TestAuthUsecase_CheckIfUserIsAdmin(t *testing.T) {
 
 	t.Run("user null", func(t *testing.T) {
 		id := uuid.FromStringOrNil("0cf712fc-e631-40c7-8572-54772e698edg")
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(domain.User{}, errors.New("error not found")).Once()
 		_, err := uc.CheckIfUserIsAdmin(id.String())
 		assert.Error(t, err)
@@ -207,7 +566,7 @@ func TestAuthUsecase_CheckIfUserIsAdmin(t *testing.T) {
 
 	t.Run("role not admin", func(t *testing.T) {
 		id := uuid.FromStringOrNil("0cf712fc-e631-40c7-8572-54772e698edf")
-		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
 		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(dummyUser[1], nil).Once()
 		res, err := uc.CheckIfUserIsAdmin(id.String())
 		assert.NoError(t, err)
@@ -215,3 +574,383 @@ func TestAuthUsecase_CheckIfUserIsAdmin(t *testing.T) {
 		mockUserRepository.AssertExpectations(t)
 	})
 }
+
+func TestAuthUsecase_HasPermission(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	moderator := dummyUser[1]
+	moderator.Roles = []domain.Role{{
+		ID:   4,
+		Name: "ROLE_MODERATOR",
+		Permissions: []domain.Permission{
+			{ID: 2, Name: "enterprise:publish"},
+			{ID: 4, Name: "review:moderate"},
+		},
+	}}
+
+	t.Run("granted by role", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", moderator.ID.String()).Return(moderator, nil).Once()
+		res, err := uc.HasPermission(moderator.ID.String(), "review:moderate")
+		assert.NoError(t, err)
+		assert.True(t, res)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("not granted", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", moderator.ID.String()).Return(moderator, nil).Once()
+		res, err := uc.HasPermission(moderator.ID.String(), "tag:manage")
+		assert.NoError(t, err)
+		assert.False(t, res)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("user null", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", mock.AnythingOfType("string")).Return(domain.User{}, errors.New("error not found")).Once()
+		_, err := uc.HasPermission(moderator.ID.String(), "tag:manage")
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_CheckUserActive(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("active", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		err := uc.CheckUserActive(dummyUser[1].ID.String())
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("suspended", func(t *testing.T) {
+		suspended := dummyUser[1]
+		now := time.Now()
+		suspended.SuspendedAt = &now
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(suspended, nil).Once()
+		err := uc.CheckUserActive(dummyUser[1].ID.String())
+		assert.EqualError(t, err, "account suspended")
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_ForgotPassword(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mailer := mail.NewMemoryMailer()
+		var saved domain.PasswordResetToken
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mailer, repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", dummyUser[1].Email).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("SavePasswordResetToken", mock.MatchedBy(func(token domain.PasswordResetToken) bool {
+			saved = token
+			return token.UserID == dummyUser[1].ID && len(token.TokenHash) == 64 && token.ExpiresAt.After(time.Now())
+		})).Return(domain.PasswordResetToken{}, nil).Once()
+		err := uc.ForgotPassword(request.ForgotPasswordRequest{Email: dummyUser[1].Email})
+		assert.NoError(t, err)
+		messages := mailer.Messages()
+		if assert.Len(t, messages, 1) {
+			assert.Equal(t, dummyUser[1].Email, messages[0].To)
+			assert.NotContains(t, messages[0].Body, saved.TokenHash)
+		}
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		mailer := mail.NewMemoryMailer()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mailer, repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", "unknown@email.com").Return(domain.User{}, errors.New("record not found")).Once()
+		err := uc.ForgotPassword(request.ForgotPasswordRequest{Email: "unknown@email.com"})
+		assert.NoError(t, err)
+		assert.Empty(t, mailer.Messages())
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_ResetPassword(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	req := request.ResetPasswordRequest{Token: "reset-token", NewPassword: "87654321"}
+	resetToken := domain.PasswordResetToken{
+		ID:        uuid.NewV4(),
+		UserID:    dummyUser[1].ID,
+		TokenHash: helper.HashPasswordResetToken(req.Token),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		resetRequired := dummyUser[1]
+		resetRequired.PasswordResetRequired = true
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindPasswordResetTokenByHash", resetToken.TokenHash).Return(resetToken, nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(resetRequired, nil).Once()
+		mockTokenRepository.On("UsePasswordResetToken", resetToken).Return(nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return !user.PasswordResetRequired && bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.NewPassword)) == nil
+		})).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("RevokeUserRefreshTokens", dummyUser[1].ID.String()).Return(nil).Once()
+		err := uc.ResetPassword(req)
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("success clears lockout", func(t *testing.T) {
+		attempts := repository.NewMemoryLoginAttemptRepository()
+		_ = attempts.Lock("user:"+dummyUser[1].ID.String(), time.Now().Add(time.Minute))
+		mailer := mail.NewMemoryMailer()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mailer, attempts, nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindPasswordResetTokenByHash", resetToken.TokenHash).Return(resetToken, nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("UsePasswordResetToken", resetToken).Return(nil).Once()
+		mockUserRepository.On("Update", mock.Anything).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("RevokeUserRefreshTokens", dummyUser[1].ID.String()).Return(nil).Once()
+		err := uc.ResetPassword(req)
+		assert.NoError(t, err)
+		locked, _ := attempts.FindLocked(time.Now())
+		assert.Empty(t, locked)
+		if assert.Len(t, mailer.Messages(), 1) {
+			assert.Equal(t, "Your account is unlocked", mailer.Messages()[0].Subject)
+		}
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error used token", func(t *testing.T) {
+		used := resetToken
+		now := time.Now()
+		used.UsedAt = &now
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindPasswordResetTokenByHash", resetToken.TokenHash).Return(used, nil).Once()
+		err := uc.ResetPassword(req)
+		assert.EqualError(t, err, "password reset token already used")
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error expired token", func(t *testing.T) {
+		expired := resetToken
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindPasswordResetTokenByHash", resetToken.TokenHash).Return(expired, nil).Once()
+		err := uc.ResetPassword(req)
+		assert.EqualError(t, err, "password reset token expired")
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error unknown token", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindPasswordResetTokenByHash", resetToken.TokenHash).Return(domain.PasswordResetToken{}, errors.New("record not found")).Once()
+		err := uc.ResetPassword(req)
+		assert.EqualError(t, err, "invalid password reset token")
+		mockTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_SendEmailVerification(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mailer := mail.NewMemoryMailer()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mailer, repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("SaveEmailVerificationToken", mock.AnythingOfType("domain.EmailVerificationToken")).Return(domain.EmailVerificationToken{}, nil).Once()
+		err := uc.SendEmailVerification(dummyUser[1].ID.String())
+		assert.NoError(t, err)
+		assert.Len(t, mailer.Messages(), 1)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error already verified", func(t *testing.T) {
+		verified := dummyUser[1]
+		now := time.Now()
+		verified.EmailVerifiedAt = &now
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(verified, nil).Once()
+		err := uc.SendEmailVerification(dummyUser[1].ID.String())
+		assert.EqualError(t, err, "email already verified")
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_VerifyEmail(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	verification := domain.EmailVerificationToken{
+		ID:        uuid.NewV4(),
+		UserID:    dummyUser[1].ID,
+		Email:     dummyUser[1].Email,
+		TokenHash: helper.HashEmailVerificationToken("verify-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindEmailVerificationTokenByHash", verification.TokenHash).Return(verification, nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("UseEmailVerificationToken", verification).Return(nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return user.EmailVerifiedAt != nil
+		})).Return(dummyUser[1], nil).Once()
+		err := uc.VerifyEmail("verify-token")
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error email changed", func(t *testing.T) {
+		changed := dummyUser[1]
+		changed.Email = "baru@email.com"
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindEmailVerificationTokenByHash", verification.TokenHash).Return(verification, nil).Once()
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(changed, nil).Once()
+		err := uc.VerifyEmail("verify-token")
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error expired", func(t *testing.T) {
+		expired := verification
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindEmailVerificationTokenByHash", verification.TokenHash).Return(expired, nil).Once()
+		err := uc.VerifyEmail("verify-token")
+		assert.EqualError(t, err, "email verification token expired")
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error unknown token", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindEmailVerificationTokenByHash", helper.HashEmailVerificationToken("unknown")).Return(domain.EmailVerificationToken{}, errors.New("record not found")).Once()
+		err := uc.VerifyEmail("unknown")
+		assert.EqualError(t, err, "invalid email verification token")
+		mockTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_CheckEmailVerified(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("verified", func(t *testing.T) {
+		verified := dummyUser[1]
+		now := time.Now()
+		verified.EmailVerifiedAt = &now
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(verified, nil).Once()
+		assert.NoError(t, uc.CheckEmailVerified(dummyUser[1].ID.String()))
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("not verified", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		assert.EqualError(t, uc.CheckEmailVerified(dummyUser[1].ID.String()), "email not verified")
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_GetLockedAccounts(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("success", func(t *testing.T) {
+		attempts := repository.NewMemoryLoginAttemptRepository()
+		until := time.Now().Add(time.Minute)
+		_, _ = attempts.RecordFailure("user:"+dummyUser[1].ID.String(), time.Now(), 15*time.Minute)
+		_ = attempts.Lock("user:"+dummyUser[1].ID.String(), until)
+		_ = attempts.Lock("ip:127.0.0.1", until)
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), attempts, nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		res, err := uc.GetLockedAccounts()
+		assert.NoError(t, err)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, dummyUser[1].ID, res[0].User.ID)
+			assert.Equal(t, 1, res[0].Failures)
+			assert.Equal(t, until, res[0].LockedUntil)
+		}
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("success empty", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		res, err := uc.GetLockedAccounts()
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestAuthUsecase_UnlockAccount(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("success", func(t *testing.T) {
+		attempts := repository.NewMemoryLoginAttemptRepository()
+		_ = attempts.Lock("user:"+dummyUser[1].ID.String(), time.Now().Add(time.Minute))
+		mailer := mail.NewMemoryMailer()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mailer, attempts, nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		err := uc.UnlockAccount(dummyUser[1].ID.String(), domain.Actor{})
+		assert.NoError(t, err)
+		attempt, _ := attempts.Get("user:" + dummyUser[1].ID.String())
+		assert.False(t, attempt.IsLocked(time.Now()))
+		if assert.Len(t, mailer.Messages(), 1) {
+			assert.Equal(t, dummyUser[1].Email, mailer.Messages()[0].To)
+		}
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error not locked", func(t *testing.T) {
+		mailer := mail.NewMemoryMailer()
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mailer, repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		err := uc.UnlockAccount(dummyUser[1].ID.String(), domain.Actor{})
+		assert.EqualError(t, err, "account is not locked")
+		assert.Empty(t, mailer.Messages())
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error user not found", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", "unknown").Return(domain.User{}, errors.New("record not found")).Once()
+		err := uc.UnlockAccount("unknown", domain.Actor{})
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"github.com/nrmadi02/mini-project/domain"
+	"time"
+)
+
+// Failed login policy. Failures older than loginFailureWindow are forgotten, unless the key is locked.
+const (
+	loginFailureWindow   = 15 * time.Minute
+	loginLockDuration    = 15 * time.Minute
+	loginMaxUserFailures = 5
+	loginMaxIPFailures   = 20
+	loginDelayAfter      = 3
+	loginMaxDelay        = 30 * time.Second
+	loginUserKeyPrefix   = "user:"
+	loginIPKeyPrefix     = "ip:"
+)
+
+func loginUserKey(id string) string {
+	return loginUserKeyPrefix + id
+}
+
+func loginIPKey(ip string) string {
+	return loginIPKeyPrefix + ip
+}
+
+// loginDelay is the time to wait after the last failure, it doubles with every failure past
+// loginDelayAfter up to loginMaxDelay.
+func loginDelay(failures int) time.Duration {
+	if failures < loginDelayAfter {
+		return 0
+	}
+	delay := time.Second
+	for i := loginDelayAfter; i < failures; i++ {
+		delay *= 2
+		if delay >= loginMaxDelay {
+			return loginMaxDelay
+		}
+	}
+	return delay
+}
+
+// checkLoginAttempt returns a LoginThrottledError while the key is locked or still has to wait.
+func (a authUsecase) checkLoginAttempt(key string, now time.Time) error {
+	attempt, err := a.loginAttemptRepository.Get(key)
+	if err != nil {
+		return err
+	}
+	if attempt.IsLocked(now) {
+		return &domain.LoginThrottledError{RetryAfter: attempt.LockedUntil.Sub(now)}
+	}
+	if attempt.Failures == 0 || now.Sub(attempt.LastFailureAt) > loginFailureWindow {
+		return nil
+	}
+	if wait := attempt.LastFailureAt.Add(loginDelay(attempt.Failures)).Sub(now); wait > 0 {
+		return &domain.LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// recordLoginFailure counts the failure and locks the key once max failures are reached.
+// It reports whether the key got locked.
+func (a authUsecase) recordLoginFailure(key string, max int, now time.Time) (bool, error) {
+	attempt, err := a.loginAttemptRepository.RecordFailure(key, now, loginFailureWindow)
+	if err != nil {
+		return false, err
+	}
+	if attempt.Failures < max || attempt.IsLocked(now) {
+		return false, nil
+	}
+	return true, a.loginAttemptRepository.Lock(key, now.Add(loginLockDuration))
+}
+
+// clearLoginLock removes the counters of the user and mails a notice when the account was locked.
+func (a authUsecase) clearLoginLock(user domain.User) error {
+	key := loginUserKey(user.ID.String())
+	attempt, err := a.loginAttemptRepository.Get(key)
+	if err != nil {
+		return err
+	}
+	if err = a.loginAttemptRepository.Reset(key); err != nil {
+		return err
+	}
+	if !attempt.IsLocked(time.Now()) {
+		return nil
+	}
+
+	body := "Hi " + user.Fullname + ",\n\n" +
+		"your account was locked after too many failed logins and is unlocked again, you can login now.\n\n" +
+		"If the failed logins were not you, change your password."
+	return a.mailer.Send(user.Email, "Your account is unlocked", body)
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/role/utils"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	request2 "github.com/nrmadi02/mini-project/web/request"
+	"github.com/nrmadi02/mini-project/web/response"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+)
+
+func (a authUsecase) oidcProvider(name string) (domain.OIDCProvider, error) {
+	for _, provider := range a.oidcProviders {
+		if provider.Name() == name {
+			return provider, nil
+		}
+	}
+	return nil, errors.New("unknown login provider " + name)
+}
+
+// OIDCAuthorizationURL starts a login at the provider. State, nonce and the PKCE verifier are
+// kept here until the provider redirects back, see OIDCLogin.
+func (a authUsecase) OIDCAuthorizationURL(provider string) (string, error) {
+	p, err := a.oidcProvider(provider)
+	if err != nil {
+		return "", err
+	}
+
+	state, hash, err := helper.NewOIDCState()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := helper.NewOIDCSecret()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := helper.NewOIDCSecret()
+	if err != nil {
+		return "", err
+	}
+	_, err = a.tokenRepository.SaveOIDCState(domain.OIDCState{
+		ID:           uuid.NewV4(),
+		Provider:     p.Name(),
+		StateHash:    hash,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(helper.OIDCStateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return p.AuthCodeURL(state, nonce, verifier)
+}
+
+// OIDCLogin finishes a login at the provider. The user is found by the provider identity, then
+// by the verified email, or registered like Register does. Two-factor authentication still applies.
+func (a authUsecase) OIDCLogin(provider string, request request2.OIDCCallbackRequest, actor domain.Actor) (response.SuccessLogin, error) {
+	p, err := a.oidcProvider(provider)
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+
+	state, err := a.tokenRepository.FindOIDCStateByHash(helper.HashOIDCState(request.State))
+	if err != nil || state.Provider != p.Name() {
+		return response.SuccessLogin{}, errors.New("invalid login state")
+	}
+	if state.UsedAt != nil {
+		return response.SuccessLogin{}, errors.New("login state already used")
+	}
+	if time.Now().After(state.ExpiresAt) {
+		return response.SuccessLogin{}, errors.New("login state expired")
+	}
+	if err = a.tokenRepository.UseOIDCState(state); err != nil {
+		return response.SuccessLogin{}, err
+	}
+
+	claims, err := p.Exchange(request.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		a.recordLoginFailed(actor, "", map[string]interface{}{"provider": p.Name(), "reason": err.Error()})
+		return response.SuccessLogin{}, err
+	}
+	user, err := a.oidcUser(p.Name(), claims)
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+
+	if err = user.CheckActive(); err != nil {
+		return response.SuccessLogin{}, err
+	}
+	if user.PasswordResetRequired {
+		return response.SuccessLogin{}, errors.New("password reset required")
+	}
+	if user.TwoFactorEnabled() {
+		return a.twoFactorChallenge(user)
+	}
+	a.recordLogin(actor, user, "oidc:"+p.Name())
+	return a.startSession(user)
+}
+
+// oidcUser returns the user linked to the identity. An unknown identity is linked to the user
+// with the same email, only when the provider has verified that email.
+func (a authUsecase) oidcUser(provider string, claims domain.OIDCClaims) (domain.User, error) {
+	user, err := a.userRepository.FindUserByIdentity(provider, claims.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if claims.Email == "" || !claims.EmailVerified {
+		return domain.User{}, errors.New("email not verified by the login provider")
+	}
+
+	user, _ = a.userRepository.FindUserByEmail(claims.Email)
+	if user.ID == uuid.Nil {
+		user, err = a.registerOIDCUser(claims)
+		if err != nil {
+			return domain.User{}, err
+		}
+	} else {
+		if user.EmailVerifiedAt == nil {
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+			if user, err = a.userRepository.Update(user); err != nil {
+				return domain.User{}, err
+			}
+		}
+		body := "Hi " + user.Fullname + ",\n\n" +
+			"your account can now be signed in to with " + provider + ".\n\n" +
+			"If this was not you, change your password and contact us."
+		if err = a.mailer.Send(user.Email, "New sign-in method", body); err != nil {
+			log.WithField("user_id", user.ID.String()).Warn("send identity linked mail: " + err.Error())
+		}
+	}
+
+	_, err = a.userRepository.SaveIdentity(domain.UserIdentity{
+		ID:       uuid.NewV4(),
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// registerOIDCUser creates a client account for the identity. The password is random, a password
+// login can be added later with forgot password.
+func (a authUsecase) registerOIDCUser(claims domain.OIDCClaims) (domain.User, error) {
+	clientRole, err := a.roleRepository.FindByName(role.Client.String())
+	if err != nil {
+		return domain.User{}, errors.New("role not found - " + role.Client.String())
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return domain.User{}, err
+	}
+	password, err := bcrypt.GenerateFromPassword(secret, bcrypt.DefaultCost)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	username := claims.PreferredUsername
+	if username == "" {
+		username = strings.Split(claims.Email, "@")[0]
+	}
+	if existing, _ := a.userRepository.FindUserByUsername(username); existing.ID != uuid.Nil {
+		username += "-" + uuid.NewV4().String()[:6]
+	}
+	fullname := claims.Name
+	if fullname == "" {
+		fullname = username
+	}
+
+	now := time.Now()
+	user := domain.User{
+		ID:              uuid.NewV4(),
+		Fullname:        fullname,
+		Username:        username,
+		Email:           claims.Email,
+		Password:        string(password),
+		Roles:           []domain.Role{clientRole},
+		EmailVerifiedAt: &now,
+	}
+	favorite := domain.Favorite{
+		ID:     uuid.NewV4(),
+		UserID: user.ID,
+	}
+
+	user, err = a.userRepository.Save(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	_, _ = a.favoriteRepository.Add(favorite)
+	return user, nil
+}
@@ -0,0 +1,210 @@
+package usecase_test
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/mail"
+	"github.com/nrmadi02/mini-project/internal/oidc"
+	"github.com/nrmadi02/mini-project/internal/oidc/oidctest"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/internal/user/repository"
+	"github.com/nrmadi02/mini-project/internal/user/usecase"
+	"github.com/nrmadi02/mini-project/web/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+var dummyOIDCClaims = domain.OIDCClaims{
+	Subject:           "248289761001",
+	Email:             "baru@email.com",
+	EmailVerified:     true,
+	Name:              "User Baru",
+	PreferredUsername: "baru",
+}
+
+// startOIDCLogin runs the login at the mock provider up to the redirect back to the app.
+func startOIDCLogin(t *testing.T, uc domain.AuthUsecase, server *oidctest.Server, tokenRepository *mocks.TokenRepository) (request.OIDCCallbackRequest, domain.OIDCState) {
+	var saved domain.OIDCState
+	tokenRepository.On("SaveOIDCState", mock.MatchedBy(func(state domain.OIDCState) bool {
+		return state.Provider == "keycloak" && len(state.StateHash) == 64 && state.Nonce != "" && state.CodeVerifier != ""
+	})).Run(func(args mock.Arguments) {
+		saved = args.Get(0).(domain.OIDCState)
+	}).Return(domain.OIDCState{}, nil).Once()
+
+	authURL, err := uc.OIDCAuthorizationURL("keycloak")
+	assert.NoError(t, err)
+	code, state, err := server.Authorize(authURL)
+	assert.NoError(t, err)
+	assert.Equal(t, saved.StateHash, helper.HashOIDCState(state))
+	return request.OIDCCallbackRequest{Code: code, State: state}, saved
+}
+
+func TestAuthUsecase_OIDCLogin(t *testing.T) {
+	server := oidctest.NewServer("umkm", "client-secret")
+	defer server.Close()
+	providers := []domain.OIDCProvider{oidc.NewProvider("keycloak", server.Issuer(), "umkm", "client-secret", "http://localhost:3000/login/callback", nil)}
+
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	newUsecase := func(mailer domain.Mailer) domain.AuthUsecase {
+		return usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, new(mocks.EnterpriseRepository), mockTokenRepository, goJWT, mailer, repository.NewMemoryLoginAttemptRepository(), nil, providers, newAuditUsecase())
+	}
+
+	t.Run("success register new user", func(t *testing.T) {
+		uc := newUsecase(mail.NewMemoryMailer())
+		server.SetUser(dummyOIDCClaims)
+		req, state := startOIDCLogin(t, uc, server, mockTokenRepository)
+
+		mockTokenRepository.On("FindOIDCStateByHash", state.StateHash).Return(state, nil).Once()
+		mockTokenRepository.On("UseOIDCState", state).Return(nil).Once()
+		mockUserRepository.On("FindUserByIdentity", "keycloak", dummyOIDCClaims.Subject).Return(domain.User{}, errors.New("record not found")).Once()
+		mockUserRepository.On("FindUserByEmail", dummyOIDCClaims.Email).Return(domain.User{}, errors.New("record not found")).Once()
+		mockRoleRepository.On("FindByName", "ROLE_CLIENT").Return(domain.Role{Name: "ROLE_CLIENT", ID: 2}, nil).Once()
+		mockUserRepository.On("FindUserByUsername", "baru").Return(domain.User{}, errors.New("record not found")).Once()
+		mockUserRepository.On("Save", mock.MatchedBy(func(user domain.User) bool {
+			return user.Email == dummyOIDCClaims.Email && user.Username == "baru" && user.Fullname == "User Baru" &&
+				user.EmailVerifiedAt != nil && len(user.Roles) == 1 && user.Roles[0].Name == "ROLE_CLIENT" && user.Password != ""
+		})).Return(func(user domain.User) domain.User { return user }, nil).Once()
+		mockFavoriteRepository.On("Add", mock.AnythingOfType("domain.Favorite")).Return(domain.Favorite{}, nil).Once()
+		mockUserRepository.On("SaveIdentity", mock.MatchedBy(func(identity domain.UserIdentity) bool {
+			return identity.Provider == "keycloak" && identity.Subject == dummyOIDCClaims.Subject
+		})).Return(domain.UserIdentity{}, nil).Once()
+		mockTokenRepository.On("SaveRefreshToken", mock.AnythingOfType("domain.RefreshToken")).Return(domain.RefreshToken{}, nil).Once()
+
+		res, err := uc.OIDCLogin("keycloak", req, domain.Actor{})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Token)
+		assert.Equal(t, dummyOIDCClaims.Email, res.Email)
+		mockUserRepository.AssertExpectations(t)
+		mockFavoriteRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("success linked identity", func(t *testing.T) {
+		uc := newUsecase(mail.NewMemoryMailer())
+		server.SetUser(dummyOIDCClaims)
+		req, state := startOIDCLogin(t, uc, server, mockTokenRepository)
+
+		mockTokenRepository.On("FindOIDCStateByHash", state.StateHash).Return(state, nil).Once()
+		mockTokenRepository.On("UseOIDCState", state).Return(nil).Once()
+		mockUserRepository.On("FindUserByIdentity", "keycloak", dummyOIDCClaims.Subject).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("SaveRefreshToken", mock.AnythingOfType("domain.RefreshToken")).Return(domain.RefreshToken{}, nil).Once()
+
+		res, err := uc.OIDCLogin("keycloak", req, domain.Actor{})
+		assert.NoError(t, err)
+		assert.Equal(t, dummyUser[1].ID, res.ID)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("success link existing user by email", func(t *testing.T) {
+		mailer := mail.NewMemoryMailer()
+		uc := newUsecase(mailer)
+		claims := dummyOIDCClaims
+		claims.Email = dummyUser[1].Email
+		server.SetUser(claims)
+		req, state := startOIDCLogin(t, uc, server, mockTokenRepository)
+
+		mockTokenRepository.On("FindOIDCStateByHash", state.StateHash).Return(state, nil).Once()
+		mockTokenRepository.On("UseOIDCState", state).Return(nil).Once()
+		mockUserRepository.On("FindUserByIdentity", "keycloak", claims.Subject).Return(domain.User{}, errors.New("record not found")).Once()
+		mockUserRepository.On("FindUserByEmail", claims.Email).Return(dummyUser[1], nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return user.ID == dummyUser[1].ID && user.EmailVerifiedAt != nil
+		})).Return(func(user domain.User) domain.User { return user }, nil).Once()
+		mockUserRepository.On("SaveIdentity", mock.MatchedBy(func(identity domain.UserIdentity) bool {
+			return identity.UserID == dummyUser[1].ID && identity.Subject == claims.Subject
+		})).Return(domain.UserIdentity{}, nil).Once()
+		mockTokenRepository.On("SaveRefreshToken", mock.AnythingOfType("domain.RefreshToken")).Return(domain.RefreshToken{}, nil).Once()
+
+		res, err := uc.OIDCLogin("keycloak", req, domain.Actor{})
+		assert.NoError(t, err)
+		assert.Equal(t, dummyUser[1].ID, res.ID)
+		if assert.Len(t, mailer.Messages(), 1) {
+			assert.Equal(t, "New sign-in method", mailer.Messages()[0].Subject)
+		}
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("success two factor challenge", func(t *testing.T) {
+		uc := newUsecase(mail.NewMemoryMailer())
+		server.SetUser(dummyOIDCClaims)
+		req, state := startOIDCLogin(t, uc, server, mockTokenRepository)
+
+		mockTokenRepository.On("FindOIDCStateByHash", state.StateHash).Return(state, nil).Once()
+		mockTokenRepository.On("UseOIDCState", state).Return(nil).Once()
+		mockUserRepository.On("FindUserByIdentity", "keycloak", dummyOIDCClaims.Subject).Return(twoFactorUser(), nil).Once()
+		mockTokenRepository.On("SaveTwoFactorChallenge", mock.AnythingOfType("domain.TwoFactorChallenge")).Return(domain.TwoFactorChallenge{}, nil).Once()
+
+		res, err := uc.OIDCLogin("keycloak", req, domain.Actor{})
+		assert.NoError(t, err)
+		assert.True(t, res.TwoFactorRequired)
+		assert.Empty(t, res.Token)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error email not verified", func(t *testing.T) {
+		uc := newUsecase(mail.NewMemoryMailer())
+		claims := dummyOIDCClaims
+		claims.Email = dummyUser[1].Email
+		claims.EmailVerified = false
+		server.SetUser(claims)
+		req, state := startOIDCLogin(t, uc, server, mockTokenRepository)
+
+		mockTokenRepository.On("FindOIDCStateByHash", state.StateHash).Return(state, nil).Once()
+		mockTokenRepository.On("UseOIDCState", state).Return(nil).Once()
+		mockUserRepository.On("FindUserByIdentity", "keycloak", claims.Subject).Return(domain.User{}, errors.New("record not found")).Once()
+
+		_, err := uc.OIDCLogin("keycloak", req, domain.Actor{})
+		assert.EqualError(t, err, "email not verified by the login provider")
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error state used", func(t *testing.T) {
+		uc := newUsecase(mail.NewMemoryMailer())
+		server.SetUser(dummyOIDCClaims)
+		req, state := startOIDCLogin(t, uc, server, mockTokenRepository)
+		usedAt := time.Now()
+		state.UsedAt = &usedAt
+
+		mockTokenRepository.On("FindOIDCStateByHash", state.StateHash).Return(state, nil).Once()
+		_, err := uc.OIDCLogin("keycloak", req, domain.Actor{})
+		assert.EqualError(t, err, "login state already used")
+	})
+
+	t.Run("error state expired", func(t *testing.T) {
+		uc := newUsecase(mail.NewMemoryMailer())
+		server.SetUser(dummyOIDCClaims)
+		req, state := startOIDCLogin(t, uc, server, mockTokenRepository)
+		state.ExpiresAt = time.Now().Add(-time.Minute)
+
+		mockTokenRepository.On("FindOIDCStateByHash", state.StateHash).Return(state, nil).Once()
+		_, err := uc.OIDCLogin("keycloak", req, domain.Actor{})
+		assert.EqualError(t, err, "login state expired")
+	})
+
+	t.Run("error state of other provider", func(t *testing.T) {
+		uc := newUsecase(mail.NewMemoryMailer())
+		server.SetUser(dummyOIDCClaims)
+		req, state := startOIDCLogin(t, uc, server, mockTokenRepository)
+		state.Provider = "google"
+
+		mockTokenRepository.On("FindOIDCStateByHash", state.StateHash).Return(state, nil).Once()
+		_, err := uc.OIDCLogin("keycloak", req, domain.Actor{})
+		assert.EqualError(t, err, "invalid login state")
+	})
+
+	t.Run("error unknown provider", func(t *testing.T) {
+		uc := newUsecase(mail.NewMemoryMailer())
+		_, err := uc.OIDCAuthorizationURL("github")
+		assert.EqualError(t, err, "unknown login provider github")
+		_, err = uc.OIDCLogin("github", request.OIDCCallbackRequest{Code: "code", State: "state"}, domain.Actor{})
+		assert.EqualError(t, err, "unknown login provider github")
+	})
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	request2 "github.com/nrmadi02/mini-project/web/request"
+	"github.com/nrmadi02/mini-project/web/response"
+	uuid "github.com/satori/go.uuid"
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
+const recoveryCodeCount = 10
+
+// twoFactorRequired reports whether a role of the user makes two-factor authentication mandatory.
+func (a authUsecase) twoFactorRequired(user domain.User) bool {
+	return user.HasRole(a.twoFactorRoles...)
+}
+
+func (a authUsecase) twoFactorChallenge(user domain.User) (response.SuccessLogin, error) {
+	token, hash, err := helper.NewTwoFactorChallenge()
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+	_, err = a.tokenRepository.SaveTwoFactorChallenge(domain.TwoFactorChallenge{
+		ID:        uuid.NewV4(),
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(helper.TwoFactorChallengeTTL),
+	})
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+
+	return response.SuccessLogin{
+		ID:                user.ID,
+		Username:          user.Username,
+		Fullname:          user.Fullname,
+		Email:             user.Email,
+		ExpiresIn:         int64(helper.TwoFactorChallengeTTL.Seconds()),
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+	}, nil
+}
+
+// VerifyTwoFactorLogin is the second login step. A wrong code counts as a failed login,
+// so codes cannot be guessed faster than passwords.
+func (a authUsecase) VerifyTwoFactorLogin(request request2.TwoFactorLoginRequest, actor domain.Actor) (response.SuccessLogin, error) {
+	now := time.Now()
+	ipKey := loginIPKey(actor.IP)
+	if err := a.checkLoginAttempt(ipKey, now); err != nil {
+		return response.SuccessLogin{}, err
+	}
+
+	challenge, err := a.tokenRepository.FindTwoFactorChallengeByHash(helper.HashTwoFactorChallenge(request.ChallengeToken))
+	if err != nil {
+		return response.SuccessLogin{}, errors.New("invalid challenge token")
+	}
+	if challenge.UsedAt != nil {
+		return response.SuccessLogin{}, errors.New("challenge token already used")
+	}
+	if now.After(challenge.ExpiresAt) {
+		return response.SuccessLogin{}, errors.New("challenge token expired")
+	}
+
+	user, err := a.userRepository.FindUserById(challenge.UserID.String())
+	if err != nil {
+		return response.SuccessLogin{}, err
+	}
+	userKey := loginUserKey(user.ID.String())
+	if err = a.checkLoginAttempt(userKey, now); err != nil {
+		return response.SuccessLogin{}, err
+	}
+
+	if err = a.verifyTwoFactorCode(&user, request.Code, true); err != nil {
+		a.recordLoginFailed(actor, user.ID.String(), map[string]interface{}{"reason": "wrong two-factor code"})
+		if _, err := a.recordLoginFailure(ipKey, loginMaxIPFailures, now); err != nil {
+			return response.SuccessLogin{}, err
+		}
+		locked, err := a.recordLoginFailure(userKey, loginMaxUserFailures, now)
+		if err != nil {
+			return response.SuccessLogin{}, err
+		}
+		if locked {
+			return response.SuccessLogin{}, &domain.LoginThrottledError{RetryAfter: loginLockDuration}
+		}
+		return response.SuccessLogin{}, errors.New("invalid code")
+	}
+
+	if err = a.tokenRepository.UseTwoFactorChallenge(challenge); err != nil {
+		return response.SuccessLogin{}, err
+	}
+	if err = a.loginAttemptRepository.Reset(userKey); err != nil {
+		return response.SuccessLogin{}, err
+	}
+	if err = user.CheckActive(); err != nil {
+		return response.SuccessLogin{}, err
+	}
+	a.recordLogin(actor, user, "two_factor")
+	return a.startSession(user)
+}
+
+// verifyTwoFactorCode accepts a TOTP code of a period after the last accepted one, and when
+// allowed an unused recovery code.
+func (a authUsecase) verifyTwoFactorCode(user *domain.User, code string, allowRecovery bool) error {
+	step, err := helper.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if err == nil && step > user.TOTPLastStep {
+		user.TOTPLastStep = step
+		_, err = a.userRepository.Update(*user)
+		return err
+	}
+	if allowRecovery && a.tokenRepository.UseRecoveryCode(user.ID.String(), helper.HashRecoveryCode(code)) == nil {
+		return nil
+	}
+	return errors.New("invalid code")
+}
+
+// SetupTwoFactor stores a new secret for the user. Two-factor authentication is only enabled
+// once a code from it is confirmed with EnableTwoFactor.
+func (a authUsecase) SetupTwoFactor(id string) (response.TwoFactorSetupResponse, error) {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return response.TwoFactorSetupResponse{}, err
+	}
+	if user.TwoFactorEnabled() {
+		return response.TwoFactorSetupResponse{}, errors.New("two-factor authentication already enabled")
+	}
+
+	secret, err := helper.NewTOTPSecret()
+	if err != nil {
+		return response.TwoFactorSetupResponse{}, err
+	}
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	if _, err = a.userRepository.Update(user); err != nil {
+		return response.TwoFactorSetupResponse{}, err
+	}
+
+	return response.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: helper.TOTPProvisioningURI(user.Email, secret),
+	}, nil
+}
+
+// EnableTwoFactor confirms the secret from SetupTwoFactor and returns the recovery codes,
+// they are not shown again.
+func (a authUsecase) EnableTwoFactor(id string, request request2.TwoFactorCodeRequest) ([]string, error) {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor authentication not set up")
+	}
+	if err = a.verifyTwoFactorCode(&user, request.Code, false); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TOTPEnabledAt = &now
+	if _, err = a.userRepository.Update(user); err != nil {
+		return nil, err
+	}
+	return a.newRecoveryCodes(user)
+}
+
+// DisableTwoFactor needs the password and a code. Users whose role requires two-factor
+// authentication cannot turn it off.
+func (a authUsecase) DisableTwoFactor(id string, request request2.DisableTwoFactorRequest) error {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return errors.New("two-factor authentication not enabled")
+	}
+	if a.twoFactorRequired(user) {
+		return errors.New("two-factor authentication is required for your role")
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+		return errors.New("password wrong")
+	}
+	if err = a.verifyTwoFactorCode(&user, request.Code, true); err != nil {
+		return err
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPEnabledAt = nil
+	user.TOTPLastStep = 0
+	if _, err = a.userRepository.Update(user); err != nil {
+		return err
+	}
+	return a.tokenRepository.ReplaceRecoveryCodes(user.ID.String(), nil)
+}
+
+// RegenerateRecoveryCodes replaces every recovery code, used or not. It takes a TOTP code only,
+// a recovery code could otherwise be turned into ten new ones.
+func (a authUsecase) RegenerateRecoveryCodes(id string, request request2.TwoFactorCodeRequest) ([]string, error) {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication not enabled")
+	}
+	if err = a.verifyTwoFactorCode(&user, request.Code, false); err != nil {
+		return nil, err
+	}
+	return a.newRecoveryCodes(user)
+}
+
+func (a authUsecase) newRecoveryCodes(user domain.User) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	recoveryCodes := make([]domain.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, hash, err := helper.NewRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		recoveryCodes = append(recoveryCodes, domain.RecoveryCode{
+			ID:       uuid.NewV4(),
+			UserID:   user.ID,
+			CodeHash: hash,
+		})
+	}
+
+	if err := a.tokenRepository.ReplaceRecoveryCodes(user.ID.String(), recoveryCodes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// CheckTwoFactor returns an error while a user whose role requires two-factor authentication
+// has not enabled it.
+func (a authUsecase) CheckTwoFactor(id string) error {
+	user, err := a.userRepository.FindUserById(id)
+	if err != nil {
+		return err
+	}
+	if a.twoFactorRequired(user) && !user.TwoFactorEnabled() {
+		return errors.New("two-factor authentication required")
+	}
+	return nil
+}
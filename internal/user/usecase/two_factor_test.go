@@ -0,0 +1,334 @@
+package usecase_test
+
+import (
+	"errors"
+	"github.com/nrmadi02/mini-project/domain"
+	"github.com/nrmadi02/mini-project/domain/mocks"
+	"github.com/nrmadi02/mini-project/internal/mail"
+	"github.com/nrmadi02/mini-project/internal/user/delivery/http/helper"
+	"github.com/nrmadi02/mini-project/internal/user/repository"
+	"github.com/nrmadi02/mini-project/internal/user/usecase"
+	"github.com/nrmadi02/mini-project/web/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func twoFactorUser() domain.User {
+	user := dummyUser[1]
+	user.TOTPSecret, _ = helper.NewTOTPSecret()
+	enabledAt := time.Now().Add(-time.Hour)
+	user.TOTPEnabledAt = &enabledAt
+	return user
+}
+
+func currentCode(user domain.User) string {
+	code, _ := helper.GenerateTOTP(user.TOTPSecret, time.Now())
+	return code
+}
+
+func TestAuthUsecase_LoginTwoFactorChallenge(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	user := twoFactorUser()
+
+	t.Run("success challenge instead of tokens", func(t *testing.T) {
+		req := request.LoginRequest{Email: user.Email, Password: "12345678"}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserByEmail", req.Email).Return(user, nil).Once()
+		mockTokenRepository.On("SaveTwoFactorChallenge", mock.MatchedBy(func(token domain.TwoFactorChallenge) bool {
+			return token.UserID == user.ID && len(token.TokenHash) == 64
+		})).Return(domain.TwoFactorChallenge{}, nil).Once()
+		res, err := uc.Login(req, domain.Actor{IP: "127.0.0.1"})
+		assert.NoError(t, err)
+		assert.True(t, res.TwoFactorRequired)
+		assert.NotEmpty(t, res.ChallengeToken)
+		assert.Empty(t, res.Token)
+		assert.Empty(t, res.RefreshToken)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_VerifyTwoFactorLogin(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	user := twoFactorUser()
+	challenge := domain.TwoFactorChallenge{
+		UserID:    user.ID,
+		TokenHash: helper.HashTwoFactorChallenge("challenge"),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	t.Run("success totp", func(t *testing.T) {
+		req := request.TwoFactorLoginRequest{ChallengeToken: "challenge", Code: currentCode(user)}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindTwoFactorChallengeByHash", challenge.TokenHash).Return(challenge, nil).Once()
+		mockUserRepository.On("FindUserById", user.ID.String()).Return(user, nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(updated domain.User) bool {
+			return updated.TOTPLastStep == helper.TOTPStep(time.Now()) || updated.TOTPLastStep == helper.TOTPStep(time.Now())-1
+		})).Return(user, nil).Once()
+		mockTokenRepository.On("UseTwoFactorChallenge", challenge).Return(nil).Once()
+		mockTokenRepository.On("SaveRefreshToken", mock.Anything).Return(domain.RefreshToken{}, nil).Once()
+		res, err := uc.VerifyTwoFactorLogin(req, domain.Actor{IP: "127.0.0.1"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Token)
+		assert.NotEmpty(t, res.RefreshToken)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("success recovery code", func(t *testing.T) {
+		req := request.TwoFactorLoginRequest{ChallengeToken: "challenge", Code: "ABCDE-FGHIJ"}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindTwoFactorChallengeByHash", challenge.TokenHash).Return(challenge, nil).Once()
+		mockUserRepository.On("FindUserById", user.ID.String()).Return(user, nil).Once()
+		mockTokenRepository.On("UseRecoveryCode", user.ID.String(), helper.HashRecoveryCode("abcdefghij")).Return(nil).Once()
+		mockTokenRepository.On("UseTwoFactorChallenge", challenge).Return(nil).Once()
+		mockTokenRepository.On("SaveRefreshToken", mock.Anything).Return(domain.RefreshToken{}, nil).Once()
+		res, err := uc.VerifyTwoFactorLogin(req, domain.Actor{IP: "127.0.0.1"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Token)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error code already used", func(t *testing.T) {
+		used := user
+		used.TOTPLastStep = helper.TOTPStep(time.Now()) + 1
+		req := request.TwoFactorLoginRequest{ChallengeToken: "challenge", Code: currentCode(user)}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindTwoFactorChallengeByHash", challenge.TokenHash).Return(challenge, nil).Once()
+		mockUserRepository.On("FindUserById", user.ID.String()).Return(used, nil).Once()
+		mockTokenRepository.On("UseRecoveryCode", user.ID.String(), mock.Anything).Return(errors.New("invalid recovery code")).Once()
+		_, err := uc.VerifyTwoFactorLogin(req, domain.Actor{IP: "127.0.0.1"})
+		assert.EqualError(t, err, "invalid code")
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error wrong code locks account", func(t *testing.T) {
+		attempts := repository.NewMemoryLoginAttemptRepository()
+		for i := 0; i < 4; i++ {
+			_, _ = attempts.RecordFailure("user:"+user.ID.String(), time.Now().Add(-time.Minute), 15*time.Minute)
+		}
+		req := request.TwoFactorLoginRequest{ChallengeToken: "challenge", Code: "000000"}
+		if req.Code == currentCode(user) {
+			req.Code = "111111"
+		}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), attempts, nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindTwoFactorChallengeByHash", challenge.TokenHash).Return(challenge, nil).Once()
+		mockUserRepository.On("FindUserById", user.ID.String()).Return(user, nil).Once()
+		mockTokenRepository.On("UseRecoveryCode", user.ID.String(), mock.Anything).Return(errors.New("invalid recovery code")).Once()
+		_, err := uc.VerifyTwoFactorLogin(req, domain.Actor{IP: "127.0.0.1"})
+		var throttled *domain.LoginThrottledError
+		assert.ErrorAs(t, err, &throttled)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error expired challenge", func(t *testing.T) {
+		expired := challenge
+		expired.ExpiresAt = time.Now().Add(-time.Second)
+		req := request.TwoFactorLoginRequest{ChallengeToken: "challenge", Code: currentCode(user)}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindTwoFactorChallengeByHash", challenge.TokenHash).Return(expired, nil).Once()
+		_, err := uc.VerifyTwoFactorLogin(req, domain.Actor{IP: "127.0.0.1"})
+		assert.EqualError(t, err, "challenge token expired")
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error unknown challenge", func(t *testing.T) {
+		req := request.TwoFactorLoginRequest{ChallengeToken: "unknown", Code: currentCode(user)}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockTokenRepository.On("FindTwoFactorChallengeByHash", helper.HashTwoFactorChallenge("unknown")).Return(domain.TwoFactorChallenge{}, errors.New("record not found")).Once()
+		_, err := uc.VerifyTwoFactorLogin(req, domain.Actor{IP: "127.0.0.1"})
+		assert.EqualError(t, err, "invalid challenge token")
+		mockTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_SetupTwoFactor(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("success", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return user.TOTPSecret != "" && user.TOTPEnabledAt == nil
+		})).Return(dummyUser[1], nil).Once()
+		res, err := uc.SetupTwoFactor(dummyUser[1].ID.String())
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Secret)
+		assert.True(t, strings.HasPrefix(res.ProvisioningURI, "otpauth://totp/"))
+		assert.Contains(t, res.ProvisioningURI, "secret="+res.Secret)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error already enabled", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(twoFactorUser(), nil).Once()
+		_, err := uc.SetupTwoFactor(dummyUser[1].ID.String())
+		assert.EqualError(t, err, "two-factor authentication already enabled")
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_EnableTwoFactor(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	pending := twoFactorUser()
+	pending.TOTPEnabledAt = nil
+
+	t.Run("success", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", pending.ID.String()).Return(pending, nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return user.TOTPEnabledAt == nil
+		})).Return(pending, nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return user.TOTPEnabledAt != nil
+		})).Return(pending, nil).Once()
+		mockTokenRepository.On("ReplaceRecoveryCodes", pending.ID.String(), mock.MatchedBy(func(codes []domain.RecoveryCode) bool {
+			return len(codes) == 10
+		})).Return(nil).Once()
+		codes, err := uc.EnableTwoFactor(pending.ID.String(), request.TwoFactorCodeRequest{Code: currentCode(pending)})
+		assert.NoError(t, err)
+		assert.Len(t, codes, 10)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error wrong code", func(t *testing.T) {
+		code := "000000"
+		if code == currentCode(pending) {
+			code = "111111"
+		}
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", pending.ID.String()).Return(pending, nil).Once()
+		_, err := uc.EnableTwoFactor(pending.ID.String(), request.TwoFactorCodeRequest{Code: code})
+		assert.EqualError(t, err, "invalid code")
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error not set up", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		_, err := uc.EnableTwoFactor(dummyUser[1].ID.String(), request.TwoFactorCodeRequest{Code: "123456"})
+		assert.EqualError(t, err, "two-factor authentication not set up")
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_DisableTwoFactor(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	user := twoFactorUser()
+
+	t.Run("success", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), []string{"ROLE_ADMIN"}, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", user.ID.String()).Return(user, nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(updated domain.User) bool {
+			return updated.TOTPSecret != ""
+		})).Return(user, nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(updated domain.User) bool {
+			return updated.TOTPSecret == "" && updated.TOTPEnabledAt == nil
+		})).Return(user, nil).Once()
+		mockTokenRepository.On("ReplaceRecoveryCodes", user.ID.String(), []domain.RecoveryCode(nil)).Return(nil).Once()
+		err := uc.DisableTwoFactor(user.ID.String(), request.DisableTwoFactorRequest{Password: "12345678", Code: currentCode(user)})
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error required for role", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), []string{"ROLE_CLIENT"}, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", user.ID.String()).Return(user, nil).Once()
+		err := uc.DisableTwoFactor(user.ID.String(), request.DisableTwoFactorRequest{Password: "12345678", Code: currentCode(user)})
+		assert.EqualError(t, err, "two-factor authentication is required for your role")
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error wrong password", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", user.ID.String()).Return(user, nil).Once()
+		err := uc.DisableTwoFactor(user.ID.String(), request.DisableTwoFactorRequest{Password: "wrong", Code: currentCode(user)})
+		assert.EqualError(t, err, "password wrong")
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_RegenerateRecoveryCodes(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	user := twoFactorUser()
+
+	t.Run("success", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", user.ID.String()).Return(user, nil).Once()
+		mockUserRepository.On("Update", mock.Anything).Return(user, nil).Once()
+		mockTokenRepository.On("ReplaceRecoveryCodes", user.ID.String(), mock.Anything).Return(nil).Once()
+		codes, err := uc.RegenerateRecoveryCodes(user.ID.String(), request.TwoFactorCodeRequest{Code: currentCode(user)})
+		assert.NoError(t, err)
+		assert.Len(t, codes, 10)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error recovery code not accepted", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), nil, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", user.ID.String()).Return(user, nil).Once()
+		_, err := uc.RegenerateRecoveryCodes(user.ID.String(), request.TwoFactorCodeRequest{Code: "abcde-fghij"})
+		assert.EqualError(t, err, "invalid code")
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestAuthUsecase_CheckTwoFactor(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	mockFavoriteRepository := new(mocks.FavoriteRepository)
+	mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("success enabled", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), []string{"ROLE_CLIENT"}, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(twoFactorUser(), nil).Once()
+		assert.NoError(t, uc.CheckTwoFactor(dummyUser[1].ID.String()))
+	})
+
+	t.Run("success role without requirement", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), []string{"ROLE_ADMIN"}, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		assert.NoError(t, uc.CheckTwoFactor(dummyUser[1].ID.String()))
+	})
+
+	t.Run("error required", func(t *testing.T) {
+		uc := usecase.NewAuthUsecase(mockUserRepository, mockRoleRepository, mockFavoriteRepository, mockEnterpriseRepository, mockTokenRepository, goJWT, mail.NewMemoryMailer(), repository.NewMemoryLoginAttemptRepository(), []string{"ROLE_CLIENT"}, nil, newAuditUsecase())
+		mockUserRepository.On("FindUserById", dummyUser[1].ID.String()).Return(dummyUser[1], nil).Once()
+		assert.EqualError(t, uc.CheckTwoFactor(dummyUser[1].ID.String()), "two-factor authentication required")
+		mockUserRepository.AssertExpectations(t)
+	})
+}
@@ -1,19 +1,340 @@
 package usecase
 
 import (
+	"errors"
 	"github.com/nrmadi02/mini-project/domain"
+	request2 "github.com/nrmadi02/mini-project/web/request"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"time"
 )
 
 type userUsecase struct {
-	UserRepo domain.UserRepository
+	UserRepo       domain.UserRepository
+	RoleRepo       domain.RoleRepository
+	TokenRepo      domain.TokenRepository
+	RatingRepo     domain.RatingRepository
+	ReviewRepo     domain.ReviewRepository
+	ImageRepo      domain.ImageRepository
+	EnterpriseRepo domain.EnterpriseRepository
+	AuthUsecase    domain.AuthUsecase
+	Blobs          domain.BlobStore
+	Searcher       domain.Searcher
+	Audit          domain.AuditUsecase
 }
 
-func NewUserUsecase(ur domain.UserRepository) domain.UserUsecase {
+func NewUserUsecase(ur domain.UserRepository, rr domain.RoleRepository, tr domain.TokenRepository, rtr domain.RatingRepository, rvr domain.ReviewRepository, ir domain.ImageRepository, er domain.EnterpriseRepository, au domain.AuthUsecase, blobs domain.BlobStore, searcher domain.Searcher, audit domain.AuditUsecase) domain.UserUsecase {
 	return userUsecase{
-		UserRepo: ur,
+		UserRepo:       ur,
+		RoleRepo:       rr,
+		TokenRepo:      tr,
+		RatingRepo:     rtr,
+		ReviewRepo:     rvr,
+		ImageRepo:      ir,
+		EnterpriseRepo: er,
+		AuthUsecase:    au,
+		Blobs:          blobs,
+		Searcher:       searcher,
+		Audit:          audit,
 	}
 }
 
-func (u userUsecase) GetAllUsers() (domain.Users, error) {
-	return u.UserRepo.FindAllUsers()
+// userAuditFields is the state of the user the admin actions change.
+func userAuditFields(user domain.User) map[string]interface{} {
+	roles := make([]string, 0, len(user.Roles))
+	for _, r := range user.Roles {
+		roles = append(roles, r.Name)
+	}
+	return map[string]interface{}{
+		"email":                   user.Email,
+		"username":                user.Username,
+		"roles":                   roles,
+		"suspended_at":            user.SuspendedAt,
+		"suspended_reason":        user.SuspendedReason,
+		"password_reset_required": user.PasswordResetRequired,
+	}
+}
+
+func (u userUsecase) GetAllUsers(page domain.PageRequest) (domain.Users, domain.PageInfo, error) {
+	return u.UserRepo.FindAllUsers(page)
+}
+
+func (u userUsecase) GetUserByID(id string) (domain.User, error) {
+	return u.UserRepo.FindUserById(id)
+}
+
+func (u userUsecase) AssignRole(id string, actor domain.Actor, roleName string) (domain.User, error) {
+	user, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	newRole, err := u.RoleRepo.FindByName(roleName)
+	if err != nil {
+		return domain.User{}, errors.New("role not found - " + roleName)
+	}
+	for _, r := range user.Roles {
+		if r.ID == newRole.ID {
+			return domain.User{}, errors.New("user already has role " + roleName)
+		}
+	}
+
+	if err = u.UserRepo.AddRole(user, newRole); err != nil {
+		return domain.User{}, err
+	}
+	updated, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	u.Audit.Record(actor, domain.AuditRoleAssign, domain.AuditTargetUser, id, userAuditFields(user), userAuditFields(updated))
+	return updated, nil
+}
+
+// RevokeRole refuses to touch the actor's own roles, an admin could otherwise lock everyone out.
+func (u userUsecase) RevokeRole(id string, actor domain.Actor, roleName string) (domain.User, error) {
+	if id == actor.UserID {
+		return domain.User{}, errors.New("cannot revoke your own role")
+	}
+	user, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	for _, r := range user.Roles {
+		if r.Name == roleName {
+			if err = u.UserRepo.RemoveRole(user, r); err != nil {
+				return domain.User{}, err
+			}
+			updated, err := u.UserRepo.FindUserById(id)
+			if err != nil {
+				return domain.User{}, err
+			}
+			u.Audit.Record(actor, domain.AuditRoleRevoke, domain.AuditTargetUser, id, userAuditFields(user), userAuditFields(updated))
+			return updated, nil
+		}
+	}
+	return domain.User{}, errors.New("user does not have role " + roleName)
+}
+
+// SuspendUser blocks login and every authenticated request, and ends the user's sessions.
+func (u userUsecase) SuspendUser(id string, actor domain.Actor, request request2.SuspendUserRequest) (domain.User, error) {
+	if id == actor.UserID {
+		return domain.User{}, errors.New("cannot suspend yourself")
+	}
+	if request.Reason == "" {
+		return domain.User{}, errors.New("reason is required")
+	}
+	user, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.SuspendedAt != nil {
+		return domain.User{}, errors.New("user already suspended")
+	}
+
+	before := userAuditFields(user)
+	now := time.Now()
+	user.SuspendedAt = &now
+	user.SuspendedReason = request.Reason
+	user, err = u.UserRepo.Update(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err = u.TokenRepo.RevokeUserRefreshTokens(id); err != nil {
+		return domain.User{}, err
+	}
+	u.Audit.Record(actor, domain.AuditUserSuspend, domain.AuditTargetUser, id, before, userAuditFields(user))
+	return user, nil
+}
+
+func (u userUsecase) ReactivateUser(id string, actor domain.Actor) (domain.User, error) {
+	user, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.SuspendedAt == nil {
+		return domain.User{}, errors.New("user is not suspended")
+	}
+
+	before := userAuditFields(user)
+	user.SuspendedAt = nil
+	user.SuspendedReason = ""
+	user, err = u.UserRepo.Update(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	u.Audit.Record(actor, domain.AuditUserReactivate, domain.AuditTargetUser, id, before, userAuditFields(user))
+	return user, nil
+}
+
+// ForcePasswordReset ends the user's sessions, login is refused until the password is changed.
+func (u userUsecase) ForcePasswordReset(id string, actor domain.Actor) (domain.User, error) {
+	user, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	before := userAuditFields(user)
+	user.PasswordResetRequired = true
+	user, err = u.UserRepo.Update(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err = u.TokenRepo.RevokeUserRefreshTokens(id); err != nil {
+		return domain.User{}, err
+	}
+	u.Audit.Record(actor, domain.AuditUserPasswordReset, domain.AuditTargetUser, id, before, userAuditFields(user))
+	return user, nil
+}
+
+// DeleteUser removes the user together with their enterprises, ratings, reviews and favorites.
+func (u userUsecase) DeleteUser(id string, actor domain.Actor) error {
+	if id == actor.UserID {
+		return errors.New("cannot delete yourself")
+	}
+	user, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return err
+	}
+	images, err := u.ImageRepo.FindByUserID(id)
+	if err != nil {
+		return err
+	}
+	enterprises, err := u.EnterpriseRepo.FindByUserID(id)
+	if err != nil {
+		return err
+	}
+	if err = u.UserRepo.Delete(user); err != nil {
+		return err
+	}
+	u.removeEnterprises(enterprises)
+	u.deleteImageFiles(images)
+	u.Audit.Record(actor, domain.AuditUserDelete, domain.AuditTargetUser, id, userAuditFields(user), nil)
+	return nil
+}
+
+// UpdateProfile changes the user's own fullname, username and email. Username and email
+// must not belong to another user, a new email has to be verified again.
+func (u userUsecase) UpdateProfile(id string, request request2.UserUpdateRequest) (domain.User, error) {
+	user, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if request.Username != user.Username {
+		existingUser, err := u.UserRepo.FindUserByUsername(request.Username)
+		if err == nil && existingUser.ID != user.ID {
+			return domain.User{}, errors.New("username already used")
+		}
+	}
+	if request.Email != user.Email {
+		existingUser, err := u.UserRepo.FindUserByEmail(request.Email)
+		if err == nil && existingUser.ID != user.ID {
+			return domain.User{}, errors.New("email already used")
+		}
+	}
+
+	if request.Email != user.Email {
+		user.EmailVerifiedAt = nil
+	}
+	user.Fullname = request.Fullname
+	user.Username = request.Username
+	user.Email = request.Email
+	return u.UserRepo.Update(user)
+}
+
+// ChangePassword requires the current password. Refresh tokens of every session are revoked,
+// so a stolen session does not outlive the password it was opened with.
+func (u userUsecase) ChangePassword(id string, request request2.ChangePasswordRequest) error {
+	user, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return err
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.CurrentPassword)); err != nil {
+		return errors.New("current password wrong")
+	}
+
+	password, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(password)
+	user.PasswordResetRequired = false
+	if _, err = u.UserRepo.Update(user); err != nil {
+		return err
+	}
+	return u.TokenRepo.RevokeUserRefreshTokens(id)
+}
+
+// ExportUserData collects the user's profile, enterprises, favorites, ratings and reviews.
+func (u userUsecase) ExportUserData(id string) (domain.UserExport, error) {
+	user, favorite, enterprises, err := u.AuthUsecase.GetUserDetails(id)
+	if err != nil {
+		return domain.UserExport{}, err
+	}
+	ratings, err := u.RatingRepo.FindByUserID(id)
+	if err != nil {
+		return domain.UserExport{}, err
+	}
+	reviews, err := u.ReviewRepo.FindByUserID(id)
+	if err != nil {
+		return domain.UserExport{}, err
+	}
+
+	return domain.UserExport{
+		User:        user,
+		Favorite:    favorite,
+		Enterprises: enterprises,
+		Ratings:     ratings,
+		Reviews:     reviews,
+		ExportedAt:  time.Now(),
+	}, nil
+}
+
+// EraseAccount deletes the user's account on their own request. The password is asked again
+// because it cannot be undone. Ratings and reviews stay under the anonymized user.
+func (u userUsecase) EraseAccount(id string, actor domain.Actor, request request2.DeleteAccountRequest) error {
+	user, err := u.UserRepo.FindUserById(id)
+	if err != nil {
+		return err
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+		return errors.New("password wrong")
+	}
+	images, err := u.ImageRepo.FindByUserID(id)
+	if err != nil {
+		return err
+	}
+	enterprises, err := u.EnterpriseRepo.FindByUserID(id)
+	if err != nil {
+		return err
+	}
+
+	if err = u.UserRepo.Erase(user.Anonymize(time.Now())); err != nil {
+		return err
+	}
+	u.removeEnterprises(enterprises)
+	u.deleteImageFiles(images)
+	// the entry only has the id, the personal fields are gone with the account
+	u.Audit.Record(actor, domain.AuditUserErase, domain.AuditTargetUser, id, nil, nil)
+	return nil
+}
+
+// removeEnterprises takes the enterprises of a deleted user out of the search index. A failure
+// only leaves stale hits until the next reindex.
+func (u userUsecase) removeEnterprises(enterprises domain.Enterprises) {
+	for _, enterprise := range enterprises {
+		if err := u.Searcher.Remove(enterprise.ID.String()); err != nil {
+			log.WithField("enterprise_id", enterprise.ID.String()).Warn("remove enterprise from search: " + err.Error())
+		}
+	}
+}
+
+// deleteImageFiles removes the files of the images of a deleted user's enterprises, the rows are
+// deleted with the enterprises. A file left behind is only logged.
+func (u userUsecase) deleteImageFiles(images domain.EnterpriseImages) {
+	for _, image := range images {
+		if err := u.Blobs.Delete(image.Key); err != nil {
+			log.WithField("key", image.Key).Warn("delete image file: " + err.Error())
+		}
+	}
 }
@@ -5,8 +5,10 @@ import (
 	"github.com/nrmadi02/mini-project/domain"
 	"github.com/nrmadi02/mini-project/domain/mocks"
 	"github.com/nrmadi02/mini-project/internal/user/usecase"
+	"github.com/nrmadi02/mini-project/web/request"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
 	"testing"
 	"time"
@@ -52,23 +54,407 @@ var dummyUser = domain.Users{
 	},
 }
 
+// newImageRepository finds no images, the users of the tests own no enterprises with images.
+func newImageRepository() *mocks.ImageRepository {
+	imageRepository := new(mocks.ImageRepository)
+	imageRepository.On("FindByUserID", mock.Anything).Return(domain.EnterpriseImages{}, nil).Maybe()
+	return imageRepository
+}
+
+// newEnterpriseRepository finds no enterprises, the users of the tests own none.
+func newEnterpriseRepository() *mocks.EnterpriseRepository {
+	enterpriseRepository := new(mocks.EnterpriseRepository)
+	enterpriseRepository.On("FindByUserID", mock.Anything).Return(domain.Enterprises{}, nil).Maybe()
+	return enterpriseRepository
+}
+
+// newSearcher accepts any index change.
+func newSearcher() *mocks.Searcher {
+	searcher := new(mocks.Searcher)
+	searcher.On("Remove", mock.Anything).Return(nil).Maybe()
+	return searcher
+}
+
+// newBlobStore accepts every file deletion.
+func newBlobStore() *mocks.BlobStore {
+	blobs := new(mocks.BlobStore)
+	blobs.On("Delete", mock.Anything).Return(nil).Maybe()
+	return blobs
+}
+
 func TestUserUsecase_GetAllUsers(t *testing.T) {
 	mockUserRepository := new(mocks.UserRepository)
 
 	t.Run("success", func(t *testing.T) {
-		mockUserRepository.On("FindAllUsers").Return(dummyUser, nil).Once()
-		uc := usecase.NewUserUsecase(mockUserRepository)
-		res, err := uc.GetAllUsers()
+		mockUserRepository.On("FindAllUsers", domain.PageRequest{Length: 20}).Return(dummyUser, domain.PageInfo{}, nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		res, _, err := uc.GetAllUsers(domain.PageRequest{Length: 20})
 		assert.NoError(t, err)
 		assert.Len(t, res, len(dummyUser))
 		mockUserRepository.AssertExpectations(t)
 	})
 
 	t.Run("error-failed", func(t *testing.T) {
-		mockUserRepository.On("FindAllUsers").Return(nil, errors.New("error something")).Once()
-		uc := usecase.NewUserUsecase(mockUserRepository)
-		_, err := uc.GetAllUsers()
+		mockUserRepository.On("FindAllUsers", domain.PageRequest{Length: 20}).Return(nil, domain.PageInfo{}, errors.New("error something")).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, _, err := uc.GetAllUsers(domain.PageRequest{Length: 20})
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+var adminID = dummyUser[0].ID.String()
+var clientID = dummyUser[1].ID.String()
+
+func TestUserUsecase_AssignRole(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockRoleRepository := new(mocks.RoleRepository)
+	moderator := domain.Role{ID: 4, Name: "ROLE_MODERATOR"}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Twice()
+		mockRoleRepository.On("FindByName", moderator.Name).Return(moderator, nil).Once()
+		mockUserRepository.On("AddRole", dummyUser[1], moderator).Return(nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, mockRoleRepository, new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.AssignRole(clientID, domain.Actor{UserID: adminID}, moderator.Name)
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockRoleRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-role-not-found", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockRoleRepository.On("FindByName", "ROLE_UNKNOWN").Return(domain.Role{}, errors.New("record not found")).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, mockRoleRepository, new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.AssignRole(clientID, domain.Actor{UserID: adminID}, "ROLE_UNKNOWN")
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-already-has-role", func(t *testing.T) {
+		client := domain.Role{ID: 1, Name: "ROLE_CLIENT"}
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockRoleRepository.On("FindByName", client.Name).Return(client, nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, mockRoleRepository, new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.AssignRole(clientID, domain.Actor{UserID: adminID}, client.Name)
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_RevokeRole(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Twice()
+		mockUserRepository.On("RemoveRole", dummyUser[1], dummyUser[1].Roles[0]).Return(nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.RevokeRole(clientID, domain.Actor{UserID: adminID}, "ROLE_CLIENT")
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-own-role", func(t *testing.T) {
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.RevokeRole(adminID, domain.Actor{UserID: adminID}, "ROLE_ADMIN")
+		assert.Error(t, err)
+	})
+
+	t.Run("error-role-not-held", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.RevokeRole(clientID, domain.Actor{UserID: adminID}, "ROLE_ADMIN")
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_SuspendUser(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+	req := request.SuspendUserRequest{Reason: "spam"}
+
+	t.Run("success", func(t *testing.T) {
+		suspended := dummyUser[1]
+		suspended.SuspendedReason = req.Reason
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return user.SuspendedAt != nil && user.SuspendedReason == req.Reason
+		})).Return(suspended, nil).Once()
+		mockTokenRepository.On("RevokeUserRefreshTokens", clientID).Return(nil).Once()
+		mockAuditUsecase := new(mocks.AuditUsecase)
+		mockAuditUsecase.On("Record", domain.Actor{UserID: adminID}, domain.AuditUserSuspend, domain.AuditTargetUser, clientID,
+			mock.MatchedBy(func(before map[string]interface{}) bool {
+				return before["suspended_reason"] == ""
+			}),
+			mock.MatchedBy(func(after map[string]interface{}) bool {
+				return after["suspended_reason"] == req.Reason
+			})).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), mockTokenRepository, new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), mockAuditUsecase)
+		_, err := uc.SuspendUser(clientID, domain.Actor{UserID: adminID}, req)
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+		mockAuditUsecase.AssertExpectations(t)
+	})
+
+	t.Run("removes-enterprises-from-search", func(t *testing.T) {
+		enterprise := domain.Enterprise{ID: uuid.NewV4(), UserID: dummyUser[1].ID}
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		searcher := new(mocks.Searcher)
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockEnterpriseRepository.On("FindByUserID", clientID).Return(domain.Enterprises{enterprise}, nil).Once()
+		mockUserRepository.On("Delete", dummyUser[1]).Return(nil).Once()
+		searcher.On("Remove", enterprise.ID.String()).Return(nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), mockEnterpriseRepository, new(mocks.AuthUsecase), newBlobStore(), searcher, newAuditUsecase())
+		err := uc.DeleteUser(clientID, domain.Actor{UserID: adminID})
+		assert.NoError(t, err)
+		searcher.AssertExpectations(t)
+	})
+
+	t.Run("error-self", func(t *testing.T) {
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), mockTokenRepository, new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.SuspendUser(adminID, domain.Actor{UserID: adminID}, req)
+		assert.Error(t, err)
+	})
+
+	t.Run("error-no-reason", func(t *testing.T) {
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), mockTokenRepository, new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.SuspendUser(clientID, domain.Actor{UserID: adminID}, request.SuspendUserRequest{})
+		assert.Error(t, err)
+	})
+
+	t.Run("error-already-suspended", func(t *testing.T) {
+		suspended := dummyUser[1]
+		now := time.Now()
+		suspended.SuspendedAt = &now
+		mockUserRepository.On("FindUserById", clientID).Return(suspended, nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), mockTokenRepository, new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.SuspendUser(clientID, domain.Actor{UserID: adminID}, req)
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_ReactivateUser(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+
+	t.Run("success", func(t *testing.T) {
+		suspended := dummyUser[1]
+		now := time.Now()
+		suspended.SuspendedAt = &now
+		suspended.SuspendedReason = "spam"
+		mockUserRepository.On("FindUserById", clientID).Return(suspended, nil).Once()
+		mockUserRepository.On("Update", dummyUser[1]).Return(dummyUser[1], nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.ReactivateUser(clientID, domain.Actor{UserID: adminID})
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-not-suspended", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.ReactivateUser(clientID, domain.Actor{UserID: adminID})
 		assert.Error(t, err)
 		mockUserRepository.AssertExpectations(t)
 	})
 }
+
+func TestUserUsecase_ForcePasswordReset(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return user.PasswordResetRequired
+		})).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("RevokeUserRefreshTokens", clientID).Return(nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), mockTokenRepository, new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.ForcePasswordReset(clientID, domain.Actor{UserID: adminID})
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-user-not-found", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(domain.User{}, errors.New("record not found")).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), mockTokenRepository, new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.ForcePasswordReset(clientID, domain.Actor{UserID: adminID})
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_DeleteUser(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockUserRepository.On("Delete", dummyUser[1]).Return(nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		err := uc.DeleteUser(clientID, domain.Actor{UserID: adminID})
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-self", func(t *testing.T) {
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		err := uc.DeleteUser(adminID, domain.Actor{UserID: adminID})
+		assert.Error(t, err)
+	})
+}
+
+func TestUserUsecase_UpdateProfile(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	req := request.UserUpdateRequest{
+		Fullname: "user dua",
+		Username: "usr2-new",
+		Email:    "dua-new@email.com",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		verified := dummyUser[1]
+		now := time.Now()
+		verified.EmailVerifiedAt = &now
+		mockUserRepository.On("FindUserById", clientID).Return(verified, nil).Once()
+		mockUserRepository.On("FindUserByUsername", req.Username).Return(domain.User{}, errors.New("record not found")).Once()
+		mockUserRepository.On("FindUserByEmail", req.Email).Return(domain.User{}, errors.New("record not found")).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return user.Username == req.Username && user.Email == req.Email && user.Fullname == req.Fullname && user.EmailVerifiedAt == nil
+		})).Return(dummyUser[1], nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.UpdateProfile(clientID, req)
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-username-used", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockUserRepository.On("FindUserByUsername", req.Username).Return(dummyUser[0], nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.UpdateProfile(clientID, req)
+		assert.EqualError(t, err, "username already used")
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-email-used", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockUserRepository.On("FindUserByUsername", req.Username).Return(domain.User{}, errors.New("record not found")).Once()
+		mockUserRepository.On("FindUserByEmail", req.Email).Return(dummyUser[0], nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.UpdateProfile(clientID, req)
+		assert.EqualError(t, err, "email already used")
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_ChangePassword(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockTokenRepository := new(mocks.TokenRepository)
+
+	t.Run("success", func(t *testing.T) {
+		resetRequired := dummyUser[1]
+		resetRequired.PasswordResetRequired = true
+		mockUserRepository.On("FindUserById", clientID).Return(resetRequired, nil).Once()
+		mockUserRepository.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return !user.PasswordResetRequired && bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("87654321")) == nil
+		})).Return(dummyUser[1], nil).Once()
+		mockTokenRepository.On("RevokeUserRefreshTokens", clientID).Return(nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), mockTokenRepository, new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		err := uc.ChangePassword(clientID, request.ChangePasswordRequest{CurrentPassword: "12345678", NewPassword: "87654321"})
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("error-current-password-wrong", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), mockTokenRepository, new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		err := uc.ChangePassword(clientID, request.ChangePasswordRequest{CurrentPassword: "wrong-password", NewPassword: "87654321"})
+		assert.EqualError(t, err, "current password wrong")
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_ExportUserData(t *testing.T) {
+	mockAuthUsecase := new(mocks.AuthUsecase)
+	mockRatingRepository := new(mocks.RatingRepository)
+	mockReviewRepository := new(mocks.ReviewRepository)
+
+	t.Run("success", func(t *testing.T) {
+		mockAuthUsecase.On("GetUserDetails", clientID).Return(dummyUser[1], domain.Favorite{}, domain.Enterprises{}, nil).Once()
+		mockRatingRepository.On("FindByUserID", clientID).Return(domain.RatingEnterprises{{Rating: 4, UserID: dummyUser[1].ID}}, nil).Once()
+		mockReviewRepository.On("FindByUserID", clientID).Return(domain.Reviews{{Review: "mantap", UserID: dummyUser[1].ID}}, nil).Once()
+		uc := usecase.NewUserUsecase(new(mocks.UserRepository), new(mocks.RoleRepository), new(mocks.TokenRepository), mockRatingRepository, mockReviewRepository, newImageRepository(), newEnterpriseRepository(), mockAuthUsecase, newBlobStore(), newSearcher(), newAuditUsecase())
+		export, err := uc.ExportUserData(clientID)
+		assert.NoError(t, err)
+		assert.Equal(t, dummyUser[1].ID, export.User.ID)
+		assert.Len(t, export.Ratings, 1)
+		assert.Len(t, export.Reviews, 1)
+		assert.False(t, export.ExportedAt.IsZero())
+	})
+
+	t.Run("error-user", func(t *testing.T) {
+		mockAuthUsecase.On("GetUserDetails", clientID).Return(domain.User{}, domain.Favorite{}, domain.Enterprises{}, errors.New("user not found")).Once()
+		uc := usecase.NewUserUsecase(new(mocks.UserRepository), new(mocks.RoleRepository), new(mocks.TokenRepository), mockRatingRepository, mockReviewRepository, newImageRepository(), newEnterpriseRepository(), mockAuthUsecase, newBlobStore(), newSearcher(), newAuditUsecase())
+		_, err := uc.ExportUserData(clientID)
+		assert.EqualError(t, err, "user not found")
+	})
+}
+
+func TestUserUsecase_EraseAccount(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	actor := domain.Actor{UserID: clientID}
+
+	t.Run("success", func(t *testing.T) {
+		mockAudit := new(mocks.AuditUsecase)
+		mockAudit.On("Record", actor, domain.AuditUserErase, domain.AuditTargetUser, clientID, mock.Anything, mock.Anything).Once()
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockUserRepository.On("Erase", mock.MatchedBy(func(user domain.User) bool {
+			return user.ID == dummyUser[1].ID && user.Email == "" && user.Password == "" && user.ErasedAt != nil
+		})).Return(nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), mockAudit)
+		err := uc.EraseAccount(clientID, actor, request.DeleteAccountRequest{Password: "12345678"})
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("error-password", func(t *testing.T) {
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), newEnterpriseRepository(), new(mocks.AuthUsecase), newBlobStore(), newSearcher(), newAuditUsecase())
+		err := uc.EraseAccount(clientID, actor, request.DeleteAccountRequest{Password: "wrong-password"})
+		assert.EqualError(t, err, "password wrong")
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("deletes-image-files", func(t *testing.T) {
+		image := domain.EnterpriseImage{ID: uuid.NewV4(), Kind: domain.ImageLogo, Key: "enterprises/" + uuid.NewV4().String() + "/logo.png"}
+		mockImageRepository := new(mocks.ImageRepository)
+		blobs := new(mocks.BlobStore)
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockImageRepository.On("FindByUserID", clientID).Return(domain.EnterpriseImages{image}, nil).Once()
+		mockUserRepository.On("Erase", mock.AnythingOfType("domain.User")).Return(nil).Once()
+		blobs.On("Delete", image.Key).Return(nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), mockImageRepository, newEnterpriseRepository(), new(mocks.AuthUsecase), blobs, newSearcher(), newAuditUsecase())
+		err := uc.EraseAccount(clientID, actor, request.DeleteAccountRequest{Password: "12345678"})
+		assert.NoError(t, err)
+		blobs.AssertExpectations(t)
+	})
+
+	t.Run("removes-enterprises-from-search", func(t *testing.T) {
+		enterprise := domain.Enterprise{ID: uuid.NewV4(), UserID: dummyUser[1].ID}
+		mockEnterpriseRepository := new(mocks.EnterpriseRepository)
+		searcher := new(mocks.Searcher)
+		mockUserRepository.On("FindUserById", clientID).Return(dummyUser[1], nil).Once()
+		mockEnterpriseRepository.On("FindByUserID", clientID).Return(domain.Enterprises{enterprise}, nil).Once()
+		mockUserRepository.On("Erase", mock.AnythingOfType("domain.User")).Return(nil).Once()
+		searcher.On("Remove", enterprise.ID.String()).Return(nil).Once()
+		uc := usecase.NewUserUsecase(mockUserRepository, new(mocks.RoleRepository), new(mocks.TokenRepository), new(mocks.RatingRepository), new(mocks.ReviewRepository), newImageRepository(), mockEnterpriseRepository, new(mocks.AuthUsecase), newBlobStore(), searcher, newAuditUsecase())
+		err := uc.EraseAccount(clientID, actor, request.DeleteAccountRequest{Password: "12345678"})
+		assert.NoError(t, err)
+		searcher.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,9 @@
+package request
+
+type AssignRoleRequest struct {
+	Role string `json:"role" form:"role"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" form:"reason"`
+}
@@ -0,0 +1,28 @@
+package request
+
+import (
+	"errors"
+	"time"
+)
+
+type APIKeyCreateRequest struct {
+	Name      string     `json:"name" form:"name"`
+	Scopes    []string   `json:"scopes" form:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at" form:"expires_at"`
+}
+
+func ValidateAPIKeyCreate(keyRequest APIKeyCreateRequest) (bool, error) {
+	if keyRequest.Name == "" {
+		return false, errors.New("name empty")
+	}
+	if len(keyRequest.Name) > 100 {
+		return false, errors.New("name, maximum 100 characters")
+	}
+	if len(keyRequest.Scopes) == 0 {
+		return false, errors.New("scopes empty")
+	}
+	if keyRequest.ExpiresAt != nil && !keyRequest.ExpiresAt.After(time.Now()) {
+		return false, errors.New("expires_at must be in the future")
+	}
+	return true, nil
+}
@@ -1,5 +1,13 @@
 package request
 
+import (
+	"errors"
+	uuid "github.com/satori/go.uuid"
+	"math"
+	"strings"
+	"time"
+)
+
 type CreateEnterpriseRequest struct {
 	Name        string   `json:"name"`
 	NumberPhone string   `json:"number_phone"`
@@ -7,6 +15,113 @@ type CreateEnterpriseRequest struct {
 	Postcode    int      `json:"postcode"`
 	Description string   `json:"description"`
 	Tags        []string `json:"tags"`
-	Latitude    string   `json:"latitude"`
-	Longitude   string   `json:"longitude"`
+	Latitude    *float64 `json:"latitude"`
+	Longitude   *float64 `json:"longitude"`
+}
+
+type UpdateStatusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+type DistanceRequest struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Unit      string  `json:"unit"`
+}
+
+type NearbyRequest struct {
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	RadiusKm  float64  `json:"radius_km"`
+	Tags      []string `json:"tags"`
+}
+
+type ListEnterpriseRequest struct {
+	Search      string
+	Tags        []string
+	TagMatch    string
+	Status      string
+	Postcode    int
+	OwnerID     string
+	MinRating   float64
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Sort        string
+	Latitude    *float64
+	Longitude   *float64
+}
+
+func ValidateListEnterprise(listRequest ListEnterpriseRequest) (bool, error) {
+	if listRequest.TagMatch != "" && listRequest.TagMatch != "any" && listRequest.TagMatch != "all" {
+		return false, errors.New("tag_match must be any or all")
+	}
+	for _, tag := range listRequest.Tags {
+		if _, err := uuid.FromString(tag); err != nil {
+			return false, errors.New("tags must be tag ids")
+		}
+	}
+	if listRequest.OwnerID != "" {
+		if _, err := uuid.FromString(listRequest.OwnerID); err != nil {
+			return false, errors.New("owner must be a user id")
+		}
+	}
+	if listRequest.Postcode < 0 {
+		return false, errors.New("postcode must not be negative")
+	}
+	if listRequest.MinRating < 0 || listRequest.MinRating > 5 {
+		return false, errors.New("min_rating must be between 0 and 5")
+	}
+	if listRequest.CreatedFrom != nil && listRequest.CreatedTo != nil && listRequest.CreatedTo.Before(*listRequest.CreatedFrom) {
+		return false, errors.New("created_to must not be before created_from")
+	}
+	switch listRequest.Sort {
+	case "", "name", "rating", "newest":
+	case "relevance":
+		if strings.TrimSpace(listRequest.Search) == "" {
+			return false, errors.New("sort by relevance needs search")
+		}
+	case "distance":
+		if listRequest.Latitude == nil || listRequest.Longitude == nil {
+			return false, errors.New("sort by distance needs lat and lon")
+		}
+		if err := validateCoordinate(*listRequest.Latitude, *listRequest.Longitude); err != nil {
+			return false, err
+		}
+	default:
+		return false, errors.New("sort must be name, rating, newest, distance or relevance")
+	}
+	return true, nil
+}
+
+func ValidateNearby(nearbyRequest NearbyRequest) (bool, error) {
+	if err := validateCoordinate(nearbyRequest.Latitude, nearbyRequest.Longitude); err != nil {
+		return false, err
+	}
+	if math.IsNaN(nearbyRequest.RadiusKm) || nearbyRequest.RadiusKm <= 0 || nearbyRequest.RadiusKm > 100 {
+		return false, errors.New("radius_km must be greater than 0 and at most 100")
+	}
+	return true, nil
+}
+
+func ValidateDistance(distanceRequest DistanceRequest) (bool, error) {
+	if err := validateCoordinate(distanceRequest.Latitude, distanceRequest.Longitude); err != nil {
+		return false, err
+	}
+	if distanceRequest.Unit != "km" && distanceRequest.Unit != "mi" {
+		return false, errors.New("unit must be km or mi")
+	}
+	return true, nil
+}
+
+// validateCoordinate checks the range of a coordinate parsed from the query. strconv.ParseFloat
+// accepts "NaN", which passes every comparison, so it is rejected first.
+func validateCoordinate(latitude, longitude float64) error {
+	if math.IsNaN(latitude) || latitude < -90 || latitude > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if math.IsNaN(longitude) || longitude < -180 || longitude > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	return nil
 }
@@ -1,6 +1,31 @@
 package request
 
+import "errors"
+
 type LoginRequest struct {
 	Email    string `json:"email" form:"email"`
 	Password string `json:"password" form:"password"`
 }
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" form:"refresh_token"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" form:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" form:"token"`
+	NewPassword string `json:"new_password" form:"new_password"`
+}
+
+func ValidateResetPassword(resetRequest ResetPasswordRequest) (bool, error) {
+	if resetRequest.Token == "" {
+		return false, errors.New("token empty")
+	}
+	if len(resetRequest.NewPassword) < 8 {
+		return false, errors.New("password, minimum 8 words")
+	}
+	return true, nil
+}
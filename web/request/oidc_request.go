@@ -0,0 +1,19 @@
+package request
+
+import "errors"
+
+// OIDCCallbackRequest carries the code and state the provider sent to the redirect URL.
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" form:"code"`
+	State string `json:"state" form:"state"`
+}
+
+func ValidateOIDCCallback(callbackRequest OIDCCallbackRequest) (bool, error) {
+	if callbackRequest.Code == "" {
+		return false, errors.New("code empty")
+	}
+	if callbackRequest.State == "" {
+		return false, errors.New("state empty")
+	}
+	return true, nil
+}
@@ -0,0 +1,29 @@
+package request
+
+import "errors"
+
+// TwoFactorCodeRequest carries a code from the authenticator app.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" form:"code"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" form:"password"`
+	Code     string `json:"code" form:"code"`
+}
+
+// TwoFactorLoginRequest is the second login step, Code is a TOTP or a recovery code.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" form:"challenge_token"`
+	Code           string `json:"code" form:"code"`
+}
+
+func ValidateTwoFactorLogin(loginRequest TwoFactorLoginRequest) (bool, error) {
+	if loginRequest.ChallengeToken == "" {
+		return false, errors.New("challenge token empty")
+	}
+	if loginRequest.Code == "" {
+		return false, errors.New("code empty")
+	}
+	return true, nil
+}
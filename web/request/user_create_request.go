@@ -1,6 +1,10 @@
 package request
 
-import "errors"
+import (
+	"errors"
+	"net/mail"
+	"strings"
+)
 
 type UserCreateRequest struct {
 	Fullname string `json:"fullname"`
@@ -16,7 +20,7 @@ func ValidateCreation(userRequest UserCreateRequest) (bool, error) {
 	if userRequest.Username == "" || len(userRequest.Username) == 0 {
 		return false, errors.New("username empty")
 	}
-	if userRequest.Email == "" || len(userRequest.Email) < 6 {
+	if !validEmail(userRequest.Email) {
 		return false, errors.New("email invalid")
 	}
 	if userRequest.Password == "" || len(userRequest.Password) < 8 {
@@ -24,3 +28,9 @@ func ValidateCreation(userRequest UserCreateRequest) (bool, error) {
 	}
 	return true, nil
 }
+
+// validEmail accepts a bare address like user@example.com, without display name.
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email && strings.Contains(email[strings.LastIndex(email, "@")+1:], ".")
+}
@@ -0,0 +1,51 @@
+package request
+
+import "errors"
+
+type UserUpdateRequest struct {
+	Fullname string `json:"fullname"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func ValidateUpdate(userRequest UserUpdateRequest) (bool, error) {
+	if userRequest.Fullname == "" {
+		return false, errors.New("fullname empty")
+	}
+	if userRequest.Username == "" {
+		return false, errors.New("username empty")
+	}
+	if !validEmail(userRequest.Email) {
+		return false, errors.New("email invalid")
+	}
+	return true, nil
+}
+
+func ValidateChangePassword(passwordRequest ChangePasswordRequest) (bool, error) {
+	if passwordRequest.CurrentPassword == "" {
+		return false, errors.New("current password empty")
+	}
+	if len(passwordRequest.NewPassword) < 8 {
+		return false, errors.New("password, minimum 8 words")
+	}
+	if passwordRequest.NewPassword == passwordRequest.CurrentPassword {
+		return false, errors.New("new password must be different")
+	}
+	return true, nil
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+func ValidateDeleteAccount(deleteRequest DeleteAccountRequest) (bool, error) {
+	if deleteRequest.Password == "" {
+		return false, errors.New("password empty")
+	}
+	return true, nil
+}
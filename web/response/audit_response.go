@@ -0,0 +1,20 @@
+package response
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+// AuditLogResponse has the changed fields of the target before and after the action.
+type AuditLogResponse struct {
+	ID         uuid.UUID              `json:"id"`
+	ActorID    string                 `json:"actor_id"`
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type"`
+	TargetID   string                 `json:"target_id"`
+	Before     map[string]interface{} `json:"before,omitempty"`
+	After      map[string]interface{} `json:"after,omitempty"`
+	IP         string                 `json:"ip"`
+	UserAgent  string                 `json:"user_agent"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
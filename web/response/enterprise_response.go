@@ -6,18 +6,47 @@ import (
 )
 
 type GetListByStatusResponse struct {
-	ID          uuid.UUID   `json:"id"`
-	UserID      uuid.UUID   `json:"user_id"`
-	Name        string      `json:"name"`
-	NumberPhone string      `json:"number_phone"`
-	Address     string      `json:"address"`
-	Postcode    int         `json:"postcode"`
-	Description string      `json:"description"`
-	Latitude    string      `json:"latitude"`
-	Longitude   string      `json:"longitude"`
-	Status      int         `json:"status"`
-	Tags        interface{} `json:"tags,omitempty"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
-	Rating      float64     `json:"rating"`
+	ID          uuid.UUID                `json:"id"`
+	UserID      uuid.UUID                `json:"user_id"`
+	Name        string                   `json:"name"`
+	NumberPhone string                   `json:"number_phone"`
+	Address     string                   `json:"address"`
+	Postcode    int                      `json:"postcode"`
+	Description string                   `json:"description"`
+	Latitude    *float64                 `json:"latitude"`
+	Longitude   *float64                 `json:"longitude"`
+	Status      int                      `json:"status"`
+	Tags        interface{}              `json:"tags,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+	Rating      float64                  `json:"rating"`
+	Distance    *float64                 `json:"distance,omitempty"`
+	Images      EnterpriseImagesResponse `json:"images"`
+}
+
+type ImageResponse struct {
+	ID  uuid.UUID `json:"id"`
+	URL string    `json:"url"`
+}
+
+// EnterpriseImagesResponse leaves out the logo and cover the enterprise does not have.
+type EnterpriseImagesResponse struct {
+	Logo    *ImageResponse  `json:"logo,omitempty"`
+	Cover   *ImageResponse  `json:"cover,omitempty"`
+	Gallery []ImageResponse `json:"gallery"`
+}
+
+type StatusHistoryResponse struct {
+	ID        uuid.UUID `json:"id"`
+	ActorID   uuid.UUID `json:"actor_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DistanceResponse struct {
+	Distance   float64                 `json:"distance"`
+	Unit       string                  `json:"unit"`
+	Enterprise GetListByStatusResponse `json:"enterprise"`
 }
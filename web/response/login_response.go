@@ -2,10 +2,30 @@ package response
 
 import uuid "github.com/satori/go.uuid"
 
+// SuccessLogin has no tokens when TwoFactorRequired is set, the client sends ChallengeToken
+// with a code to /login/2fa to get them.
 type SuccessLogin struct {
-	ID       uuid.UUID `json:"id"`
-	Email    string    `json:"email"`
-	Fullname string    `json:"fullname"`
-	Username string    `json:"username"`
-	Token    string    `json:"token" form:"token"`
+	ID                uuid.UUID `json:"id"`
+	Email             string    `json:"email"`
+	Fullname          string    `json:"fullname"`
+	Username          string    `json:"username"`
+	Token             string    `json:"token" form:"token"`
+	RefreshToken      string    `json:"refresh_token"`
+	ExpiresIn         int64     `json:"expires_in"`
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token,omitempty"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// OIDCAuthorizationResponse has the URL of the provider the browser is sent to.
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
 }
@@ -30,6 +30,18 @@ type JSONUnauthorizedResult struct {
 	Message string `json:"message"`
 }
 
+type JSONForbiddenResult struct {
+	Code    int    `json:"code"`
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+}
+
+type JSONTooManyRequestsResult struct {
+	Code    int    `json:"code"`
+	Status  bool   `json:"status"`
+	Message string `json:"message"`
+}
+
 type JSONSuccessListResult struct {
 	Code     int         `json:"code"`
 	Status   bool        `json:"status"`
@@ -38,6 +50,14 @@ type JSONSuccessListResult struct {
 	Metadata interface{} `json:"metadata"`
 }
 
+// PageMetadata is the Metadata of the cursor paginated listings, a nil cursor means there is no such page.
+type PageMetadata struct {
+	Length     int     `json:"length"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+	TotalData  *int    `json:"total_data,omitempty"`
+}
+
 func SuccessResponse(c echo.Context, code int, status bool, message string, data interface{}) error {
 	return c.JSON(code, JSONSuccessResult{
 		Code:    code,
@@ -74,6 +94,14 @@ func FailResponse(c echo.Context, code int, status bool, message string) error {
 		})
 	}
 
+	if code == http.StatusForbidden {
+		return c.JSON(http.StatusForbidden, JSONForbiddenResult{
+			Code:    code,
+			Message: message,
+			Status:  status,
+		})
+	}
+
 	if code == http.StatusBadRequest {
 		return c.JSON(http.StatusBadRequest, JSONBadRequestResult{
 			Code:    code,
@@ -90,5 +118,13 @@ func FailResponse(c echo.Context, code int, status bool, message string) error {
 		})
 	}
 
+	if code == http.StatusTooManyRequests {
+		return c.JSON(http.StatusTooManyRequests, JSONTooManyRequestsResult{
+			Code:    code,
+			Message: message,
+			Status:  status,
+		})
+	}
+
 	return nil
 }
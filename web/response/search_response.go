@@ -0,0 +1,7 @@
+package response
+
+type SuggestionResponse struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+	Text string `json:"text"`
+}
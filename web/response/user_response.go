@@ -15,14 +15,15 @@ type UserCreateResponse struct {
 }
 
 type UserDetailResponse struct {
-	ID          uuid.UUID     `json:"id"`
-	Email       string        `json:"email"`
-	Fullname    string        `json:"fullname"`
-	Username    string        `json:"username"`
-	Favorite    interface{}   `json:"favorite,omitempty"`
-	Enterprises []interface{} `json:"enterprises,omitempty"`
-	CreatedAt   time.Time     `json:"created_at"`
-	UpdatedAt   time.Time     `json:"updated_at"`
+	ID              uuid.UUID     `json:"id"`
+	Email           string        `json:"email"`
+	EmailVerifiedAt *time.Time    `json:"email_verified_at"`
+	Fullname        string        `json:"fullname"`
+	Username        string        `json:"username"`
+	Favorite        interface{}   `json:"favorite,omitempty"`
+	Enterprises     []interface{} `json:"enterprises,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
 }
 
 type UsersListResponse struct {
@@ -33,3 +34,70 @@ type UsersListResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type UserAdminResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	Email                 string     `json:"email"`
+	EmailVerifiedAt       *time.Time `json:"email_verified_at"`
+	Fullname              string     `json:"fullname"`
+	Username              string     `json:"username"`
+	Roles                 []string   `json:"roles"`
+	SuspendedAt           *time.Time `json:"suspended_at"`
+	SuspendedReason       string     `json:"suspended_reason,omitempty"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	TwoFactorEnabled      bool       `json:"two_factor_enabled"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+type LockedAccountResponse struct {
+	User        UserAdminResponse `json:"user"`
+	Failures    int               `json:"failures"`
+	LockedUntil time.Time         `json:"locked_until"`
+}
+
+// UserExportResponse is the user's copy of their data, the profile uses the admin view so
+// roles and account state are included.
+type UserExportResponse struct {
+	ExportedAt  time.Time                      `json:"exported_at"`
+	Profile     UserAdminResponse              `json:"profile"`
+	Enterprises []UserExportEnterpriseResponse `json:"enterprises"`
+	Favorites   []UserExportFavoriteResponse   `json:"favorites"`
+	Ratings     []UserExportRatingResponse     `json:"ratings"`
+	Reviews     []UserExportReviewResponse     `json:"reviews"`
+}
+
+type UserExportEnterpriseResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	NumberPhone  string    `json:"number_phone"`
+	Address      string    `json:"address"`
+	Postcode     int       `json:"postcode"`
+	Description  string    `json:"description"`
+	Latitude     *float64  `json:"latitude"`
+	Longitude    *float64  `json:"longitude"`
+	Status       string    `json:"status"`
+	StatusReason string    `json:"status_reason,omitempty"`
+	Tags         []string  `json:"tags"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type UserExportFavoriteResponse struct {
+	EnterpriseID uuid.UUID `json:"enterprise_id"`
+	Name         string    `json:"name"`
+}
+
+type UserExportRatingResponse struct {
+	ID           uuid.UUID `json:"id"`
+	EnterpriseID uuid.UUID `json:"enterprise_id"`
+	Rating       int       `json:"rating"`
+}
+
+type UserExportReviewResponse struct {
+	ID           uuid.UUID `json:"id"`
+	EnterpriseID uuid.UUID `json:"enterprise_id"`
+	Review       string    `json:"review"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}